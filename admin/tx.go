@@ -14,6 +14,7 @@ import (
 	"github.com/bnb-chain/node/plugins/tokens/burn"
 	"github.com/bnb-chain/node/plugins/tokens/freeze"
 	"github.com/bnb-chain/node/plugins/tokens/issue"
+	"github.com/bnb-chain/node/plugins/tokens/metadata"
 	"github.com/bnb-chain/node/plugins/tokens/ownership"
 	"github.com/bnb-chain/node/plugins/tokens/seturi"
 	"github.com/bnb-chain/node/plugins/tokens/swap"
@@ -31,12 +32,16 @@ var transferOnlyModeBlackList = []string{
 	timelock.TimeLockMsg{}.Type(),
 	timelock.TimeUnlockMsg{}.Type(),
 	timelock.TimeRelockMsg{}.Type(),
+	timelock.VestingLockMsg{}.Type(),
+	timelock.VestingClaimMsg{}.Type(),
+	timelock.VestingRevokeMsg{}.Type(),
 	issue.IssueMiniMsg{}.Type(),
 	issue.IssueTinyMsg{}.Type(),
 	seturi.SetURIMsg{}.Type(),
 	list.ListMsg{}.Type(),
 	list.ListMiniMsg{}.Type(),
 	ownership.TransferOwnershipMsg{}.Type(),
+	metadata.SetTokenMetadataMsg{}.Type(),
 	swap.HTLTMsg{}.Type(),
 	swap.DepositHTLTMsg{}.Type(),
 	swap.ClaimHTLTMsg{}.Type(),
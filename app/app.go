@@ -7,6 +7,7 @@ import (
 	"io"
 	"math"
 	"os"
+	"path/filepath"
 	"runtime/debug"
 	"sort"
 	"time"
@@ -59,6 +60,7 @@ import (
 	tokenRecover "github.com/bnb-chain/node/plugins/recover"
 	"github.com/bnb-chain/node/plugins/tokens"
 	"github.com/bnb-chain/node/plugins/tokens/issue"
+	"github.com/bnb-chain/node/plugins/tokens/metadata"
 	"github.com/bnb-chain/node/plugins/tokens/ownership"
 	"github.com/bnb-chain/node/plugins/tokens/seturi"
 	"github.com/bnb-chain/node/plugins/tokens/swap"
@@ -103,12 +105,15 @@ type BNBBeaconChain struct {
 	DexKeeper      *dex.DexKeeper
 	AccountKeeper  auth.AccountKeeper
 	TokenMapper    tokens.Mapper
+	TokenHolders   *tokens.HolderIndex // nil if the token holder index is disabled
+	FusionMonitor  *tokens.FusionMonitor
 	ValAddrCache   *ValAddrCache
 	stakeKeeper    stake.Keeper
 	slashKeeper    slashing.Keeper
 	govKeeper      gov.Keeper
 	timeLockKeeper timelock.Keeper
 	swapKeeper     swap.Keeper
+	refundQueue    tokens.RefundQueue // kept in the time lock store
 	oracleKeeper   oracle.Keeper
 	bridgeKeeper   bridge.Keeper
 	ibcKeeper      ibc.Keeper
@@ -122,10 +127,12 @@ type BNBBeaconChain struct {
 	abciQueryBlackList map[string]bool
 	publicationConfig  *config.PublicationConfig
 	publisher          pub.MarketDataPublisher
+	bookSnapshotted    bool // whether the whole order books have been published since the node starts
 	psServer           *pubsub.Server
 	subscriber         *pubsub.Subscriber
 
-	dexConfig *config.DexConfig
+	dexConfig   *config.DexConfig
+	queryConfig *config.QueryConfig
 
 	// Unlike tendermint, we don't need implement a no-op metrics, usage of this field should
 	// check nil-ness to know whether metrics collection is turn on
@@ -153,6 +160,7 @@ func NewBNBBeaconChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseA
 		abciQueryBlackList: getABCIQueryBlackList(ServerContext.QueryConfig),
 		publicationConfig:  ServerContext.PublicationConfig,
 		dexConfig:          ServerContext.DexConfig,
+		queryConfig:        ServerContext.QueryConfig,
 	}
 	// set upgrade config
 	SetUpgradeConfig(app.upgradeConfig)
@@ -161,7 +169,7 @@ func NewBNBBeaconChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseA
 
 	// mappers
 	app.AccountKeeper = auth.NewAccountKeeper(cdc, common.AccountStoreKey, types.ProtoAppAccount)
-	app.TokenMapper = tokens.NewMapper(cdc, common.TokenStoreKey)
+	app.TokenMapper = tokens.NewMapper(cdc, common.TokenStoreKey, common.TokenMetadataStoreKey)
 	app.CoinKeeper = bank.NewBaseKeeper(app.AccountKeeper)
 	app.ParamHub = param.NewKeeper(cdc, common.ParamsStoreKey, common.TParamsStoreKey)
 	app.scKeeper = sidechain.NewKeeper(common.SideChainStoreKey, app.ParamHub.Subspace(sidechain.DefaultParamspace), app.Codec)
@@ -196,6 +204,7 @@ func NewBNBBeaconChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseA
 
 	app.timeLockKeeper = timelock.NewKeeper(cdc, common.TimeLockStoreKey, app.CoinKeeper, app.AccountKeeper,
 		timelock.DefaultCodespace)
+	app.refundQueue = tokens.NewRefundQueue(cdc, common.TimeLockStoreKey)
 
 	app.swapKeeper = swap.NewKeeper(cdc, common.AtomicSwapStoreKey, app.CoinKeeper, app.Pool, swap.DefaultCodespace)
 	app.oracleKeeper = oracle.NewKeeper(cdc, common.OracleStoreKey, app.ParamHub.Subspace(oracle.DefaultParamSpace),
@@ -213,12 +222,14 @@ func NewBNBBeaconChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseA
 		pub.ToPublishCh = make(chan pub.BlockInfoToPublish, app.publicationConfig.PublicationChannelSize)
 		pub.ToPublishEventCh = make(chan *appsub.ToPublishEvent, app.publicationConfig.PublicationChannelSize)
 
-		publishers := make([]pub.MarketDataPublisher, 0, 1)
-		if app.publicationConfig.PublishKafka {
-			publishers = append(publishers, pub.NewKafkaMarketDataPublisher(app.Logger, ServerContext.Config.DBDir(), app.publicationConfig.StopOnKafkaFail))
-		}
-		if app.publicationConfig.PublishLocal {
-			publishers = append(publishers, pub.NewLocalMarketDataPublisher(ServerContext.Config.RootDir, app.Logger, app.publicationConfig))
+		publishers, err := pub.NewPublishers(app.publicationConfig.PublisherNames(), pub.PublisherEnv{
+			Logger:  app.Logger,
+			Config:  app.publicationConfig,
+			RootDir: ServerContext.Config.RootDir,
+			DBDir:   ServerContext.Config.DBDir(),
+		})
+		if err != nil {
+			panic(err)
 		}
 
 		if len(publishers) == 0 {
@@ -229,8 +240,28 @@ func NewBNBBeaconChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseA
 			} else {
 				app.publisher = pub.NewAggregatedMarketDataPublisher(publishers...)
 			}
+			if watchListFile := app.publicationConfig.WatchListFile; watchListFile != "" {
+				if !filepath.IsAbs(watchListFile) {
+					watchListFile = filepath.Join(ServerContext.Config.RootDir, watchListFile)
+				}
+				interval := time.Duration(app.publicationConfig.WatchListReloadInterval) * time.Second
+				publisher, err := pub.NewWatchListMarketDataPublisher(app.publisher, watchListFile, interval)
+				if err != nil {
+					panic(err)
+				}
+				app.publisher = publisher
+			}
+
+			bookSequencesDB, err := dbm.NewGoLevelDB(pub.BookSequencesDB, ServerContext.Config.DBDir())
+			if err != nil {
+				panic(err)
+			}
+			bookSequencer, err := pub.NewBookSequencer(bookSequencesDB)
+			if err != nil {
+				panic(err)
+			}
 
-			go pub.Publish(app.publisher, app.metrics, logger, app.publicationConfig, pub.ToPublishCh)
+			go pub.Publish(app.publisher, app.metrics, logger, app.publicationConfig, bookSequencer, pub.ToPublishCh)
 			go pub.PublishEvent(app.publisher, logger, app.publicationConfig, pub.ToPublishEventCh)
 			pub.IsLive = true
 		}
@@ -262,6 +293,7 @@ func NewBNBBeaconChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseA
 		common.OracleStoreKey,
 		common.IbcStoreKey,
 		common.ReconStoreKey,
+		common.TokenMetadataStoreKey,
 	)
 	app.SetAnteHandler(tx.NewAnteHandler(app.AccountKeeper))
 	app.SetPreChecker(tx.NewTxPreChecker())
@@ -365,6 +397,16 @@ func SetUpgradeConfig(upgradeConfig *config.UpgradeConfig) {
 	upgrade.Mgr.AddUpgradeHeight(upgrade.FirstSunset, upgradeConfig.FirstSunsetHeight)
 	upgrade.Mgr.AddUpgradeHeight(upgrade.SecondSunset, upgradeConfig.SecondSunsetHeight)
 	upgrade.Mgr.AddUpgradeHeight(upgrade.FinalSunset, upgradeConfig.FinalSunsetHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TokenMetadata, upgradeConfig.TokenMetadataHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TimeLockVesting, upgradeConfig.TimeLockVestingHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TimeLockBeneficiary, upgradeConfig.TimeLockBeneficiaryHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.SunsetRefundRetry, upgradeConfig.SunsetRefundRetryHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.MarketOrder, upgradeConfig.MarketOrderHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.ConditionalOrder, upgradeConfig.ConditionalOrderHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.FOKAndPostOnly, upgradeConfig.FOKAndPostOnlyHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BatchOrder, upgradeConfig.BatchOrderHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.CancelAllOrders, upgradeConfig.CancelAllOrdersHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.AmendOrder, upgradeConfig.AmendOrderHeight)
 
 	// register store keys of upgrade
 	upgrade.Mgr.RegisterStoreKeys(upgrade.BEP9, common.TimeLockStoreKey.Name())
@@ -373,6 +415,7 @@ func SetUpgradeConfig(upgradeConfig *config.UpgradeConfig) {
 		common.SlashingStoreKey.Name(), common.BridgeStoreKey.Name(), common.OracleStoreKey.Name())
 	upgrade.Mgr.RegisterStoreKeys(upgrade.BEP128, common.StakeRewardStoreKey.Name())
 	upgrade.Mgr.RegisterStoreKeys(upgrade.BEP255, common.ReconStoreKey.Name())
+	upgrade.Mgr.RegisterStoreKeys(upgrade.TokenMetadata, common.TokenMetadataStoreKey.Name())
 
 	// register msg types of upgrade
 	upgrade.Mgr.RegisterMsgTypes(upgrade.BEP9,
@@ -419,6 +462,18 @@ func SetUpgradeConfig(upgradeConfig *config.UpgradeConfig) {
 	)
 
 	upgrade.Mgr.RegisterMsgTypes(upgrade.BEP82, ownership.TransferOwnershipMsg{}.Type())
+	upgrade.Mgr.RegisterMsgTypes(upgrade.TokenMetadata, metadata.SetTokenMetadataMsg{}.Type())
+	upgrade.Mgr.RegisterMsgTypes(upgrade.TimeLockVesting,
+		timelock.VestingLockMsg{}.Type(),
+		timelock.VestingClaimMsg{}.Type(),
+		timelock.VestingRevokeMsg{}.Type(),
+	)
+	upgrade.Mgr.RegisterMsgTypes(upgrade.BatchOrder,
+		order.BatchNewOrderMsg{}.Type(),
+		order.BatchCancelOrderMsg{}.Type(),
+	)
+	upgrade.Mgr.RegisterMsgTypes(upgrade.CancelAllOrders, order.CancelAllOrdersMsg{}.Type())
+	upgrade.Mgr.RegisterMsgTypes(upgrade.AmendOrder, order.AmendOrderMsg{}.Type())
 }
 
 func getABCIQueryBlackList(queryConfig *config.QueryConfig) map[string]bool {
@@ -443,6 +498,27 @@ func (app *BNBBeaconChain) initDex() {
 		app.publicationConfig.ShouldPublishAny())
 	app.DexKeeper.SubscribeParamChange(app.ParamHub)
 	app.DexKeeper.SetBUSDSymbol(app.dexConfig.BUSDSymbol)
+	if app.dexConfig.OrderBookWAL {
+		app.DexKeeper.EnableOrderBookWAL(filepath.Join(ServerContext.Config.DBDir(), "orderbook.wal"))
+	}
+	if app.dexConfig.OrderHistory {
+		app.DexKeeper.EnableOrderHistory(order.NewOrderHistory(app.dexConfig.MaxClosedOrders, app.dexConfig.MaxClosedOrdersPerAccount,
+			app.dexConfig.TradeHistoryBlocks, app.dexConfig.MaxTradesPerPair))
+	}
+	if app.dexConfig.Klines || app.publicationConfig.PublishKline {
+		var klinesDB dbm.DB
+		if app.dexConfig.KlinesPersisted {
+			db, err := dbm.NewGoLevelDB("klines", ServerContext.Config.DBDir())
+			if err != nil {
+				panic(err)
+			}
+			klinesDB = db
+		}
+		app.DexKeeper.EnableKlines(order.NewKlineAggregator(app.dexConfig.MaxKlines, klinesDB))
+	}
+	if app.dexConfig.Ticker {
+		app.DexKeeper.EnableTickers(order.NewTickerStats())
+	}
 
 	// do not proceed if we are in a unit test and `CheckState` is unset.
 	if app.CheckState == nil {
@@ -477,7 +553,10 @@ func (app *BNBBeaconChain) initPlugins() {
 	app.initOracle()
 	app.initParamHub()
 	app.initBridge()
-	tokens.InitPlugin(app, app.TokenMapper, app.AccountKeeper, app.CoinKeeper, app.timeLockKeeper, app.swapKeeper)
+	app.initTokenHolders()
+	app.FusionMonitor = tokens.NewFusionMonitor()
+	tokens.InitPlugin(app, app.TokenMapper, app.AccountKeeper, app.CoinKeeper, app.timeLockKeeper, app.swapKeeper,
+		app.TokenHolders)
 	dex.InitPlugin(app, app.DexKeeper, app.TokenMapper, app.govKeeper)
 	account.InitPlugin(app, app.AccountKeeper)
 	bridge.InitPlugin(app, app.bridgeKeeper)
@@ -502,9 +581,22 @@ func (app *BNBBeaconChain) initPlugins() {
 
 	app.RegisterQueryHandler("account", app.AccountHandler)
 	app.RegisterQueryHandler("admin", admin.GetHandler(ServerContext.Config))
+	app.RegisterQueryHandler("fusion", app.FusionHandler)
 
 }
 
+func (app *BNBBeaconChain) initTokenHolders() {
+	if !app.queryConfig.TokenHolders {
+		return
+	}
+	app.TokenHolders = tokens.NewHolderIndex(app.queryConfig.MaxTopHolders)
+	// do not build the index if we are in a unit test and `CheckState` is unset.
+	if app.CheckState == nil {
+		return
+	}
+	app.TokenHolders.Build(app.CheckState.Ctx, app.AccountKeeper)
+}
+
 func (app *BNBBeaconChain) initSideChain() {
 	app.scKeeper.SetGovKeeper(&app.govKeeper)
 	app.scKeeper.SetIbcKeeper(&app.ibcKeeper)
@@ -555,6 +647,22 @@ func (app *BNBBeaconChain) initParamHub() {
 	app.ParamHub.SetupForSideChain(&app.scKeeper, &app.ibcKeeper)
 
 	paramHub.RegisterUpgradeBeginBlocker(app.ParamHub)
+	app.registerFixedFees(upgrade.TokenMetadata,
+		&paramTypes.FixedFeeParams{MsgType: metadata.SetTokenMetadataMsgType, Fee: metadata.SetTokenMetadataFee, FeeFor: sdk.FeeForProposer})
+	app.registerFixedFees(upgrade.TimeLockVesting,
+		&paramTypes.FixedFeeParams{MsgType: timelock.VestingLockMsg{}.Type(), Fee: timelock.VestingLockFee, FeeFor: sdk.FeeForProposer},
+		&paramTypes.FixedFeeParams{MsgType: timelock.VestingClaimMsg{}.Type(), Fee: timelock.VestingClaimFee, FeeFor: sdk.FeeForProposer},
+		&paramTypes.FixedFeeParams{MsgType: timelock.VestingRevokeMsg{}.Type(), Fee: timelock.VestingRevokeFee, FeeFor: sdk.FeeForProposer})
+	// same as the single order msgs, the fee of each order or cancel in a batch is charged in the dex
+	app.registerFees(upgrade.BatchOrder, order.BatchFeeCalculatorGen,
+		&paramTypes.FixedFeeParams{MsgType: order.BatchNewOrderMsgType, Fee: 0, FeeFor: sdk.FeeFree},
+		&paramTypes.FixedFeeParams{MsgType: order.BatchCancelOrderMsgType, Fee: 0, FeeFor: sdk.FeeFree})
+	// the cancel fee of each canceled order is charged in the dex
+	app.registerFixedFees(upgrade.CancelAllOrders,
+		&paramTypes.FixedFeeParams{MsgType: order.CancelAllOrdersMsgType, Fee: 0, FeeFor: sdk.FeeFree})
+	// same as a new order, the amended order is charged in the dex when it's filled or expired
+	app.registerFixedFees(upgrade.AmendOrder,
+		&paramTypes.FixedFeeParams{MsgType: order.AmendOrderMsgType, Fee: 0, FeeFor: sdk.FeeFree})
 	upgrade.Mgr.RegisterBeginBlocker(sdk.LaunchBscUpgrade, func(ctx sdk.Context) {
 		app.scKeeper.SetChannelSendPermission(ctx, sdk.ChainID(ServerContext.BscIbcChainId), param.ChannelId, sdk.ChannelAllow)
 		storePrefix := app.scKeeper.GetSideChainStorePrefix(ctx, ServerContext.BscChainId)
@@ -568,6 +676,25 @@ func (app *BNBBeaconChain) initParamHub() {
 	})
 }
 
+// registerFixedFees registers the fixed fees of the msgs not known by the param hub, the fees are set at the upgrade
+// which introduces the msgs and can be changed by the fee change proposals afterwards
+func (app *BNBBeaconChain) registerFixedFees(upgradeName string, feeParams ...*paramTypes.FixedFeeParams) {
+	app.registerFees(upgradeName, fees.FixedFeeCalculatorGen, feeParams...)
+}
+
+// registerFees is the same as registerFixedFees, but the fees are calculated by the given generator
+func (app *BNBBeaconChain) registerFees(upgradeName string, gen fees.FeeCalculatorGenerator, feeParams ...*paramTypes.FixedFeeParams) {
+	updates := make([]paramTypes.FeeParam, 0, len(feeParams))
+	for _, feeParam := range feeParams {
+		fees.CalculatorsGen[feeParam.MsgType] = gen
+		paramTypes.ValidFixedFeeMsgTypes[feeParam.MsgType] = struct{}{}
+		updates = append(updates, feeParam)
+	}
+	upgrade.Mgr.RegisterBeginBlocker(upgradeName, func(ctx sdk.Context) {
+		app.ParamHub.UpdateFeeParams(ctx, updates)
+	})
+}
+
 func (app *BNBBeaconChain) initStaking() {
 	app.stakeKeeper.SetupForSideChain(&app.scKeeper, &app.ibcKeeper)
 	app.stakeKeeper.SetPbsbServer(app.psServer)
@@ -918,7 +1045,8 @@ func (app *BNBBeaconChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock)
 	isBreatheBlock := app.isBreatheBlock(height, lastBlockTime, blockTime)
 	var tradesToPublish []*pub.Trade
 	if sdk.IsUpgrade(upgrade.BEP19) || !isBreatheBlock {
-		if app.publicationConfig.ShouldPublishAny() && pub.IsLive {
+		// the order changes of matching are also collected for the order history
+		if app.publicationConfig.ShouldPublishAny() && pub.IsLive || app.DexKeeper.GetOrderHistory() != nil {
 			tradesToPublish = pub.MatchAndAllocateAllForPublish(app.DexKeeper, ctx, isBreatheBlock)
 		} else {
 			app.DexKeeper.MatchAndAllocateSymbols(ctx, nil, isBreatheBlock)
@@ -936,10 +1064,12 @@ func (app *BNBBeaconChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock)
 		tokens.EndBreatheBlock(ctx, app.swapKeeper)
 	} else {
 		app.Logger.Debug("normal block", "height", height)
-		tokens.EndBlocker(ctx, app.timeLockKeeper, app.swapKeeper)
+		tokens.EndBlocker(ctx, app.timeLockKeeper, app.swapKeeper, app.refundQueue, app.FusionMonitor)
 	}
 
 	app.DexKeeper.StoreTradePrices(ctx)
+	app.DexKeeper.UpdateKlines(height, blockTime.UnixNano())
+	app.DexKeeper.UpdateTickers(blockTime.UnixNano())
 
 	var blockFee pub.BlockFee
 	if sdk.IsUpgrade(upgrade.BEP159) {
@@ -972,14 +1102,15 @@ func (app *BNBBeaconChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock)
 		app.ValAddrCache.ClearCache()
 	}
 
+	// update the history before the closed orders are removed by publication
+	app.DexKeeper.UpdateOrderHistory(height, blockTime.UnixNano())
 	if app.publicationConfig.ShouldPublishAny() &&
 		pub.IsLive {
 		stakeUpdates := pub.CollectStakeUpdatesForPublish(completedUbd)
 		if height >= app.publicationConfig.FromHeightInclusive {
-			app.publish(tradesToPublish, &proposals, &sideProposals, &stakeUpdates, blockFee, ctx, height, blockTime.UnixNano())
-
 			appsub.SetMeta(height, blockTime, isBreatheBlock)
 			app.subscriber.Wait()
+			app.publish(tradesToPublish, &proposals, &sideProposals, &stakeUpdates, blockFee, ctx, height, blockTime.UnixNano(), isBreatheBlock)
 			app.publishEvent()
 		}
 
@@ -989,6 +1120,10 @@ func (app *BNBBeaconChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock)
 
 		// clean up intermediate cached data used to be published
 		appsub.Clear()
+	} else if app.DexKeeper.GetOrderHistory() != nil {
+		app.DexKeeper.RemoveClosedOrderInfosForPub(height)
+		app.DexKeeper.ClearOrderChanges()
+		app.DexKeeper.ClearRoundFee()
 	}
 	fees.Pool.Clear()
 	// just clean it, no matter use it or not.
@@ -1001,6 +1136,9 @@ func (app *BNBBeaconChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock)
 	if sdk.IsUpgrade(upgrade.BEP255) {
 		app.reconBalance(ctx, accountIavl, tokenIavl)
 	}
+	if app.TokenHolders != nil {
+		app.updateTokenHolders(ctx, accountIavl)
+	}
 	accountIavl.ResetDiff()
 	tokenIavl.ResetDiff()
 
@@ -1184,19 +1322,25 @@ func MakeCodec() *wire.Codec {
 }
 
 func (app *BNBBeaconChain) publishEvent() {
+	// the events are published along with the block in exactly-once mode
+	if app.publicationConfig.PublishExactlyOnce() {
+		return
+	}
 	if appsub.ToPublish() != nil && appsub.ToPublish().EventData != nil {
 		pub.ToPublishEventCh <- appsub.ToPublish()
 	}
 
 }
 
-func (app *BNBBeaconChain) publish(tradesToPublish []*pub.Trade, proposalsToPublish *pub.Proposals, sideProposalsToPublish *pub.SideProposals, stakeUpdates *pub.StakeUpdates, blockFee pub.BlockFee, ctx sdk.Context, height, blockTime int64) {
+func (app *BNBBeaconChain) publish(tradesToPublish []*pub.Trade, proposalsToPublish *pub.Proposals, sideProposalsToPublish *pub.SideProposals, stakeUpdates *pub.StakeUpdates, blockFee pub.BlockFee, ctx sdk.Context, height, blockTime int64, isBreatheBlock bool) {
 	pub.Logger.Info("start to collect publish information", "height", height)
 
 	var accountsToPublish map[string]pub.Account
 	var transferToPublish *pub.Transfers
 	var blockToPublish *pub.Block
 	var latestPriceLevels order.ChangedPriceLevelsMap
+	var klinesToPublish *pub.Klines
+	var bookSnapshot bool
 
 	orderChanges := app.DexKeeper.GetAllOrderChanges()
 	orderInfoForPublish := app.DexKeeper.GetAllOrderInfosForPub()
@@ -1222,7 +1366,19 @@ func (app *BNBBeaconChain) publish(tradesToPublish []*pub.Trade, proposalsToPubl
 			blockToPublish = pub.GetBlockPublished(app.Pool, header, blockHash)
 		}
 		if app.publicationConfig.PublishOrderBook {
-			latestPriceLevels = app.DexKeeper.GetOrderBooks(pub.MaxOrderBookLevel)
+			// a consumer can only sync with the deltas after a snapshot of the whole books
+			bookSnapshot = !app.bookSnapshotted || isBreatheBlock ||
+				app.publicationConfig.OrderBookSnapshotInterval > 0 && height%app.publicationConfig.OrderBookSnapshotInterval == 0
+			if bookSnapshot {
+				latestPriceLevels = app.DexKeeper.GetOrderBooks(math.MaxInt32)
+				app.bookSnapshotted = true
+			} else {
+				latestPriceLevels = app.DexKeeper.GetOrderBooks(pub.MaxOrderBookLevel)
+				app.DexKeeper.AddTouchedPriceLevels(latestPriceLevels, height)
+			}
+		}
+		if app.publicationConfig.PublishKline {
+			klinesToPublish = pub.GetKlinesPublished(app.DexKeeper.GetKlineAggregator())
 		}
 	})
 
@@ -1245,6 +1401,15 @@ func (app *BNBBeaconChain) publish(tradesToPublish []*pub.Trade, proposalsToPubl
 		len(accountsToPublish))
 	pub.ToRemoveOrderIdCh = make(chan pub.OrderSymbolId, pub.ToRemoveOrderIdChannelSize)
 
+	// in exactly-once mode, the events of the block are published in the same transaction, and the block isn't
+	// committed until the transaction is, so the node publishes the block again if it stops before that
+	var events *appsub.ToPublishEvent
+	var committed chan struct{}
+	if app.publicationConfig.PublishExactlyOnce() {
+		events = appsub.ToPublish()
+		committed = make(chan struct{})
+	}
+
 	pub.ToPublishCh <- pub.NewBlockInfoToPublish(
 		height,
 		blockTime,
@@ -1259,7 +1424,11 @@ func (app *BNBBeaconChain) publish(tradesToPublish []*pub.Trade, proposalsToPubl
 		blockFee,
 		app.DexKeeper.RoundOrderFees, // only use DexKeeper RoundOrderFees
 		transferToPublish,
-		blockToPublish)
+		blockToPublish,
+		klinesToPublish,
+		bookSnapshot,
+		events,
+		committed)
 
 	// remove item from OrderInfoForPublish when we published removed order (cancel, iocnofill, fullyfilled, expired)
 	for o := range pub.ToRemoveOrderIdCh {
@@ -1267,5 +1436,9 @@ func (app *BNBBeaconChain) publish(tradesToPublish []*pub.Trade, proposalsToPubl
 		app.DexKeeper.RemoveOrderInfosForPub(o.Symbol, o.Id)
 	}
 
+	if committed != nil {
+		<-committed
+	}
+
 	pub.Logger.Debug("finish publish", "height", height)
 }
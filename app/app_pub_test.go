@@ -98,7 +98,8 @@ func setupAppTest(t *testing.T) (*assert.Assertions, *require.Assertions, *BNBBe
 	pub.ToPublishCh = make(chan pub.BlockInfoToPublish, app.publicationConfig.PublicationChannelSize)
 	pub.ToPublishEventCh = make(chan *appsub.ToPublishEvent, app.publicationConfig.PublicationChannelSize)
 	app.publisher = pub.NewMockMarketDataPublisher()
-	go pub.Publish(app.publisher, app.metrics, logger, app.publicationConfig, pub.ToPublishCh)
+	bookSequencer, _ := pub.NewBookSequencer(dbm.NewMemDB())
+	go pub.Publish(app.publisher, app.metrics, logger, app.publicationConfig, bookSequencer, pub.ToPublishCh)
 	pub.IsLive = true
 	go pub.PublishEvent(app.publisher, logger, app.publicationConfig, pub.ToPublishEventCh)
 	app.startPubSub(logger)
@@ -137,8 +138,10 @@ func TestAppPub_AddOrder(t *testing.T) {
 	}
 	publisher.Lock.Lock()
 	require.Len(publisher.BooksPublished, 1)
-	require.Len(publisher.BooksPublished[0].Books, 1)
-	assert.Equal(pub.OrderBookDelta{"XYZ-000_BNB", []pub.PriceLevel{{102000, 3000000}}, make([]pub.PriceLevel, 0)}, publisher.BooksPublished[0].Books[0])
+	// the first block published is a snapshot of all the pairs
+	require.Len(publisher.BooksPublished[0].Books, 2)
+	assert.Equal(pub.OrderBookDelta{"ZCB-000_BNB", make([]pub.PriceLevel, 0), make([]pub.PriceLevel, 0), 1, true}, publisher.BooksPublished[0].Books[1])
+	assert.Equal(pub.OrderBookDelta{"XYZ-000_BNB", []pub.PriceLevel{{102000, 3000000}}, make([]pub.PriceLevel, 0), 1, true}, publisher.BooksPublished[0].Books[0])
 	publisher.Lock.Unlock()
 }
 
@@ -183,7 +186,7 @@ func TestAppPub_MatchOrder(t *testing.T) {
 	publisher.Lock.Lock()
 	require.Len(publisher.BooksPublished, 2)
 	require.Len(publisher.BooksPublished[1].Books, 1)
-	assert.Equal(pub.OrderBookDelta{"XYZ-000_BNB", []pub.PriceLevel{{102000, 0}}, []pub.PriceLevel{{102000, 100000000}}}, publisher.BooksPublished[1].Books[0])
+	assert.Equal(pub.OrderBookDelta{"XYZ-000_BNB", []pub.PriceLevel{{102000, 0}}, []pub.PriceLevel{{102000, 100000000}}, 2, false}, publisher.BooksPublished[1].Books[0])
 	expectedAccountToPub = pub.Account{string(buyerAcc.GetAddress()), "BNB:153", 1, []*pub.AssetBalance{{"BNB", 99999693847, 0, 0}, {"XYZ-000", 100300000000, 0, 0}}}
 	expectedAccountToPubSeller := pub.Account{string(sellerAcc.GetAddress()), "BNB:153", 1, []*pub.AssetBalance{{"BNB", 100000305847, 0, 0}, {"XYZ-000", 99600000000, 0, 100000000}}}
 	require.Len(publisher.AccountPublished, 2)
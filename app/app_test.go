@@ -11,7 +11,6 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/abci/types"
 	abci "github.com/tendermint/tendermint/abci/types"
-	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/crypto"
 	dbm "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
@@ -19,9 +18,17 @@ import (
 	"github.com/bnb-chain/node/common/testutils"
 )
 
+var home = testutils.SetupTempHome() // has to be set before the app is created in init
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	TearDown()
+	os.Exit(code)
+}
+
 func TearDown() {
 	// remove block db
-	os.RemoveAll(cfg.DefaultConfig().DBDir())
+	os.RemoveAll(home)
 }
 
 func defaultLogger() log.Logger {
@@ -15,7 +15,6 @@ import (
 	abcicli "github.com/tendermint/tendermint/abci/client"
 	"github.com/tendermint/tendermint/abci/types"
 	abci "github.com/tendermint/tendermint/abci/types"
-	cfg "github.com/tendermint/tendermint/config"
 	. "github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
 	"github.com/tendermint/tendermint/crypto/tmhash"
@@ -25,6 +24,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/bnb-chain/node/app"
+	"github.com/bnb-chain/node/common/testutils"
 	common "github.com/bnb-chain/node/common/types"
 	"github.com/bnb-chain/node/plugins/dex"
 	"github.com/bnb-chain/node/plugins/tokens"
@@ -78,6 +78,7 @@ func (tc *TestClient) CheckTxSync(msg sdk.Msg, cdc *wire.Codec) (*types.Response
 
 // util objects
 var (
+	home                              = testutils.SetupTempHome() // has to be set before the app is created
 	memDB                             = db.NewMemDB()
 	logger                            = log.NewTMLogger(os.Stdout)
 	testApp                           = app.NewBNBBeaconChain(logger, memDB, os.Stdout)
@@ -86,9 +87,15 @@ var (
 	testClient = NewTestClient(testApp)
 )
 
+func TestMain(m *testing.M) {
+	code := m.Run()
+	TearDown()
+	os.Exit(code)
+}
+
 func TearDown() {
 	// remove block db
-	os.RemoveAll(cfg.DefaultConfig().DBDir())
+	os.RemoveAll(home)
 }
 
 func InitAccounts(ctx sdk.Context, app *app.BNBBeaconChain) *[]sdk.Account {
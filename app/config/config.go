@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"math"
 	"path/filepath"
+	"strings"
 	"text/template"
 
 	"github.com/cosmos/cosmos-sdk/server"
@@ -25,6 +26,15 @@ const (
 	AppConfigFileName = "app"
 )
 
+// names of the built-in publisher backends
+const (
+	KafkaPublisher          = "kafka"
+	LocalPublisher          = "local"
+	WebSocketPublisher      = "websocket"
+	PublicationLogPublisher = "publog"
+	StdoutPublisher         = "stdout"
+)
+
 // Note: any changes to the comments/variables/mapstructure
 // must be reflected in the appropriate struct in config/config.go
 const appConfigTemplate = `# This is a TOML config file.
@@ -109,10 +119,35 @@ FirstSunsetHeight = {{ .UpgradeConfig.FirstSunsetHeight }}
 SecondSunsetHeight = {{ .UpgradeConfig.SecondSunsetHeight }}
 # Block height of FinalSunset upgrade
 FinalSunsetHeight = {{ .UpgradeConfig.FinalSunsetHeight }}
+# Block height of TokenMetadata upgrade
+TokenMetadataHeight = {{ .UpgradeConfig.TokenMetadataHeight }}
+# Block height of TimeLockVesting upgrade
+TimeLockVestingHeight = {{ .UpgradeConfig.TimeLockVestingHeight }}
+# Block height of TimeLockBeneficiary upgrade
+TimeLockBeneficiaryHeight = {{ .UpgradeConfig.TimeLockBeneficiaryHeight }}
+# Block height of SunsetRefundRetry upgrade
+SunsetRefundRetryHeight = {{ .UpgradeConfig.SunsetRefundRetryHeight }}
+# Block height of MarketOrder upgrade
+MarketOrderHeight = {{ .UpgradeConfig.MarketOrderHeight }}
+# Block height of ConditionalOrder upgrade
+ConditionalOrderHeight = {{ .UpgradeConfig.ConditionalOrderHeight }}
+# Block height of FOKAndPostOnly upgrade
+FOKAndPostOnlyHeight = {{ .UpgradeConfig.FOKAndPostOnlyHeight }}
+# Block height of BatchOrder upgrade
+BatchOrderHeight = {{ .UpgradeConfig.BatchOrderHeight }}
+# Block height of CancelAllOrders upgrade
+CancelAllOrdersHeight = {{ .UpgradeConfig.CancelAllOrdersHeight }}
+# Block height of AmendOrder upgrade
+AmendOrderHeight = {{ .UpgradeConfig.AmendOrderHeight }}
 
 [query]
-# ABCI query interface black list, suggested value: ["custom/gov/proposals", "custom/timelock/timelocks", "custom/atomicSwap/swapcreator", "custom/atomicSwap/swaprecipient"]
+# ABCI query interface black list, suggested value: ["custom/gov/proposals", "custom/timelock/timelocks", "custom/atomicSwap/swapcreator", "custom/atomicSwap/swaprecipient", "fusion/status"]
 ABCIQueryBlackList = {{ .QueryConfig.ABCIQueryBlackList }}
+# Whether to index the holders of the tokens in memory for the tokens/holders query, the index is built
+# from the account store on startup
+tokenHolders = {{ .QueryConfig.TokenHolders }}
+# Max number of the top holders of a token returned by the tokens/holders query
+maxTopHolders = {{ .QueryConfig.MaxTopHolders }}
 
 [addr]
 # Bech32PrefixAccAddr defines the Bech32 prefix of an account's address
@@ -145,6 +180,9 @@ accountBalanceKafka = "{{ .PublicationConfig.AccountBalanceKafka }}"
 publishOrderBook = {{ .PublicationConfig.PublishOrderBook }}
 orderBookTopic = "{{ .PublicationConfig.OrderBookTopic }}"
 orderBookKafka = "{{ .PublicationConfig.OrderBookKafka }}"
+# Interval blocks of the full order book snapshots, besides the ones on breathe blocks and on the first block
+# published. 0 disables the periodic snapshots.
+orderBookSnapshotInterval = {{ .PublicationConfig.OrderBookSnapshotInterval }}
 
 # Whether we want publish block fee changes
 publishBlockFee = {{ .PublicationConfig.PublishBlockFee }}
@@ -196,6 +234,11 @@ publishBreatheBlock = {{ .PublicationConfig.PublishBreatheBlock }}
 breatheBlockTopic = "{{ .PublicationConfig.BreatheBlockTopic }}"
 breatheBlockKafka = "{{ .PublicationConfig.BreatheBlockKafka }}"
 
+# Whether we want publish the klines updated or closed in each block, which also enables the klines in [dex]
+publishKline = {{ .PublicationConfig.PublishKline }}
+klineTopic = "{{ .PublicationConfig.KlineTopic }}"
+klineKafka = "{{ .PublicationConfig.KlineKafka }}"
+
 # Global setting
 publicationChannelSize = {{ .PublicationConfig.PublicationChannelSize }}
 publishKafka = {{ .PublicationConfig.PublishKafka }}
@@ -204,6 +247,29 @@ publishLocal = {{ .PublicationConfig.PublishLocal }}
 localMaxSize = {{ .PublicationConfig.LocalMaxSize }}
 # max days of marketdata json files to keep before deleted
 localMaxAge = {{ .PublicationConfig.LocalMaxAge }}
+# Comma separated names of the publisher backends besides the ones enabled by publishKafka and publishLocal,
+# the built-in backends are kafka, local, websocket, publog and stdout
+publishers = "{{ .PublicationConfig.Publishers }}"
+# Address the websocket publisher listens on
+webSocketAddress = "{{ .PublicationConfig.WebSocketAddress }}"
+# Max number of the messages queued for a websocket client, a client falling behind more is disconnected
+webSocketClientBuffer = {{ .PublicationConfig.WebSocketClientBuffer }}
+# Comma separated origins the browsers may connect to the websocket publisher from, e.g. "https://example.com",
+# "*" allows any origin. Only the same origin is allowed if it's empty, the clients sending no origin are always allowed
+webSocketAllowedOrigins = "{{ .PublicationConfig.WebSocketAllowedOrigins }}"
+# Max number of the websocket clients connected at the same time, the others are rejected
+webSocketMaxClients = {{ .PublicationConfig.WebSocketMaxClients }}
+# Size in megabytes of a segment of the publication log written by the publog publisher under the data dir,
+# which can be published again by "bnbchaind publish replay"
+publicationLogSegmentSize = {{ .PublicationConfig.PublicationLogSegmentSize }}
+# Max number of the segments of the publication log to keep
+publicationLogMaxSegments = {{ .PublicationConfig.PublicationLogMaxSegments }}
+# Path (absolute or relative to the home dir) of the json watch list of the addresses, symbols and msg types
+# to publish, e.g. {"addresses": ["bnb1..."], "symbols": ["XYZ-000_BNB"], "msgTypes": ["Accounts"]},
+# everything is published if it's empty
+watchListFile = "{{ .PublicationConfig.WatchListFile }}"
+# Interval in seconds to check the watch list file and reload it once modified, 0 to disable the reload
+watchListReloadInterval = {{ .PublicationConfig.WatchListReloadInterval }}
 
 # whether the kafka open SASL_PLAINTEXT auth
 auth = {{ .PublicationConfig.Auth }}
@@ -217,6 +283,22 @@ stopOnKafkaFail = {{ .PublicationConfig.StopOnKafkaFail }}
 # kafka broker version, default (and most recommended) is 2.1.0. Minimal supported version could be 0.8.2.0
 kafkaVersion = "{{ .PublicationConfig.KafkaVersion }}"
 
+# publish the messages of every block height, including the events, in one kafka transaction, and record the last
+# height committed in the publication db under the data dir. A block is committed by the node only after the
+# transaction of its height is, so the node resumes from the last height committed after a restart, and consumers
+# reading with isolation.level=read_committed see every height exactly once. The node stops if a transaction fails.
+# All the topics should be on the same kafka brokers, and kafkaVersion should be 0.11.0.0 or above
+kafkaExactlyOnce = {{ .PublicationConfig.KafkaExactlyOnce }}
+# transactional id of the kafka producer, it should be unique among the nodes publishing to the same brokers and
+# stay the same across restarts. Default to the host name if it's empty
+kafkaTransactionalId = "{{ .PublicationConfig.KafkaTransactionalId }}"
+
+# url of the confluent compatible schema registry, the kafka messages are prefixed with the schema id
+# in the wire format of the schema registry if it's set
+schemaRegistryUrl = "{{ .PublicationConfig.SchemaRegistryUrl }}"
+# register the schemas in the schema registry on startup, otherwise the schemas should be registered in advance
+schemaRegistryAutoRegister = {{ .PublicationConfig.SchemaRegistryAutoRegister }}
+
 [log]
 
 # Write logs to console instead of file
@@ -241,6 +323,29 @@ bscIbcChainId = {{ .CrossChainConfig.BscIbcChainId }}
 [dex]
 # The suffixed symbol of BUSD
 BUSDSymbol = "{{ .DexConfig.BUSDSymbol }}"
+# Whether to write order book changes to a write-ahead log, so that the order book can be recovered
+# from the last breathe block snapshot and the log instead of replaying all the blocks since the breathe block
+orderBookWAL = {{ .DexConfig.OrderBookWAL }}
+# Whether to keep the recent closed orders of each account and the recent trades of each trading pair in memory,
+# which serve the orderhistory and trades queries without an external indexer
+orderHistory = {{ .DexConfig.OrderHistory }}
+# Max number of the closed orders kept for all the accounts, the accounts whose orders closed least recently are evicted
+maxClosedOrders = {{ .DexConfig.MaxClosedOrders }}
+# Max number of the closed orders kept for each account
+maxClosedOrdersPerAccount = {{ .DexConfig.MaxClosedOrdersPerAccount }}
+# Number of the recent blocks whose trades are kept
+tradeHistoryBlocks = {{ .DexConfig.TradeHistoryBlocks }}
+# Max number of the trades kept for each trading pair
+maxTradesPerPair = {{ .DexConfig.MaxTradesPerPair }}
+# Whether to aggregate the trades of each block into the klines of each trading pair,
+# which serve the klines query and the klines publication
+klines = {{ .DexConfig.Klines }}
+# Max number of the klines kept for each trading pair and interval
+maxKlines = {{ .DexConfig.MaxKlines }}
+# Whether to write the closed klines into the klines db under the data dir, so they survive a restart
+klinesPersisted = {{ .DexConfig.KlinesPersisted }}
+# Whether to keep the rolling 24h statistics of each trading pair, which serve the ticker query
+ticker = {{ .DexConfig.Ticker }}
 `
 
 type BNBBeaconChainContext struct {
@@ -313,9 +418,10 @@ type PublicationConfig struct {
 	AccountBalanceTopic   string `mapstructure:"accountBalanceTopic"`
 	AccountBalanceKafka   string `mapstructure:"accountBalanceKafka"`
 
-	PublishOrderBook bool   `mapstructure:"publishOrderBook"`
-	OrderBookTopic   string `mapstructure:"orderBookTopic"`
-	OrderBookKafka   string `mapstructure:"orderBookKafka"`
+	PublishOrderBook          bool   `mapstructure:"publishOrderBook"`
+	OrderBookTopic            string `mapstructure:"orderBookTopic"`
+	OrderBookKafka            string `mapstructure:"orderBookKafka"`
+	OrderBookSnapshotInterval int64  `mapstructure:"orderBookSnapshotInterval"`
 
 	PublishBlockFee bool   `mapstructure:"publishBlockFee"`
 	BlockFeeTopic   string `mapstructure:"blockFeeTopic"`
@@ -357,6 +463,10 @@ type PublicationConfig struct {
 	BreatheBlockTopic   string `mapstructure:"breatheBlockTopic"`
 	BreatheBlockKafka   string `mapstructure:"breatheBlockKafka"`
 
+	PublishKline bool   `mapstructure:"publishKline"`
+	KlineTopic   string `mapstructure:"klineTopic"`
+	KlineKafka   string `mapstructure:"klineKafka"`
+
 	PublicationChannelSize int `mapstructure:"publicationChannelSize"`
 
 	// DO NOT put this option in config file
@@ -374,12 +484,30 @@ type PublicationConfig struct {
 	// refer: https://github.com/natefinch/lumberjack/blob/7d6a1875575e09256dc552b4c0e450dcd02bd10e/lumberjack.go#L89-L94
 	LocalMaxAge int `mapstructure:"localMaxAge"`
 
+	// Comma separated names of the publisher backends in the publisher registry
+	Publishers              string `mapstructure:"publishers"`
+	WebSocketAddress        string `mapstructure:"webSocketAddress"`
+	WebSocketClientBuffer   int    `mapstructure:"webSocketClientBuffer"`
+	WebSocketAllowedOrigins string `mapstructure:"webSocketAllowedOrigins"`
+	WebSocketMaxClients     int    `mapstructure:"webSocketMaxClients"`
+
+	PublicationLogSegmentSize int `mapstructure:"publicationLogSegmentSize"`
+	PublicationLogMaxSegments int `mapstructure:"publicationLogMaxSegments"`
+
+	WatchListFile           string `mapstructure:"watchListFile"`
+	WatchListReloadInterval int    `mapstructure:"watchListReloadInterval"`
+
 	Auth            bool   `mapstructure:"auth"`
 	StopOnKafkaFail bool   `mapstructure:"stopOnKafkaFail"`
 	KafkaUserName   string `mapstructure:"kafkaUserName"`
 	KafkaPassword   string `mapstructure:"kafkaPassword"`
 
-	KafkaVersion string `mapstructure:"kafkaVersion"`
+	KafkaVersion         string `mapstructure:"kafkaVersion"`
+	KafkaExactlyOnce     bool   `mapstructure:"kafkaExactlyOnce"`
+	KafkaTransactionalId string `mapstructure:"kafkaTransactionalId"`
+
+	SchemaRegistryUrl          string `mapstructure:"schemaRegistryUrl"`
+	SchemaRegistryAutoRegister bool   `mapstructure:"schemaRegistryAutoRegister"`
 }
 
 func defaultPublicationConfig() *PublicationConfig {
@@ -392,9 +520,10 @@ func defaultPublicationConfig() *PublicationConfig {
 		AccountBalanceTopic:   "accounts",
 		AccountBalanceKafka:   "127.0.0.1:9092",
 
-		PublishOrderBook: false,
-		OrderBookTopic:   "orders",
-		OrderBookKafka:   "127.0.0.1:9092",
+		PublishOrderBook:          false,
+		OrderBookTopic:            "orders",
+		OrderBookKafka:            "127.0.0.1:9092",
+		OrderBookSnapshotInterval: 0,
 
 		PublishBlockFee: false,
 		BlockFeeTopic:   "accounts",
@@ -436,6 +565,10 @@ func defaultPublicationConfig() *PublicationConfig {
 		BreatheBlockTopic:   "breatheBlock",
 		BreatheBlockKafka:   "127.0.0.1:9092",
 
+		PublishKline: false,
+		KlineTopic:   "kline",
+		KlineKafka:   "127.0.0.1:9092",
+
 		PublicationChannelSize: 10000,
 		FromHeightInclusive:    1,
 		PublishKafka:           false,
@@ -444,13 +577,76 @@ func defaultPublicationConfig() *PublicationConfig {
 		LocalMaxSize: 1024,
 		LocalMaxAge:  7,
 
+		Publishers:              "",
+		WebSocketAddress:        "127.0.0.1:27148",
+		WebSocketClientBuffer:   100,
+		WebSocketAllowedOrigins: "",
+		WebSocketMaxClients:     100,
+
+		PublicationLogSegmentSize: 64,
+		PublicationLogMaxSegments: 100,
+
+		WatchListFile:           "",
+		WatchListReloadInterval: 10,
+
 		Auth:            false,
 		KafkaUserName:   "",
 		KafkaPassword:   "",
 		StopOnKafkaFail: false,
 
-		KafkaVersion: "2.1.0",
+		KafkaVersion:         "2.1.0",
+		KafkaExactlyOnce:     false,
+		KafkaTransactionalId: "",
+
+		SchemaRegistryUrl:          "",
+		SchemaRegistryAutoRegister: true,
+	}
+}
+
+// PublisherNames returns the names of the publisher backends to start, without duplicates
+func (pubCfg PublicationConfig) PublisherNames() []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if pubCfg.PublishKafka {
+		add(KafkaPublisher)
+	}
+	if pubCfg.PublishLocal {
+		add(LocalPublisher)
+	}
+	for _, name := range strings.Split(pubCfg.Publishers, ",") {
+		add(strings.TrimSpace(name))
+	}
+	return names
+}
+
+// PublishExactlyOnce returns whether the messages of every height are published in one kafka transaction
+func (pubCfg PublicationConfig) PublishExactlyOnce() bool {
+	if !pubCfg.KafkaExactlyOnce {
+		return false
+	}
+	for _, name := range pubCfg.PublisherNames() {
+		if name == KafkaPublisher {
+			return true
+		}
+	}
+	return false
+}
+
+// WebSocketOrigins returns the origins allowed to connect to the websocket publisher
+func (pubCfg PublicationConfig) WebSocketOrigins() []string {
+	origins := make([]string, 0)
+	for _, origin := range strings.Split(pubCfg.WebSocketAllowedOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
 	}
+	return origins
 }
 
 func (pubCfg PublicationConfig) ShouldPublishAny() bool {
@@ -466,7 +662,8 @@ func (pubCfg PublicationConfig) ShouldPublishAny() bool {
 		pubCfg.PublishCrossTransfer ||
 		pubCfg.PublishMirror ||
 		pubCfg.PublishSideProposal ||
-		pubCfg.PublishBreatheBlock
+		pubCfg.PublishBreatheBlock ||
+		pubCfg.PublishKline
 }
 
 type CrossChainConfig struct {
@@ -561,6 +758,16 @@ type UpgradeConfig struct {
 	FirstSunsetHeight                               int64 `mapstructure:"FirstSunsetHeight"`
 	SecondSunsetHeight                              int64 `mapstructure:"SecondSunsetHeight"`
 	FinalSunsetHeight                               int64 `mapstructure:"FinalSunsetHeight"`
+	TokenMetadataHeight                             int64 `mapstructure:"TokenMetadataHeight"`
+	TimeLockVestingHeight                           int64 `mapstructure:"TimeLockVestingHeight"`
+	TimeLockBeneficiaryHeight                       int64 `mapstructure:"TimeLockBeneficiaryHeight"`
+	SunsetRefundRetryHeight                         int64 `mapstructure:"SunsetRefundRetryHeight"`
+	MarketOrderHeight                               int64 `mapstructure:"MarketOrderHeight"`
+	ConditionalOrderHeight                          int64 `mapstructure:"ConditionalOrderHeight"`
+	FOKAndPostOnlyHeight                            int64 `mapstructure:"FOKAndPostOnlyHeight"`
+	BatchOrderHeight                                int64 `mapstructure:"BatchOrderHeight"`
+	CancelAllOrdersHeight                           int64 `mapstructure:"CancelAllOrdersHeight"`
+	AmendOrderHeight                                int64 `mapstructure:"AmendOrderHeight"`
 }
 
 func defaultUpgradeConfig() *UpgradeConfig {
@@ -595,30 +802,64 @@ func defaultUpgradeConfig() *UpgradeConfig {
 		BEP171Height:                      math.MaxInt64,
 		FixFailAckPackageHeight:           math.MaxInt64,
 		EnableAccountScriptsForCrossChainTransferHeight: math.MaxInt64,
-		BEP255Height:       math.MaxInt64,
-		FirstSunsetHeight:  math.MaxInt64,
-		SecondSunsetHeight: math.MaxInt64,
-		FinalSunsetHeight:  math.MaxInt64,
+		BEP255Height:              math.MaxInt64,
+		FirstSunsetHeight:         math.MaxInt64,
+		SecondSunsetHeight:        math.MaxInt64,
+		FinalSunsetHeight:         math.MaxInt64,
+		TokenMetadataHeight:       math.MaxInt64,
+		TimeLockVestingHeight:     math.MaxInt64,
+		TimeLockBeneficiaryHeight: math.MaxInt64,
+		SunsetRefundRetryHeight:   math.MaxInt64,
+		MarketOrderHeight:         math.MaxInt64,
+		ConditionalOrderHeight:    math.MaxInt64,
+		FOKAndPostOnlyHeight:      math.MaxInt64,
+		BatchOrderHeight:          math.MaxInt64,
+		CancelAllOrdersHeight:     math.MaxInt64,
+		AmendOrderHeight:          math.MaxInt64,
 	}
 }
 
 type QueryConfig struct {
 	ABCIQueryBlackList []string `mapstructure:"ABCIQueryBlackList"`
+	TokenHolders       bool     `mapstructure:"tokenHolders"`
+	MaxTopHolders      int      `mapstructure:"maxTopHolders"`
 }
 
 func defaultQueryConfig() *QueryConfig {
 	return &QueryConfig{
 		ABCIQueryBlackList: nil,
+		TokenHolders:       false,
+		MaxTopHolders:      100,
 	}
 }
 
 type DexConfig struct {
-	BUSDSymbol string `mapstructure:"BUSDSymbol"`
+	BUSDSymbol                string `mapstructure:"BUSDSymbol"`
+	OrderBookWAL              bool   `mapstructure:"orderBookWAL"`
+	OrderHistory              bool   `mapstructure:"orderHistory"`
+	MaxClosedOrders           int    `mapstructure:"maxClosedOrders"`
+	MaxClosedOrdersPerAccount int    `mapstructure:"maxClosedOrdersPerAccount"`
+	TradeHistoryBlocks        int64  `mapstructure:"tradeHistoryBlocks"`
+	MaxTradesPerPair          int    `mapstructure:"maxTradesPerPair"`
+	Klines                    bool   `mapstructure:"klines"`
+	MaxKlines                 int    `mapstructure:"maxKlines"`
+	KlinesPersisted           bool   `mapstructure:"klinesPersisted"`
+	Ticker                    bool   `mapstructure:"ticker"`
 }
 
 func defaultGovConfig() *DexConfig {
 	return &DexConfig{
-		BUSDSymbol: "",
+		BUSDSymbol:                "",
+		OrderBookWAL:              false,
+		OrderHistory:              false,
+		MaxClosedOrders:           1000000,
+		MaxClosedOrdersPerAccount: 50,
+		TradeHistoryBlocks:        10000,
+		MaxTradesPerPair:          1000,
+		Klines:                    false,
+		MaxKlines:                 1000,
+		KlinesPersisted:           false,
+		Ticker:                    false,
 	}
 }
 
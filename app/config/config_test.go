@@ -13,7 +13,15 @@ func TestKafkaVersion(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	if version != sarama.MaxVersion {
+	supported := false
+	for _, supportedVer := range sarama.SupportedVersions {
+		if version == supportedVer {
+			supported = true
+			break
+		}
+	}
+	// the transactional producer of the exactly-once publishing needs 0.11.0.0 or above
+	if !supported || !version.IsAtLeast(sarama.V0_11_0_0) {
 		t.Error(fmt.Errorf("default publisher setting is not compatible with current kafka setting"))
 	}
 }
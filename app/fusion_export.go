@@ -0,0 +1,185 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/sidechain"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+	sTypes "github.com/cosmos/cosmos-sdk/x/stake/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/types"
+	tokenRecover "github.com/bnb-chain/node/plugins/recover"
+	tkstore "github.com/bnb-chain/node/plugins/tokens/store"
+	"github.com/bnb-chain/node/plugins/tokens/swap"
+	"github.com/bnb-chain/node/plugins/tokens/timelock"
+	"github.com/bnb-chain/node/wire"
+)
+
+// ExportFusionSnapshot exports the balances, tokens, time locks, vestings, open swaps and validators from the multi store loaded at
+// the height for the token recovery after BC fusion. The coins held by the time lock and atomic swap accounts are
+// attributed to the users they would be returned to, so every balance leaf belongs to a user.
+func ExportFusionSnapshot(cdc *wire.Codec, ms sdk.MultiStore, height int64, commitHash []byte) (*tokenRecover.FusionSnapshot, error) {
+	ctx := sdk.NewContext(ms, abci.Header{Height: height}, sdk.RunTxModeCheck, log.NewNopLogger())
+	accountKeeper := auth.NewAccountKeeper(cdc, common.AccountStoreKey, types.ProtoAppAccount)
+	coinKeeper := bank.NewBaseKeeper(accountKeeper)
+	tokenMapper := tkstore.NewMapper(cdc, common.TokenStoreKey, common.TokenMetadataStoreKey)
+	timeLockKeeper := timelock.NewKeeper(cdc, common.TimeLockStoreKey, coinKeeper, accountKeeper, timelock.DefaultCodespace)
+	swapKeeper := swap.NewKeeper(cdc, common.AtomicSwapStoreKey, coinKeeper, nil, swap.DefaultCodespace)
+
+	snapshot := &tokenRecover.FusionSnapshot{
+		Height:     height,
+		CommitHash: commitHash,
+	}
+	balances := newFusionBalances()
+
+	var err error
+	accountKeeper.IterateAccounts(ctx, func(acc sdk.Account) bool {
+		appAcc, ok := acc.(types.NamedAccount)
+		if !ok {
+			err = fmt.Errorf("unexpected account type %T of %s", acc, acc.GetAddress())
+			return true
+		}
+		snapshot.Accounts = append(snapshot.Accounts, tokenRecover.AccountBalance{
+			Address: acc.GetAddress(),
+			Free:    acc.GetCoins(),
+			Frozen:  appAcc.GetFrozenCoins(),
+			Locked:  appAcc.GetLockedCoins(),
+			Flags:   appAcc.GetFlags(),
+		})
+		if acc.GetAddress().Equals(timelock.TimeLockCoinsAccAddr) || acc.GetAddress().Equals(swap.AtomicSwapCoinsAccAddr) {
+			return false
+		}
+		balances.add(acc.GetAddress(), acc.GetCoins())
+		balances.add(acc.GetAddress(), appAcc.GetFrozenCoins())
+		balances.add(acc.GetAddress(), appAcc.GetLockedCoins())
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, token := range tokenMapper.GetTokenList(ctx, true, false) {
+		snapshot.Tokens = append(snapshot.Tokens, token.(*types.Token))
+	}
+	for _, token := range tokenMapper.GetTokenList(ctx, true, true) {
+		snapshot.MiniTokens = append(snapshot.MiniTokens, token.(*types.MiniToken))
+	}
+
+	timeLockIterator := timeLockKeeper.GetTimeLockRecordIterator(ctx)
+	defer timeLockIterator.Close()
+	for ; timeLockIterator.Valid(); timeLockIterator.Next() {
+		addr, _, err := timelock.ParseKeyRecord(timeLockIterator.Key())
+		if err != nil {
+			return nil, err
+		}
+		var record timelock.TimeLockRecord
+		cdc.MustUnmarshalBinaryLengthPrefixed(timeLockIterator.Value(), &record)
+		snapshot.TimeLocks = append(snapshot.TimeLocks, tokenRecover.TimeLockEntry{Owner: addr, Record: record})
+		if record.HasBeneficiary() {
+			balances.add(record.Beneficiary, record.Amount)
+		} else {
+			balances.add(addr, record.Amount)
+		}
+	}
+
+	vestingIterator := timeLockKeeper.GetVestingRecordIterator(ctx)
+	defer vestingIterator.Close()
+	for ; vestingIterator.Valid(); vestingIterator.Next() {
+		var record timelock.VestingRecord
+		cdc.MustUnmarshalBinaryLengthPrefixed(vestingIterator.Value(), &record)
+		snapshot.Vestings = append(snapshot.Vestings, record)
+		balances.add(record.Beneficiary, record.Amount.Minus(record.Released))
+	}
+
+	swapIterator := swapKeeper.GetSwapIterator(ctx)
+	defer swapIterator.Close()
+	for ; swapIterator.Valid(); swapIterator.Next() {
+		var atomicSwap swap.AtomicSwap
+		cdc.MustUnmarshalBinaryBare(swapIterator.Value(), &atomicSwap)
+		if atomicSwap.Status != swap.Open {
+			continue
+		}
+		swapID := swap.SwapBytes(swapIterator.Key()[len(swap.HashKey):])
+		snapshot.Swaps = append(snapshot.Swaps, tokenRecover.SwapEntry{SwapID: swapID, Swap: atomicSwap})
+		// the coins are refunded to the sender and the depositor if the swap expires
+		balances.add(atomicSwap.From, atomicSwap.OutAmount)
+		balances.add(atomicSwap.To, atomicSwap.InAmount)
+	}
+
+	snapshot.Validators = exportFusionValidators(cdc, ctx)
+	snapshot.Leaves = balances.leaves()
+	return snapshot, nil
+}
+
+// exportFusionValidators returns the validators of BC followed by the ones of every side chain, the stake keeper is
+// not built since only its store is read
+func exportFusionValidators(cdc *wire.Codec, ctx sdk.Context) []tokenRecover.ValidatorEntry {
+	ctxs := []sdk.Context{ctx}
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(common.SideChainStoreKey), sidechain.SideChainStorePrefixByIdKey)
+	for ; iterator.Valid(); iterator.Next() {
+		ctxs = append(ctxs, ctx.WithSideChainKeyPrefix(iterator.Value()))
+	}
+	iterator.Close()
+
+	var validators []tokenRecover.ValidatorEntry
+	for _, ctx := range ctxs {
+		validatorIterator := sdk.KVStorePrefixIterator(ctx.KVStore(common.StakeStoreKey), stake.ValidatorsKey)
+		for ; validatorIterator.Valid(); validatorIterator.Next() {
+			validator := sTypes.MustUnmarshalValidator(cdc, validatorIterator.Value())
+			validators = append(validators, tokenRecover.ValidatorEntry{
+				OperatorAddr: validator.OperatorAddr,
+				Moniker:      validator.Description.Moniker,
+				SideChainId:  validator.SideChainId,
+				Status:       validator.Status,
+				Jailed:       validator.Jailed,
+			})
+		}
+		validatorIterator.Close()
+	}
+	return validators
+}
+
+// fusionBalances sums up the coins of every address
+type fusionBalances map[string]map[string]int64
+
+func newFusionBalances() fusionBalances {
+	return make(fusionBalances)
+}
+
+func (b fusionBalances) add(addr sdk.AccAddress, coins sdk.Coins) {
+	if coins.IsZero() {
+		return
+	}
+	key := string(addr)
+	if b[key] == nil {
+		b[key] = make(map[string]int64)
+	}
+	for _, coin := range coins {
+		b[key][coin.Denom] += coin.Amount
+	}
+}
+
+func (b fusionBalances) leaves() tokenRecover.BalanceLeaves {
+	var leaves tokenRecover.BalanceLeaves
+	for addr, coins := range b {
+		for symbol, amount := range coins {
+			if amount == 0 {
+				continue
+			}
+			leaves = append(leaves, tokenRecover.BalanceLeaf{
+				Address: sdk.AccAddress(addr),
+				Symbol:  symbol,
+				Amount:  amount,
+			})
+		}
+	}
+	sort.Sort(leaves)
+	return leaves
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	sdkstore "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/sidechain"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+	sTypes "github.com/cosmos/cosmos-sdk/x/stake/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/types"
+	tokenRecover "github.com/bnb-chain/node/plugins/recover"
+	tkstore "github.com/bnb-chain/node/plugins/tokens/store"
+	"github.com/bnb-chain/node/plugins/tokens/swap"
+	"github.com/bnb-chain/node/plugins/tokens/timelock"
+)
+
+func TestExportFusionSnapshot(t *testing.T) {
+	cdc := MakeCodec()
+	ms := sdkstore.NewCommitMultiStore(db.NewMemDB())
+	for _, key := range []sdk.StoreKey{common.AccountStoreKey, common.TokenStoreKey, common.TokenMetadataStoreKey, common.TimeLockStoreKey, common.AtomicSwapStoreKey,
+		common.StakeStoreKey, common.SideChainStoreKey} {
+		ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+	}
+	require.NoError(t, ms.LoadLatestVersion())
+	cms := ms.CacheMultiStore()
+
+	accountCache := auth.NewAccountCache(auth.NewAccountStoreCache(cdc, cms.GetKVStore(common.AccountStoreKey), 10))
+	ctx := sdk.NewContext(cms, abci.Header{Time: time.Now()}, sdk.RunTxModeDeliver, log.NewNopLogger()).
+		WithAccountCache(accountCache)
+	accountKeeper := auth.NewAccountKeeper(cdc, common.AccountStoreKey, types.ProtoAppAccount)
+	coinKeeper := bank.NewBaseKeeper(accountKeeper)
+	tokenMapper := tkstore.NewMapper(cdc, common.TokenStoreKey, common.TokenMetadataStoreKey)
+	timeLockKeeper := timelock.NewKeeper(cdc, common.TimeLockStoreKey, coinKeeper, accountKeeper, timelock.DefaultCodespace)
+	swapKeeper := swap.NewKeeper(cdc, common.AtomicSwapStoreKey, coinKeeper, nil, swap.DefaultCodespace)
+
+	_, acc1 := testutils.NewAccount(ctx, accountKeeper, 1000e8)
+	acc1.(types.NamedAccount).SetFrozenCoins(sdk.Coins{sdk.NewCoin("BNB", 10e8)})
+	accountKeeper.SetAccount(ctx, acc1)
+	_, addr2 := testutils.PrivAndAddr()
+
+	token, err := types.NewToken("New BNB", "NNB-000", 10000e8, acc1.GetAddress(), false)
+	require.NoError(t, err)
+	require.NoError(t, tokenMapper.NewToken(ctx, token))
+
+	_, sdkErr := timeLockKeeper.TimeLockFor(ctx, acc1.GetAddress(), addr2, "Test", sdk.Coins{sdk.NewCoin("BNB", 100e8)}, time.Now().Add(time.Hour))
+	require.Nil(t, sdkErr)
+
+	_, sdkErr = coinKeeper.SendCoins(ctx, acc1.GetAddress(), swap.AtomicSwapCoinsAccAddr, sdk.Coins{sdk.NewCoin("BNB", 50e8)})
+	require.Nil(t, sdkErr)
+	sdkErr = swapKeeper.CreateSwap(ctx, swap.SwapBytes{1}, &swap.AtomicSwap{
+		From:      acc1.GetAddress(),
+		To:        addr2,
+		OutAmount: sdk.Coins{sdk.NewCoin("BNB", 50e8)},
+		Status:    swap.Open,
+	})
+	require.Nil(t, sdkErr)
+	accountCache.Write()
+
+	// a validator of BC and one of BSC
+	bcValidator := stake.NewValidator(sdk.ValAddress(acc1.GetAddress()), ed25519.GenPrivKey().PubKey(), stake.Description{Moniker: "bc"})
+	cms.GetKVStore(common.StakeStoreKey).Set(stake.GetValidatorKey(bcValidator.OperatorAddr), sTypes.MustMarshalValidator(cdc, bcValidator))
+	bscPrefix := []byte{0x99}
+	cms.GetKVStore(common.SideChainStoreKey).Set(sidechain.GetSideChainStorePrefixKey("bsc"), bscPrefix)
+	bscValidator := stake.NewSideChainValidator(addr2, sdk.ValAddress(addr2), stake.Description{Moniker: "bsc"}, "bsc",
+		[]byte{1}, []byte{2}, nil)
+	ctx.WithSideChainKeyPrefix(bscPrefix).KVStore(common.StakeStoreKey).
+		Set(stake.GetValidatorKey(bscValidator.OperatorAddr), sTypes.MustMarshalValidator(cdc, bscValidator))
+
+	snapshot, err := ExportFusionSnapshot(cdc, cms, 10, []byte{1})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), snapshot.Height)
+	require.Len(t, snapshot.Accounts, 3)
+	require.Len(t, snapshot.Tokens, 1)
+	require.Equal(t, "NNB-000", snapshot.Tokens[0].Symbol)
+	require.Len(t, snapshot.TimeLocks, 1)
+	require.Equal(t, addr2, snapshot.TimeLocks[0].Record.Beneficiary)
+	require.Len(t, snapshot.Swaps, 1)
+	require.Equal(t, swap.SwapBytes{1}, snapshot.Swaps[0].SwapID)
+	require.Equal(t, []tokenRecover.ValidatorEntry{
+		{OperatorAddr: bcValidator.OperatorAddr, Moniker: "bc", Status: sdk.Unbonded},
+		{OperatorAddr: bscValidator.OperatorAddr, Moniker: "bsc", SideChainId: "bsc", Status: sdk.Unbonded},
+	}, snapshot.Validators)
+
+	// the coins of the time lock and the open swap are attributed to the users
+	expected := tokenRecover.BalanceLeaves{
+		{Address: acc1.GetAddress(), Symbol: "BNB", Amount: 1000e8 - 100e8 - 50e8 + 10e8 + 50e8},
+		{Address: addr2, Symbol: "BNB", Amount: 100e8},
+	}
+	if string(addr2) < string(acc1.GetAddress()) {
+		expected[0], expected[1] = expected[1], expected[0]
+	}
+	require.Equal(t, expected, snapshot.Leaves)
+}
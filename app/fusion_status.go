@@ -0,0 +1,64 @@
+package app
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/tokens"
+)
+
+// FusionHandler handles the fusion queries:
+//   - fusion/status: the progress of the refunds after the sunset upgrades
+//   - fusion/retries: the failed refunds waiting for a retry
+//   - fusion/dead-letters: the refunds given up after MaxRefundAttempts attempts
+func (app *BNBBeaconChain) FusionHandler(chainApp types.ChainApp, req abci.RequestQuery, path []string) *abci.ResponseQuery {
+	if len(path) != 2 {
+		res := sdk.ErrUnknownRequest("invalid path").QueryResult()
+		return &res
+	}
+
+	ctx := chainApp.GetContextForCheckState()
+	var result interface{}
+	switch path[1] {
+	case "status":
+		result = app.FusionMonitor.Status(ctx.BlockHeight(), func() tokens.FusionStatus {
+			return tokens.GetFusionStatus(ctx, app.timeLockKeeper, app.swapKeeper, app.refundQueue,
+				app.FusionMonitor, app.countRemainingDelegations(ctx))
+		})
+	case "retries":
+		result = app.refundQueue.GetRetries(ctx)
+	case "dead-letters":
+		result = app.refundQueue.GetDeadLetters(ctx)
+	default:
+		res := sdk.ErrUnknownRequest("invalid path").QueryResult()
+		return &res
+	}
+	bz, err := chainApp.GetCodec().MarshalBinaryLengthPrefixed(result)
+	if err != nil {
+		res := sdk.ErrInternal(err.Error()).QueryResult()
+		return &res
+	}
+	return &abci.ResponseQuery{
+		Code:  uint32(sdk.ABCICodeOK),
+		Value: bz,
+	}
+}
+
+// countRemainingDelegations counts the delegations of all the side chains, which are refunded by the stake module
+// after SecondSunset
+func (app *BNBBeaconChain) countRemainingDelegations(ctx sdk.Context) int64 {
+	if !sdk.IsUpgrade(sdk.BEP128) {
+		return 0
+	}
+	count := int64(0)
+	_, storePrefixes := app.scKeeper.GetAllSideChainPrefixes(ctx)
+	for _, storePrefix := range storePrefixes {
+		iterator := app.stakeKeeper.IteratorAllDelegations(ctx.WithSideChainKeyPrefix(storePrefix))
+		for ; iterator.Valid(); iterator.Next() {
+			count++
+		}
+		iterator.Close()
+	}
+	return count
+}
@@ -150,6 +150,16 @@ func (app *BNBBeaconChain) processErrAbciResponseForPub(txBytes []byte) {
 				// The error on deliver should be rare and only impact witness publisher's performance
 				// OrderInfo must has been in keeper.orderInfosForPub
 				app.DexKeeper.UpdateOrderChangeSync(order.OrderChange{Id: msg.RefId, Tpe: order.FailedBlocking, MsgForFailedTx: msg}, msg.Symbol)
+			case order.BatchNewOrderMsg:
+				app.Logger.Info("failed to process BatchNewOrderMsg", "orders", len(msg.Orders))
+				for _, o := range msg.Orders {
+					app.DexKeeper.UpdateOrderChangeSync(order.OrderChange{Id: o.Id, Tpe: order.FailedBlocking, MsgForFailedTx: o}, o.Symbol)
+				}
+			case order.BatchCancelOrderMsg:
+				app.Logger.Info("failed to process BatchCancelOrderMsg", "cancels", len(msg.Cancels))
+				for _, c := range msg.Cancels {
+					app.DexKeeper.UpdateOrderChangeSync(order.OrderChange{Id: c.RefId, Tpe: order.FailedBlocking, MsgForFailedTx: c}, c.Symbol)
+				}
 			default:
 				// deliberately do nothing for message other than NewOrderMsg
 				// in future, we may publish fail status of send msg
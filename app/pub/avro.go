@@ -0,0 +1,125 @@
+package pub
+
+import (
+	"fmt"
+
+	"github.com/linkedin/goavro"
+)
+
+// latestSchemas are the schemas of the msg types in the versions of latestSchemaVersions
+var latestSchemas = map[msgType]string{
+	accountsTpe:        accountSchema,
+	booksTpe:           booksSchema,
+	executionResultTpe: executionResultSchema,
+	blockFeeTpe:        blockfeeSchema,
+	transferTpe:        transfersSchema,
+	blockTpe:           blockDatasSchema,
+	stakingTpe:         stakingSchema,
+	distributionTpe:    distributionSchema,
+	slashingTpe:        slashingSchema,
+	crossTransferTpe:   crossTransferSchema,
+	mirrorTpe:          mirrorSchema,
+	sideProposalType:   sideProposalsSchema,
+	breatheBlockTpe:    breatheBlockSchema,
+	klineTpe:           klinesSchema,
+}
+
+// avroCodecs serializes the messages in avro with the schemas in schemas.go
+type avroCodecs struct {
+	booksCodec            *goavro.Codec
+	accountCodec          *goavro.Codec
+	executionResultsCodec *goavro.Codec
+	blockFeeCodec         *goavro.Codec
+	transfersCodec        *goavro.Codec
+	blockCodec            *goavro.Codec
+	stakingCodec          *goavro.Codec
+	distributionCodec     *goavro.Codec
+	slashingCodec         *goavro.Codec
+	crossTransferCodec    *goavro.Codec
+	mirrorCodec           *goavro.Codec
+	sideProposalCodec     *goavro.Codec
+	breatheBlockCodec     *goavro.Codec
+	klinesCodec           *goavro.Codec
+}
+
+func (codecs *avroCodecs) codec(tpe msgType) (*goavro.Codec, error) {
+	var codec *goavro.Codec
+	switch tpe {
+	case accountsTpe:
+		codec = codecs.accountCodec
+	case booksTpe:
+		codec = codecs.booksCodec
+	case executionResultTpe:
+		codec = codecs.executionResultsCodec
+	case blockFeeTpe:
+		codec = codecs.blockFeeCodec
+	case transferTpe:
+		codec = codecs.transfersCodec
+	case blockTpe:
+		codec = codecs.blockCodec
+	case stakingTpe:
+		codec = codecs.stakingCodec
+	case distributionTpe:
+		codec = codecs.distributionCodec
+	case slashingTpe:
+		codec = codecs.slashingCodec
+	case crossTransferTpe:
+		codec = codecs.crossTransferCodec
+	case mirrorTpe:
+		codec = codecs.mirrorCodec
+	case sideProposalType:
+		codec = codecs.sideProposalCodec
+	case breatheBlockTpe:
+		codec = codecs.breatheBlockCodec
+	case klineTpe:
+		codec = codecs.klinesCodec
+	default:
+		return nil, fmt.Errorf("doesn't support marshal kafka msg tpe: %s", tpe.String())
+	}
+	return codec, nil
+}
+
+func (codecs *avroCodecs) marshal(msg AvroOrJsonMsg, tpe msgType) ([]byte, error) {
+	codec, err := codecs.codec(tpe)
+	if err != nil {
+		return nil, err
+	}
+	bb, err := codec.BinaryFromNative(nil, msg.ToNativeMap())
+	if err != nil {
+		Logger.Error("failed to serialize message", "msg", msg, "err", err)
+	}
+	return bb, err
+}
+
+func (codecs *avroCodecs) initAvroCodecs() (err error) {
+	if codecs.executionResultsCodec, err = goavro.NewCodec(executionResultSchema); err != nil {
+		return err
+	} else if codecs.booksCodec, err = goavro.NewCodec(booksSchema); err != nil {
+		return err
+	} else if codecs.accountCodec, err = goavro.NewCodec(accountSchema); err != nil {
+		return err
+	} else if codecs.blockFeeCodec, err = goavro.NewCodec(blockfeeSchema); err != nil {
+		return err
+	} else if codecs.transfersCodec, err = goavro.NewCodec(transfersSchema); err != nil {
+		return err
+	} else if codecs.blockCodec, err = goavro.NewCodec(blockDatasSchema); err != nil {
+		return err
+	} else if codecs.stakingCodec, err = goavro.NewCodec(stakingSchema); err != nil {
+		return err
+	} else if codecs.distributionCodec, err = goavro.NewCodec(distributionSchema); err != nil {
+		return err
+	} else if codecs.slashingCodec, err = goavro.NewCodec(slashingSchema); err != nil {
+		return err
+	} else if codecs.crossTransferCodec, err = goavro.NewCodec(crossTransferSchema); err != nil {
+		return err
+	} else if codecs.mirrorCodec, err = goavro.NewCodec(mirrorSchema); err != nil {
+		return err
+	} else if codecs.sideProposalCodec, err = goavro.NewCodec(sideProposalsSchema); err != nil {
+		return err
+	} else if codecs.breatheBlockCodec, err = goavro.NewCodec(breatheBlockSchema); err != nil {
+		return err
+	} else if codecs.klinesCodec, err = goavro.NewCodec(klinesSchema); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package pub
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+// The order book deltas of each symbol carry a sequence number, which is increased by one for every
+// OrderBookDelta of the symbol published, and a full snapshot of the whole book is published on the first
+// block after the node starts, on every breathe block and every publishOrderBookSnapshotInterval blocks.
+//
+// BookSequencer generates the sequences on the publisher side, and BookSyncer rebuilds the books from the
+// published deltas on the consumer side, detecting the gaps between the sequences.
+
+const BookSequencesDB = "booksequences"
+
+var bookSequencesKey = []byte("sequences")
+
+// BookSequencer is not thread-safe, it's only used by the publication goroutine. The sequences are persisted
+// into the db, so they keep increasing after the node restarts.
+type BookSequencer struct {
+	db        dbm.DB
+	sequences map[string]int64 // symbol -> the sequence of the last published delta
+}
+
+func NewBookSequencer(db dbm.DB) (*BookSequencer, error) {
+	sequencer := &BookSequencer{db: db, sequences: make(map[string]int64)}
+	if bz := db.Get(bookSequencesKey); bz != nil {
+		if err := json.Unmarshal(bz, &sequencer.sequences); err != nil {
+			return nil, err
+		}
+	}
+	return sequencer, nil
+}
+
+// Next returns the sequence of the next delta of the symbol
+func (s *BookSequencer) Next(symbol string) int64 {
+	s.sequences[symbol]++
+	return s.sequences[symbol]
+}
+
+// Save persists the sequences of the deltas published
+func (s *BookSequencer) Save() {
+	bz, err := json.Marshal(s.sequences)
+	if err != nil {
+		Logger.Error("failed to marshal order book sequences", "err", err)
+		return
+	}
+	s.db.SetSync(bookSequencesKey, bz)
+}
+
+// ErrBookGap is returned by BookSyncer when some deltas of the symbol are missing, the book of the symbol
+// is out of sync until the next snapshot is applied
+type ErrBookGap struct {
+	Symbol   string
+	Expected int64
+	Got      int64
+}
+
+func (e ErrBookGap) Error() string {
+	return fmt.Sprintf("order book of %s is out of sync, expected sequence %d, got %d", e.Symbol, e.Expected, e.Got)
+}
+
+// LocalBook is the order book of a symbol rebuilt from the deltas, price -> quantity
+type LocalBook struct {
+	Sequence int64
+	Buys     map[int64]int64
+	Sells    map[int64]int64
+}
+
+// Levels returns the buy levels from the highest price and the sell levels from the lowest price
+func (b *LocalBook) Levels() (buys []PriceLevel, sells []PriceLevel) {
+	toLevels := func(side map[int64]int64, desc bool) []PriceLevel {
+		levels := make([]PriceLevel, 0, len(side))
+		for price, qty := range side {
+			levels = append(levels, PriceLevel{price, qty})
+		}
+		sort.Slice(levels, func(i, j int) bool {
+			if desc {
+				return levels[i].Price > levels[j].Price
+			}
+			return levels[i].Price < levels[j].Price
+		})
+		return levels
+	}
+	return toLevels(b.Buys, true), toLevels(b.Sells, false)
+}
+
+// BookSyncer keeps the order books of the consumer in sync with the published deltas. It's not thread-safe.
+type BookSyncer struct {
+	books map[string]*LocalBook // only the books in sync
+}
+
+func NewBookSyncer() *BookSyncer {
+	return &BookSyncer{books: make(map[string]*LocalBook)}
+}
+
+// ApplyBooks applies all the deltas of the message, and returns the gaps found
+func (s *BookSyncer) ApplyBooks(books *Books) []ErrBookGap {
+	var gaps []ErrBookGap
+	for _, delta := range books.Books {
+		if err := s.Apply(delta); err != nil {
+			gaps = append(gaps, err.(ErrBookGap))
+		}
+	}
+	return gaps
+}
+
+// Apply applies a delta or a snapshot to the book of its symbol. A delta that doesn't follow the last
+// applied sequence of the symbol leaves the book out of sync and returns ErrBookGap, the later deltas are
+// ignored till a snapshot comes. A book that is never synced ignores the deltas without any error.
+func (s *BookSyncer) Apply(delta OrderBookDelta) error {
+	if delta.Snapshot {
+		book := &LocalBook{
+			Sequence: delta.Sequence,
+			Buys:     make(map[int64]int64, len(delta.Buys)),
+			Sells:    make(map[int64]int64, len(delta.Sells)),
+		}
+		applyLevels(book.Buys, delta.Buys)
+		applyLevels(book.Sells, delta.Sells)
+		s.books[delta.Symbol] = book
+		return nil
+	}
+
+	book, ok := s.books[delta.Symbol]
+	if !ok {
+		return nil
+	}
+	if delta.Sequence != book.Sequence+1 {
+		delete(s.books, delta.Symbol)
+		return ErrBookGap{delta.Symbol, book.Sequence + 1, delta.Sequence}
+	}
+	book.Sequence = delta.Sequence
+	applyLevels(book.Buys, delta.Buys)
+	applyLevels(book.Sells, delta.Sells)
+	return nil
+}
+
+func applyLevels(side map[int64]int64, levels []PriceLevel) {
+	for _, l := range levels {
+		if l.LastQty == 0 {
+			delete(side, l.Price)
+		} else {
+			side[l.Price] = l.LastQty
+		}
+	}
+}
+
+// Book returns the book of the symbol, or false if it's not in sync
+func (s *BookSyncer) Book(symbol string) (*LocalBook, bool) {
+	book, ok := s.books[symbol]
+	return book, ok
+}
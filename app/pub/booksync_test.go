@@ -0,0 +1,61 @@
+package pub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+func TestBookSyncer_Apply(t *testing.T) {
+	sequencer, err := NewBookSequencer(dbm.NewMemDB())
+	require.NoError(t, err)
+	syncer := NewBookSyncer()
+	symbol := "XYZ-000_BNB"
+
+	// deltas before the first snapshot are ignored
+	require.NoError(t, syncer.Apply(OrderBookDelta{symbol, []PriceLevel{{100, 1}}, nil, sequencer.Next(symbol), false}))
+	_, ok := syncer.Book(symbol)
+	require.False(t, ok)
+
+	snapshot := OrderBookDelta{symbol, []PriceLevel{{100, 1}, {99, 2}}, []PriceLevel{{101, 3}}, sequencer.Next(symbol), true}
+	require.NoError(t, syncer.Apply(snapshot))
+	require.NoError(t, syncer.Apply(OrderBookDelta{symbol, []PriceLevel{{100, 0}, {98, 4}}, []PriceLevel{{102, 5}}, sequencer.Next(symbol), false}))
+	book, ok := syncer.Book(symbol)
+	require.True(t, ok)
+	require.Equal(t, int64(3), book.Sequence)
+	buys, sells := book.Levels()
+	require.Equal(t, []PriceLevel{{99, 2}, {98, 4}}, buys)
+	require.Equal(t, []PriceLevel{{101, 3}, {102, 5}}, sells)
+
+	// a delta is lost
+	sequencer.Next(symbol)
+	gaps := syncer.ApplyBooks(&Books{Books: []OrderBookDelta{{symbol, nil, []PriceLevel{{101, 0}}, sequencer.Next(symbol), false}}})
+	require.Equal(t, []ErrBookGap{{symbol, 4, 5}}, gaps)
+	_, ok = syncer.Book(symbol)
+	require.False(t, ok)
+	require.NoError(t, syncer.Apply(OrderBookDelta{symbol, nil, nil, sequencer.Next(symbol), false}))
+
+	// synced again by the next snapshot
+	require.NoError(t, syncer.Apply(OrderBookDelta{symbol, []PriceLevel{{99, 2}}, nil, sequencer.Next(symbol), true}))
+	book, ok = syncer.Book(symbol)
+	require.True(t, ok)
+	require.Equal(t, int64(7), book.Sequence)
+}
+
+func TestBookSequencer_Save(t *testing.T) {
+	db := dbm.NewMemDB()
+	sequencer, err := NewBookSequencer(db)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), sequencer.Next("XYZ-000_BNB"))
+	require.Equal(t, int64(2), sequencer.Next("XYZ-000_BNB"))
+	require.Equal(t, int64(1), sequencer.Next("ABC-000_BNB"))
+	sequencer.Save()
+
+	// the sequences keep increasing after the node restarts
+	sequencer, err = NewBookSequencer(db)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), sequencer.Next("XYZ-000_BNB"))
+	require.Equal(t, int64(2), sequencer.Next("ABC-000_BNB"))
+}
@@ -19,16 +19,22 @@ import (
 
 	"github.com/bnb-chain/node/common/types"
 	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/dex/store"
 	"github.com/bnb-chain/node/plugins/tokens/burn"
 	"github.com/bnb-chain/node/plugins/tokens/freeze"
 	"github.com/bnb-chain/node/plugins/tokens/issue"
+	"github.com/bnb-chain/node/plugins/tokens/metadata"
 	"github.com/bnb-chain/node/plugins/tokens/seturi"
 )
 
 func GetTradeAndOrdersRelatedAccounts(tradesToPublish []*Trade, orderChanges orderPkg.OrderChanges, orderInfosForPublish orderPkg.OrderInfoForPublish) []string {
 	res := make([]string, 0, len(tradesToPublish)*2+len(orderChanges))
+	list := getWatchList()
 
 	for _, t := range tradesToPublish {
+		if !list.watchSymbol(t.Symbol) {
+			continue
+		}
 
 		if bo, ok := orderInfosForPublish[t.Bid]; ok {
 			res = append(res, string(bo.Sender.Bytes()))
@@ -44,7 +50,9 @@ func GetTradeAndOrdersRelatedAccounts(tradesToPublish []*Trade, orderChanges ord
 
 	for _, orderChange := range orderChanges {
 		if orderInfo := orderInfosForPublish[orderChange.Id]; orderInfo != nil {
-			res = append(res, string(orderInfo.Sender.Bytes()))
+			if list.watchSymbol(orderInfo.Symbol) {
+				res = append(res, string(orderInfo.Sender.Bytes()))
+			}
 		} else {
 			Logger.Error("failed to locate order change in OrderChangesMap", "orderChange", orderChange.String())
 		}
@@ -53,6 +61,38 @@ func GetTradeAndOrdersRelatedAccounts(tradesToPublish []*Trade, orderChanges ord
 	return res
 }
 
+// GetKlinesPublished collects the klines updated or closed in the last block
+func GetKlinesPublished(klines *orderPkg.KlineAggregator) *Klines {
+	if klines == nil {
+		return nil
+	}
+	updated := make([]store.Kline, 0)
+	list := getWatchList()
+	for _, k := range klines.Updated() {
+		if list.watchSymbol(k.Symbol) {
+			updated = append(updated, k)
+		}
+	}
+	res := &Klines{NumOfMsgs: len(updated), Klines: make([]*Kline, len(updated))}
+	for i, k := range updated {
+		res.Klines[i] = &Kline{
+			Symbol:         k.Symbol,
+			Interval:       k.Interval,
+			OpenTime:       k.OpenTime,
+			CloseTime:      k.CloseTime,
+			Open:           k.Open.ToInt64(),
+			High:           k.High.ToInt64(),
+			Low:            k.Low.ToInt64(),
+			Close:          k.Close.ToInt64(),
+			Volume:         k.Volume.ToInt64(),
+			QuoteVolume:    k.QuoteVolume.ToInt64(),
+			NumberOfTrades: k.NumberOfTrades,
+			Closed:         k.Closed,
+		}
+	}
+	return res
+}
+
 func GetBlockPublished(pool *sdk.Pool, header abci.Header, blockHash []byte) *Block {
 	txs := pool.GetTxs()
 	transactionsToPublish := make([]Transaction, 0)
@@ -113,6 +153,16 @@ func GetBlockPublished(pool *sdk.Pool, header abci.Header, blockHash []byte) *Bl
 		case orderPkg.CancelOrderMsg:
 			orderId = msg.RefId
 			txAsset = msg.Symbol
+		case orderPkg.BatchNewOrderMsg:
+			// ids of the orders are given in the data of the tx result
+			txAsset = msg.Orders[0].Symbol
+		case orderPkg.BatchCancelOrderMsg:
+			txAsset = msg.Cancels[0].Symbol
+		case orderPkg.CancelAllOrdersMsg:
+			txAsset = msg.Symbol
+		case orderPkg.AmendOrderMsg:
+			orderId = msg.RefId
+			txAsset = msg.Symbol
 		case bank.MsgSend:
 			// TODO for now there is no requirement to support multi send message, will support multi send in issue #680
 			txAsset = msg.Inputs[0].Coins[0].Denom
@@ -137,6 +187,8 @@ func GetBlockPublished(pool *sdk.Pool, header abci.Header, blockHash []byte) *Bl
 			txAsset = msg.Symbol
 		case seturi.SetURIMsg:
 			txAsset = msg.Symbol
+		case metadata.SetTokenMetadataMsg:
+			txAsset = msg.Symbol
 		}
 		transactionsToPublish = append(transactionsToPublish, Transaction{
 			TxHash:    txhash,
@@ -182,6 +234,7 @@ func GetBlockPublished(pool *sdk.Pool, header abci.Header, blockHash []byte) *Bl
 
 func GetTransferPublished(pool *sdk.Pool, height, blockTime int64) *Transfers {
 	transferToPublish := make([]Transfer, 0)
+	list := getWatchList()
 	txs := pool.GetTxs()
 	txs.Range(func(key, value interface{}) bool {
 		txhash := key.(string)
@@ -197,7 +250,7 @@ func GetTransferPublished(pool *sdk.Pool, height, blockTime int64) *Transfers {
 		msgs := stdTx.GetMsgs()
 		for _, m := range msgs {
 			msg, ok := m.(bank.MsgSend)
-			if !ok {
+			if !ok || !list.watchSend(msg) {
 				continue
 			}
 			receivers := make([]Receiver, 0, len(msg.Outputs))
@@ -217,9 +270,13 @@ func GetTransferPublished(pool *sdk.Pool, height, blockTime int64) *Transfers {
 
 func GetAccountBalances(mapper auth.AccountKeeper, ctx sdk.Context, accSlices ...[]string) (res map[string]Account) {
 	res = make(map[string]Account)
+	list := getWatchList()
 
 	for _, accs := range accSlices {
 		for _, addrBytesStr := range accs {
+			if !list.watchAddress(addrBytesStr) {
+				continue
+			}
 			if _, ok := res[addrBytesStr]; !ok {
 				addr := sdk.AccAddress([]byte(addrBytesStr))
 				if acc, ok := mapper.GetAccount(ctx, addr).(types.NamedAccount); ok {
@@ -446,21 +503,28 @@ func filterChangedOrderBooksByOrders(
 			sellQtyDiff[symbol] = make(map[int64]int64)
 		}
 
-		switch o.Side {
-		case orderPkg.Side.BUY:
-			if qty, ok := latestPriceLevels[symbol].Buys[price]; ok {
-				res[symbol].Buys[price] = qty
-			} else {
-				res[symbol].Buys[price] = 0
-			}
-			buyQtyDiff[symbol][price] += o.effectQtyToOrderBook()
-		case orderPkg.Side.SELL:
-			if qty, ok := latestPriceLevels[symbol].Sells[price]; ok {
-				res[symbol].Sells[price] = qty
-			} else {
-				res[symbol].Sells[price] = 0
+		touch := func(price int64, diff int64) {
+			switch o.Side {
+			case orderPkg.Side.BUY:
+				if qty, ok := latestPriceLevels[symbol].Buys[price]; ok {
+					res[symbol].Buys[price] = qty
+				} else {
+					res[symbol].Buys[price] = 0
+				}
+				buyQtyDiff[symbol][price] += diff
+			case orderPkg.Side.SELL:
+				if qty, ok := latestPriceLevels[symbol].Sells[price]; ok {
+					res[symbol].Sells[price] = qty
+				} else {
+					res[symbol].Sells[price] = 0
+				}
+				sellQtyDiff[symbol][price] += diff
 			}
-			sellQtyDiff[symbol][price] += o.effectQtyToOrderBook()
+		}
+		touch(price, o.effectQtyToOrderBook())
+		// an order amended to another price also leaves its previous price level
+		if o.Status == orderPkg.Amended && o.amendment != nil && o.amendment.PrevPrice != price {
+			touch(o.amendment.PrevPrice, o.CumQty-o.amendment.PrevQuantity)
 		}
 	}
 
@@ -504,7 +568,7 @@ func tradeToOrder(t *Trade, o *orderPkg.OrderInfo, timestamp int64, feeHolder or
 		t.Id,
 		owner.String(),
 		o.Side,
-		orderPkg.OrderType.LIMIT,
+		o.OrderType,
 		o.Price,
 		o.Quantity,
 		t.Price,
@@ -517,6 +581,7 @@ func tradeToOrder(t *Trade, o *orderPkg.OrderInfo, timestamp int64, feeHolder or
 		orderPkg.NEW,
 		o.TxHash,
 		"",
+		nil,
 	}
 	if o.Side == orderPkg.Side.BUY {
 		res.SingleFee = t.BSingleFee
@@ -617,10 +682,11 @@ func collectOrders(orderChanges orderPkg.OrderChanges, orderInfos orderPkg.Order
 			orderToPublish := Order{
 				orderInfo.Symbol, o.Tpe, o.Id,
 				"", orderInfo.Sender.String(), orderInfo.Side,
-				orderPkg.OrderType.LIMIT, orderInfo.Price, orderInfo.Quantity,
+				orderInfo.OrderType, orderInfo.Price, orderInfo.Quantity,
 				0, 0, orderInfo.CumQty, "",
 				orderInfo.CreatedTimestamp, timestamp, orderInfo.TimeInForce,
 				orderPkg.NEW, orderInfo.TxHash, o.SingleFee,
+				o.Amendment,
 			}
 
 			if o.Tpe.IsOpen() {
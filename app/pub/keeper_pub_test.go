@@ -111,10 +111,55 @@ func TestKeeper_AddOrder(t *testing.T) {
 	assert.Equal("0D42245EB2BF574A5B9D485404E0E61B1A2397A9", orderInfo1.TxHash)
 }
 
+func TestKeeper_AmendOrder(t *testing.T) {
+	assert, require := setupKeeperTest(t)
+
+	msg := orderPkg.NewNewOrderMsg(buyer, "1", orderPkg.Side.BUY, "XYZ-000_BNB", 102000, 3000000)
+	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "08E19B16880CF70D59DDD996E3D75C66CD0405DE", 0}, false)
+	_, err := keeper.AmendOrder("XYZ-000_BNB", "1", 103000, 2000000, 42, 100, false)
+	require.NoError(err)
+	amendment := &orderPkg.OrderAmendment{PrevPrice: 102000, PrevQuantity: 3000000}
+	keeper.UpdateOrderChangeSync(orderPkg.OrderChange{Id: "1", Tpe: orderPkg.Amended, Amendment: amendment}, "XYZ-000_BNB")
+
+	opens, _, _ := collectOrdersToPublish(nil, keeper.GetOrderChanges(orderPkg.PairType.BEP2),
+		keeper.GetOrderInfosForPub(orderPkg.PairType.BEP2), orderPkg.FeeHolder{}, 100)
+	require.Len(opens, 2)
+	assert.Equal(orderPkg.Amended, opens[1].Status)
+	assert.Equal(int64(103000), opens[1].Price)
+	assert.Equal(int64(2000000), opens[1].Qty)
+
+	// the order is moved out of the previous price level, which is emptied
+	changed := filterChangedOrderBooksByOrders(opens, keeper.GetOrderBooks(100))
+	require.Len(changed, 1)
+	assert.Equal(map[int64]int64{102000: 0, 103000: 2000000}, changed["XYZ-000_BNB"].Buys)
+	assert.Empty(changed["XYZ-000_BNB"].Sells)
+}
+
+func TestKeeper_AddTouchedPriceLevels(t *testing.T) {
+	assert, require := setupKeeperTest(t)
+
+	msg := orderPkg.NewNewOrderMsg(buyer, "1", orderPkg.Side.BUY, "XYZ-000_BNB", 102000, 3000000)
+	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "08E19B16880CF70D59DDD996E3D75C66CD0405DE", 0}, false)
+	msg = orderPkg.NewNewOrderMsg(buyer, "2", orderPkg.Side.BUY, "XYZ-000_BNB", 101000, 1000000)
+	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "0D42245EB2BF574A5B9D485404E0E61B1A2397A9", 0}, false)
+
+	// the level of order 2 is deeper than the levels collected
+	levels := keeper.GetOrderBooks(1)
+	assert.Equal(map[int64]int64{102000: 3000000}, levels["XYZ-000_BNB"].Buys)
+	keeper.AddTouchedPriceLevels(levels, 42)
+	assert.Equal(map[int64]int64{102000: 3000000, 101000: 1000000}, levels["XYZ-000_BNB"].Buys)
+
+	opens, _, _ := collectOrdersToPublish(nil, keeper.GetOrderChanges(orderPkg.PairType.BEP2),
+		keeper.GetOrderInfosForPub(orderPkg.PairType.BEP2), orderPkg.FeeHolder{}, 100)
+	changed := filterChangedOrderBooksByOrders(opens, levels)
+	require.Len(changed, 1)
+	assert.Equal(map[int64]int64{102000: 3000000, 101000: 1000000}, changed["XYZ-000_BNB"].Buys)
+}
+
 func TestKeeper_IOCExpireWithFee(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.IOC}
+	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.IOC, 0, false}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "08E19B16880CF70D59DDD996E3D75C66CD0405DE", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 1)
@@ -142,7 +187,7 @@ func TestKeeper_IOCExpireWithFee(t *testing.T) {
 func TestKeeper_ExpireWithFee(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.GTE}
+	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.GTE, 0, false}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "08E19B16880CF70D59DDD996E3D75C66CD0405DE", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 1)
@@ -167,7 +212,7 @@ func TestKeeper_ExpireWithFee(t *testing.T) {
 func TestKeeper_DelistWithFee(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.GTE}
+	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.GTE, 0, false}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "08E19B16880CF70D59DDD996E3D75C66CD0405DE", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 1)
@@ -192,9 +237,9 @@ func TestKeeper_DelistWithFee(t *testing.T) {
 func Test_IOCPartialExpire(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 300000000, orderPkg.TimeInForce.IOC}
+	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 300000000, orderPkg.TimeInForce.IOC, 0, false}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "", 0}, false)
-	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 100000000, orderPkg.TimeInForce.GTE}
+	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 100000000, orderPkg.TimeInForce.GTE, 0, false}
 	keeper.AddOrder(orderPkg.OrderInfo{msg2, 42, 100, 42, 100, 0, "", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 2)
@@ -232,9 +277,9 @@ func Test_IOCPartialExpire(t *testing.T) {
 func Test_GTEPartialExpire(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 100000000, orderPkg.TimeInForce.GTE}
+	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 100000000, orderPkg.TimeInForce.GTE, 0, false}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "", 0}, false)
-	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 300000000, orderPkg.TimeInForce.GTE}
+	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 300000000, orderPkg.TimeInForce.GTE, 0, false}
 	keeper.AddOrder(orderPkg.OrderInfo{msg2, 42, 100, 42, 100, 0, "", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 2)
@@ -277,11 +322,11 @@ func Test_GTEPartialExpire(t *testing.T) {
 func Test_OneBuyVsTwoSell(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 300000000, orderPkg.TimeInForce.GTE}
+	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 300000000, orderPkg.TimeInForce.GTE, 0, false}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "", 0}, false)
-	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 100000000, orderPkg.TimeInForce.GTE}
+	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 100000000, orderPkg.TimeInForce.GTE, 0, false}
 	keeper.AddOrder(orderPkg.OrderInfo{msg2, 42, 100, 42, 100, 0, "", 0}, false)
-	msg3 := orderPkg.NewOrderMsg{seller, "s-2", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 200000000, orderPkg.TimeInForce.GTE}
+	msg3 := orderPkg.NewOrderMsg{seller, "s-2", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 200000000, orderPkg.TimeInForce.GTE, 0, false}
 	keeper.AddOrder(orderPkg.OrderInfo{msg3, 42, 100, 42, 100, 0, "", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 3)
@@ -27,6 +27,7 @@ const (
 	mirrorTpe
 	sideProposalType
 	breatheBlockTpe
+	klineTpe
 )
 
 var (
@@ -64,6 +65,8 @@ func (this msgType) String() string {
 		return "SideProposal"
 	case breatheBlockTpe:
 		return "BreatheBlock"
+	case klineTpe:
+		return "Klines"
 	default:
 		return "Unknown"
 	}
@@ -75,7 +78,7 @@ func (this msgType) String() string {
 // This allows consumers be deployed independently (in advance) with publisher
 var latestSchemaVersions = map[msgType]int{
 	accountsTpe:        1,
-	booksTpe:           0,
+	booksTpe:           1,
 	executionResultTpe: 1,
 	blockFeeTpe:        0,
 	transferTpe:        1,
@@ -87,6 +90,7 @@ var latestSchemaVersions = map[msgType]int{
 	mirrorTpe:          0,
 	sideProposalType:   0,
 	breatheBlockTpe:    0,
+	klineTpe:           0,
 }
 
 type AvroOrJsonMsg interface {
@@ -306,6 +310,8 @@ type Order struct {
 	CurrentExecutionType orderPkg.ExecutionType
 	TxHash               string
 	SingleFee            string // fee for this order update - ADDED Galileo
+
+	amendment *orderPkg.OrderAmendment // only for Amended, not published
 }
 
 func (msg *Order) String() string {
@@ -315,13 +321,29 @@ func (msg *Order) String() string {
 func (msg *Order) effectQtyToOrderBook() int64 {
 	switch msg.Status {
 	case orderPkg.Ack:
+		if orderPkg.IsConditionalOrderType(msg.OrderType) {
+			return 0 // placed into the order book when triggered
+		}
 		return msg.Qty
+	case orderPkg.Triggered:
+		return msg.Qty - msg.CumQty
+	case orderPkg.TriggerCanceled:
+		return 0 // conditional order never reached the order book
 	case orderPkg.FullyFill, orderPkg.PartialFill:
 		return -msg.LastExecutedQty
-	case orderPkg.Expired, orderPkg.IocExpire, orderPkg.IocNoFill, orderPkg.Canceled, orderPkg.FailedMatching:
+	case orderPkg.Expired, orderPkg.IocExpire, orderPkg.IocNoFill, orderPkg.Canceled, orderPkg.FailedMatching,
+		orderPkg.PostOnlyCanceled:
 		return msg.CumQty - msg.Qty // deliberated be negative value
 	case orderPkg.FailedBlocking:
 		return 0
+	case orderPkg.Amended:
+		if msg.amendment == nil {
+			return 0
+		}
+		if msg.amendment.PrevPrice == msg.Price {
+			return msg.Qty - msg.amendment.PrevQuantity
+		}
+		return msg.Qty - msg.CumQty // the previous price level is handled by the caller
 	default:
 		Logger.Error("does not supported order status", "order", msg.String())
 		return 0
@@ -492,14 +514,19 @@ func (msg *PriceLevel) ToNativeMap() map[string]interface{} {
 	return native
 }
 
+// OrderBookDelta carries the changed price levels of the symbol, or all the levels if it's a snapshot.
+// Sequence is increased by one for every delta of the symbol, see BookSequencer.
 type OrderBookDelta struct {
-	Symbol string
-	Buys   []PriceLevel
-	Sells  []PriceLevel
+	Symbol   string
+	Buys     []PriceLevel
+	Sells    []PriceLevel
+	Sequence int64
+	Snapshot bool
 }
 
 func (msg *OrderBookDelta) String() string {
-	return fmt.Sprintf("orderBookDelta for: %s, num of buys prices: %d, num of sell prices: %d", msg.Symbol, len(msg.Buys), len(msg.Sells))
+	return fmt.Sprintf("orderBookDelta for: %s, sequence: %d, snapshot: %v, num of buys prices: %d, num of sell prices: %d",
+		msg.Symbol, msg.Sequence, msg.Snapshot, len(msg.Buys), len(msg.Sells))
 }
 
 func (msg *OrderBookDelta) ToNativeMap() map[string]interface{} {
@@ -515,6 +542,8 @@ func (msg *OrderBookDelta) ToNativeMap() map[string]interface{} {
 		ss[idx] = sell.ToNativeMap()
 	}
 	native["sells"] = ss
+	native["sequence"] = msg.Sequence
+	native["snapshot"] = msg.Snapshot
 	return native
 }
 
@@ -1175,3 +1204,63 @@ func (msg *BreatheBlockMsg) EmptyCopy() AvroOrJsonMsg {
 		msg.Timestamp,
 	}
 }
+
+type Klines struct {
+	Height    int64
+	Timestamp int64
+	NumOfMsgs int
+	Klines    []*Kline
+}
+
+func (msg *Klines) String() string {
+	return fmt.Sprintf("Klines in block: %d, numOfMsgs: %d", msg.Height, msg.NumOfMsgs)
+}
+
+func (msg *Klines) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["height"] = msg.Height
+	native["timestamp"] = msg.Timestamp
+	native["numOfMsgs"] = msg.NumOfMsgs
+	ks := make([]map[string]interface{}, len(msg.Klines))
+	for idx, k := range msg.Klines {
+		ks[idx] = k.toNativeMap()
+	}
+	native["klines"] = ks
+	return native
+}
+
+type Kline struct {
+	Symbol         string
+	Interval       string
+	OpenTime       int64
+	CloseTime      int64
+	Open           int64
+	High           int64
+	Low            int64
+	Close          int64
+	Volume         int64
+	QuoteVolume    int64
+	NumberOfTrades int64
+	Closed         bool
+}
+
+func (msg *Kline) String() string {
+	return fmt.Sprintf("Kline: %v", msg.toNativeMap())
+}
+
+func (msg *Kline) toNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["symbol"] = msg.Symbol
+	native["interval"] = msg.Interval
+	native["openTime"] = msg.OpenTime
+	native["closeTime"] = msg.CloseTime
+	native["open"] = msg.Open
+	native["high"] = msg.High
+	native["low"] = msg.Low
+	native["close"] = msg.Close
+	native["volume"] = msg.Volume
+	native["quoteVolume"] = msg.QuoteVolume
+	native["numberOfTrades"] = msg.NumberOfTrades
+	native["closed"] = msg.Closed
+	return native
+}
@@ -0,0 +1,61 @@
+package pub
+
+import (
+	"encoding/json"
+	"sync"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+const PublicationProgressDB = "publication"
+
+var publicationProgressKey = []byte("progress")
+
+// PublicationProgress records the last height whose kafka transaction is committed into the node's db. The node
+// doesn't commit a block before the transaction of its height is committed, so a block replayed after a restart
+// either has all its messages committed before, or none of them. The recorded height only moves forward by the
+// heights committed, a height failed to commit stops the node before any later height is published.
+type PublicationProgress struct {
+	mtx sync.Mutex
+	db  dbm.DB
+
+	CommittedHeight int64 `json:"committed_height"`
+}
+
+func NewPublicationProgress(db dbm.DB) (*PublicationProgress, error) {
+	progress := &PublicationProgress{db: db}
+	if bz := db.Get(publicationProgressKey); bz != nil {
+		if err := json.Unmarshal(bz, progress); err != nil {
+			return nil, err
+		}
+	}
+	return progress, nil
+}
+
+// IsCommitted returns whether the messages of the height have been committed before restart
+func (progress *PublicationProgress) IsCommitted(height int64) bool {
+	progress.mtx.Lock()
+	defer progress.mtx.Unlock()
+	return height <= progress.CommittedHeight
+}
+
+// Commit records the transaction of the height is committed
+func (progress *PublicationProgress) Commit(height int64) {
+	progress.mtx.Lock()
+	defer progress.mtx.Unlock()
+	if height <= progress.CommittedHeight {
+		return
+	}
+	progress.CommittedHeight = height
+
+	bz, err := json.Marshal(progress)
+	if err != nil {
+		Logger.Error("failed to marshal publication progress", "err", err)
+		return
+	}
+	progress.db.SetSync(publicationProgressKey, bz)
+}
+
+func (progress *PublicationProgress) Close() {
+	progress.db.Close()
+}
@@ -0,0 +1,36 @@
+package pub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+func TestPublicationProgress(t *testing.T) {
+	db := dbm.NewMemDB()
+	progress, err := NewPublicationProgress(db)
+	require.NoError(t, err)
+	require.False(t, progress.IsCommitted(1))
+
+	progress.Commit(1)
+	progress.Commit(2)
+	require.True(t, progress.IsCommitted(2))
+	require.False(t, progress.IsCommitted(3))
+
+	// the node restarts and replays the blocks from height 2
+	progress, err = NewPublicationProgress(db)
+	require.NoError(t, err)
+	require.True(t, progress.IsCommitted(1))
+	require.True(t, progress.IsCommitted(2))
+	require.False(t, progress.IsCommitted(3))
+
+	// an earlier height doesn't move the progress back
+	progress.Commit(3)
+	progress.Commit(2)
+	progress, err = NewPublicationProgress(db)
+	require.NoError(t, err)
+	require.True(t, progress.IsCommitted(3))
+	require.False(t, progress.IsCommitted(4))
+}
@@ -2,6 +2,7 @@ package pub
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -41,394 +42,403 @@ type MarketDataPublisher interface {
 	Stop()
 }
 
+// HeightTransactor is implemented by the publishers that publish all the messages of a height in one transaction
+type HeightTransactor interface {
+	BeginHeight(height int64)
+	CommitHeight(height int64) error
+}
+
 func PublishEvent(
 	publisher MarketDataPublisher,
 	Logger tmlog.Logger,
 	cfg *config.PublicationConfig,
 	ToPublishEventCh <-chan *sub.ToPublishEvent) {
 	for toPublish := range ToPublishEventCh {
-		eventData := toPublish.EventData
-		//Logger.Debug("publisher queue status", "size", len(ToPublishCh))
-		if cfg.PublishStaking {
-			var msgNum int
-			var validators []*Validator
-			var removedValidators map[string][]sdk.ValAddress
-			var delegationsMap map[string][]*Delegation
-			var ubdsMap map[string][]*UnbondingDelegation
-			var redsMap map[string][]*ReDelegation
-			var completedUBDsMap map[string][]*CompletedUnbondingDelegation
-			var completedREDsMap map[string][]*CompletedReDelegation
-			var delegateEventsMap map[string][]*DelegateEvent
-			var undelegateEventsMap map[string][]*UndelegateEvent
-			var redelegateEventsMap map[string][]*RedelegateEvent
-			var electedValidatorsMap map[string][]*Validator
-
-			if eventData.StakeData != nil {
-				if len(eventData.StakeData.Validators) > 0 {
-					validators = make([]*Validator, len(eventData.StakeData.Validators))
-					msgNum += len(eventData.StakeData.Validators)
+		publishEvent(publisher, cfg, toPublish)
+	}
+}
+
+func publishEvent(publisher MarketDataPublisher, cfg *config.PublicationConfig, toPublish *sub.ToPublishEvent) {
+	eventData := toPublish.EventData
+	if cfg.PublishStaking {
+		var msgNum int
+		var validators []*Validator
+		var removedValidators map[string][]sdk.ValAddress
+		var delegationsMap map[string][]*Delegation
+		var ubdsMap map[string][]*UnbondingDelegation
+		var redsMap map[string][]*ReDelegation
+		var completedUBDsMap map[string][]*CompletedUnbondingDelegation
+		var completedREDsMap map[string][]*CompletedReDelegation
+		var delegateEventsMap map[string][]*DelegateEvent
+		var undelegateEventsMap map[string][]*UndelegateEvent
+		var redelegateEventsMap map[string][]*RedelegateEvent
+		var electedValidatorsMap map[string][]*Validator
+
+		if eventData.StakeData != nil {
+			if len(eventData.StakeData.Validators) > 0 {
+				validators = make([]*Validator, len(eventData.StakeData.Validators))
+				msgNum += len(eventData.StakeData.Validators)
+				var i int
+				for _, val := range eventData.StakeData.Validators {
+					v := Validator(val)
+					validators[i] = &v
+					i++
+				}
+			}
+			if len(eventData.StakeData.RemovedValidators) > 0 {
+				removedValidators = make(map[string][]sdk.ValAddress)
+				for chainId, removedVals := range eventData.StakeData.RemovedValidators {
+					vals := make([]sdk.ValAddress, len(removedVals))
+					msgNum += len(removedVals)
 					var i int
-					for _, val := range eventData.StakeData.Validators {
-						v := Validator(val)
-						validators[i] = &v
+					for _, val := range removedVals {
+						vals[i] = val
 						i++
 					}
+					removedValidators[chainId] = vals
 				}
-				if len(eventData.StakeData.RemovedValidators) > 0 {
-					removedValidators = make(map[string][]sdk.ValAddress)
-					for chainId, removedVals := range eventData.StakeData.RemovedValidators {
-						vals := make([]sdk.ValAddress, len(removedVals))
-						msgNum += len(removedVals)
-						var i int
-						for _, val := range removedVals {
-							vals[i] = val
-							i++
-						}
-						removedValidators[chainId] = vals
+			}
+			if len(eventData.StakeData.Delegations) > 0 || len(eventData.StakeData.RemovedDelegations) > 0 {
+				delegationsMap = make(map[string][]*Delegation)
+				for chainId, dels := range eventData.StakeData.Delegations {
+					delegations := make([]*Delegation, len(dels))
+					msgNum += len(dels)
+					var i int
+					for _, del := range dels {
+						d := Delegation(del)
+						delegations[i] = &d
+						i++
 					}
+					delegationsMap[chainId] = delegations
 				}
-				if len(eventData.StakeData.Delegations) > 0 || len(eventData.StakeData.RemovedDelegations) > 0 {
-					delegationsMap = make(map[string][]*Delegation)
-					for chainId, dels := range eventData.StakeData.Delegations {
-						delegations := make([]*Delegation, len(dels))
-						msgNum += len(dels)
-						var i int
-						for _, del := range dels {
-							d := Delegation(del)
-							delegations[i] = &d
-							i++
-						}
-						delegationsMap[chainId] = delegations
-					}
 
-					for chainId, removedDels := range eventData.StakeData.RemovedDelegations {
-						if delegationsMap[chainId] == nil {
-							delegationsMap[chainId] = make([]*Delegation, 0)
-						}
-						msgNum += len(removedDels)
-						for _, dvPair := range removedDels {
-							d := Delegation{
-								DelegatorAddr: dvPair.DelegatorAddr,
-								ValidatorAddr: dvPair.ValidatorAddr,
-								Shares:        sdk.ZeroDec(),
-							}
-							delegationsMap[chainId] = append(delegationsMap[chainId], &d)
+				for chainId, removedDels := range eventData.StakeData.RemovedDelegations {
+					if delegationsMap[chainId] == nil {
+						delegationsMap[chainId] = make([]*Delegation, 0)
+					}
+					msgNum += len(removedDels)
+					for _, dvPair := range removedDels {
+						d := Delegation{
+							DelegatorAddr: dvPair.DelegatorAddr,
+							ValidatorAddr: dvPair.ValidatorAddr,
+							Shares:        sdk.ZeroDec(),
 						}
-
+						delegationsMap[chainId] = append(delegationsMap[chainId], &d)
 					}
+
 				}
-				if len(eventData.StakeData.UnbondingDelegations) > 0 {
-					ubdsMap = make(map[string][]*UnbondingDelegation)
-					for chainId, ubds := range eventData.StakeData.UnbondingDelegations {
-						unbondingDelegations := make([]*UnbondingDelegation, len(ubds))
-						msgNum += len(ubds)
-						var i int
-						for _, ubd := range ubds {
-							u := UnbondingDelegation(ubd)
-							unbondingDelegations[i] = &u
-							i++
-						}
-						ubdsMap[chainId] = unbondingDelegations
+			}
+			if len(eventData.StakeData.UnbondingDelegations) > 0 {
+				ubdsMap = make(map[string][]*UnbondingDelegation)
+				for chainId, ubds := range eventData.StakeData.UnbondingDelegations {
+					unbondingDelegations := make([]*UnbondingDelegation, len(ubds))
+					msgNum += len(ubds)
+					var i int
+					for _, ubd := range ubds {
+						u := UnbondingDelegation(ubd)
+						unbondingDelegations[i] = &u
+						i++
 					}
+					ubdsMap[chainId] = unbondingDelegations
 				}
-				if len(eventData.StakeData.ReDelegations) > 0 {
-					redsMap = make(map[string][]*ReDelegation)
-					for chainId, reds := range eventData.StakeData.ReDelegations {
-						redelgations := make([]*ReDelegation, len(reds))
-						msgNum += len(reds)
-						var i int
-						for _, red := range reds {
-							r := ReDelegation(red)
-							redelgations[i] = &r
-							i++
-						}
-						redsMap[chainId] = redelgations
+			}
+			if len(eventData.StakeData.ReDelegations) > 0 {
+				redsMap = make(map[string][]*ReDelegation)
+				for chainId, reds := range eventData.StakeData.ReDelegations {
+					redelgations := make([]*ReDelegation, len(reds))
+					msgNum += len(reds)
+					var i int
+					for _, red := range reds {
+						r := ReDelegation(red)
+						redelgations[i] = &r
+						i++
 					}
+					redsMap[chainId] = redelgations
 				}
-				if len(eventData.StakeData.CompletedUBDs) > 0 {
-					completedUBDsMap = make(map[string][]*CompletedUnbondingDelegation)
-					for chainId, ubds := range eventData.StakeData.CompletedUBDs {
-						comUBDs := make([]*CompletedUnbondingDelegation, len(ubds))
-						msgNum += len(ubds)
-						for i, ubd := range ubds {
-							comUBDs[i] = &CompletedUnbondingDelegation{
-								Validator: ubd.Validator,
-								Delegator: ubd.Delegator,
-								Amount:    Coin{Denom: ubd.Amount.Denom, Amount: ubd.Amount.Amount},
-							}
+			}
+			if len(eventData.StakeData.CompletedUBDs) > 0 {
+				completedUBDsMap = make(map[string][]*CompletedUnbondingDelegation)
+				for chainId, ubds := range eventData.StakeData.CompletedUBDs {
+					comUBDs := make([]*CompletedUnbondingDelegation, len(ubds))
+					msgNum += len(ubds)
+					for i, ubd := range ubds {
+						comUBDs[i] = &CompletedUnbondingDelegation{
+							Validator: ubd.Validator,
+							Delegator: ubd.Delegator,
+							Amount:    Coin{Denom: ubd.Amount.Denom, Amount: ubd.Amount.Amount},
 						}
-						completedUBDsMap[chainId] = comUBDs
 					}
+					completedUBDsMap[chainId] = comUBDs
 				}
-				if len(eventData.StakeData.CompletedREDs) > 0 {
-					completedREDsMap = make(map[string][]*CompletedReDelegation)
-					for chainId, reds := range eventData.StakeData.CompletedREDs {
-						comREDs := make([]*CompletedReDelegation, len(reds))
-						msgNum += len(reds)
-						for i, red := range reds {
-							comREDs[i] = &CompletedReDelegation{
-								Delegator:    red.DelegatorAddr,
-								ValidatorSrc: red.ValidatorSrcAddr,
-								ValidatorDst: red.ValidatorDstAddr,
-							}
+			}
+			if len(eventData.StakeData.CompletedREDs) > 0 {
+				completedREDsMap = make(map[string][]*CompletedReDelegation)
+				for chainId, reds := range eventData.StakeData.CompletedREDs {
+					comREDs := make([]*CompletedReDelegation, len(reds))
+					msgNum += len(reds)
+					for i, red := range reds {
+						comREDs[i] = &CompletedReDelegation{
+							Delegator:    red.DelegatorAddr,
+							ValidatorSrc: red.ValidatorSrcAddr,
+							ValidatorDst: red.ValidatorDstAddr,
 						}
-						completedREDsMap[chainId] = comREDs
 					}
+					completedREDsMap[chainId] = comREDs
 				}
-				if len(eventData.StakeData.DelegateEvents) > 0 {
-					delegateEventsMap = make(map[string][]*DelegateEvent)
-					for chainId, des := range eventData.StakeData.DelegateEvents {
-						dess := make([]*DelegateEvent, len(des))
-						msgNum += len(des)
-						for i, de := range des {
-							dess[i] = &DelegateEvent{
-								Delegator: de.Delegator,
-								Validator: de.Validator,
-								Amount: Coin{
-									Denom:  de.Denom,
-									Amount: de.Amount,
-								},
-								TxHash: de.TxHash,
-							}
+			}
+			if len(eventData.StakeData.DelegateEvents) > 0 {
+				delegateEventsMap = make(map[string][]*DelegateEvent)
+				for chainId, des := range eventData.StakeData.DelegateEvents {
+					dess := make([]*DelegateEvent, len(des))
+					msgNum += len(des)
+					for i, de := range des {
+						dess[i] = &DelegateEvent{
+							Delegator: de.Delegator,
+							Validator: de.Validator,
+							Amount: Coin{
+								Denom:  de.Denom,
+								Amount: de.Amount,
+							},
+							TxHash: de.TxHash,
 						}
-						delegateEventsMap[chainId] = dess
 					}
+					delegateEventsMap[chainId] = dess
 				}
-				if len(eventData.StakeData.UndelegateEvents) > 0 {
-					undelegateEventsMap = make(map[string][]*UndelegateEvent)
-					for chainId, v := range eventData.StakeData.UndelegateEvents {
-						vv := make([]*UndelegateEvent, len(v))
-						msgNum += len(v)
-						for i, ude := range v {
-							vv[i] = &UndelegateEvent{
-								Delegator: ude.Delegator,
-								Validator: ude.Validator,
-								Amount: Coin{
-									Denom:  ude.Denom,
-									Amount: ude.Amount,
-								},
-								TxHash: ude.TxHash,
-							}
+			}
+			if len(eventData.StakeData.UndelegateEvents) > 0 {
+				undelegateEventsMap = make(map[string][]*UndelegateEvent)
+				for chainId, v := range eventData.StakeData.UndelegateEvents {
+					vv := make([]*UndelegateEvent, len(v))
+					msgNum += len(v)
+					for i, ude := range v {
+						vv[i] = &UndelegateEvent{
+							Delegator: ude.Delegator,
+							Validator: ude.Validator,
+							Amount: Coin{
+								Denom:  ude.Denom,
+								Amount: ude.Amount,
+							},
+							TxHash: ude.TxHash,
 						}
-						undelegateEventsMap[chainId] = vv
 					}
+					undelegateEventsMap[chainId] = vv
 				}
-				if len(eventData.StakeData.RedelegateEvents) > 0 {
-					redelegateEventsMap = make(map[string][]*RedelegateEvent)
-					for chainId, v := range eventData.StakeData.RedelegateEvents {
-						vv := make([]*RedelegateEvent, len(v))
-						msgNum += len(v)
-						for i, ude := range v {
-							vv[i] = &RedelegateEvent{
-								Delegator:    ude.Delegator,
-								ValidatorSrc: ude.SrcValidator,
-								ValidatorDst: ude.DstValidator,
-								Amount: Coin{
-									Denom:  ude.Denom,
-									Amount: ude.Amount,
-								},
-								TxHash: ude.TxHash,
-							}
+			}
+			if len(eventData.StakeData.RedelegateEvents) > 0 {
+				redelegateEventsMap = make(map[string][]*RedelegateEvent)
+				for chainId, v := range eventData.StakeData.RedelegateEvents {
+					vv := make([]*RedelegateEvent, len(v))
+					msgNum += len(v)
+					for i, ude := range v {
+						vv[i] = &RedelegateEvent{
+							Delegator:    ude.Delegator,
+							ValidatorSrc: ude.SrcValidator,
+							ValidatorDst: ude.DstValidator,
+							Amount: Coin{
+								Denom:  ude.Denom,
+								Amount: ude.Amount,
+							},
+							TxHash: ude.TxHash,
 						}
-						redelegateEventsMap[chainId] = vv
 					}
+					redelegateEventsMap[chainId] = vv
 				}
-				if len(eventData.StakeData.ElectedValidators) > 0 {
-					electedValidatorsMap = make(map[string][]*Validator)
-					for chainId, vals := range eventData.StakeData.ElectedValidators {
-						msgNum += len(vals)
-						electedVals := make([]*Validator, len(vals))
-						for i := range vals {
-							val := Validator(vals[i])
-							electedVals[i] = &val
-						}
-						electedValidatorsMap[chainId] = electedVals
+			}
+			if len(eventData.StakeData.ElectedValidators) > 0 {
+				electedValidatorsMap = make(map[string][]*Validator)
+				for chainId, vals := range eventData.StakeData.ElectedValidators {
+					msgNum += len(vals)
+					electedVals := make([]*Validator, len(vals))
+					for i := range vals {
+						val := Validator(vals[i])
+						electedVals[i] = &val
 					}
+					electedValidatorsMap[chainId] = electedVals
 				}
 			}
+		}
 
-			msg := StakingMsg{
-				NumOfMsgs: msgNum,
-				Height:    toPublish.Height,
-				Timestamp: toPublish.Timestamp.Unix(),
-
-				Validators:           validators,
-				RemovedValidators:    removedValidators,
-				Delegations:          delegationsMap,
-				UnbondingDelegations: ubdsMap,
-				ReDelegations:        redsMap,
-				CompletedUBDs:        completedUBDsMap,
-				CompletedREDs:        completedREDsMap,
-				DelegateEvents:       delegateEventsMap,
-				UndelegateEvents:     undelegateEventsMap,
-				RedelegateEvents:     redelegateEventsMap,
-				ElectedValidators:    electedValidatorsMap,
-			}
-			publisher.publish(&msg, stakingTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+		msg := StakingMsg{
+			NumOfMsgs: msgNum,
+			Height:    toPublish.Height,
+			Timestamp: toPublish.Timestamp.Unix(),
+
+			Validators:           validators,
+			RemovedValidators:    removedValidators,
+			Delegations:          delegationsMap,
+			UnbondingDelegations: ubdsMap,
+			ReDelegations:        redsMap,
+			CompletedUBDs:        completedUBDsMap,
+			CompletedREDs:        completedREDsMap,
+			DelegateEvents:       delegateEventsMap,
+			UndelegateEvents:     undelegateEventsMap,
+			RedelegateEvents:     redelegateEventsMap,
+			ElectedValidators:    electedValidatorsMap,
 		}
+		publisher.publish(&msg, stakingTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+	}
 
-		if cfg.PublishDistributeReward {
-			var msgNum int
-			distributions := make(map[string][]*Distribution)
-			if eventData.StakeData != nil {
-				for chainId, disData := range eventData.StakeData.Distribution {
-					dis := make([]*Distribution, len(disData))
-					for i, disData := range disData {
-						rewards := make([]*Reward, len(disData.Rewards))
-						for i, reward := range disData.Rewards {
-							rewardMsg := &Reward{
-								Validator: reward.ValAddr,
-								Delegator: reward.AccAddr,
-								Amount:    reward.Amount,
-								Tokens:    reward.Tokens.RawInt(),
-							}
-							rewards[i] = rewardMsg
-						}
-						var valTokens, totalReward, commission int64
-						if disData.Validator != nil {
-							valTokens = disData.ValTokens.RawInt()
-							totalReward = disData.TotalReward.RawInt()
-							commission = disData.Commission.RawInt()
-						}
-						dis[i] = &Distribution{
-							Validator:      disData.Validator,
-							SelfDelegator:  disData.SelfDelegator,
-							DistributeAddr: disData.DistributeAddr,
-							ValTokens:      valTokens,
-							TotalReward:    totalReward,
-							Commission:     commission,
-							Rewards:        rewards,
+	if cfg.PublishDistributeReward {
+		var msgNum int
+		distributions := make(map[string][]*Distribution)
+		if eventData.StakeData != nil {
+			for chainId, disData := range eventData.StakeData.Distribution {
+				dis := make([]*Distribution, len(disData))
+				for i, disData := range disData {
+					rewards := make([]*Reward, len(disData.Rewards))
+					for i, reward := range disData.Rewards {
+						rewardMsg := &Reward{
+							Validator: reward.ValAddr,
+							Delegator: reward.AccAddr,
+							Amount:    reward.Amount,
+							Tokens:    reward.Tokens.RawInt(),
 						}
+						rewards[i] = rewardMsg
+					}
+					var valTokens, totalReward, commission int64
+					if disData.Validator != nil {
+						valTokens = disData.ValTokens.RawInt()
+						totalReward = disData.TotalReward.RawInt()
+						commission = disData.Commission.RawInt()
+					}
+					dis[i] = &Distribution{
+						Validator:      disData.Validator,
+						SelfDelegator:  disData.SelfDelegator,
+						DistributeAddr: disData.DistributeAddr,
+						ValTokens:      valTokens,
+						TotalReward:    totalReward,
+						Commission:     commission,
+						Rewards:        rewards,
 					}
-					msgNum++
-					distributions[chainId] = dis
 				}
+				msgNum++
+				distributions[chainId] = dis
 			}
-
-			distributionMsg := DistributionMsg{
-				NumOfMsgs:     msgNum,
-				Height:        toPublish.Height,
-				Timestamp:     toPublish.Timestamp.Unix(),
-				Distributions: distributions,
-			}
-			publisher.publish(&distributionMsg, distributionTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
 		}
 
-		if cfg.PublishSlashing {
-			var msgNum int
-			slashData := make(map[string][]*Slash)
-			for chainId, slashes := range eventData.SlashData {
-				slashDataPerChain := make([]*Slash, len(slashes))
-				for i, slash := range slashes {
-
-					vc := make([]*AllocatedAmt, len(slash.ValidatorsCompensation))
-					var idx int
-					for address, amount := range slash.ValidatorsCompensation {
-						vc[idx] = &AllocatedAmt{Address: sdk.AccAddress([]byte(address)).String(), Amount: amount}
-						idx++
-					}
+		distributionMsg := DistributionMsg{
+			NumOfMsgs:     msgNum,
+			Height:        toPublish.Height,
+			Timestamp:     toPublish.Timestamp.Unix(),
+			Distributions: distributions,
+		}
+		publisher.publish(&distributionMsg, distributionTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+	}
 
-					slashDataPerChain[i] = &Slash{
-						Validator:              slash.Validator,
-						InfractionType:         slash.InfractionType,
-						InfractionHeight:       slash.InfractionHeight,
-						JailUtil:               slash.JailUtil.Unix(),
-						SlashAmount:            slash.SlashAmount,
-						ToFeePool:              slash.ToFeePool,
-						Submitter:              slash.Submitter,
-						SubmitterReward:        slash.SubmitterReward,
-						ValidatorsCompensation: vc,
-					}
-					msgNum++
+	if cfg.PublishSlashing {
+		var msgNum int
+		slashData := make(map[string][]*Slash)
+		for chainId, slashes := range eventData.SlashData {
+			slashDataPerChain := make([]*Slash, len(slashes))
+			for i, slash := range slashes {
+
+				vc := make([]*AllocatedAmt, len(slash.ValidatorsCompensation))
+				var idx int
+				for address, amount := range slash.ValidatorsCompensation {
+					vc[idx] = &AllocatedAmt{Address: sdk.AccAddress([]byte(address)).String(), Amount: amount}
+					idx++
 				}
-				slashData[chainId] = slashDataPerChain
-			}
 
-			slashMsg := SlashMsg{
-				NumOfMsgs: msgNum,
-				Height:    toPublish.Height,
-				Timestamp: toPublish.Timestamp.Unix(),
-				SlashData: slashData,
+				slashDataPerChain[i] = &Slash{
+					Validator:              slash.Validator,
+					InfractionType:         slash.InfractionType,
+					InfractionHeight:       slash.InfractionHeight,
+					JailUtil:               slash.JailUtil.Unix(),
+					SlashAmount:            slash.SlashAmount,
+					ToFeePool:              slash.ToFeePool,
+					Submitter:              slash.Submitter,
+					SubmitterReward:        slash.SubmitterReward,
+					ValidatorsCompensation: vc,
+				}
+				msgNum++
 			}
-			publisher.publish(&slashMsg, slashingTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+			slashData[chainId] = slashDataPerChain
+		}
 
+		slashMsg := SlashMsg{
+			NumOfMsgs: msgNum,
+			Height:    toPublish.Height,
+			Timestamp: toPublish.Timestamp.Unix(),
+			SlashData: slashData,
 		}
+		publisher.publish(&slashMsg, slashingTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
 
-		if cfg.PublishCrossTransfer {
-			var msgNum int
-			crossTransfers := make([]CrossTransfer, 0)
+	}
 
-			for _, crossTransfer := range eventData.CrossTransferData {
-				msgNum++
-				ct := CrossTransfer{
-					TxHash:     crossTransfer.TxHash,
-					ChainId:    crossTransfer.ChainId,
-					RelayerFee: crossTransfer.RelayerFee,
-					Type:       crossTransfer.Type,
-					From:       crossTransfer.From,
-					Denom:      crossTransfer.Denom,
-					Contract:   crossTransfer.Contract,
-					Decimals:   crossTransfer.Decimals,
-				}
-				for _, receive := range crossTransfer.To {
-					ct.To = append(ct.To, CrossReceiver{
-						Addr:   receive.Addr,
-						Amount: receive.Amount,
-					})
-				}
-				crossTransfers = append(crossTransfers, ct)
+	if cfg.PublishCrossTransfer {
+		var msgNum int
+		crossTransfers := make([]CrossTransfer, 0)
+
+		for _, crossTransfer := range eventData.CrossTransferData {
+			msgNum++
+			ct := CrossTransfer{
+				TxHash:     crossTransfer.TxHash,
+				ChainId:    crossTransfer.ChainId,
+				RelayerFee: crossTransfer.RelayerFee,
+				Type:       crossTransfer.Type,
+				From:       crossTransfer.From,
+				Denom:      crossTransfer.Denom,
+				Contract:   crossTransfer.Contract,
+				Decimals:   crossTransfer.Decimals,
 			}
-			crossTransferMsg := CrossTransfers{
-				Num:       msgNum,
-				Height:    toPublish.Height,
-				Timestamp: toPublish.Timestamp.Unix(),
-				Transfers: crossTransfers,
+			for _, receive := range crossTransfer.To {
+				ct.To = append(ct.To, CrossReceiver{
+					Addr:   receive.Addr,
+					Amount: receive.Amount,
+				})
 			}
-			publisher.publish(&crossTransferMsg, crossTransferTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+			crossTransfers = append(crossTransfers, ct)
 		}
+		crossTransferMsg := CrossTransfers{
+			Num:       msgNum,
+			Height:    toPublish.Height,
+			Timestamp: toPublish.Timestamp.Unix(),
+			Transfers: crossTransfers,
+		}
+		publisher.publish(&crossTransferMsg, crossTransferTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+	}
 
-		if cfg.PublishMirror {
-			var msgNum int
-			mirrors := make([]Mirror, 0)
-
-			for _, mirror := range eventData.MirrorData {
-				msgNum++
-				mr := Mirror{
-					TxHash:         mirror.TxHash,
-					ChainId:        mirror.ChainId,
-					Type:           mirror.Type,
-					RelayerFee:     mirror.RelayerFee,
-					Sender:         mirror.Sender,
-					Contract:       mirror.Contract,
-					BEP20Name:      mirror.BEP20Name,
-					BEP20Symbol:    mirror.BEP20Symbol,
-					BEP2Symbol:     mirror.BEP2Symbol,
-					OldTotalSupply: mirror.OldTotalSupply,
-					TotalSupply:    mirror.TotalSupply,
-					Decimals:       mirror.Decimals,
-					Fee:            mirror.Fee,
-				}
-
-				mirrors = append(mirrors, mr)
+	if cfg.PublishMirror {
+		var msgNum int
+		mirrors := make([]Mirror, 0)
+
+		for _, mirror := range eventData.MirrorData {
+			msgNum++
+			mr := Mirror{
+				TxHash:         mirror.TxHash,
+				ChainId:        mirror.ChainId,
+				Type:           mirror.Type,
+				RelayerFee:     mirror.RelayerFee,
+				Sender:         mirror.Sender,
+				Contract:       mirror.Contract,
+				BEP20Name:      mirror.BEP20Name,
+				BEP20Symbol:    mirror.BEP20Symbol,
+				BEP2Symbol:     mirror.BEP2Symbol,
+				OldTotalSupply: mirror.OldTotalSupply,
+				TotalSupply:    mirror.TotalSupply,
+				Decimals:       mirror.Decimals,
+				Fee:            mirror.Fee,
 			}
 
-			mirrorsMsg := Mirrors{
-				Num:       msgNum,
-				Height:    toPublish.Height,
-				Timestamp: toPublish.Timestamp.Unix(),
-				Mirrors:   mirrors,
-			}
-			publisher.publish(&mirrorsMsg, mirrorTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+			mirrors = append(mirrors, mr)
+		}
 
+		mirrorsMsg := Mirrors{
+			Num:       msgNum,
+			Height:    toPublish.Height,
+			Timestamp: toPublish.Timestamp.Unix(),
+			Mirrors:   mirrors,
 		}
+		publisher.publish(&mirrorsMsg, mirrorTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
 
-		if cfg.PublishBreatheBlock && toPublish.IsBreatheBlock {
-			breatheBlockMsg := BreatheBlockMsg{
-				Height:    toPublish.Height,
-				Timestamp: toPublish.Timestamp.UnixNano(),
-			}
-			publisher.publish(&breatheBlockMsg, breatheBlockTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+	}
+
+	if cfg.PublishBreatheBlock && toPublish.IsBreatheBlock {
+		breatheBlockMsg := BreatheBlockMsg{
+			Height:    toPublish.Height,
+			Timestamp: toPublish.Timestamp.UnixNano(),
 		}
+		publisher.publish(&breatheBlockMsg, breatheBlockTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
 	}
 }
 
@@ -437,6 +447,7 @@ func Publish(
 	metrics *Metrics,
 	Logger tmlog.Logger,
 	cfg *config.PublicationConfig,
+	bookSequencer *BookSequencer,
 	ToPublishCh <-chan BlockInfoToPublish) {
 	var lastPublishedTime time.Time
 	for marketData := range ToPublishCh {
@@ -446,6 +457,11 @@ func Publish(
 		}
 
 		publishTotalTime := Timer(Logger, fmt.Sprintf("publish market data, height=%d", marketData.height), func() {
+			transactor, transactional := publisher.(HeightTransactor)
+			if transactional {
+				transactor.BeginHeight(marketData.height)
+			}
+
 			// Implementation note: publication order are important here,
 			// DEX query service team relies on the fact that we publish orders before trades so that
 			// they can assign buyer/seller address into trade before persist into DB
@@ -469,14 +485,16 @@ func Publish(
 
 			ordersToPublish := append(opensToPublish, closedToPublish...)
 
+			watchList := getWatchList()
+
 			if cfg.PublishOrderUpdates {
 				duration := Timer(Logger, "publish all orders", func() {
 					publishExecutionResult(
 						publisher,
 						marketData.height,
 						marketData.timestamp,
-						ordersToPublish,
-						marketData.tradesToPublish,
+						watchList.filterOrders(ordersToPublish),
+						watchList.filterTrades(marketData.tradesToPublish),
 						marketData.proposalsToPublish,
 						marketData.stakeUpdates)
 				})
@@ -501,8 +519,14 @@ func Publish(
 
 			if cfg.PublishOrderBook {
 				var changedPrices = make(orderPkg.ChangedPriceLevelsMap)
+				snapshot := marketData.bookSnapshot
 				duration := Timer(Logger, "prepare order books to publish", func() {
-					changedPrices = filterChangedOrderBooksByOrders(ordersToPublish, marketData.latestPricesLevels)
+					if snapshot {
+						changedPrices = marketData.latestPricesLevels
+					} else {
+						changedPrices = filterChangedOrderBooksByOrders(ordersToPublish, marketData.latestPricesLevels)
+					}
+					changedPrices = watchList.filterOrderBooks(changedPrices)
 				})
 				if metrics != nil {
 					numOfChangedPrices := 0
@@ -515,7 +539,7 @@ func Publish(
 				}
 
 				duration = Timer(Logger, "publish changed order books", func() {
-					publishOrderBookDelta(publisher, marketData.height, marketData.timestamp, changedPrices)
+					publishOrderBookDelta(publisher, marketData.height, marketData.timestamp, changedPrices, bookSequencer, snapshot)
 				})
 
 				if metrics != nil {
@@ -561,6 +585,27 @@ func Publish(
 				}
 			}
 
+			if cfg.PublishKline {
+				Timer(Logger, "publish klines", func() {
+					publishKlines(publisher, marketData.height, marketData.timestamp, marketData.klines)
+				})
+			}
+
+			if marketData.events != nil {
+				publishEvent(publisher, cfg, marketData.events)
+			}
+
+			if transactional {
+				if err := transactor.CommitHeight(marketData.height); err != nil {
+					// the node hasn't committed the block yet, it publishes the height again after restart
+					Logger.Error("failed to commit the messages of the height", "height", marketData.height, "err", err)
+					panic(err)
+				}
+			}
+			if marketData.committed != nil {
+				close(marketData.committed)
+			}
+
 			if metrics != nil {
 				metrics.PublicationHeight.Set(float64(marketData.height))
 				blockInterval := time.Since(lastPublishedTime)
@@ -641,7 +686,8 @@ func publishAccount(publisher MarketDataPublisher, height int64, timestamp int64
 	publisher.publish(&accountsMsg, accountsTpe, height, timestamp)
 }
 
-func publishOrderBookDelta(publisher MarketDataPublisher, height int64, timestamp int64, changedPriceLevels orderPkg.ChangedPriceLevelsMap) {
+func publishOrderBookDelta(publisher MarketDataPublisher, height int64, timestamp int64, changedPriceLevels orderPkg.ChangedPriceLevelsMap,
+	sequencer *BookSequencer, snapshot bool) {
 	var deltas []OrderBookDelta
 	for pair, pls := range changedPriceLevels {
 		buys := make([]PriceLevel, len(pls.Buys))
@@ -656,12 +702,14 @@ func publishOrderBookDelta(publisher MarketDataPublisher, height int64, timestam
 			sells[idx] = PriceLevel{price, qty}
 			idx++
 		}
-		deltas = append(deltas, OrderBookDelta{pair, buys, sells})
+		deltas = append(deltas, OrderBookDelta{pair, buys, sells, sequencer.Next(pair), snapshot})
 	}
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].Symbol < deltas[j].Symbol })
 
 	books := Books{height, timestamp, len(deltas), deltas}
 
 	publisher.publish(&books, booksTpe, height, timestamp)
+	sequencer.Save()
 }
 
 func publishBlockFee(publisher MarketDataPublisher, height, timestamp int64, blockFee BlockFee) {
@@ -682,6 +730,14 @@ func publishSideProposals(publisher MarketDataPublisher, height, timestamp int64
 	}
 }
 
+func publishKlines(publisher MarketDataPublisher, height, timestamp int64, klines *Klines) {
+	if klines != nil {
+		klines.Height = height
+		klines.Timestamp = timestamp
+		publisher.publish(klines, klineTpe, height, timestamp)
+	}
+}
+
 func publishBlock(publisher MarketDataPublisher, height, timestamp int64, block *Block) {
 	if block != nil {
 		publisher.publish(block, blockTpe, height, timestamp)
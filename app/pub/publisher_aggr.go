@@ -10,6 +10,25 @@ func (publisher *AggregatedMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe m
 	}
 }
 
+func (publisher *AggregatedMarketDataPublisher) BeginHeight(height int64) {
+	for _, pub := range publisher.publishers {
+		if transactor, ok := pub.(HeightTransactor); ok {
+			transactor.BeginHeight(height)
+		}
+	}
+}
+
+func (publisher *AggregatedMarketDataPublisher) CommitHeight(height int64) (err error) {
+	for _, pub := range publisher.publishers {
+		if transactor, ok := pub.(HeightTransactor); ok {
+			if commitErr := transactor.CommitHeight(height); commitErr != nil && err == nil {
+				err = commitErr
+			}
+		}
+	}
+	return err
+}
+
 func (publisher *AggregatedMarketDataPublisher) Stop() {
 	for _, pub := range publisher.publishers {
 		pub.Stop()
@@ -11,10 +11,10 @@ import (
 	"github.com/Shopify/sarama"
 	prometheusmetrics "github.com/deathowl/go-metrics-prometheus"
 	"github.com/eapache/go-resiliency/breaker"
-	"github.com/linkedin/goavro"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
 )
 
@@ -24,23 +24,20 @@ const (
 )
 
 type KafkaMarketDataPublisher struct {
-	booksCodec            *goavro.Codec
-	accountCodec          *goavro.Codec
-	executionResultsCodec *goavro.Codec
-	blockFeeCodec         *goavro.Codec
-	transfersCodec        *goavro.Codec
-	blockCodec            *goavro.Codec
-	stakingCodec          *goavro.Codec
-	distributionCodec     *goavro.Codec
-	slashingCodec         *goavro.Codec
-	crossTransferCodec    *goavro.Codec
-	mirrorCodec           *goavro.Codec
-	sideProposalCodec     *goavro.Codec
-	breatheBlockCodec     *goavro.Codec
+	avroCodecs
 
 	failFast         bool
 	essentialLogPath string                         // the path (default to db dir) we write essential file to make up data on kafka error
 	producers        map[string]sarama.SyncProducer // topic -> producer
+	schemaIds        map[msgType]int32              // nil if the schema registry is not configured
+
+	// exactly-once publishing, all the topics share one transactional producer
+	txnProducer sarama.SyncProducer
+	txnBrokers  string
+	progress    *PublicationProgress // nil if exactly-once publishing is disabled
+	txnHeight   int64                // the height of the current transaction
+	txnSkipped  bool                 // the messages of the current height have been committed before restart
+	txnErr      error                // the first error in the current transaction
 }
 
 func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config, err error) {
@@ -84,10 +81,19 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	// Refer: https://github.com/Shopify/sarama/issues/718
 	config.Net.MaxOpenRequests = 1
 
+	if Cfg.KafkaExactlyOnce {
+		// the brokers drop the duplicates sent by the retries, and expose the messages of a transaction to the
+		// read_committed consumers only after it's committed
+		config.Producer.Idempotent = true
+		if config.Producer.Transaction.ID = Cfg.KafkaTransactionalId; config.Producer.Transaction.ID == "" {
+			config.Producer.Transaction.ID = config.ClientID
+		}
+	}
+
 	if Cfg.PublishOrderUpdates {
 		if _, ok := publisher.producers[Cfg.OrderUpdatesTopic]; !ok {
 			publisher.producers[Cfg.OrderUpdatesTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.OrderUpdatesKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.OrderUpdatesKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create order updates producer", "err", err)
@@ -97,7 +103,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishOrderBook {
 		if _, ok := publisher.producers[Cfg.OrderBookTopic]; !ok {
 			publisher.producers[Cfg.OrderBookTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.OrderBookKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.OrderBookKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create order book producer", "err", err)
@@ -107,7 +113,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishAccountBalance {
 		if _, ok := publisher.producers[Cfg.AccountBalanceTopic]; !ok {
 			publisher.producers[Cfg.AccountBalanceTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.AccountBalanceKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.AccountBalanceKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create account balance producer", "err", err)
@@ -117,7 +123,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishBlockFee {
 		if _, ok := publisher.producers[Cfg.BlockFeeTopic]; !ok {
 			publisher.producers[Cfg.BlockFeeTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.BlockFeeKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.BlockFeeKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create blockfee producer", "err", err)
@@ -127,7 +133,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishTransfer {
 		if _, ok := publisher.producers[Cfg.TransferTopic]; !ok {
 			publisher.producers[Cfg.TransferTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.TransferKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.TransferKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create transfers producer", "err", err)
@@ -137,7 +143,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishBlock {
 		if _, ok := publisher.producers[Cfg.BlockTopic]; !ok {
 			publisher.producers[Cfg.BlockTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.BlockKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.BlockKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create blocks producer", "err", err)
@@ -147,7 +153,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishStaking {
 		if _, ok := publisher.producers[Cfg.StakingTopic]; !ok {
 			publisher.producers[Cfg.StakingTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.StakingKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.StakingKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create staking producer", "err", err)
@@ -157,7 +163,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishDistributeReward {
 		if _, ok := publisher.producers[Cfg.DistributeRewardTopic]; !ok {
 			publisher.producers[Cfg.DistributeRewardTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.DistributeRewardKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.DistributeRewardKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create distribution producer", "err", err)
@@ -167,7 +173,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishSlashing {
 		if _, ok := publisher.producers[Cfg.SlashingTopic]; !ok {
 			publisher.producers[Cfg.SlashingTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.SlashingKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.SlashingKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create slashing producer", "err", err)
@@ -177,7 +183,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishCrossTransfer {
 		if _, ok := publisher.producers[Cfg.CrossTransferTopic]; !ok {
 			publisher.producers[Cfg.CrossTransferTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.CrossTransferKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.CrossTransferKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create crossTransfer producer", "err", err)
@@ -187,7 +193,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishMirror {
 		if _, ok := publisher.producers[Cfg.MirrorTopic]; !ok {
 			publisher.producers[Cfg.MirrorTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.MirrorKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.MirrorKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create mirror producer", "err", err)
@@ -197,7 +203,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishSideProposal {
 		if _, ok := publisher.producers[Cfg.SideProposalTopic]; !ok {
 			publisher.producers[Cfg.SideProposalTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.SideProposalKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.SideProposalKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create crossTransfer producer", "err", err)
@@ -207,13 +213,23 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if Cfg.PublishBreatheBlock {
 		if _, ok := publisher.producers[Cfg.BreatheBlockTopic]; !ok {
 			publisher.producers[Cfg.BreatheBlockTopic], err =
-				publisher.connectWithRetry(strings.Split(Cfg.BreatheBlockKafka, KafkaBrokerSep), config)
+				publisher.connect(Cfg.BreatheBlockKafka, config)
 		}
 		if err != nil {
 			Logger.Error("failed to create breathe block producer", "err", err)
 			return
 		}
 	}
+	if Cfg.PublishKline {
+		if _, ok := publisher.producers[Cfg.KlineTopic]; !ok {
+			publisher.producers[Cfg.KlineTopic], err =
+				publisher.connect(Cfg.KlineKafka, config)
+		}
+		if err != nil {
+			Logger.Error("failed to create kline producer", "err", err)
+			return
+		}
+	}
 	return
 }
 
@@ -222,6 +238,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 // 2. timestamp of message
 // 3. type of value (multiple types of messages can be published for one kafka topic)
 // 4. value's encoding schema version.
+// Value is prefixed with the schema id in the wire format of the schema registry if it's configured.
 func (publisher *KafkaMarketDataPublisher) prepareMessage(
 	topic string,
 	msgId string,
@@ -234,17 +251,33 @@ func (publisher *KafkaMarketDataPublisher) prepareMessage(
 		Key:       sarama.StringEncoder(fmt.Sprintf("%s_%d_%s_%d", msgId, timeStamp, msgTpe.String(), latestSchemaVersions[msgTpe])),
 		Value:     sarama.ByteEncoder(message),
 	}
+	if schemaId, ok := publisher.schemaIds[msgTpe]; ok {
+		msg.Value = sarama.ByteEncoder(ToWireFormat(schemaId, message))
+	}
 
 	return msg
 }
 
 func (publisher *KafkaMarketDataPublisher) publish(avroMessage AvroOrJsonMsg, tpe msgType, height, timestamp int64) {
 	topic := publisher.resolveTopic(tpe)
+	if publisher.progress != nil {
+		if height != publisher.txnHeight {
+			Logger.Error("failed to publish out of the transaction of the height", "topic", topic, "msg", avroMessage.String())
+			return
+		}
+		if publisher.txnSkipped || publisher.txnErr != nil {
+			return
+		}
+	}
 
 	if msg, err := publisher.marshal(avroMessage, tpe); err == nil {
 		kafkaMsg := publisher.prepareMessage(topic, strconv.FormatInt(height, 10), timestamp, tpe, msg)
 		if partition, offset, err := publisher.publishWithRetry(kafkaMsg, topic); err == nil {
 			Logger.Info("published", "topic", topic, "msg", avroMessage.String(), "offset", offset, "partition", partition)
+		} else if publisher.progress != nil {
+			// the transaction is aborted, and the height is published again after restart
+			Logger.Error("failed to publish in the transaction", "topic", topic, "msg", avroMessage.String(), "err", err)
+			publisher.txnErr = err
 		} else {
 			Logger.Error("failed to publish, tring to log essential message", "topic", topic, "msg", avroMessage.String(), "err", err)
 			if essMsg, ok := avroMessage.(EssMsg); ok {
@@ -259,6 +292,40 @@ func (publisher *KafkaMarketDataPublisher) publish(avroMessage AvroOrJsonMsg, tp
 	}
 }
 
+// BeginHeight begins the transaction of the height, the messages of the height committed before restart are skipped
+func (publisher *KafkaMarketDataPublisher) BeginHeight(height int64) {
+	if publisher.progress == nil {
+		return
+	}
+	publisher.txnHeight, publisher.txnErr = height, nil
+	if publisher.txnSkipped = publisher.progress.IsCommitted(height); publisher.txnSkipped {
+		Logger.Info("skip the height committed before restart", "height", height)
+		return
+	}
+	publisher.txnErr = publisher.txnProducer.BeginTxn()
+}
+
+// CommitHeight commits the transaction of the height and records the height committed, or aborts the transaction
+// if any message of the height failed
+func (publisher *KafkaMarketDataPublisher) CommitHeight(height int64) error {
+	if publisher.progress == nil || publisher.txnSkipped {
+		return nil
+	}
+	err := publisher.txnErr
+	if err == nil {
+		err = publisher.txnProducer.CommitTxn()
+	}
+	if err != nil {
+		if abortErr := publisher.txnProducer.AbortTxn(); abortErr != nil {
+			Logger.Error("failed to abort the transaction", "height", height, "err", abortErr)
+		}
+		return fmt.Errorf("failed to commit the transaction of height %d: %v", height, err)
+	}
+	publisher.progress.Commit(height)
+	Logger.Info("committed the transaction", "height", height)
+	return nil
+}
+
 func (publisher KafkaMarketDataPublisher) publishEssentialMsg(essMsg EssMsg, topic string, tpe msgType, height, timestamp int64) {
 	// First, publish an empty copy to make sure downstream service not hanging
 	if msg, err := publisher.marshal(essMsg.EmptyCopy(), tpe); err == nil {
@@ -311,23 +378,68 @@ func (publisher KafkaMarketDataPublisher) resolveTopic(tpe msgType) (topic strin
 		topic = Cfg.SideProposalTopic
 	case breatheBlockTpe:
 		topic = Cfg.BreatheBlockTopic
+	case klineTpe:
+		topic = Cfg.KlineTopic
 	}
 	return
 }
 
+// registerSchemas resolves the schema ids of the msg types published to the topics with producers
+func (publisher *KafkaMarketDataPublisher) registerSchemas() (err error) {
+	topics := make(map[msgType]string)
+	for tpe := range latestSchemas {
+		if topic := publisher.resolveTopic(tpe); topic != "" {
+			if _, ok := publisher.producers[topic]; ok {
+				topics[tpe] = topic
+			}
+		}
+	}
+	registry := NewSchemaRegistry(Cfg.SchemaRegistryUrl)
+	if publisher.schemaIds, err = registry.RegisterSchemas(topics, Cfg.SchemaRegistryAutoRegister); err != nil {
+		return err
+	}
+	Logger.Info("resolved schemas in schema registry", "url", Cfg.SchemaRegistryUrl, "ids", publisher.schemaIds)
+	return nil
+}
+
 func (publisher *KafkaMarketDataPublisher) Stop() {
 	Logger.Debug("start to stop KafkaMarketDataPublisher")
+	closed := make(map[sarama.SyncProducer]bool)
 	for topic, producer := range publisher.producers {
 		// nil check because this method would be called when we failed to create producer
-		if producer != nil {
+		if producer != nil && !closed[producer] {
+			closed[producer] = true
 			if err := producer.Close(); err != nil {
 				Logger.Error("failed to stop producer for topic", "topic", topic, "err", err)
 			}
 		}
 	}
+	if publisher.progress != nil {
+		publisher.progress.Close()
+	}
 	Logger.Debug("finished stop KafkaMarketDataPublisher")
 }
 
+// connect creates the producer of a topic, all the topics share one producer in exactly-once mode, so they should
+// be on the same brokers
+func (publisher *KafkaMarketDataPublisher) connect(brokers string, config *sarama.Config) (sarama.SyncProducer, error) {
+	if !Cfg.KafkaExactlyOnce {
+		return publisher.connectWithRetry(strings.Split(brokers, KafkaBrokerSep), config)
+	}
+	if publisher.txnProducer != nil {
+		if brokers != publisher.txnBrokers {
+			return nil, fmt.Errorf("all the topics should be on the same kafka brokers to publish exactly once, got %q and %q",
+				publisher.txnBrokers, brokers)
+		}
+		return publisher.txnProducer, nil
+	}
+	producer, err := publisher.connectWithRetry(strings.Split(brokers, KafkaBrokerSep), config)
+	if err == nil {
+		publisher.txnProducer, publisher.txnBrokers = producer, brokers
+	}
+	return producer, err
+}
+
 // endlessly retry on retriable errors, the abnormal situation should be reported by prometheus alarm
 func (publisher *KafkaMarketDataPublisher) connectWithRetry(
 	hostports []string,
@@ -373,77 +485,6 @@ func (publisher *KafkaMarketDataPublisher) isRetryableErr(err error) bool {
 		strings.Contains(err.Error(), "connection refused")
 }
 
-func (publisher *KafkaMarketDataPublisher) marshal(msg AvroOrJsonMsg, tpe msgType) ([]byte, error) {
-	native := msg.ToNativeMap()
-	var codec *goavro.Codec
-	switch tpe {
-	case accountsTpe:
-		codec = publisher.accountCodec
-	case booksTpe:
-		codec = publisher.booksCodec
-	case executionResultTpe:
-		codec = publisher.executionResultsCodec
-	case blockFeeTpe:
-		codec = publisher.blockFeeCodec
-	case transferTpe:
-		codec = publisher.transfersCodec
-	case blockTpe:
-		codec = publisher.blockCodec
-	case stakingTpe:
-		codec = publisher.stakingCodec
-	case distributionTpe:
-		codec = publisher.distributionCodec
-	case slashingTpe:
-		codec = publisher.slashingCodec
-	case crossTransferTpe:
-		codec = publisher.crossTransferCodec
-	case mirrorTpe:
-		codec = publisher.mirrorCodec
-	case sideProposalType:
-		codec = publisher.sideProposalCodec
-	case breatheBlockTpe:
-		codec = publisher.breatheBlockCodec
-	default:
-		return nil, fmt.Errorf("doesn't support marshal kafka msg tpe: %s", tpe.String())
-	}
-	bb, err := codec.BinaryFromNative(nil, native)
-	if err != nil {
-		Logger.Error("failed to serialize message", "msg", msg, "err", err)
-	}
-	return bb, err
-}
-
-func (publisher *KafkaMarketDataPublisher) initAvroCodecs() (err error) {
-	if publisher.executionResultsCodec, err = goavro.NewCodec(executionResultSchema); err != nil {
-		return err
-	} else if publisher.booksCodec, err = goavro.NewCodec(booksSchema); err != nil {
-		return err
-	} else if publisher.accountCodec, err = goavro.NewCodec(accountSchema); err != nil {
-		return err
-	} else if publisher.blockFeeCodec, err = goavro.NewCodec(blockfeeSchema); err != nil {
-		return err
-	} else if publisher.transfersCodec, err = goavro.NewCodec(transfersSchema); err != nil {
-		return err
-	} else if publisher.blockCodec, err = goavro.NewCodec(blockDatasSchema); err != nil {
-		return err
-	} else if publisher.stakingCodec, err = goavro.NewCodec(stakingSchema); err != nil {
-		return err
-	} else if publisher.distributionCodec, err = goavro.NewCodec(distributionSchema); err != nil {
-		return err
-	} else if publisher.slashingCodec, err = goavro.NewCodec(slashingSchema); err != nil {
-		return err
-	} else if publisher.crossTransferCodec, err = goavro.NewCodec(crossTransferSchema); err != nil {
-		return err
-	} else if publisher.mirrorCodec, err = goavro.NewCodec(mirrorSchema); err != nil {
-		return err
-	} else if publisher.sideProposalCodec, err = goavro.NewCodec(sideProposalsSchema); err != nil {
-		return err
-	} else if publisher.breatheBlockCodec, err = goavro.NewCodec(breatheBlockSchema); err != nil {
-		return err
-	}
-	return nil
-}
-
 func NewKafkaMarketDataPublisher(
 	logger log.Logger, dbDir string, failFast bool) (publisher *KafkaMarketDataPublisher) {
 
@@ -476,6 +517,25 @@ func NewKafkaMarketDataPublisher(
 		go pClient.UpdatePrometheusMetrics()
 	}
 
+	if Cfg.KafkaExactlyOnce {
+		db, err := dbm.NewGoLevelDB(PublicationProgressDB, dbDir)
+		if err != nil {
+			logger.Error("failed to open publication progress db", "err", err)
+			panic(err)
+		}
+		if publisher.progress, err = NewPublicationProgress(db); err != nil {
+			logger.Error("failed to load publication progress", "err", err)
+			panic(err)
+		}
+	}
+
+	if Cfg.SchemaRegistryUrl != "" {
+		if err := publisher.registerSchemas(); err != nil {
+			logger.Error("failed to register schemas", "err", err)
+			panic(err)
+		}
+	}
+
 	if err := common.EnsureDir(publisher.essentialLogPath, 0755); err != nil {
 		logger.Error("failed to create essential log path", "err", err)
 	}
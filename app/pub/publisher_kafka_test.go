@@ -0,0 +1,73 @@
+package pub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/app/config"
+)
+
+func newTransactionalPublisher(t *testing.T, db dbm.DB) (*KafkaMarketDataPublisher, *mocks.SyncProducer) {
+	saramaCfg := mocks.NewTestConfig()
+	saramaCfg.Version = sarama.V2_1_0_0
+	saramaCfg.Producer.RequiredAcks = sarama.WaitForAll
+	saramaCfg.Net.MaxOpenRequests = 1
+	saramaCfg.Producer.Idempotent = true
+	saramaCfg.Producer.Transaction.ID = "test"
+	producer := mocks.NewSyncProducer(t, saramaCfg)
+
+	progress, err := NewPublicationProgress(db)
+	require.NoError(t, err)
+	publisher := &KafkaMarketDataPublisher{
+		producers:   map[string]sarama.SyncProducer{"blockfee": producer},
+		txnProducer: producer,
+		progress:    progress,
+	}
+	require.NoError(t, publisher.initAvroCodecs())
+	return publisher, producer
+}
+
+func TestKafkaMarketDataPublisher_Transaction(t *testing.T) {
+	origCfg := Cfg
+	defer func() { Cfg = origCfg }()
+	Cfg = &config.PublicationConfig{KafkaVersion: "2.1.0", KafkaExactlyOnce: true, BlockFeeTopic: "blockfee"}
+
+	db := dbm.NewMemDB()
+	publisher, producer := newTransactionalPublisher(t, db)
+
+	publisher.BeginHeight(1)
+	producer.ExpectSendMessageAndSucceed()
+	publisher.publish(&BlockFee{Height: 1, Fee: "BNB:1"}, blockFeeTpe, 1, 1000)
+	require.NoError(t, publisher.CommitHeight(1))
+
+	// a failed message aborts the transaction, and the height isn't recorded as committed
+	publisher.BeginHeight(2)
+	producer.ExpectSendMessageAndFail(errors.New("record too large"))
+	publisher.publish(&BlockFee{Height: 2, Fee: "BNB:1"}, blockFeeTpe, 2, 2000)
+	// the rest of the height isn't sent once the transaction fails
+	publisher.publish(&BlockFee{Height: 2, Fee: "BNB:2"}, blockFeeTpe, 2, 2000)
+	require.Error(t, publisher.CommitHeight(2))
+	require.NoError(t, producer.Close())
+
+	// the node restarts and replays the blocks from height 1, the committed height 1 is skipped and height 2 is
+	// published again
+	publisher, producer = newTransactionalPublisher(t, db)
+	publisher.BeginHeight(1)
+	publisher.publish(&BlockFee{Height: 1, Fee: "BNB:1"}, blockFeeTpe, 1, 1000)
+	require.NoError(t, publisher.CommitHeight(1))
+	publisher.BeginHeight(2)
+	producer.ExpectSendMessageAndSucceed()
+	publisher.publish(&BlockFee{Height: 2, Fee: "BNB:1"}, blockFeeTpe, 2, 2000)
+	require.NoError(t, publisher.CommitHeight(2))
+	require.NoError(t, producer.Close())
+
+	progress, err := NewPublicationProgress(db)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), progress.CommittedHeight)
+}
@@ -0,0 +1,145 @@
+package pub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// PublicationLogPublisher writes the published messages in avro into the publication log, so they can be
+// published again by ReplayPublicationLog
+type PublicationLogPublisher struct {
+	avroCodecs
+	log *PublicationLog
+}
+
+func NewPublicationLogPublisher(dir string, maxSegmentSizeMB int, maxSegments int) (*PublicationLogPublisher, error) {
+	log, err := NewPublicationLog(dir, int64(maxSegmentSizeMB)*1024*1024, maxSegments)
+	if err != nil {
+		return nil, err
+	}
+	publisher := &PublicationLogPublisher{log: log}
+	if err := publisher.initAvroCodecs(); err != nil {
+		return nil, err
+	}
+	return publisher, nil
+}
+
+func (publisher *PublicationLogPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64) {
+	bz, err := publisher.marshal(msg, tpe)
+	if err != nil {
+		Logger.Error("failed to write publication log", "height", height, "msg", msg.String(), "err", err)
+		return
+	}
+	record := PublicationLogRecord{
+		Height:    height,
+		Timestamp: timestamp,
+		Topic:     tpe.String(),
+		Version:   latestSchemaVersions[tpe],
+		Data:      bz,
+	}
+	if err := publisher.log.Append(record); err != nil {
+		Logger.Error("failed to write publication log", "height", height, "msg", msg.String(), "err", err)
+		return
+	}
+	if err := publisher.log.Flush(); err != nil {
+		Logger.Error("failed to flush publication log", "height", height, "err", err)
+	}
+}
+
+func (publisher *PublicationLogPublisher) Stop() {
+	if err := publisher.log.Close(); err != nil {
+		Logger.Error("failed to close publication log", "err", err)
+	}
+}
+
+func msgTypeOf(name string) (msgType, bool) {
+	for tpe := range latestSchemaVersions {
+		if tpe.String() == name {
+			return tpe, true
+		}
+	}
+	return 0, false
+}
+
+// replayedMsg is a message read back from the publication log
+type replayedMsg struct {
+	topic  string
+	height int64
+	native map[string]interface{}
+}
+
+func (msg *replayedMsg) String() string {
+	return fmt.Sprintf("replayed %s at height: %d", msg.topic, msg.height)
+}
+
+func (msg *replayedMsg) ToNativeMap() map[string]interface{} {
+	return msg.native
+}
+
+// ReplayPublicationLog publishes the messages of the heights in [fromHeight, toHeight] in the publication
+// log under dir again in the order they were published, and returns the number of the messages published
+func ReplayPublicationLog(dir string, fromHeight, toHeight int64, publisher MarketDataPublisher) (int, error) {
+	var codecs avroCodecs
+	if err := codecs.initAvroCodecs(); err != nil {
+		return 0, err
+	}
+	num := 0
+	err := ReadPublicationLog(dir, fromHeight, toHeight, func(record PublicationLogRecord) error {
+		tpe, ok := msgTypeOf(record.Topic)
+		if !ok {
+			return fmt.Errorf("unknown topic %s at height %d", record.Topic, record.Height)
+		}
+		if record.Version != latestSchemaVersions[tpe] {
+			return fmt.Errorf("%s at height %d is in schema version %d, but the current version is %d",
+				record.Topic, record.Height, record.Version, latestSchemaVersions[tpe])
+		}
+		codec, err := codecs.codec(tpe)
+		if err != nil {
+			return err
+		}
+		native, _, err := codec.NativeFromBinary(record.Data)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s at height %d: %v", record.Topic, record.Height, err)
+		}
+		nativeMap, ok := native.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s at height %d is not a record", record.Topic, record.Height)
+		}
+		publisher.publish(&replayedMsg{record.Topic, record.Height, nativeMap}, tpe, record.Height, record.Timestamp)
+		num++
+		return nil
+	})
+	return num, err
+}
+
+// WriterMarketDataPublisher writes the messages as json lines of {"topic", "height", "timestamp", "data"}
+type WriterMarketDataPublisher struct {
+	mtx    sync.Mutex
+	writer io.Writer
+}
+
+func NewWriterMarketDataPublisher(writer io.Writer) *WriterMarketDataPublisher {
+	return &WriterMarketDataPublisher{writer: writer}
+}
+
+func (publisher *WriterMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64) {
+	publisher.mtx.Lock()
+	defer publisher.mtx.Unlock()
+	bz, err := json.Marshal(map[string]interface{}{
+		"topic":     tpe.String(),
+		"height":    height,
+		"timestamp": timestamp,
+		"data":      msg.ToNativeMap(),
+	})
+	if err != nil {
+		Logger.Error("failed to publish msg", "err", err, "height", height, "msg", msg.String())
+		return
+	}
+	if _, err := publisher.writer.Write(append(bz, '\n')); err != nil {
+		Logger.Error("failed to publish msg", "err", err, "height", height, "msg", msg.String())
+	}
+}
+
+func (publisher *WriterMarketDataPublisher) Stop() {}
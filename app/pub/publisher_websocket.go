@@ -0,0 +1,424 @@
+package pub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	wsWriteTimeout       = 10 * time.Second
+	defaultWSClientQueue = 100
+	defaultWSMaxClients  = 100
+)
+
+// WebSocketMarketDataPublisher serves the published messages to websocket clients directly from the node.
+//
+// A client subscribes to the topics, which are the names of the message types (e.g. ExecutionResults,
+// Books, Accounts), by sending:
+//
+//	{"method": "subscribe", "topic": "ExecutionResults", "symbols": ["XYZ-000_BNB"], "addresses": ["bnb1..."]}
+//
+// and cancels the subscription by {"method": "unsubscribe", "topic": "ExecutionResults"}. The symbols and
+// the addresses are optional filters of the trades, orders, books, accounts, transfers and klines, the
+// other topics are not filtered. Each message is sent as {"topic", "height", "timestamp", "data"} in json.
+// A client that can't keep up with the messages is disconnected. The browsers can only connect from the allowed
+// origins, and the clients beyond maxClients are rejected.
+type WebSocketMarketDataPublisher struct {
+	logger      tmlog.Logger
+	listener    net.Listener
+	server      *http.Server
+	upgrader    websocket.Upgrader
+	clientQueue int
+	maxClients  int
+
+	mtx     sync.RWMutex
+	clients map[*wsClient]struct{}
+}
+
+type wsRequest struct {
+	Method    string   `json:"method"`
+	Topic     string   `json:"topic"`
+	Symbols   []string `json:"symbols"`
+	Addresses []string `json:"addresses"`
+}
+
+type wsResponse struct {
+	Result string `json:"result,omitempty"`
+	Topic  string `json:"topic,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type wsMessage struct {
+	Topic     string        `json:"topic"`
+	Height    int64         `json:"height"`
+	Timestamp int64         `json:"timestamp"`
+	Data      AvroOrJsonMsg `json:"data"`
+}
+
+// wsFilter is empty if the topic is not filtered
+type wsFilter struct {
+	symbols   map[string]bool
+	addresses map[string]bool // both bech32 and the raw bytes of the addresses
+}
+
+func (f *wsFilter) empty() bool {
+	return len(f.symbols) == 0 && len(f.addresses) == 0
+}
+
+func (f *wsFilter) matchSymbol(symbol string) bool {
+	return len(f.symbols) == 0 || f.symbols[symbol]
+}
+
+func (f *wsFilter) matchAddress(addrs ...string) bool {
+	if len(f.addresses) == 0 {
+		return true
+	}
+	for _, addr := range addrs {
+		if f.addresses[addr] {
+			return true
+		}
+	}
+	return false
+}
+
+type wsClient struct {
+	conn *websocket.Conn
+	send chan []byte
+
+	mtx     sync.RWMutex
+	filters map[string]*wsFilter // topic -> filter
+}
+
+func (c *wsClient) filter(topic string) *wsFilter {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.filters[topic]
+}
+
+func NewWebSocketMarketDataPublisher(logger tmlog.Logger, address string, clientQueue int, allowedOrigins []string,
+	maxClients int) (*WebSocketMarketDataPublisher, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s for websocket publisher: %v", address, err)
+	}
+	if clientQueue <= 0 {
+		clientQueue = defaultWSClientQueue
+	}
+	if maxClients <= 0 {
+		maxClients = defaultWSMaxClients
+	}
+	publisher := &WebSocketMarketDataPublisher{
+		logger:      logger.With("publisher", "websocket"),
+		listener:    listener,
+		upgrader:    websocket.Upgrader{CheckOrigin: checkOrigin(allowedOrigins)},
+		clientQueue: clientQueue,
+		maxClients:  maxClients,
+		clients:     make(map[*wsClient]struct{}),
+	}
+	publisher.server = &http.Server{Handler: http.HandlerFunc(publisher.serveWS)}
+	go func() {
+		if err := publisher.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			publisher.logger.Error("websocket publisher stopped serving", "err", err)
+		}
+	}()
+	publisher.logger.Info("websocket publisher started", "address", listener.Addr().String())
+	return publisher, nil
+}
+
+// checkOrigin allows the requests from the origins, or from the same origin only if there is none. The requests
+// without an origin are not sent by the browsers and are always allowed.
+func checkOrigin(origins []string) func(r *http.Request) bool {
+	if len(origins) == 0 {
+		// the same origin check of the websocket package
+		return nil
+	}
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		if origin == "*" {
+			return func(r *http.Request) bool { return true }
+		}
+		allowed[strings.ToLower(origin)] = true
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || allowed[strings.ToLower(origin)]
+	}
+}
+
+// Addr returns the address the publisher listens on
+func (publisher *WebSocketMarketDataPublisher) Addr() net.Addr {
+	return publisher.listener.Addr()
+}
+
+func (publisher *WebSocketMarketDataPublisher) serveWS(w http.ResponseWriter, r *http.Request) {
+	if publisher.numClients() >= publisher.maxClients {
+		http.Error(w, "too many websocket clients", http.StatusServiceUnavailable)
+		return
+	}
+	conn, err := publisher.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		publisher.logger.Debug("failed to upgrade websocket connection", "err", err)
+		return
+	}
+	client := &wsClient{
+		conn:    conn,
+		send:    make(chan []byte, publisher.clientQueue),
+		filters: make(map[string]*wsFilter),
+	}
+	publisher.mtx.Lock()
+	// the clients may be connected concurrently after the check above
+	if len(publisher.clients) >= publisher.maxClients {
+		publisher.mtx.Unlock()
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too many websocket clients"), time.Now().Add(wsWriteTimeout))
+		_ = conn.Close()
+		return
+	}
+	publisher.clients[client] = struct{}{}
+	publisher.mtx.Unlock()
+
+	go publisher.writeLoop(client)
+	publisher.readLoop(client)
+}
+
+func (publisher *WebSocketMarketDataPublisher) numClients() int {
+	publisher.mtx.RLock()
+	defer publisher.mtx.RUnlock()
+	return len(publisher.clients)
+}
+
+func (publisher *WebSocketMarketDataPublisher) readLoop(client *wsClient) {
+	defer publisher.removeClient(client)
+	for {
+		var req wsRequest
+		if err := client.conn.ReadJSON(&req); err != nil {
+			if _, ok := err.(*websocket.CloseError); !ok {
+				publisher.logger.Debug("failed to read websocket request", "err", err)
+			}
+			return
+		}
+		resp := publisher.handleRequest(client, req)
+		bz, _ := json.Marshal(resp)
+		if !publisher.enqueue(client, bz) {
+			return
+		}
+	}
+}
+
+func (publisher *WebSocketMarketDataPublisher) handleRequest(client *wsClient, req wsRequest) wsResponse {
+	if req.Topic == "" {
+		return wsResponse{Error: "topic is required"}
+	}
+	switch req.Method {
+	case "subscribe":
+		filter := &wsFilter{symbols: make(map[string]bool), addresses: make(map[string]bool)}
+		for _, symbol := range req.Symbols {
+			filter.symbols[strings.ToUpper(symbol)] = true
+		}
+		for _, bech32Addr := range req.Addresses {
+			addr, err := sdk.AccAddressFromBech32(bech32Addr)
+			if err != nil {
+				return wsResponse{Topic: req.Topic, Error: fmt.Sprintf("invalid address %s", bech32Addr)}
+			}
+			filter.addresses[addr.String()] = true
+			filter.addresses[string(addr.Bytes())] = true
+		}
+		client.mtx.Lock()
+		client.filters[req.Topic] = filter
+		client.mtx.Unlock()
+		return wsResponse{Result: "subscribed", Topic: req.Topic}
+	case "unsubscribe":
+		client.mtx.Lock()
+		delete(client.filters, req.Topic)
+		client.mtx.Unlock()
+		return wsResponse{Result: "unsubscribed", Topic: req.Topic}
+	default:
+		return wsResponse{Topic: req.Topic, Error: fmt.Sprintf("unknown method %s", req.Method)}
+	}
+}
+
+func (publisher *WebSocketMarketDataPublisher) writeLoop(client *wsClient) {
+	defer publisher.removeClient(client)
+	for bz := range client.send {
+		_ = client.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err := client.conn.WriteMessage(websocket.TextMessage, bz); err != nil {
+			publisher.logger.Debug("failed to write websocket message", "err", err)
+			return
+		}
+	}
+}
+
+// enqueue returns false if the client is too slow to receive the messages and so removed
+func (publisher *WebSocketMarketDataPublisher) enqueue(client *wsClient, bz []byte) bool {
+	publisher.mtx.RLock()
+	_, ok := publisher.clients[client]
+	sent := false
+	if ok {
+		select {
+		case client.send <- bz:
+			sent = true
+		default:
+		}
+	}
+	publisher.mtx.RUnlock()
+	if ok && !sent {
+		publisher.logger.Info("disconnect slow websocket client", "remote", client.conn.RemoteAddr().String())
+		publisher.removeClient(client)
+	}
+	return sent
+}
+
+func (publisher *WebSocketMarketDataPublisher) removeClient(client *wsClient) {
+	publisher.mtx.Lock()
+	if _, ok := publisher.clients[client]; ok {
+		delete(publisher.clients, client)
+		close(client.send)
+	}
+	publisher.mtx.Unlock()
+	_ = client.conn.Close()
+}
+
+func (publisher *WebSocketMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64) {
+	topic := tpe.String()
+	publisher.mtx.RLock()
+	clients := make([]*wsClient, 0, len(publisher.clients))
+	for client := range publisher.clients {
+		clients = append(clients, client)
+	}
+	publisher.mtx.RUnlock()
+
+	var unfiltered []byte
+	for _, client := range clients {
+		filter := client.filter(topic)
+		if filter == nil {
+			continue
+		}
+		var bz []byte
+		if filter.empty() {
+			if unfiltered == nil {
+				var err error
+				if unfiltered, err = json.Marshal(wsMessage{topic, height, timestamp, msg}); err != nil {
+					publisher.logger.Error("failed to marshal msg", "err", err, "height", height, "msg", msg.String())
+					return
+				}
+			}
+			bz = unfiltered
+		} else {
+			filtered := filterMsg(msg, filter)
+			if filtered == nil {
+				continue
+			}
+			var err error
+			if bz, err = json.Marshal(wsMessage{topic, height, timestamp, filtered}); err != nil {
+				publisher.logger.Error("failed to marshal msg", "err", err, "height", height, "msg", msg.String())
+				continue
+			}
+		}
+		publisher.enqueue(client, bz)
+	}
+}
+
+func (publisher *WebSocketMarketDataPublisher) Stop() {
+	_ = publisher.server.Close()
+	publisher.mtx.RLock()
+	clients := make([]*wsClient, 0, len(publisher.clients))
+	for client := range publisher.clients {
+		clients = append(clients, client)
+	}
+	publisher.mtx.RUnlock()
+	for _, client := range clients {
+		publisher.removeClient(client)
+	}
+	publisher.logger.Info("websocket publisher stopped")
+}
+
+// filterMsg returns a copy of the msg with only the items matching the filter, or nil if nothing matches.
+// The msg types that can't be filtered are returned as they are.
+func filterMsg(msg AvroOrJsonMsg, filter *wsFilter) AvroOrJsonMsg {
+	switch m := msg.(type) {
+	case *ExecutionResults:
+		res := ExecutionResults{Height: m.Height, Timestamp: m.Timestamp}
+		for _, t := range m.Trades.Trades {
+			if filter.matchSymbol(t.Symbol) && filter.matchAddress(t.SAddr, t.BAddr) {
+				res.Trades.Trades = append(res.Trades.Trades, t)
+			}
+		}
+		for _, o := range m.Orders.Orders {
+			if filter.matchSymbol(o.Symbol) && filter.matchAddress(o.Owner) {
+				res.Orders.Orders = append(res.Orders.Orders, o)
+			}
+		}
+		res.Trades.NumOfMsgs = len(res.Trades.Trades)
+		res.Orders.NumOfMsgs = len(res.Orders.Orders)
+		res.NumOfMsgs = res.Trades.NumOfMsgs + res.Orders.NumOfMsgs
+		if res.NumOfMsgs == 0 {
+			return nil
+		}
+		return &res
+	case *Books:
+		res := Books{Height: m.Height, Timestamp: m.Timestamp}
+		for _, book := range m.Books {
+			if filter.matchSymbol(book.Symbol) {
+				res.Books = append(res.Books, book)
+			}
+		}
+		res.NumOfMsgs = len(res.Books)
+		if res.NumOfMsgs == 0 {
+			return nil
+		}
+		return &res
+	case *Accounts:
+		res := Accounts{Height: m.Height}
+		for _, acc := range m.Accounts {
+			if filter.matchAddress(acc.Owner) {
+				res.Accounts = append(res.Accounts, acc)
+			}
+		}
+		res.NumOfMsgs = len(res.Accounts)
+		if res.NumOfMsgs == 0 {
+			return nil
+		}
+		return &res
+	case *Transfers:
+		res := Transfers{Height: m.Height, Timestamp: m.Timestamp}
+		for _, t := range m.Transfers {
+			addrs := []string{t.From}
+			for _, r := range t.To {
+				addrs = append(addrs, r.Addr)
+			}
+			if filter.matchAddress(addrs...) {
+				res.Transfers = append(res.Transfers, t)
+			}
+		}
+		res.Num = len(res.Transfers)
+		if res.Num == 0 {
+			return nil
+		}
+		return &res
+	case *Klines:
+		res := Klines{Height: m.Height, Timestamp: m.Timestamp}
+		for _, k := range m.Klines {
+			if filter.matchSymbol(k.Symbol) {
+				res.Klines = append(res.Klines, k)
+			}
+		}
+		res.NumOfMsgs = len(res.Klines)
+		if res.NumOfMsgs == 0 {
+			return nil
+		}
+		return &res
+	default:
+		return msg
+	}
+}
@@ -0,0 +1,107 @@
+package pub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/testutils"
+)
+
+func TestWebSocketMarketDataPublisher(t *testing.T) {
+	publisher, err := NewWebSocketMarketDataPublisher(Logger, "127.0.0.1:0", 10, nil, 0)
+	require.NoError(t, err)
+	defer publisher.Stop()
+
+	conn, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s", publisher.Addr().String()), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	_, addr := testutils.PrivAndAddr()
+	require.NoError(t, conn.WriteJSON(wsRequest{Method: "subscribe", Topic: "Books", Symbols: []string{"xyz-000_bnb"}}))
+	require.NoError(t, conn.WriteJSON(wsRequest{Method: "subscribe", Topic: "Accounts", Addresses: []string{addr.String()}}))
+	require.NoError(t, conn.WriteJSON(wsRequest{Method: "subscribe", Topic: "Transfers", Addresses: []string{"bnb1invalid"}}))
+	var resp wsResponse
+	require.NoError(t, conn.ReadJSON(&resp))
+	require.Equal(t, wsResponse{Result: "subscribed", Topic: "Books"}, resp)
+	require.NoError(t, conn.ReadJSON(&resp))
+	require.Equal(t, wsResponse{Result: "subscribed", Topic: "Accounts"}, resp)
+	resp = wsResponse{}
+	require.NoError(t, conn.ReadJSON(&resp))
+	require.Equal(t, "Transfers", resp.Topic)
+	require.NotEmpty(t, resp.Error)
+
+	// not subscribed
+	publisher.publish(&BlockFee{Height: 10}, blockFeeTpe, 10, 100)
+	// nothing left after filtering
+	publisher.publish(&Accounts{Height: 10, NumOfMsgs: 1, Accounts: []Account{{Owner: "other"}}}, accountsTpe, 10, 100)
+	publisher.publish(&Books{10, 100, 2, []OrderBookDelta{
+		{"ABC-000_BNB", []PriceLevel{{1, 1}}, nil, 1, false},
+		{"XYZ-000_BNB", []PriceLevel{{2, 2}}, nil, 1, false},
+	}}, booksTpe, 10, 100)
+	publisher.publish(&Accounts{Height: 10, NumOfMsgs: 2, Accounts: []Account{{Owner: "other"}, {Owner: string(addr.Bytes())}}}, accountsTpe, 10, 100)
+
+	var msg struct {
+		Topic     string
+		Height    int64
+		Timestamp int64
+		Data      json.RawMessage
+	}
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, "Books", msg.Topic)
+	var books Books
+	require.NoError(t, json.Unmarshal(msg.Data, &books))
+	require.Equal(t, 1, books.NumOfMsgs)
+	require.Equal(t, "XYZ-000_BNB", books.Books[0].Symbol)
+
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, "Accounts", msg.Topic)
+	var accounts struct {
+		NumOfMsgs int
+		Accounts  []struct{ Owner string }
+	}
+	require.NoError(t, json.Unmarshal(msg.Data, &accounts))
+	require.Equal(t, 1, accounts.NumOfMsgs)
+	require.Equal(t, addr.String(), accounts.Accounts[0].Owner)
+}
+
+func TestWebSocketMarketDataPublisher_Limits(t *testing.T) {
+	publisher, err := NewWebSocketMarketDataPublisher(Logger, "127.0.0.1:0", 10, []string{"https://example.com"}, 1)
+	require.NoError(t, err)
+	defer publisher.Stop()
+	url := fmt.Sprintf("ws://%s", publisher.Addr().String())
+
+	// the origin is not allowed
+	_, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{"https://other.com"}})
+	require.Error(t, err)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{"https://EXAMPLE.com"}})
+	require.NoError(t, err)
+	defer conn.Close()
+	// make sure the client is registered
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	require.NoError(t, conn.WriteJSON(wsRequest{Method: "subscribe", Topic: "Books"}))
+	var subscribed wsResponse
+	require.NoError(t, conn.ReadJSON(&subscribed))
+
+	// too many clients
+	_, resp, err = websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestNewPublishers(t *testing.T) {
+	_, err := NewPublishers([]string{"unknown"}, PublisherEnv{Logger: Logger, Config: Cfg})
+	require.Error(t, err)
+	require.Contains(t, RegisteredPublishers(), "websocket")
+	require.Panics(t, func() {
+		RegisterPublisher("websocket", nil)
+	})
+}
@@ -0,0 +1,251 @@
+package pub
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tendermint/tendermint/libs/common"
+)
+
+const (
+	PublicationLogDir    = "publog"
+	publogSegmentSuffix  = ".seg"
+	publogRecordHeadSize = 8 // 4 bytes of the payload length and 4 bytes of the crc32 of the payload
+)
+
+// PublicationLogRecord is a published message in the publication log
+type PublicationLogRecord struct {
+	Height    int64  `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Topic     string `json:"topic"`   // name of the msg type
+	Version   int    `json:"version"` // schema version of the msg type
+	Data      []byte `json:"data"`    // the msg in avro
+}
+
+// PublicationLog writes the published messages into segments under dir, which are named by the first height
+// in them, so the messages of a height range can be found without an index. A segment is rolled over at the
+// first message of a height once it exceeds maxSegmentSize, so the messages of a height are in one segment,
+// and the oldest segments beyond maxSegments are deleted. A new segment is started whenever the node starts,
+// the segments after its first height are deleted and the messages of the earlier segments at or above its
+// first height are superseded by it, as they are published again after the node restarts.
+type PublicationLog struct {
+	mtx            sync.Mutex
+	dir            string
+	maxSegmentSize int64
+	maxSegments    int
+
+	segment     *os.File // nil before the first message
+	writer      *bufio.Writer
+	segmentSize int64
+	lastHeight  int64
+}
+
+func NewPublicationLog(dir string, maxSegmentSize int64, maxSegments int) (*PublicationLog, error) {
+	if err := common.EnsureDir(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &PublicationLog{
+		dir:            dir,
+		maxSegmentSize: maxSegmentSize,
+		maxSegments:    maxSegments,
+	}, nil
+}
+
+func segmentName(firstHeight int64) string {
+	return fmt.Sprintf("%020d%s", firstHeight, publogSegmentSuffix)
+}
+
+// listSegments returns the first heights of the segments under dir in ascending order
+func listSegments(dir string) ([]int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	heights := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, publogSegmentSuffix) {
+			continue
+		}
+		height, err := strconv.ParseInt(strings.TrimSuffix(name, publogSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, nil
+}
+
+// Append writes the record, the records must be appended in the order of height
+func (l *PublicationLog) Append(record PublicationLogRecord) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.segment == nil || record.Height > l.lastHeight && l.segmentSize >= l.maxSegmentSize {
+		if err := l.roll(record.Height); err != nil {
+			return err
+		}
+	}
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	var head [publogRecordHeadSize]byte
+	binary.BigEndian.PutUint32(head[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(head[4:], crc32.ChecksumIEEE(payload))
+	if _, err := l.writer.Write(head[:]); err != nil {
+		return err
+	}
+	if _, err := l.writer.Write(payload); err != nil {
+		return err
+	}
+	l.segmentSize += int64(publogRecordHeadSize + len(payload))
+	l.lastHeight = record.Height
+	return nil
+}
+
+// Flush makes the appended records readable
+func (l *PublicationLog) Flush() error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.writer == nil {
+		return nil
+	}
+	return l.writer.Flush()
+}
+
+func (l *PublicationLog) roll(firstHeight int64) error {
+	if err := l.closeSegment(); err != nil {
+		return err
+	}
+	heights, err := listSegments(l.dir)
+	if err != nil {
+		return err
+	}
+	// the segments left from before the node restarted at a lower height would supersede the new one
+	for _, height := range heights {
+		if height > firstHeight {
+			if err := os.Remove(filepath.Join(l.dir, segmentName(height))); err != nil {
+				return err
+			}
+		}
+	}
+	segment, err := os.OpenFile(filepath.Join(l.dir, segmentName(firstHeight)), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	l.segment = segment
+	l.writer = bufio.NewWriter(segment)
+	l.segmentSize = 0
+
+	heights, err = listSegments(l.dir)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(heights)-l.maxSegments; i++ {
+		if err := os.Remove(filepath.Join(l.dir, segmentName(heights[i]))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *PublicationLog) closeSegment() error {
+	if l.segment == nil {
+		return nil
+	}
+	if err := l.writer.Flush(); err != nil {
+		return err
+	}
+	err := l.segment.Close()
+	l.segment, l.writer = nil, nil
+	return err
+}
+
+func (l *PublicationLog) Close() error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.closeSegment()
+}
+
+// ReadPublicationLog calls fn with the records of the heights in [fromHeight, toHeight] in the order they
+// are published, toHeight <= 0 means no upper bound. A record torn by a crash ends its segment.
+func ReadPublicationLog(dir string, fromHeight, toHeight int64, fn func(record PublicationLogRecord) error) error {
+	heights, err := listSegments(dir)
+	if err != nil {
+		return err
+	}
+	// the segments before the last one starting at or below fromHeight are all below fromHeight or superseded
+	start := 0
+	for i, height := range heights {
+		if height <= fromHeight {
+			start = i
+		}
+	}
+	for i := start; i < len(heights); i++ {
+		if toHeight > 0 && heights[i] > toHeight {
+			break
+		}
+		nextFirst := int64(-1)
+		if i+1 < len(heights) {
+			nextFirst = heights[i+1]
+		}
+		if err := readSegment(filepath.Join(dir, segmentName(heights[i])), func(record PublicationLogRecord) error {
+			if record.Height < fromHeight || toHeight > 0 && record.Height > toHeight ||
+				nextFirst >= 0 && record.Height >= nextFirst {
+				return nil
+			}
+			return fn(record)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSegment(path string, fn func(record PublicationLogRecord) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	var head [publogRecordHeadSize]byte
+	for {
+		if _, err := io.ReadFull(reader, head[:]); err != nil {
+			if err != io.EOF && err != io.ErrUnexpectedEOF {
+				return err
+			}
+			return nil
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(head[:4]))
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			if err != io.EOF && err != io.ErrUnexpectedEOF {
+				return err
+			}
+			return nil
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(head[4:]) {
+			Logger.Error("publication log is torn", "segment", path)
+			return nil
+		}
+		var record PublicationLogRecord
+		if err := json.Unmarshal(payload, &record); err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
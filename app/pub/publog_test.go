@@ -0,0 +1,97 @@
+package pub
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readHeights(t *testing.T, dir string, fromHeight, toHeight int64) []int64 {
+	var heights []int64
+	require.NoError(t, ReadPublicationLog(dir, fromHeight, toHeight, func(record PublicationLogRecord) error {
+		heights = append(heights, record.Height)
+		return nil
+	}))
+	return heights
+}
+
+func appendHeights(t *testing.T, log *PublicationLog, heights ...int64) {
+	for _, height := range heights {
+		require.NoError(t, log.Append(PublicationLogRecord{height, height * 1000, "BlockFee", 0, []byte("fee")}))
+	}
+	require.NoError(t, log.Flush())
+}
+
+func TestPublicationLog_AppendAndRead(t *testing.T) {
+	dir := t.TempDir()
+	log, err := NewPublicationLog(dir, 1, 3)
+	require.NoError(t, err)
+
+	// every height rolls a new segment as the segments exceed 1 byte, and the messages of a height stay together
+	appendHeights(t, log, 1, 1, 2, 3, 4, 5)
+	segments, err := listSegments(dir)
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 4, 5}, segments)
+
+	require.Equal(t, []int64{3, 4, 5}, readHeights(t, dir, 1, 0))
+	require.Equal(t, []int64{4}, readHeights(t, dir, 4, 4))
+	require.Empty(t, readHeights(t, dir, 6, 0))
+
+	// the node restarts at height 4, the messages of the earlier segments from height 4 are replaced
+	require.NoError(t, log.Close())
+	log, err = NewPublicationLog(dir, 1<<20, 10)
+	require.NoError(t, err)
+	appendHeights(t, log, 4, 4, 5, 6)
+	require.Equal(t, []int64{3, 4, 4, 5, 6}, readHeights(t, dir, 1, 0))
+	require.NoError(t, log.Close())
+}
+
+func TestPublicationLog_TornRecord(t *testing.T) {
+	dir := t.TempDir()
+	log, err := NewPublicationLog(dir, 1<<20, 10)
+	require.NoError(t, err)
+	appendHeights(t, log, 1, 2)
+	require.NoError(t, log.Close())
+
+	// a crash while writing the last record
+	path := filepath.Join(dir, segmentName(1))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, info.Size()-2))
+	require.Equal(t, []int64{1}, readHeights(t, dir, 1, 0))
+}
+
+func TestReplayPublicationLog(t *testing.T) {
+	dir := t.TempDir()
+	publisher, err := NewPublicationLogPublisher(dir, 64, 10)
+	require.NoError(t, err)
+	publisher.publish(BlockFee{1, "BNB:100", []string{"val1"}}, blockFeeTpe, 1, 1000)
+	publisher.publish(BlockFee{2, "BNB:200", []string{"val2"}}, blockFeeTpe, 2, 2000)
+	publisher.publish(BlockFee{3, "BNB:300", []string{"val3"}}, blockFeeTpe, 3, 3000)
+	publisher.Stop()
+
+	var buf bytes.Buffer
+	num, err := ReplayPublicationLog(dir, 2, 3, NewWriterMarketDataPublisher(&buf))
+	require.NoError(t, err)
+	require.Equal(t, 2, num)
+
+	decoder := json.NewDecoder(&buf)
+	for _, height := range []int64{2, 3} {
+		var line struct {
+			Topic     string
+			Height    int64
+			Timestamp int64
+			Data      map[string]interface{}
+		}
+		require.NoError(t, decoder.Decode(&line))
+		require.Equal(t, "BlockFee", line.Topic)
+		require.Equal(t, height, line.Height)
+		require.Equal(t, height*1000, line.Timestamp)
+		require.Equal(t, float64(height), line.Data["height"])
+	}
+	require.False(t, decoder.More())
+}
@@ -0,0 +1,125 @@
+package pub
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/app/config"
+)
+
+// PublisherEnv is what a publisher backend is created with
+type PublisherEnv struct {
+	Logger  tmlog.Logger
+	Config  *config.PublicationConfig
+	RootDir string // home of the node
+	DBDir   string
+}
+
+// PublisherFactory creates a publisher backend, backends outside of this package can wrap themselves
+// with NewBackendPublisher
+type PublisherFactory func(env PublisherEnv) (MarketDataPublisher, error)
+
+var (
+	registryMtx sync.RWMutex
+	registry    = make(map[string]PublisherFactory)
+)
+
+func init() {
+	RegisterPublisher(config.KafkaPublisher, func(env PublisherEnv) (MarketDataPublisher, error) {
+		return NewKafkaMarketDataPublisher(env.Logger, env.DBDir, env.Config.StopOnKafkaFail), nil
+	})
+	RegisterPublisher(config.LocalPublisher, func(env PublisherEnv) (MarketDataPublisher, error) {
+		return NewLocalMarketDataPublisher(env.RootDir, env.Logger, env.Config), nil
+	})
+	RegisterPublisher(config.WebSocketPublisher, func(env PublisherEnv) (MarketDataPublisher, error) {
+		return NewWebSocketMarketDataPublisher(env.Logger, env.Config.WebSocketAddress, env.Config.WebSocketClientBuffer,
+			env.Config.WebSocketOrigins(), env.Config.WebSocketMaxClients)
+	})
+	RegisterPublisher(config.PublicationLogPublisher, func(env PublisherEnv) (MarketDataPublisher, error) {
+		return NewPublicationLogPublisher(filepath.Join(env.DBDir, PublicationLogDir),
+			env.Config.PublicationLogSegmentSize, env.Config.PublicationLogMaxSegments)
+	})
+	RegisterPublisher(config.StdoutPublisher, func(env PublisherEnv) (MarketDataPublisher, error) {
+		return NewWriterMarketDataPublisher(os.Stdout), nil
+	})
+}
+
+// RegisterPublisher makes the publisher backend selectable by name in the publishers of the publication
+// config. It panics if the name is registered twice.
+func RegisterPublisher(name string, factory PublisherFactory) {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("publisher %s is registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// RegisteredPublishers returns the names of all the registered publisher backends
+func RegisteredPublishers() []string {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	return registeredPublishers()
+}
+
+func registeredPublishers() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewPublishers creates the publisher backends by names, the created ones are stopped if any fails
+func NewPublishers(names []string, env PublisherEnv) ([]MarketDataPublisher, error) {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	publishers := make([]MarketDataPublisher, 0, len(names))
+	for _, name := range names {
+		factory, ok := registry[name]
+		var publisher MarketDataPublisher
+		var err error
+		if !ok {
+			err = fmt.Errorf("unknown publisher %s, registered publishers: %v", name, registeredPublishers())
+		} else {
+			publisher, err = factory(env)
+		}
+		if err != nil {
+			for _, p := range publishers {
+				p.Stop()
+			}
+			return nil, err
+		}
+		publishers = append(publishers, publisher)
+	}
+	return publishers, nil
+}
+
+// Backend is a publisher backend implemented outside of this package, topic is the name of the message type
+type Backend interface {
+	Publish(topic string, msg AvroOrJsonMsg, height int64, timestamp int64)
+	Stop()
+}
+
+type backendPublisher struct {
+	backend Backend
+}
+
+func (publisher *backendPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64) {
+	publisher.backend.Publish(tpe.String(), msg, height, timestamp)
+}
+
+func (publisher *backendPublisher) Stop() {
+	publisher.backend.Stop()
+}
+
+// NewBackendPublisher adapts the backend to MarketDataPublisher
+func NewBackendPublisher(backend Backend) MarketDataPublisher {
+	return &backendPublisher{backend}
+}
@@ -0,0 +1,273 @@
+package pub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true, "float": true, "double": true, "bytes": true, "string": true,
+}
+
+// avroPromotions are the writer types can be read as the reader types other than themselves
+var avroPromotions = map[string][]string{
+	"int":    {"long", "float", "double"},
+	"long":   {"float", "double"},
+	"float":  {"double"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+// avroSchema is a parsed avro schema with its named types indexed by full name
+type avroSchema struct {
+	root  interface{}
+	names map[string]map[string]interface{}
+}
+
+func parseAvroSchema(schema string) (*avroSchema, error) {
+	var root interface{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, err
+	}
+	parsed := &avroSchema{root: root, names: make(map[string]map[string]interface{})}
+	parsed.index(root, "")
+	return parsed, nil
+}
+
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func namespaceOf(fullName string) string {
+	if i := strings.LastIndex(fullName, "."); i >= 0 {
+		return fullName[:i]
+	}
+	return ""
+}
+
+func shortName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func (schema *avroSchema) index(node interface{}, namespace string) {
+	switch n := node.(type) {
+	case []interface{}:
+		for _, branch := range n {
+			schema.index(branch, namespace)
+		}
+	case map[string]interface{}:
+		if name, ok := n["name"].(string); ok {
+			if ns, ok := n["namespace"].(string); ok {
+				namespace = ns
+			}
+			name = fullName(name, namespace)
+			schema.names[name] = n
+			namespace = namespaceOf(name)
+		}
+		if fields, ok := n["fields"].([]interface{}); ok {
+			for _, field := range fields {
+				if f, ok := field.(map[string]interface{}); ok {
+					schema.index(f["type"], namespace)
+				}
+			}
+		}
+		for _, key := range []string{"type", "items", "values"} {
+			if child, ok := n[key]; ok {
+				if _, isString := child.(string); !isString {
+					schema.index(child, namespace)
+				}
+			}
+		}
+	}
+}
+
+// resolve returns the type name, the definition and the enclosing namespace of the node, the definition is nil
+// for primitives and unions
+func (schema *avroSchema) resolve(node interface{}, namespace string) (string, map[string]interface{}, string, error) {
+	switch n := node.(type) {
+	case string:
+		if avroPrimitives[n] {
+			return n, nil, namespace, nil
+		}
+		for _, name := range []string{fullName(n, namespace), n} {
+			if def, ok := schema.names[name]; ok {
+				return schema.resolve(def, namespaceOf(name))
+			}
+		}
+		return "", nil, namespace, fmt.Errorf("unknown type %s", n)
+	case []interface{}:
+		return "union", nil, namespace, nil
+	case map[string]interface{}:
+		tpe, ok := n["type"].(string)
+		if !ok {
+			return schema.resolve(n["type"], namespace)
+		}
+		if name, ok := n["name"].(string); ok && (tpe == "record" || tpe == "enum" || tpe == "fixed") {
+			if ns, ok := n["namespace"].(string); ok {
+				namespace = ns
+			}
+			return tpe, n, namespaceOf(fullName(name, namespace)), nil
+		}
+		if avroPrimitives[tpe] || tpe == "array" || tpe == "map" {
+			return tpe, n, namespace, nil
+		}
+		return schema.resolve(tpe, namespace)
+	default:
+		return "", nil, namespace, fmt.Errorf("invalid schema %v", node)
+	}
+}
+
+type schemaCompatChecker struct {
+	reader, writer *avroSchema
+	checking       map[[2]string]bool // the record pairs being checked, to stop at recursive types
+}
+
+// CheckSchemaCompatibility returns an error describing the first incompatibility found if the data written
+// with the writer schema can't be read with the reader schema, following the schema resolution rules of the
+// avro specification. A new schema of a msg type is backward compatible if it can read the data written with
+// the schemas released before, so the consumers can upgrade ahead of the publisher.
+func CheckSchemaCompatibility(reader, writer string) error {
+	readerSchema, err := parseAvroSchema(reader)
+	if err != nil {
+		return fmt.Errorf("invalid reader schema: %v", err)
+	}
+	writerSchema, err := parseAvroSchema(writer)
+	if err != nil {
+		return fmt.Errorf("invalid writer schema: %v", err)
+	}
+	checker := schemaCompatChecker{readerSchema, writerSchema, make(map[[2]string]bool)}
+	return checker.check(readerSchema.root, "", writerSchema.root, "", "")
+}
+
+func (c *schemaCompatChecker) check(reader interface{}, readerNs string, writer interface{}, writerNs string, path string) error {
+	readerType, readerDef, readerNs, err := c.reader.resolve(reader, readerNs)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	writerType, writerDef, writerNs, err := c.writer.resolve(writer, writerNs)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	if writerType == "union" {
+		for _, branch := range unionBranches(writer) {
+			if err := c.check(reader, readerNs, branch, writerNs, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if readerType == "union" {
+		for _, branch := range unionBranches(reader) {
+			if c.check(branch, readerNs, writer, writerNs, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %s is not in the reader union", path, writerType)
+	}
+
+	if readerType != writerType {
+		for _, promoted := range avroPromotions[writerType] {
+			if promoted == readerType {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %s can't be read as %s", path, writerType, readerType)
+	}
+
+	switch readerType {
+	case "array":
+		return c.check(readerDef["items"], readerNs, writerDef["items"], writerNs, path+"[]")
+	case "map":
+		return c.check(readerDef["values"], readerNs, writerDef["values"], writerNs, path+"{}")
+	case "fixed":
+		if shortName(readerDef["name"].(string)) != shortName(writerDef["name"].(string)) {
+			return fmt.Errorf("%s: fixed %v can't be read as %v", path, writerDef["name"], readerDef["name"])
+		}
+		if readerDef["size"] != writerDef["size"] {
+			return fmt.Errorf("%s: size of fixed %v changed", path, readerDef["name"])
+		}
+	case "enum":
+		if shortName(readerDef["name"].(string)) != shortName(writerDef["name"].(string)) {
+			return fmt.Errorf("%s: enum %v can't be read as %v", path, writerDef["name"], readerDef["name"])
+		}
+		if _, ok := readerDef["default"]; ok {
+			return nil
+		}
+		symbols := make(map[interface{}]bool)
+		readerSymbols, _ := readerDef["symbols"].([]interface{})
+		for _, symbol := range readerSymbols {
+			symbols[symbol] = true
+		}
+		writerSymbols, _ := writerDef["symbols"].([]interface{})
+		for _, symbol := range writerSymbols {
+			if !symbols[symbol] {
+				return fmt.Errorf("%s: symbol %v is removed from enum %v", path, symbol, readerDef["name"])
+			}
+		}
+	case "record":
+		return c.checkRecord(readerDef, readerNs, writerDef, writerNs, path)
+	}
+	return nil
+}
+
+func (c *schemaCompatChecker) checkRecord(reader map[string]interface{}, readerNs string, writer map[string]interface{}, writerNs string, path string) error {
+	readerName, writerName := reader["name"].(string), writer["name"].(string)
+	if shortName(readerName) != shortName(writerName) {
+		return fmt.Errorf("%s: record %s can't be read as %s", path, writerName, readerName)
+	}
+	key := [2]string{fullName(readerName, readerNs), fullName(writerName, writerNs)}
+	if c.checking[key] {
+		return nil
+	}
+	c.checking[key] = true
+	defer delete(c.checking, key)
+
+	writerFields := make(map[string]map[string]interface{})
+	fields, _ := writer["fields"].([]interface{})
+	for _, field := range fields {
+		if f, ok := field.(map[string]interface{}); ok {
+			writerFields[f["name"].(string)] = f
+		}
+	}
+	fields, _ = reader["fields"].([]interface{})
+	for _, field := range fields {
+		readerField, ok := field.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := readerField["name"].(string)
+		fieldPath := strings.TrimPrefix(path+"."+name, ".")
+		writerField, ok := writerFields[name]
+		if !ok {
+			aliases, _ := readerField["aliases"].([]interface{})
+			for _, alias := range aliases {
+				if writerField, ok = writerFields[fmt.Sprint(alias)]; ok {
+					break
+				}
+			}
+		}
+		if !ok {
+			if _, hasDefault := readerField["default"]; !hasDefault {
+				return fmt.Errorf("%s: new field without a default value", fieldPath)
+			}
+			continue
+		}
+		if err := c.check(readerField["type"], readerNs, writerField["type"], writerNs, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unionBranches(node interface{}) []interface{} {
+	if m, ok := node.(map[string]interface{}); ok {
+		return unionBranches(m["type"])
+	}
+	branches, _ := node.([]interface{})
+	return branches
+}
@@ -0,0 +1,196 @@
+package pub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
+)
+
+// incompatibleSchemaVersions are the schema versions released with breaking changes before the compatibility
+// check, the current schemas are not checked against them
+var incompatibleSchemaVersions = map[string]bool{
+	"accounts0.avsc":         true,
+	"distribution0.avsc":     true,
+	"executionResults0.avsc": true,
+	"transfers0.avsc":        true,
+}
+
+// schemaSnapshot returns the path of the snapshot of the schema of the msg type in the version under schemas,
+// which is released and must not be changed
+func schemaSnapshot(tpe msgType, version int) string {
+	name := tpe.String()
+	return filepath.Join("schemas", fmt.Sprintf("%s%s%d.avsc", strings.ToLower(name[:1]), name[1:], version))
+}
+
+// A change of a schema must bump its version in latestSchemaVersions and add the snapshot of the new version,
+// and must be able to read the data written in the released versions
+func TestSchemaEvolution(t *testing.T) {
+	for tpe, schema := range latestSchemas {
+		version := latestSchemaVersions[tpe]
+		snapshot, err := os.ReadFile(schemaSnapshot(tpe, version))
+		require.NoError(t, err, "no snapshot of the schema of %s version %d", tpe.String(), version)
+		var expected, actual interface{}
+		require.NoError(t, json.Unmarshal(snapshot, &expected))
+		require.NoError(t, json.Unmarshal([]byte(schema), &actual))
+		require.Equal(t, expected, actual,
+			"schema of %s is changed, bump its version and add the snapshot of the new version", tpe.String())
+
+		for v := 0; v < version; v++ {
+			path := schemaSnapshot(tpe, v)
+			if incompatibleSchemaVersions[filepath.Base(path)] {
+				continue
+			}
+			released, err := os.ReadFile(path)
+			if os.IsNotExist(err) {
+				continue
+			}
+			require.NoError(t, err)
+			require.NoError(t, CheckSchemaCompatibility(schema, string(released)),
+				"schema of %s is not backward compatible with version %d", tpe.String(), v)
+		}
+	}
+}
+
+// schemaSamples returns a sample of every msg type with all the optional parts populated, so the output of
+// ToNativeMap is checked against every field of the schema
+func schemaSamples(t *testing.T) map[msgType]AvroOrJsonMsg {
+	valAddr := sdk.ValAddress(bytes.Repeat([]byte{1}, sdk.AddrLen))
+	accAddr := sdk.AccAddress(bytes.Repeat([]byte{2}, sdk.AddrLen))
+	validator := &Validator{FeeAddr: accAddr, OperatorAddr: valAddr, Status: 1, DelegatorShares: sdk.NewDecWithoutFra(10000)}
+
+	var block Block
+	require.NoError(t, json.Unmarshal([]byte(testBlock), &block))
+	return map[msgType]AvroOrJsonMsg{
+		accountsTpe: &Accounts{Height: 42, NumOfMsgs: 1, Accounts: []Account{{Owner: accAddr.String(), Fee: "BNB:1000",
+			Sequence: 3, Balances: []*AssetBalance{{Asset: "BNB", Free: 100, Frozen: 10, Locked: 1}}}}},
+		booksTpe: &Books{Height: 42, Timestamp: 100, NumOfMsgs: 1, Books: []OrderBookDelta{{Symbol: "XYZ-000_BNB",
+			Buys: []PriceLevel{{100, 100}}, Sells: []PriceLevel{{101, 0}}, Sequence: 7, Snapshot: true}}},
+		executionResultTpe: &ExecutionResults{Height: 42, Timestamp: 100, NumOfMsgs: 4,
+			Trades: trades{NumOfMsgs: 1, Trades: []*Trade{{Id: "42-0", Symbol: "XYZ-000_BNB", Price: 100, Qty: 100,
+				Sid: "s-1", Bid: "b-1", TickType: 1, Sfee: "BNB:8", Bfee: "BNB:10", SSingleFee: "BNB:8",
+				BSingleFee: "BNB:10", SAddr: "s", BAddr: "b", SSrc: 1, BSrc: 2}}},
+			Orders: Orders{NumOfMsgs: 1, Orders: []*Order{{"XYZ-000_BNB", orderPkg.FullyFill, "b-1", "42-0", "b",
+				orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:10", 99, 100,
+				orderPkg.TimeInForce.GTE, orderPkg.NEW, "TXHASH", "BNB:10", nil}}},
+			Proposals: Proposals{NumOfMsgs: 1, Proposals: []*Proposal{{1, Succeed}}},
+			StakeUpdates: StakeUpdates{NumOfMsgs: 1, CompletedUnbondingDelegations: []*CompletedUnbondingDelegation{{
+				Validator: valAddr, Delegator: accAddr, Amount: Coin{"BNB", 100}}}},
+		},
+		blockFeeTpe: &BlockFee{Height: 42, Fee: "BNB:1000", Validators: []string{"bnc1", "bnc2"}},
+		transferTpe: &Transfers{Height: 42, Num: 1, Timestamp: 100, Transfers: []Transfer{{TxHash: "TXHASH", Memo: "memo",
+			From: "bnc0", To: []Receiver{{"bnc1", []Coin{{"BNB", 100}, {"BTC", 100}}}}}}},
+		blockTpe: &block,
+		stakingTpe: &StakingMsg{NumOfMsgs: 6, Height: 42, Timestamp: 100,
+			Validators:        []*Validator{validator},
+			RemovedValidators: map[string][]sdk.ValAddress{"chain-id-1": {valAddr}},
+			Delegations: map[string][]*Delegation{"chain-id-1": {{DelegatorAddr: accAddr, ValidatorAddr: valAddr,
+				Shares: sdk.NewDecWithoutFra(1)}}},
+			DelegateEvents: map[string][]*DelegateEvent{"chain-id-1": {{accAddr, valAddr,
+				Coin{Denom: "BNB", Amount: 100}, "TXHASH"}}},
+			ElectedValidators: map[string][]*Validator{"chain-id-1": {validator}},
+		},
+		distributionTpe: &DistributionMsg{NumOfMsgs: 1, Height: 42, Timestamp: 100, Distributions: map[string][]*Distribution{
+			"chain-id-1": {{Validator: valAddr, SelfDelegator: accAddr, DistributeAddr: accAddr, ValTokens: 100,
+				TotalReward: 10, Commission: 1, Rewards: []*Reward{{Validator: valAddr, Delegator: accAddr, Tokens: 100,
+					Amount: 9}}}}}},
+		slashingTpe: &SlashMsg{NumOfMsgs: 1, Height: 42, Timestamp: 100, SlashData: map[string][]*Slash{"chain-id-1": {{
+			Validator: valAddr, InfractionType: 1, InfractionHeight: 40, JailUtil: 100000, SlashAmount: 100,
+			ToFeePool: 10, Submitter: accAddr, SubmitterReward: 80,
+			ValidatorsCompensation: []*AllocatedAmt{{Address: accAddr.String(), Amount: 10}}}}}},
+		crossTransferTpe: &CrossTransfers{Height: 42, Num: 1, Timestamp: 100, Transfers: []CrossTransfer{{TxHash: "TXHASH",
+			ChainId: "bsc", Type: "TCP", From: "bnc0", RelayerFee: 1, Denom: "BNB",
+			To: []CrossReceiver{{Addr: "0x01", Amount: 100}}}}},
+		mirrorTpe: &Mirrors{Height: 42, Num: 1, Timestamp: 100, Mirrors: []Mirror{{TxHash: "TXHASH", ChainId: "bsc",
+			Type: "MIRROR", Sender: "bnc0", RelayerFee: 1, Contract: "0x01", BEP20Name: "XYZ", BEP20Symbol: "XYZ",
+			BEP2Symbol: "XYZ-000", TotalSupply: 1000, Decimals: 8, Fee: 100}}},
+		sideProposalType: &SideProposals{Height: 42, NumOfMsgs: 1, Timestamp: 100,
+			Proposals: []*SideProposal{{Id: 1, ChainId: "bsc", Status: Succeed}}},
+		breatheBlockTpe: &BreatheBlockMsg{Height: 42, Timestamp: 100},
+		klineTpe: &Klines{Height: 42, NumOfMsgs: 1, Timestamp: 100, Klines: []*Kline{{Symbol: "XYZ-000_BNB",
+			Interval: "1m", OpenTime: 60000, CloseTime: 119999, Open: 100, High: 120, Low: 90, Close: 110, Volume: 1000,
+			QuoteVolume: 1100, NumberOfTrades: 3, Closed: true}}},
+	}
+}
+
+// The output of ToNativeMap of every msg type must be encoded by the codec of its current schema, a field added to
+// the schema but not to ToNativeMap only fails when the msg is published otherwise
+func TestSchemaSamples(t *testing.T) {
+	var codecs avroCodecs
+	require.NoError(t, codecs.initAvroCodecs())
+	samples := schemaSamples(t)
+	for tpe := range latestSchemas {
+		sample, ok := samples[tpe]
+		require.True(t, ok, "no sample of %s", tpe.String())
+		bz, err := codecs.marshal(sample, tpe)
+		require.NoError(t, err, "failed to encode the sample of %s", tpe.String())
+
+		codec, err := codecs.codec(tpe)
+		require.NoError(t, err)
+		native, remaining, err := codec.NativeFromBinary(bz)
+		require.NoError(t, err, "failed to decode the sample of %s", tpe.String())
+		require.Empty(t, remaining)
+		reencoded, err := codec.BinaryFromNative(nil, native)
+		require.NoError(t, err)
+		require.Equal(t, bz, reencoded, "the sample of %s is not encoded consistently", tpe.String())
+	}
+}
+
+func TestCheckSchemaCompatibility(t *testing.T) {
+	writer := `{"type": "record", "name": "Orders", "namespace": "com.company", "fields": [
+		{"name": "height", "type": "int"},
+		{"name": "side", "type": {"type": "enum", "name": "Side", "symbols": ["BUY", "SELL"]}},
+		{"name": "orders", "type": {"type": "array", "items": {"type": "record", "name": "Order", "fields": [
+			{"name": "id", "type": "string"},
+			{"name": "side", "type": "Side"},
+			{"name": "fee", "type": ["null", "string"]}
+		]}}}
+	]}`
+	for _, tc := range []struct {
+		name   string
+		reader string
+		err    string
+	}{
+		{"same", writer, ""},
+		{"promoted and removed fields", `{"type": "record", "name": "Orders", "fields": [
+			{"name": "height", "type": "long"},
+			{"name": "orders", "type": {"type": "array", "items": {"type": "record", "name": "Order", "fields": [
+				{"name": "id", "type": "bytes"}
+			]}}}
+		]}`, ""},
+		{"new field with default", `{"type": "record", "name": "Orders", "fields": [
+			{"name": "height", "type": "int"},
+			{"name": "sequence", "type": "long", "default": 0}
+		]}`, ""},
+		{"new field without default", `{"type": "record", "name": "Orders", "fields": [
+			{"name": "height", "type": "int"},
+			{"name": "sequence", "type": "long"}
+		]}`, "sequence: new field without a default value"},
+		{"narrowed type", `{"type": "record", "name": "Orders", "fields": [
+			{"name": "height", "type": "float"},
+			{"name": "side", "type": {"type": "enum", "name": "Side", "symbols": ["BUY"]}}
+		]}`, "side: symbol SELL is removed from enum Side"},
+		{"union narrowed", `{"type": "record", "name": "Orders", "fields": [
+			{"name": "orders", "type": {"type": "array", "items": {"type": "record", "name": "Order", "fields": [
+				{"name": "fee", "type": "string"}
+			]}}}
+		]}`, "orders[].fee: null can't be read as string"},
+		{"renamed record", `{"type": "record", "name": "Trades", "fields": []}`, "record Orders can't be read as Trades"},
+	} {
+		err := CheckSchemaCompatibility(tc.reader, writer)
+		if tc.err == "" {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.Error(t, err, tc.name)
+			require.Contains(t, err.Error(), tc.err, tc.name)
+		}
+	}
+}
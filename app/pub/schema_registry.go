@@ -0,0 +1,155 @@
+package pub
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	schemaRegistryContentType = "application/vnd.schemaregistry.v1+json"
+	schemaRegistryTimeout     = 10 * time.Second
+
+	// the confluent wire format prefixes the avro binary with a zero magic byte and the 4 bytes schema id
+	wireFormatMagicByte  = 0
+	wireFormatHeaderSize = 5
+)
+
+// SchemaRegistry is a client of the confluent compatible schema registry
+type SchemaRegistry struct {
+	url    string
+	client *http.Client
+}
+
+func NewSchemaRegistry(registryUrl string) *SchemaRegistry {
+	return &SchemaRegistry{
+		url:    strings.TrimSuffix(registryUrl, "/"),
+		client: &http.Client{Timeout: schemaRegistryTimeout},
+	}
+}
+
+// SchemaSubject returns the subject of the schema of the msg type published to the topic, following the
+// TopicRecordNameStrategy as multiple msg types can be published to one topic
+func SchemaSubject(topic string, tpe msgType) (string, error) {
+	schema, ok := latestSchemas[tpe]
+	if !ok {
+		return "", fmt.Errorf("no schema for msg type %s", tpe.String())
+	}
+	var record struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	}
+	if err := json.Unmarshal([]byte(schema), &record); err != nil {
+		return "", err
+	}
+	return topic + "-" + fullName(record.Name, record.Namespace), nil
+}
+
+type schemaRegistryError struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+func (registry *SchemaRegistry) post(path string, schema string, result interface{}) error {
+	body, err := json.Marshal(map[string]string{"schema": schema})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, registry.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", schemaRegistryContentType)
+	req.Header.Set("Accept", schemaRegistryContentType)
+	resp, err := registry.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bz, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var registryErr schemaRegistryError
+		if json.Unmarshal(bz, &registryErr) == nil && registryErr.Message != "" {
+			return fmt.Errorf("schema registry error %d: %s", registryErr.ErrorCode, registryErr.Message)
+		}
+		return fmt.Errorf("schema registry returns %s", resp.Status)
+	}
+	return json.Unmarshal(bz, result)
+}
+
+// Register registers the schema under the subject if it's not registered yet, and returns the schema id.
+// The registry rejects the schema if it breaks the compatibility level configured for the subject.
+func (registry *SchemaRegistry) Register(subject, schema string) (int32, error) {
+	var result struct {
+		Id int32 `json:"id"`
+	}
+	err := registry.post(fmt.Sprintf("/subjects/%s/versions", url.PathEscape(subject)), schema, &result)
+	return result.Id, err
+}
+
+// Lookup returns the id of the schema registered under the subject
+func (registry *SchemaRegistry) Lookup(subject, schema string) (int32, error) {
+	var result struct {
+		Id int32 `json:"id"`
+	}
+	err := registry.post(fmt.Sprintf("/subjects/%s", url.PathEscape(subject)), schema, &result)
+	return result.Id, err
+}
+
+// IsCompatible checks the schema against the latest version registered under the subject
+func (registry *SchemaRegistry) IsCompatible(subject, schema string) (bool, error) {
+	var result struct {
+		IsCompatible bool `json:"is_compatible"`
+	}
+	err := registry.post(fmt.Sprintf("/compatibility/subjects/%s/versions/latest", url.PathEscape(subject)), schema, &result)
+	return result.IsCompatible, err
+}
+
+// RegisterSchemas registers or looks up the latest schemas of the msg types published to the topics, and
+// returns the schema ids of the msg types
+func (registry *SchemaRegistry) RegisterSchemas(topics map[msgType]string, autoRegister bool) (map[msgType]int32, error) {
+	ids := make(map[msgType]int32, len(topics))
+	for tpe, topic := range topics {
+		subject, err := SchemaSubject(topic, tpe)
+		if err != nil {
+			return nil, err
+		}
+		var id int32
+		if autoRegister {
+			id, err = registry.Register(subject, latestSchemas[tpe])
+		} else {
+			id, err = registry.Lookup(subject, latestSchemas[tpe])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve schema of %s under subject %s: %v", tpe.String(), subject, err)
+		}
+		ids[tpe] = id
+	}
+	return ids, nil
+}
+
+// ToWireFormat prefixes the avro binary with the magic byte and the schema id
+func ToWireFormat(schemaId int32, avro []byte) []byte {
+	bz := make([]byte, wireFormatHeaderSize+len(avro))
+	bz[0] = wireFormatMagicByte
+	binary.BigEndian.PutUint32(bz[1:wireFormatHeaderSize], uint32(schemaId))
+	copy(bz[wireFormatHeaderSize:], avro)
+	return bz
+}
+
+// FromWireFormat returns the schema id and the avro binary of the message in the wire format
+func FromWireFormat(bz []byte) (int32, []byte, error) {
+	if len(bz) < wireFormatHeaderSize || bz[0] != wireFormatMagicByte {
+		return 0, nil, fmt.Errorf("not in the schema registry wire format")
+	}
+	return int32(binary.BigEndian.Uint32(bz[1:wireFormatHeaderSize])), bz[wireFormatHeaderSize:], nil
+}
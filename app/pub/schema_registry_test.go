@@ -0,0 +1,60 @@
+package pub
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaRegistry_RegisterSchemas(t *testing.T) {
+	registered := make(map[string]int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Schema string }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, schemaRegistryContentType, r.Header.Get("Content-Type"))
+		subject := strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/versions"), "/subjects/")
+		if strings.HasSuffix(r.URL.Path, "/versions") {
+			if _, ok := registered[subject]; !ok {
+				registered[subject] = int32(len(registered) + 1)
+			}
+		} else if _, ok := registered[subject]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code": 40401, "message": "Subject not found."}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]int32{"id": registered[subject]})
+	}))
+	defer server.Close()
+
+	registry := NewSchemaRegistry(server.URL + "/")
+	topics := map[msgType]string{booksTpe: "books", klineTpe: "klines"}
+
+	_, err := registry.RegisterSchemas(map[msgType]string{booksTpe: "books"}, false)
+	require.EqualError(t, err, "failed to resolve schema of Books under subject books-com.company.Books: "+
+		"schema registry error 40401: Subject not found.")
+
+	ids, err := registry.RegisterSchemas(topics, true)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	require.NotEqual(t, ids[booksTpe], ids[klineTpe])
+
+	lookedUp, err := registry.RegisterSchemas(topics, false)
+	require.NoError(t, err)
+	require.Equal(t, ids, lookedUp)
+}
+
+func TestWireFormat(t *testing.T) {
+	bz := ToWireFormat(258, []byte{42})
+	require.Equal(t, []byte{0, 0, 0, 1, 2, 42}, bz)
+	id, avro, err := FromWireFormat(bz)
+	require.NoError(t, err)
+	require.Equal(t, int32(258), id)
+	require.Equal(t, []byte{42}, avro)
+
+	_, _, err = FromWireFormat([]byte{1, 0, 0, 1, 2})
+	require.Error(t, err)
+}
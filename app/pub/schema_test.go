@@ -40,9 +40,9 @@ func TestExecutionResultsMarshaling(t *testing.T) {
 	orders := Orders{
 		NumOfMsgs: 3,
 		Orders: []*Order{
-			{"NNB_BNB", orderPkg.Ack, "b-1", "", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 0, 0, 0, "", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", ""},
-			{"NNB_BNB", orderPkg.FullyFill, "b-1", "42-0", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:10;BTC:1", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:10;BTC:1"},
-			{"NNB_BNB", orderPkg.FullyFill, "s-1", "42-0", "s", orderPkg.Side.SELL, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:8;ETH:1", 99, 99, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:8;ETH:1"},
+			{"NNB_BNB", orderPkg.Ack, "b-1", "", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 0, 0, 0, "", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "", nil},
+			{"NNB_BNB", orderPkg.FullyFill, "b-1", "42-0", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:10;BTC:1", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:10;BTC:1", nil},
+			{"NNB_BNB", orderPkg.FullyFill, "s-1", "42-0", "s", orderPkg.Side.SELL, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:8;ETH:1", 99, 99, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:8;ETH:1", nil},
 		},
 	}
 	proposals := Proposals{
@@ -83,7 +83,7 @@ func TestExecutionResultsMarshaling(t *testing.T) {
 
 func TestBooksMarshaling(t *testing.T) {
 	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
-	book := OrderBookDelta{"NNB_BNB", []PriceLevel{{100, 100}}, []PriceLevel{{100, 100}}}
+	book := OrderBookDelta{"NNB_BNB", []PriceLevel{{100, 100}}, []PriceLevel{{100, 100}}, 1, false}
 	msg := Books{42, 100, 1, []OrderBookDelta{book}}
 	_, err := publisher.marshal(&msg, booksTpe)
 	if err != nil {
@@ -181,6 +181,23 @@ func TestSideProposalMarsha(t *testing.T) {
 	}
 }
 
+func TestKlinesMarshaling(t *testing.T) {
+	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
+	msg := Klines{
+		Height:    10,
+		NumOfMsgs: 1,
+		Timestamp: time.Now().Unix(),
+		Klines: []*Kline{
+			{Symbol: "XYZ-000_BNB", Interval: "1m", OpenTime: 60000, CloseTime: 119999, Open: 100, High: 120, Low: 90,
+				Close: 110, Volume: 1000, QuoteVolume: 1100, NumberOfTrades: 3, Closed: true},
+		},
+	}
+	_, err := publisher.marshal(&msg, klineTpe)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestStakingMarshaling(t *testing.T) {
 	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
 	valAddr, _ := sdk.ValAddressFromBech32("bva1e2y8w2rz957lahwy0y5h3w53sm8d78qexkn3rh")
@@ -184,7 +184,9 @@ const (
                                 { "name": "sells", "type": {
                                     "type": "array",
                                     "items": "com.company.PriceLevel"
-                                } }
+                                } },
+                                { "name": "sequence", "type": "long", "default": 0 },
+                                { "name": "snapshot", "type": "boolean", "default": false }
                             ]
                         }
                     }, "default": []
@@ -967,4 +969,41 @@ const (
 			]
 		}
 	`
+
+	klinesSchema = `
+		{
+			"type": "record",
+			"name": "Klines",
+			"namespace": "org.binance.dex.model.avro",
+			"fields": [
+				{ "name": "height", "type": "long" },
+				{ "name": "timestamp", "type": "long" },
+				{ "name": "numOfMsgs", "type": "int" },
+				{ "name": "klines", "type": {
+					"type": "array",
+					"items":
+					{
+						"type": "record",
+						"name": "Kline",
+						"namespace": "org.binance.dex.model.avro",
+						"fields": [
+							{ "name": "symbol", "type": "string" },
+							{ "name": "interval", "type": "string" },
+							{ "name": "openTime", "type": "long" },
+							{ "name": "closeTime", "type": "long" },
+							{ "name": "open", "type": "long" },
+							{ "name": "high", "type": "long" },
+							{ "name": "low", "type": "long" },
+							{ "name": "close", "type": "long" },
+							{ "name": "volume", "type": "long" },
+							{ "name": "quoteVolume", "type": "long" },
+							{ "name": "numberOfTrades", "type": "long" },
+							{ "name": "closed", "type": "boolean" }
+						]
+					}
+				   }
+				}
+			]
+		}
+	`
 )
@@ -1,6 +1,7 @@
 package pub
 
 import (
+	"github.com/bnb-chain/node/app/pub/sub"
 	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
 )
 
@@ -20,6 +21,10 @@ type BlockInfoToPublish struct {
 	feeHolder          orderPkg.FeeHolder
 	transfers          *Transfers
 	block              *Block
+	klines             *Klines
+	bookSnapshot       bool                // publish the full order books instead of the changed levels
+	events             *sub.ToPublishEvent // published along with the block if they share the kafka transaction
+	committed          chan<- struct{}     // closed once all the messages of the block are published, nil if not waited
 }
 
 func NewBlockInfoToPublish(
@@ -34,7 +39,8 @@ func NewBlockInfoToPublish(
 	accounts map[string]Account,
 	latestPriceLevels orderPkg.ChangedPriceLevelsMap,
 	blockFee BlockFee,
-	feeHolder orderPkg.FeeHolder, transfers *Transfers, block *Block) BlockInfoToPublish {
+	feeHolder orderPkg.FeeHolder, transfers *Transfers, block *Block, klines *Klines, bookSnapshot bool,
+	events *sub.ToPublishEvent, committed chan<- struct{}) BlockInfoToPublish {
 	return BlockInfoToPublish{
 		height,
 		timestamp,
@@ -50,5 +56,9 @@ func NewBlockInfoToPublish(
 		feeHolder,
 		transfers,
 		block,
+		klines,
+		bookSnapshot,
+		events,
+		committed,
 	}
 }
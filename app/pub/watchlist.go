@@ -0,0 +1,254 @@
+package pub
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
+)
+
+// WatchList restricts the publication to the addresses, the symbols and the msg types in it, a dimension
+// without any entry is not restricted. The addresses filter the accounts, the transfers, the orders and the
+// trades, and the symbols filter the orders, the trades, the order books and the klines.
+//
+// It's loaded from a json file like:
+//
+//	{"addresses": ["bnb1..."], "symbols": ["XYZ-000_BNB"], "msgTypes": ["Accounts", "Transfers"]}
+type WatchList struct {
+	addresses map[string]bool // bytes of the addresses
+	symbols   map[string]bool
+	msgTypes  map[string]bool
+}
+
+type watchListFile struct {
+	Addresses []string `json:"addresses"`
+	Symbols   []string `json:"symbols"`
+	MsgTypes  []string `json:"msgTypes"`
+}
+
+// watchList is nil if the watch list is not configured
+var watchList atomic.Value
+
+func LoadWatchList(path string) (*WatchList, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file watchListFile
+	if err := json.Unmarshal(bz, &file); err != nil {
+		return nil, fmt.Errorf("invalid watch list %s: %v", path, err)
+	}
+	list := &WatchList{
+		addresses: make(map[string]bool, len(file.Addresses)),
+		symbols:   make(map[string]bool, len(file.Symbols)),
+		msgTypes:  make(map[string]bool, len(file.MsgTypes)),
+	}
+	for _, bech32Addr := range file.Addresses {
+		addr, err := sdk.AccAddressFromBech32(bech32Addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %s in watch list: %v", bech32Addr, err)
+		}
+		list.addresses[string(addr.Bytes())] = true
+	}
+	for _, symbol := range file.Symbols {
+		list.symbols[symbol] = true
+	}
+	for _, name := range file.MsgTypes {
+		if _, ok := msgTypeOf(name); !ok {
+			return nil, fmt.Errorf("unknown msg type %s in watch list", name)
+		}
+		list.msgTypes[name] = true
+	}
+	return list, nil
+}
+
+// SetWatchList replaces the watch list, nil publishes everything
+func SetWatchList(list *WatchList) {
+	watchList.Store(list)
+}
+
+func getWatchList() *WatchList {
+	list, _ := watchList.Load().(*WatchList)
+	return list
+}
+
+// watchWatchList loads the watch list from the file and reloads it every interval once the file is modified
+// until quit is closed, it's not reloaded if the interval is not positive.
+// A modified file that fails to load is reported and the watch list in use is kept.
+func watchWatchList(path string, interval time.Duration, quit <-chan struct{}) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	list, err := LoadWatchList(path)
+	if err != nil {
+		return err
+	}
+	SetWatchList(list)
+	Logger.Info("loaded watch list", "path", path, "addresses", len(list.addresses),
+		"symbols", len(list.symbols), "msgTypes", len(list.msgTypes))
+	if interval <= 0 {
+		return nil
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		modTime := info.ModTime()
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				Logger.Error("failed to stat watch list", "path", path, "err", err)
+				continue
+			}
+			if info.ModTime().Equal(modTime) {
+				continue
+			}
+			modTime = info.ModTime()
+			list, err := LoadWatchList(path)
+			if err != nil {
+				Logger.Error("failed to reload watch list", "path", path, "err", err)
+				continue
+			}
+			SetWatchList(list)
+			Logger.Info("reloaded watch list", "path", path, "addresses", len(list.addresses),
+				"symbols", len(list.symbols), "msgTypes", len(list.msgTypes))
+		}
+	}()
+	return nil
+}
+
+func (list *WatchList) watchAddress(addrBytes string) bool {
+	return list == nil || len(list.addresses) == 0 || list.addresses[addrBytes]
+}
+
+func (list *WatchList) watchSymbol(symbol string) bool {
+	return list == nil || len(list.symbols) == 0 || list.symbols[symbol]
+}
+
+func (list *WatchList) watchMsgType(tpe msgType) bool {
+	return list == nil || len(list.msgTypes) == 0 || list.msgTypes[tpe.String()]
+}
+
+// watchOrder checks the owner of the order, which is a bech32 address unlike the raw bytes of the trades
+func (list *WatchList) watchOrder(order *Order) bool {
+	if !list.watchSymbol(order.Symbol) {
+		return false
+	}
+	if list == nil || len(list.addresses) == 0 {
+		return true
+	}
+	owner, err := sdk.AccAddressFromBech32(order.Owner)
+	return err == nil && list.addresses[string(owner.Bytes())]
+}
+
+func (list *WatchList) watchTrade(trade *Trade) bool {
+	return list.watchSymbol(trade.Symbol) && (list.watchAddress(trade.SAddr) || list.watchAddress(trade.BAddr))
+}
+
+func (list *WatchList) watchSend(msg bank.MsgSend) bool {
+	if list == nil || len(list.addresses) == 0 {
+		return true
+	}
+	for _, input := range msg.Inputs {
+		if list.addresses[string(input.Address.Bytes())] {
+			return true
+		}
+	}
+	for _, output := range msg.Outputs {
+		if list.addresses[string(output.Address.Bytes())] {
+			return true
+		}
+	}
+	return false
+}
+
+func (list *WatchList) filterOrders(orders []*Order) []*Order {
+	if list == nil || len(list.addresses) == 0 && len(list.symbols) == 0 {
+		return orders
+	}
+	res := make([]*Order, 0, len(orders))
+	for _, order := range orders {
+		if list.watchOrder(order) {
+			res = append(res, order)
+		}
+	}
+	return res
+}
+
+func (list *WatchList) filterTrades(trades []*Trade) []*Trade {
+	if list == nil || len(list.addresses) == 0 && len(list.symbols) == 0 {
+		return trades
+	}
+	res := make([]*Trade, 0, len(trades))
+	for _, trade := range trades {
+		if list.watchTrade(trade) {
+			res = append(res, trade)
+		}
+	}
+	return res
+}
+
+func (list *WatchList) filterOrderBooks(books orderPkg.ChangedPriceLevelsMap) orderPkg.ChangedPriceLevelsMap {
+	if list == nil || len(list.symbols) == 0 {
+		return books
+	}
+	res := make(orderPkg.ChangedPriceLevelsMap, len(list.symbols))
+	for symbol, book := range books {
+		if list.symbols[symbol] {
+			res[symbol] = book
+		}
+	}
+	return res
+}
+
+// WatchListMarketDataPublisher drops the messages of the msg types out of the watch list,
+// and keeps reloading the watch list file until it's stopped
+type WatchListMarketDataPublisher struct {
+	MarketDataPublisher
+	quit chan struct{}
+}
+
+func NewWatchListMarketDataPublisher(publisher MarketDataPublisher, path string, reloadInterval time.Duration) (
+	*WatchListMarketDataPublisher, error) {
+	quit := make(chan struct{})
+	if err := watchWatchList(path, reloadInterval, quit); err != nil {
+		return nil, err
+	}
+	return &WatchListMarketDataPublisher{publisher, quit}, nil
+}
+
+func (publisher *WatchListMarketDataPublisher) Stop() {
+	close(publisher.quit)
+	publisher.MarketDataPublisher.Stop()
+}
+
+func (publisher *WatchListMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64) {
+	if getWatchList().watchMsgType(tpe) {
+		publisher.MarketDataPublisher.publish(msg, tpe, height, timestamp)
+	}
+}
+
+func (publisher *WatchListMarketDataPublisher) BeginHeight(height int64) {
+	if transactor, ok := publisher.MarketDataPublisher.(HeightTransactor); ok {
+		transactor.BeginHeight(height)
+	}
+}
+
+func (publisher *WatchListMarketDataPublisher) CommitHeight(height int64) error {
+	if transactor, ok := publisher.MarketDataPublisher.(HeightTransactor); ok {
+		return transactor.CommitHeight(height)
+	}
+	return nil
+}
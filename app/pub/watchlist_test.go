@@ -0,0 +1,112 @@
+package pub
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
+)
+
+func writeWatchList(t *testing.T, path string, content string, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestLoadWatchList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlist.json")
+	watched := sdk.AccAddress([]byte("watched-address-0000"))
+	other := sdk.AccAddress([]byte("other-address-000000"))
+
+	writeWatchList(t, path, `{"msgTypes": ["Unknown"]}`, time.Now())
+	_, err := LoadWatchList(path)
+	require.EqualError(t, err, "unknown msg type Unknown in watch list")
+
+	writeWatchList(t, path, fmt.Sprintf(`{"addresses": ["%s"], "symbols": ["XYZ-000_BNB"]}`, watched), time.Now())
+	list, err := LoadWatchList(path)
+	require.NoError(t, err)
+
+	require.True(t, list.watchAddress(string(watched)))
+	require.False(t, list.watchAddress(string(other)))
+	require.True(t, list.watchMsgType(booksTpe))
+	// the orders are built the same way as they are published
+	newOrder := func(id, symbol string, owner sdk.AccAddress) *orderPkg.OrderInfo {
+		msg := orderPkg.NewNewOrderMsg(owner, id, orderPkg.Side.BUY, symbol, 1e8, 1e8)
+		return &orderPkg.OrderInfo{NewOrderMsg: msg}
+	}
+	infos := orderPkg.OrderInfoForPublish{
+		"1": newOrder("1", "XYZ-000_BNB", watched),
+		"2": newOrder("2", "XYZ-000_BNB", other),
+		"3": newOrder("3", "ZCB-000_BNB", watched),
+	}
+	changes := orderPkg.OrderChanges{{Id: "1", Tpe: orderPkg.Ack}, {Id: "2", Tpe: orderPkg.Ack}, {Id: "3", Tpe: orderPkg.Ack}}
+	opens, _ := collectOrders(changes, infos, 0, map[string]int{}, map[string]int{})
+	filtered := list.filterOrders(opens)
+	require.Len(t, filtered, 1)
+	require.Equal(t, "1", filtered[0].OrderId)
+	require.Equal(t, watched.String(), filtered[0].Owner)
+	require.Len(t, list.filterTrades([]*Trade{
+		{Symbol: "XYZ-000_BNB", SAddr: string(other), BAddr: string(watched)},
+		{Symbol: "XYZ-000_BNB", SAddr: string(other), BAddr: string(other)},
+	}), 1)
+	books := list.filterOrderBooks(orderPkg.ChangedPriceLevelsMap{
+		"XYZ-000_BNB": orderPkg.ChangedPriceLevelsPerSymbol{},
+		"ZCB-000_BNB": orderPkg.ChangedPriceLevelsPerSymbol{},
+	})
+	require.Len(t, books, 1)
+	require.Contains(t, books, "XYZ-000_BNB")
+
+	coins := sdk.Coins{sdk.NewCoin("BNB", 1)}
+	require.True(t, list.watchSend(bank.NewMsgSend(
+		[]bank.Input{bank.NewInput(other, coins)}, []bank.Output{bank.NewOutput(watched, coins)})))
+	require.False(t, list.watchSend(bank.NewMsgSend(
+		[]bank.Input{bank.NewInput(other, coins)}, []bank.Output{bank.NewOutput(other, coins)})))
+
+	// nothing is filtered without a watch list
+	var none *WatchList
+	require.True(t, none.watchAddress(string(other)))
+	require.True(t, none.watchSymbol("ZCB-000_BNB"))
+	require.True(t, none.watchMsgType(accountsTpe))
+}
+
+func TestWatchListMarketDataPublisher(t *testing.T) {
+	defer SetWatchList(nil)
+	path := filepath.Join(t.TempDir(), "watchlist.json")
+	modTime := time.Now().Add(-time.Minute)
+	writeWatchList(t, path, `{"msgTypes": ["BlockFee"]}`, modTime)
+
+	mock := NewMockMarketDataPublisher()
+	publisher, err := NewWatchListMarketDataPublisher(mock, path, 10*time.Millisecond)
+	require.NoError(t, err)
+	publisher.publish(BlockFee{Height: 1}, blockFeeTpe, 1, 1)
+	publisher.publish(&Books{Height: 1}, booksTpe, 1, 1)
+	require.Len(t, mock.BlockFeePublished, 1)
+	require.Len(t, mock.BooksPublished, 0)
+
+	// an invalid watch list is not loaded
+	writeWatchList(t, path, `{"msgTypes": ["Books"`, modTime.Add(time.Second))
+	time.Sleep(50 * time.Millisecond)
+	require.True(t, getWatchList().watchMsgType(blockFeeTpe))
+
+	writeWatchList(t, path, `{"msgTypes": ["Books"]}`, modTime.Add(2*time.Second))
+	require.Eventually(t, func() bool {
+		return getWatchList().watchMsgType(booksTpe)
+	}, time.Second, 10*time.Millisecond)
+	publisher.publish(BlockFee{Height: 2}, blockFeeTpe, 2, 2)
+	publisher.publish(&Books{Height: 2}, booksTpe, 2, 2)
+	require.Len(t, mock.BlockFeePublished, 1)
+	require.Len(t, mock.BooksPublished, 1)
+
+	// the watch list is not reloaded once the publisher is stopped
+	publisher.Stop()
+	writeWatchList(t, path, `{"msgTypes": ["BlockFee"]}`, modTime.Add(3*time.Second))
+	time.Sleep(50 * time.Millisecond)
+	require.False(t, getWatchList().watchMsgType(blockFeeTpe))
+}
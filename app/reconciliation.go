@@ -14,6 +14,9 @@ import (
 
 const globalAccountNumber = "globalAccountNumber"
 
+// accountStoreKeyPrefix is the prefix of the keys of the accounts in the account store, see auth.AddressStoreKey
+const accountStoreKeyPrefix = "account:"
+
 // unbalancedBlockHeightKey for saving unbalanced block height for reconciliation
 var unbalancedBlockHeightKey = []byte("0x01")
 
@@ -84,6 +87,29 @@ func (app *BNBBeaconChain) getAccountChanges(ctx sdk.Context, accountStore *stor
 	return preCoins, currentCoins
 }
 
+// updateTokenHolders updates the token holder index with the accounts changed in the block
+func (app *BNBBeaconChain) updateTokenHolders(ctx sdk.Context, accountStore *store.IavlStore) {
+	for k := range accountStore.GetDiff() {
+		if !strings.HasPrefix(k, accountStoreKeyPrefix) {
+			continue
+		}
+		addr := sdk.AccAddress(k[len(accountStoreKeyPrefix):])
+		v := accountStore.Get([]byte(k))
+		if v == nil {
+			app.TokenHolders.Update(addr, nil)
+			continue
+		}
+		var acc sdk.Account
+		if err := app.Codec.UnmarshalBinaryBare(v, &acc); err != nil {
+			ctx.Logger().Error("failed to unmarshal current account value", "err", err.Error())
+			continue
+		}
+		if nacc, ok := acc.(types.NamedAccount); ok {
+			app.TokenHolders.Update(addr, nacc)
+		}
+	}
+}
+
 func (app *BNBBeaconChain) getTokenChanges(ctx sdk.Context, tokenStore *store.IavlStore) (sdk.Coins, sdk.Coins) {
 	preCoins := sdk.Coins{}
 	currentCoins := sdk.Coins{}
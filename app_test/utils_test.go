@@ -25,6 +25,7 @@ import (
 
 // this file has to named with suffix _test, this is a golang bug: https://github.com/golang/go/issues/24895
 var (
+	home   = testutils.SetupTempHome()
 	keeper *orderPkg.DexKeeper
 	buyer  sdk.AccAddress
 	seller sdk.AccAddress
@@ -34,6 +35,12 @@ var (
 	cdc    *wire.Codec
 )
 
+func TestMain(m *testing.M) {
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
 func setup(t *testing.T, symbol string, upgrade bool) (ass *assert.Assertions, req *require.Assertions, pair string) {
 	baseAssetSymbol := symbol
 	logger := log.NewTMLogger(os.Stdout)
@@ -0,0 +1,108 @@
+package init
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/libs/cli"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/node"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/app"
+	configPkg "github.com/bnb-chain/node/app/config"
+	"github.com/bnb-chain/node/common"
+	tokenRecover "github.com/bnb-chain/node/plugins/recover"
+)
+
+const (
+	flagOutput    = "output"
+	flagChunkSize = "chunk-size"
+)
+
+func ExportFusionCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-fusion",
+		Short: "Export the balances, tokens, time locks and open swaps at a height for the token recovery after BC fusion",
+		Long: `Export the balances, tokens, time locks, vestings and open swaps at a height into chunked json files,
+together with a manifest containing the merkle root over the (address, symbol, amount) leaves of the user balances.
+
+$ bnbchaind export-fusion --height 1000000 --output ./fusion-export
+`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			height := viper.GetInt64(flagHeight)
+			if height <= 0 {
+				return fmt.Errorf("--%s should be positive", flagHeight)
+			}
+			chunkSize := viper.GetInt(flagChunkSize)
+			if chunkSize <= 0 {
+				return fmt.Errorf("--%s should be positive", flagChunkSize)
+			}
+
+			logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout))
+
+			config := ctx.Config
+			config.SetRoot(viper.GetString(cli.HomeFlag))
+			appCtx := configPkg.NewDefaultContext()
+			if err := appCtx.ParseAppConfigInPlace(); err != nil {
+				return err
+			}
+			app.SetUpgradeConfig(appCtx.BNBBeaconChainConfig.UpgradeConfig)
+
+			output := viper.GetString(flagOutput)
+			if output == "" {
+				output = filepath.Join(config.RootDir, fmt.Sprintf("fusion-export-%d", height))
+			}
+
+			logger.Info("setup application db")
+			appDB, err := node.DefaultDBProvider(&node.DBContext{ID: "application", Config: config})
+			if err != nil {
+				return err
+			}
+			defer appDB.Close()
+
+			cms := store.NewCommitMultiStore(appDB)
+			for _, name := range common.NonTransientStoreKeyNames {
+				cms.MountStoreWithDB(common.StoreKeyNameMap[name], sdk.StoreTypeIAVL, nil)
+			}
+			cms.MountStoreWithDB(common.TParamsStoreKey, sdk.StoreTypeTransient, nil)
+			cms.MountStoreWithDB(common.TStakeStoreKey, sdk.StoreTypeTransient, nil)
+
+			logger.Info("load version", "height", height)
+			if err := cms.LoadVersion(height); err != nil {
+				return err
+			}
+			sdk.UpgradeMgr.SetHeight(height)
+
+			snapshot, err := app.ExportFusionSnapshot(cdc, cms.CacheMultiStore(), height, cms.LastCommitID().Hash)
+			if err != nil {
+				return err
+			}
+
+			manifest, err := tokenRecover.WriteSnapshot(cdc, output, snapshot, chunkSize)
+			if err != nil {
+				return err
+			}
+			logger.Info("exported fusion snapshot", "height", height, "output", output, "accounts", len(snapshot.Accounts),
+				"tokens", len(snapshot.Tokens)+len(snapshot.MiniTokens), "timeLocks", len(snapshot.TimeLocks),
+				"vestings", len(snapshot.Vestings), "swaps", len(snapshot.Swaps), "leaves", manifest.Leaves,
+				"merkleRoot", manifest.MerkleRoot)
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64(flagHeight, 0, "the height to export, the state of the height must not have been pruned")
+	cmd.Flags().String(flagOutput, "", "the directory to write the exported files to, default to $home/fusion-export-$height")
+	cmd.Flags().Int(flagChunkSize, tokenRecover.DefaultSnapshotChunkSize, "the max number of entries in a chunk file")
+	_ = cmd.MarkFlagRequired(flagHeight)
+
+	return cmd
+}
@@ -0,0 +1,89 @@
+package init
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/libs/cli"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/server"
+
+	configPkg "github.com/bnb-chain/node/app/config"
+	"github.com/bnb-chain/node/app/pub"
+)
+
+const (
+	flagFromHeight = "from-height"
+	flagToHeight   = "to-height"
+	flagTarget     = "target"
+)
+
+func PublishCmd(ctx *server.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "publish",
+		Short: "Manage the messages in the publication log",
+	}
+	cmd.AddCommand(publishReplayCmd(ctx))
+	return cmd
+}
+
+func publishReplayCmd(ctx *server.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Publish the messages of the historical heights in the publication log again in the original order",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			fromHeight := viper.GetInt64(flagFromHeight)
+			toHeight := viper.GetInt64(flagToHeight)
+			if fromHeight <= 0 {
+				return fmt.Errorf("--%s should be positive", flagFromHeight)
+			}
+			if toHeight > 0 && toHeight < fromHeight {
+				return fmt.Errorf("--%s should not be less than --%s", flagToHeight, flagFromHeight)
+			}
+			target := viper.GetString(flagTarget)
+			if target != configPkg.KafkaPublisher && target != configPkg.StdoutPublisher {
+				return fmt.Errorf("unsupported target %s, should be %s or %s",
+					target, configPkg.KafkaPublisher, configPkg.StdoutPublisher)
+			}
+
+			// the messages are written to stdout with the stdout target
+			logger := log.NewTMLogger(log.NewSyncWriter(os.Stderr))
+
+			config := ctx.Config
+			config.SetRoot(viper.GetString(cli.HomeFlag))
+			appCtx := configPkg.NewDefaultContext()
+			if err := appCtx.ParseAppConfigInPlace(); err != nil {
+				return err
+			}
+			pub.Logger = logger.With("module", "pub")
+			pub.Cfg = appCtx.PublicationConfig
+
+			publishers, err := pub.NewPublishers([]string{target}, pub.PublisherEnv{
+				Logger:  logger,
+				Config:  appCtx.PublicationConfig,
+				RootDir: config.RootDir,
+				DBDir:   config.DBDir(),
+			})
+			if err != nil {
+				return err
+			}
+			defer publishers[0].Stop()
+
+			dir := filepath.Join(config.DBDir(), pub.PublicationLogDir)
+			logger.Info("replay publication log", "dir", dir, "from", fromHeight, "to", toHeight, "target", target)
+			num, err := pub.ReplayPublicationLog(dir, fromHeight, toHeight, publishers[0])
+			logger.Info("replayed publication log", "messages", num)
+			return err
+		},
+	}
+	cmd.Flags().Int64(flagFromHeight, 1, "the first height to replay")
+	cmd.Flags().Int64(flagToHeight, 0, "the last height to replay, 0 to replay to the end of the log")
+	cmd.Flags().String(flagTarget, configPkg.StdoutPublisher,
+		fmt.Sprintf("the publisher to replay to, %s or %s", configPkg.KafkaPublisher, configPkg.StdoutPublisher))
+	return cmd
+}
@@ -48,6 +48,8 @@ func main() {
 	startCmd.Flags().Int64VarP(&ctx.PublicationConfig.FromHeightInclusive, "fromHeight", "f", 1, "from which height (inclusive) we want publish market data")
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(bnbInit.SnapshotCmd(ctx.ToCosmosServerCtx(), cdc))
+	rootCmd.AddCommand(bnbInit.PublishCmd(ctx.ToCosmosServerCtx()))
+	rootCmd.AddCommand(bnbInit.ExportFusionCmd(ctx.ToCosmosServerCtx(), cdc))
 
 	// prepare and add flags
 	executor := cli.PrepareBaseCmd(rootCmd, "BC", app.DefaultNodeHome)
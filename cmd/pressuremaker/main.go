@@ -14,6 +14,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/cosmos/cosmos-sdk/server"
+	dbm "github.com/tendermint/tendermint/libs/db"
 
 	"github.com/bnb-chain/node/app/config"
 	"github.com/bnb-chain/node/app/pub"
@@ -89,7 +90,8 @@ var rootCmd = &cobra.Command{
 		pub.Cfg = &cfg.PublicationConfig
 		pub.ToPublishCh = make(chan pub.BlockInfoToPublish, cfg.PublicationConfig.PublicationChannelSize)
 		publisher := pub.NewKafkaMarketDataPublisher(pub.Logger, "", false)
-		go pub.Publish(publisher, nil, pub.Logger, pub.Cfg, pub.ToPublishCh)
+		bookSequencer, _ := pub.NewBookSequencer(dbm.NewMemDB())
+		go pub.Publish(publisher, nil, pub.Logger, pub.Cfg, bookSequencer, pub.ToPublishCh)
 		pub.IsLive = true
 		srv := &http.Server{
 			Addr: cfg.PrometheusAddr,
@@ -60,8 +60,8 @@ func (mg *MessageGenerator) OneOnOneMessages(height int, timeNow time.Time) (tra
 		mg.OrderChangeMap[buyOrder.Id] = &buyOrder
 		mg.OrderChangeMap[sellOrder.Id] = &sellOrder
 
-		orderChanges[i*2] = orderPkg.OrderChange{buyOrder.Id, orderPkg.Ack, "", nil}
-		orderChanges[i*2+1] = orderPkg.OrderChange{sellOrder.Id, orderPkg.Ack, "", nil}
+		orderChanges[i*2] = orderPkg.OrderChange{buyOrder.Id, orderPkg.Ack, "", nil, nil}
+		orderChanges[i*2+1] = orderPkg.OrderChange{sellOrder.Id, orderPkg.Ack, "", nil, nil}
 
 		tradesToPublish[i] = makeTradeToPub(fmt.Sprintf("%d-%d", height, i), sellOrder.Id, buyOrder.Id, mg.sellerAddrs[i].String(), mg.buyerAddrs[i].String(), price, amount)
 
@@ -89,7 +89,7 @@ func (mg *MessageGenerator) TwoOnOneMessages(height int, timeNow time.Time) (tra
 		for i := 0; i < mg.NumOfTradesPerBlock; i++ {
 			buyOrder := makeOrderInfo(mg.buyerAddrs[i], 1, int64(height), 100000000, 100000000, 0, timePub)
 			mg.OrderChangeMap[buyOrder.Id] = &buyOrder
-			orderChanges[i] = orderPkg.OrderChange{buyOrder.Id, orderPkg.Ack, "", nil}
+			orderChanges[i] = orderPkg.OrderChange{buyOrder.Id, orderPkg.Ack, "", nil, nil}
 		}
 	} else {
 		// place big sell orders
@@ -107,7 +107,7 @@ func (mg *MessageGenerator) TwoOnOneMessages(height int, timeNow time.Time) (tra
 			}
 			sellOrder := makeOrderInfo(mg.sellerAddrs[i/2], 2, int64(height), 100000000, 200000000, cumQty, timePub)
 			if i%2 == 0 {
-				orderChanges[i/2] = orderPkg.OrderChange{sellOrder.Id, orderPkg.Ack, "", nil}
+				orderChanges[i/2] = orderPkg.OrderChange{sellOrder.Id, orderPkg.Ack, "", nil, nil}
 			}
 			tradesToPublish[i] = makeTradeToPub(fmt.Sprintf("%d-%d", height, i), buyOrder.Id, sellOrder.Id, mg.sellerAddrs[i].String(),
 				mg.buyerAddrs[i].String(), 100000000, 100000000)
@@ -135,7 +135,7 @@ func (mg *MessageGenerator) ExpireMessages(height int, timeNow time.Time) (trade
 	for i := 0; i < 1000000; i++ {
 		o := makeOrderInfo(mg.buyerAddrs[0], 1, int64(height), 1000000000, 1000000000, 500000000, timePub)
 		mg.OrderChangeMap[fmt.Sprintf("%d", i)] = &o
-		orderChanges = append(orderChanges, orderPkg.OrderChange{fmt.Sprintf("%d", i), orderPkg.Expired, "", nil})
+		orderChanges = append(orderChanges, orderPkg.OrderChange{fmt.Sprintf("%d", i), orderPkg.Expired, "", nil, nil})
 	}
 	return
 }
@@ -159,7 +159,11 @@ func (mg MessageGenerator) Publish(height, timePub int64, tradesToPublish []*pub
 		pub.BlockFee{},
 		nil,
 		transfers,
-		block)
+		block,
+		nil,
+		false,
+		nil,
+		nil)
 }
 
 func makeOrderInfo(sender sdk.AccAddress, side int8, height, price, qty, cumQty, timePub int64) orderPkg.OrderInfo {
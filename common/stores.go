@@ -3,24 +3,25 @@ package common
 import sdk "github.com/cosmos/cosmos-sdk/types"
 
 const (
-	MainStoreName        = "main"
-	AccountStoreName     = "acc"
-	ValAddrStoreName     = "val"
-	TokenStoreName       = "tokens"
-	DexStoreName         = "dex"
-	PairStoreName        = "pairs"
-	StakeStoreName       = "stake"
-	StakeRewardStoreName = "stake_reward"
-	SlashingStoreName    = "slashing"
-	ParamsStoreName      = "params"
-	GovStoreName         = "gov"
-	TimeLockStoreName    = "time_lock"
-	AtomicSwapStoreName  = "atomic_swap"
-	BridgeStoreName      = "bridge"
-	OracleStoreName      = "oracle"
-	IbcStoreName         = "ibc"
-	SideChainStoreName   = "sc"
-	ReconStoreName       = "recon"
+	MainStoreName          = "main"
+	AccountStoreName       = "acc"
+	ValAddrStoreName       = "val"
+	TokenStoreName         = "tokens"
+	DexStoreName           = "dex"
+	PairStoreName          = "pairs"
+	StakeStoreName         = "stake"
+	StakeRewardStoreName   = "stake_reward"
+	SlashingStoreName      = "slashing"
+	ParamsStoreName        = "params"
+	GovStoreName           = "gov"
+	TimeLockStoreName      = "time_lock"
+	AtomicSwapStoreName    = "atomic_swap"
+	BridgeStoreName        = "bridge"
+	OracleStoreName        = "oracle"
+	IbcStoreName           = "ibc"
+	SideChainStoreName     = "sc"
+	ReconStoreName         = "recon"
+	TokenMetadataStoreName = "token_metadata"
 
 	StakeTransientStoreName  = "transient_stake"
 	ParamsTransientStoreName = "transient_params"
@@ -28,24 +29,25 @@ const (
 
 var (
 	// keys to access the substores
-	MainStoreKey        = sdk.NewKVStoreKey(MainStoreName)
-	AccountStoreKey     = sdk.NewKVStoreKey(AccountStoreName)
-	ValAddrStoreKey     = sdk.NewKVStoreKey(ValAddrStoreName)
-	TokenStoreKey       = sdk.NewKVStoreKey(TokenStoreName)
-	DexStoreKey         = sdk.NewKVStoreKey(DexStoreName)
-	PairStoreKey        = sdk.NewKVStoreKey(PairStoreName)
-	StakeStoreKey       = sdk.NewKVStoreKey(StakeStoreName)
-	StakeRewardStoreKey = sdk.NewKVStoreKey(StakeRewardStoreName)
-	SlashingStoreKey    = sdk.NewKVStoreKey(SlashingStoreName)
-	ParamsStoreKey      = sdk.NewKVStoreKey(ParamsStoreName)
-	GovStoreKey         = sdk.NewKVStoreKey(GovStoreName)
-	TimeLockStoreKey    = sdk.NewKVStoreKey(TimeLockStoreName)
-	AtomicSwapStoreKey  = sdk.NewKVStoreKey(AtomicSwapStoreName)
-	BridgeStoreKey      = sdk.NewKVStoreKey(BridgeStoreName)
-	OracleStoreKey      = sdk.NewKVStoreKey(OracleStoreName)
-	IbcStoreKey         = sdk.NewKVStoreKey(IbcStoreName)
-	SideChainStoreKey   = sdk.NewKVStoreKey(SideChainStoreName)
-	ReconStoreKey       = sdk.NewKVStoreKey(ReconStoreName)
+	MainStoreKey          = sdk.NewKVStoreKey(MainStoreName)
+	AccountStoreKey       = sdk.NewKVStoreKey(AccountStoreName)
+	ValAddrStoreKey       = sdk.NewKVStoreKey(ValAddrStoreName)
+	TokenStoreKey         = sdk.NewKVStoreKey(TokenStoreName)
+	DexStoreKey           = sdk.NewKVStoreKey(DexStoreName)
+	PairStoreKey          = sdk.NewKVStoreKey(PairStoreName)
+	StakeStoreKey         = sdk.NewKVStoreKey(StakeStoreName)
+	StakeRewardStoreKey   = sdk.NewKVStoreKey(StakeRewardStoreName)
+	SlashingStoreKey      = sdk.NewKVStoreKey(SlashingStoreName)
+	ParamsStoreKey        = sdk.NewKVStoreKey(ParamsStoreName)
+	GovStoreKey           = sdk.NewKVStoreKey(GovStoreName)
+	TimeLockStoreKey      = sdk.NewKVStoreKey(TimeLockStoreName)
+	AtomicSwapStoreKey    = sdk.NewKVStoreKey(AtomicSwapStoreName)
+	BridgeStoreKey        = sdk.NewKVStoreKey(BridgeStoreName)
+	OracleStoreKey        = sdk.NewKVStoreKey(OracleStoreName)
+	IbcStoreKey           = sdk.NewKVStoreKey(IbcStoreName)
+	SideChainStoreKey     = sdk.NewKVStoreKey(SideChainStoreName)
+	ReconStoreKey         = sdk.NewKVStoreKey(ReconStoreName)
+	TokenMetadataStoreKey = sdk.NewKVStoreKey(TokenMetadataStoreName)
 
 	TStakeStoreKey  = sdk.NewTransientStoreKey(StakeTransientStoreName)
 	TParamsStoreKey = sdk.NewTransientStoreKey(ParamsTransientStoreName)
@@ -69,6 +71,7 @@ var (
 		BridgeStoreName:          BridgeStoreKey,
 		OracleStoreName:          OracleStoreKey,
 		ReconStoreName:           ReconStoreKey,
+		TokenMetadataStoreName:   TokenMetadataStoreKey,
 		StakeTransientStoreName:  TStakeStoreKey,
 		ParamsTransientStoreName: TParamsStoreKey,
 	}
@@ -92,6 +95,7 @@ var (
 		BridgeStoreName,
 		OracleStoreName,
 		ReconStoreName,
+		TokenMetadataStoreName,
 	}
 )
 
@@ -1,17 +1,32 @@
 package testutils
 
 import (
+	"os"
+
 	"github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/spf13/viper"
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/libs/cli"
 	dbm "github.com/tendermint/tendermint/libs/db"
 
 	"github.com/bnb-chain/node/common"
 	"github.com/bnb-chain/node/common/types"
 )
 
+// SetupTempHome points the node home to a new temp dir, so the block and state dbs opened by the app are created
+// out of the source tree. The caller removes the returned dir after the tests.
+func SetupTempHome() string {
+	home, err := os.MkdirTemp("", "bnbchaind-test")
+	if err != nil {
+		panic(err)
+	}
+	viper.Set(cli.HomeFlag, home)
+	return home
+}
+
 func SetupMultiStoreForUnitTest() (sdk.MultiStore, *sdk.KVStoreKey, *sdk.KVStoreKey) {
 	_, ms, capKey, capKey2, _ := SetupMultiStoreWithDBForUnitTest()
 	return ms, capKey, capKey2
@@ -0,0 +1,89 @@
+package types
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+const (
+	MaxTokenDescriptionLength = 512
+	MaxTokenWebsiteLength     = 256
+	TokenLogoHashLength       = 32 // sha256 of the logo content
+	MaxTokenSocialLinks       = 8
+	MaxTokenSocialNameLength  = 32
+	MaxTokenSocialLinkLength  = 256
+)
+
+// TokenMetadata is the metadata of a BEP2 token set by its owner, so wallets and explorers can display the token
+// without maintaining their own registries
+type TokenMetadata struct {
+	Description     string       `json:"description"`
+	Website         string       `json:"website"`
+	LogoHash        string       `json:"logo_hash"`        // hex encoded sha256 of the logo content
+	DisplayDecimals int8         `json:"display_decimals"` // decimals to display the amounts with
+	SocialLinks     []SocialLink `json:"social_links"`
+}
+
+type SocialLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func (metadata TokenMetadata) Validate() error {
+	if len(metadata.Description) > MaxTokenDescriptionLength {
+		return fmt.Errorf("description should not exceed %d characters", MaxTokenDescriptionLength)
+	}
+	if len(metadata.Website) > MaxTokenWebsiteLength {
+		return fmt.Errorf("website should not exceed %d characters", MaxTokenWebsiteLength)
+	}
+	if len(metadata.Website) > 0 {
+		if err := validateHttpURL(metadata.Website); err != nil {
+			return fmt.Errorf("invalid website: %v", err)
+		}
+	}
+	if len(metadata.LogoHash) > 0 {
+		hash, err := hex.DecodeString(metadata.LogoHash)
+		if err != nil || len(hash) != TokenLogoHashLength {
+			return fmt.Errorf("logo hash should be %d bytes in hex", TokenLogoHashLength)
+		}
+	}
+	if metadata.DisplayDecimals < 0 || metadata.DisplayDecimals > TokenDecimals {
+		return fmt.Errorf("display decimals should be between 0 and %d", TokenDecimals)
+	}
+	if len(metadata.SocialLinks) > MaxTokenSocialLinks {
+		return fmt.Errorf("social links should not exceed %d", MaxTokenSocialLinks)
+	}
+	names := make(map[string]bool, len(metadata.SocialLinks))
+	for _, link := range metadata.SocialLinks {
+		if len(link.Name) == 0 || len(link.Name) > MaxTokenSocialNameLength {
+			return fmt.Errorf("name of social link should be 1 to %d characters", MaxTokenSocialNameLength)
+		}
+		if names[link.Name] {
+			return fmt.Errorf("duplicate social link %s", link.Name)
+		}
+		names[link.Name] = true
+		if len(link.URL) > MaxTokenSocialLinkLength {
+			return fmt.Errorf("social link %s should not exceed %d characters", link.Name, MaxTokenSocialLinkLength)
+		}
+		if err := validateHttpURL(link.URL); err != nil {
+			return fmt.Errorf("invalid social link %s: %v", link.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateHttpURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("only http and https urls are allowed")
+	}
+	if len(u.Host) == 0 {
+		return errors.New("host is missing")
+	}
+	return nil
+}
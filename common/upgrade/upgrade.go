@@ -53,6 +53,17 @@ const (
 	FirstSunset                 = sdk.FirstSunsetFork  // https://github.com/bnb-chain/BEPs/pull/333 BNB Chain Fusion
 	SecondSunset                = sdk.SecondSunsetFork // https://github.com/bnb-chain/BEPs/pull/333 BNB Chain Fusion
 	FinalSunset                 = sdk.FinalSunsetFork  // https://github.com/bnb-chain/BEPs/pull/333 BNB Chain Fusion
+
+	TokenMetadata       = "TokenMetadata"       // owner-signed metadata of BEP2 tokens
+	TimeLockVesting     = "TimeLockVesting"     // vesting schedules on top of the time locks
+	TimeLockBeneficiary = "TimeLockBeneficiary" // time locks for a third-party beneficiary
+	SunsetRefundRetry   = "SunsetRefundRetry"   // retry queue of the failed refunds after SecondSunset
+	MarketOrder         = "MarketOrder"         // market orders matched at the concluded price
+	ConditionalOrder    = "ConditionalOrder"    // stop limit and take profit orders
+	FOKAndPostOnly      = "FOKAndPostOnly"      // fill-or-kill and post-only orders
+	BatchOrder          = "BatchOrder"          // batch new order and batch cancel order messages
+	CancelAllOrders     = "CancelAllOrders"     // cancel all orders message
+	AmendOrder          = "AmendOrder"          // amend order message
 )
 
 func UpgradeBEP10(before func(), after func()) {
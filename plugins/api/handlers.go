@@ -117,6 +117,22 @@ func (s *server) handleDexOpenOrdersReq(cdc *wire.Codec, ctx context.CLIContext)
 	return dexapi.OpenOrdersReqHandler(cdc, ctx)
 }
 
+func (s *server) handleDexClosedOrdersReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.ClosedOrdersReqHandler(cdc, ctx)
+}
+
+func (s *server) handleDexTradesReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.TradesReqHandler(cdc, ctx)
+}
+
+func (s *server) handleDexTickerReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.TickerReqHandler(cdc, ctx, dex.DexAbciQueryPrefix, dex.DexMiniAbciQueryPrefix)
+}
+
+func (s *server) handleDexKlinesReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.KlinesReqHandler(cdc, ctx)
+}
+
 func (s *server) handleTokenReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
 	return tksapi.GetTokenReqHandler(cdc, ctx, false)
 }
@@ -129,6 +145,14 @@ func (s *server) handleMiniTokenReq(cdc *wire.Codec, ctx context.CLIContext) htt
 	return tksapi.GetTokenReqHandler(cdc, ctx, true)
 }
 
+func (s *server) handleTokenHoldersReq(cdc *wire.Codec, ctx context.CLIContext, isMini bool) http.HandlerFunc {
+	return tksapi.GetTokenHoldersReqHandler(cdc, ctx, isMini)
+}
+
+func (s *server) handleTokenMetadataReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return tksapi.GetTokenMetadataReqHandler(cdc, ctx)
+}
+
 func (s *server) handleMiniTokensReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
 	return tksapi.GetTokensReqHandler(cdc, ctx, true)
 }
@@ -172,3 +196,7 @@ func (s *server) handleQuerySwapIDsByCreatorReq(cdc *wire.Codec, ctx context.CLI
 func (s *server) handleQuerySwapIDsByRecipientReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
 	return tksapi.QuerySwapIDsByRecipientReqHandler(cdc, ctx)
 }
+
+func (s *server) handleFusionStatusReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return tksapi.GetFusionStatusReqHandler(cdc, ctx)
+}
@@ -44,6 +44,20 @@ func (s *server) bindRoutes() *server {
 		Queries("address", "{address}", "symbol", "{symbol}").
 		Methods("GET")
 
+	r.HandleFunc(prefix+"/orders/closed", s.handleDexClosedOrdersReq(s.cdc, s.ctx)).
+		Queries("address", "{address}").
+		Methods("GET")
+	r.HandleFunc(prefix+"/trades", s.handleDexTradesReq(s.cdc, s.ctx)).
+		Queries("symbol", "{symbol}").
+		Methods("GET")
+
+	r.HandleFunc(prefix+"/ticker/24hr", s.handleDexTickerReq(s.cdc, s.ctx)).
+		Methods("GET")
+
+	r.HandleFunc(prefix+"/klines", s.handleDexKlinesReq(s.cdc, s.ctx)).
+		Queries("symbol", "{symbol}", "interval", "{interval}").
+		Methods("GET")
+
 	r.HandleFunc(prefix+"/mini/markets", s.handleMiniPairsReq(s.cdc, s.ctx)).
 		Methods("GET")
 
@@ -52,6 +66,10 @@ func (s *server) bindRoutes() *server {
 		Methods("GET")
 	r.HandleFunc(prefix+"/tokens/{symbol}", s.handleTokenReq(s.cdc, s.ctx)).
 		Methods("GET")
+	r.HandleFunc(prefix+"/tokens/{symbol}/holders", s.handleTokenHoldersReq(s.cdc, s.ctx, false)).
+		Methods("GET")
+	r.HandleFunc(prefix+"/tokens/{symbol}/metadata", s.handleTokenMetadataReq(s.cdc, s.ctx)).
+		Methods("GET")
 	r.HandleFunc(prefix+"/balances/{address}", s.handleBalancesReq(s.cdc, s.ctx, s.tokens)).
 		Methods("GET")
 	r.HandleFunc(prefix+"/balances/{address}/{symbol}", s.handleBalanceReq(s.cdc, s.ctx, s.tokens)).
@@ -62,6 +80,8 @@ func (s *server) bindRoutes() *server {
 		Methods("GET")
 	r.HandleFunc(prefix+"/mini/tokens/{symbol}", s.handleMiniTokenReq(s.cdc, s.ctx)).
 		Methods("GET")
+	r.HandleFunc(prefix+"/mini/tokens/{symbol}/holders", s.handleTokenHoldersReq(s.cdc, s.ctx, true)).
+		Methods("GET")
 
 	// fee params
 	r.HandleFunc(prefix+"/fees", s.handleFeesParamReq(s.cdc, s.ctx)).
@@ -85,6 +105,9 @@ func (s *server) bindRoutes() *server {
 		Queries("offset", "{offset:[0-9]+}", "limit", "{limit:[0-9]+}").
 		Methods("GET")
 
+	// BC fusion
+	r.HandleFunc(prefix+"/fusion/status", s.handleFusionStatusReq(s.cdc, s.ctx)).Methods("GET")
+
 	// keys rest routes disabled for security. while the nodes with keys (validators) run in a secure ringfenced environment,
 	// disabling this is a precaution to protect third-party validators that might not have protected their networks adequately.
 	//keys.RegisterRoutes(r, true)
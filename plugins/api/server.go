@@ -45,7 +45,7 @@ func newServer(ctx context.CLIContext, cdc *wire.Codec) *server {
 		ctx:          ctx,
 		cdc:          cdc,
 		keyBase:      kb,
-		tokens:       tokens.NewMapper(cdc, common.TokenStoreKey),
+		tokens:       tokens.NewMapper(cdc, common.TokenStoreKey, common.TokenMetadataStoreKey),
 		accStoreName: common.AccountStoreName,
 	}
 }
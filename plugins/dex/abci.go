@@ -3,6 +3,7 @@ package dex
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 
@@ -17,6 +18,7 @@ import (
 
 const MaxDepthLevels = 1000    // matches UI requirement
 const DefaultDepthLevels = 100 // matches UI requirement
+const MaxKlinesLimit = 1000
 
 func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQueryHandler {
 	queryPrefix := abciQueryPrefix
@@ -184,6 +186,210 @@ func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQ
 				Code:  uint32(sdk.ABCICodeOK),
 				Value: bz,
 			}
+		case "triggerorders": // args: ["dex", "triggerorders", <pair>, <bech32Str>]
+			if len(path) < 4 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "TriggerOrders query requires the pair symbol and address",
+				}
+			}
+
+			// verify pair is legal
+			pair := path[2]
+			baseAsset, quoteAsset, err := utils.TradingPair2Assets(pair)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "pair is not valid",
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			if !keeper.PairMapper.Exists(ctx, baseAsset, quoteAsset) {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "pair is not listed",
+				}
+			}
+
+			addr, err := sdk.AccAddressFromBech32(path[3])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "address is not valid",
+				}
+			}
+			triggerOrders := keeper.GetTriggerOrders(pair, addr)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(triggerOrders)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "orderhistory": // args: ["dex", "orderhistory", <bech32Str>]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "OrderHistory query requires the address",
+				}
+			}
+			history := keeper.GetOrderHistory()
+			if history == nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "order history is not enabled on this node",
+				}
+			}
+			addr, err := sdk.AccAddressFromBech32(path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "address is not valid",
+				}
+			}
+			closedOrders := history.ClosedOrders(addr.String())
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(closedOrders)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "trades": // args: ["dex" or "dex-mini", "trades", <pair>, <fromHeight>]
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Trades query requires the pair symbol",
+				}
+			}
+			history := keeper.GetOrderHistory()
+			if history == nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "order history is not enabled on this node",
+				}
+			}
+			pair := strings.ToUpper(path[2])
+			if _, _, err := utils.TradingPair2Assets(pair); err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "pair is not valid",
+				}
+			}
+			var fromHeight int64
+			if len(path) > 3 {
+				var err error
+				fromHeight, err = strconv.ParseInt(path[3], 10, 64)
+				if err != nil || fromHeight < 0 {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  "Trades query requires valid fromHeight parameter",
+					}
+				}
+			}
+			trades := history.Trades(pair, fromHeight)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(trades)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "ticker": // args: ["dex" or "dex-mini", "ticker"] or ["dex" or "dex-mini", "ticker", <pair>]
+			if keeper.GetTickerStats() == nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "24h tickers are not enabled on this node",
+				}
+			}
+			pair := ""
+			if len(path) >= 3 {
+				pair = strings.ToUpper(path[2])
+				if _, _, err := utils.TradingPair2Assets(pair); err != nil {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeInternal),
+						Log:  "pair is not valid",
+					}
+				}
+			}
+			tickers := keeper.GetTickers(pair, queryPrefix == DexMiniAbciQueryPrefix)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(tickers)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "klines": // args: ["dex" or "dex-mini", "klines", <pair>, <interval>, <limit>]
+			if len(path) < 5 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Klines query requires the pair symbol, interval and limit",
+				}
+			}
+			klines := keeper.GetKlineAggregator()
+			if klines == nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "klines are not enabled on this node",
+				}
+			}
+			pair := strings.ToUpper(path[2])
+			if _, _, err := utils.TradingPair2Assets(pair); err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "pair is not valid",
+				}
+			}
+			interval := path[3]
+			if !order.IsValidKlineInterval(interval) {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  fmt.Sprintf("Klines query requires valid interval, supported intervals: %v", order.KlineIntervals),
+				}
+			}
+			limit, err := strconv.Atoi(path[4])
+			if err != nil || limit <= 0 || limit > MaxKlinesLimit {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  fmt.Sprintf("Klines query requires valid limit (>0 && <=%d)", MaxKlinesLimit),
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(klines.Klines(pair, interval, limit))
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
 		default:
 			return &abci.ResponseQuery{
 				Code: uint32(sdk.ABCICodeOK),
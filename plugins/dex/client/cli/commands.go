@@ -25,7 +25,9 @@ func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
 			listMiniTradingPairCmd(cdc),
 			client.LineBreak,
 			newOrderCmd(cdc),
-			cancelOrderCmd(cdc))...)
+			cancelOrderCmd(cdc),
+			cancelAllOrdersCmd(cdc),
+			amendOrderCmd(cdc))...)
 	dexCmd.AddCommand(
 		client.GetCommands(
 			showOrderBookCmd(cdc))...)
@@ -29,11 +29,14 @@ const (
 	flagQty         = "qty"
 	flagSide        = "side"
 	flagTimeInForce = "tif"
+	flagOrderType   = "type"
+	flagStopPrice   = "stop-price"
+	flagPostOnly    = "post-only"
 )
 
 func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "order -l <pair> -s <side> -p <price> -q <qty> -t <timeInForce>",
+		Use:   "order -l <pair> -s <side> -p <price> -q <qty> -t <timeInForce> [--type <orderType>]",
 		Short: "Submit a new order",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cliCtx, txBldr := client.PrepareCtx(cdc)
@@ -50,12 +53,21 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 
 			symbol = strings.ToUpper(symbol)
 
-			priceStr := viper.GetString(flagPrice)
-			price, err := utils.ParsePrice(priceStr)
+			orderType, err := order.OrderTypeStringToOrderTypeCode(viper.GetString(flagOrderType))
 			if err != nil {
 				return err
 			}
 
+			// market orders have no price
+			var price int64
+			if orderType != order.OrderType.MARKET {
+				priceStr := viper.GetString(flagPrice)
+				price, err = utils.ParsePrice(priceStr)
+				if err != nil {
+					return err
+				}
+			}
+
 			qtyStr := viper.GetString(flagQty)
 			qty, err := utils.ParsePrice(qtyStr)
 			if err != nil {
@@ -90,6 +102,17 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 			}
 
 			msg.TimeInForce = tif
+			msg.PostOnly = viper.GetBool(flagPostOnly)
+			if orderType == order.OrderType.MARKET {
+				msg.OrderType = orderType
+				msg.TimeInForce = order.TimeInForce.IOC
+			} else if order.IsConditionalOrderType(orderType) {
+				msg.OrderType = orderType
+				msg.StopPrice, err = utils.ParsePrice(viper.GetString(flagStopPrice))
+				if err != nil {
+					return err
+				}
+			}
 
 			err = client.SendOrPrintTx(cliCtx, txBldr, msg)
 			if err != nil {
@@ -103,8 +126,11 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 	cmd.Flags().StringP(flagSymbol, "l", "", "the listed trading pair, such as ADA_BNB")
 	cmd.Flags().StringP(flagSide, "s", "", "side (buy as 1 or sell as 2) of the order")
 	cmd.Flags().StringP(flagPrice, "p", "", "price for the order")
-	cmd.Flags().StringP(flagQty, "q", "", "quantity for the order")
-	cmd.Flags().StringP(flagTimeInForce, "t", "gte", "TimeInForce for the order (gte or ioc)")
+	cmd.Flags().StringP(flagQty, "q", "", "quantity for the order, or the quote asset amount to spend for a market buy order")
+	cmd.Flags().StringP(flagTimeInForce, "t", "gte", "TimeInForce for the order (gte, ioc or fok), market order is always ioc")
+	cmd.Flags().String(flagOrderType, "limit", "type of the order (limit, market, stop_limit or take_profit)")
+	cmd.Flags().String(flagStopPrice, "", "stop price for stop_limit and take_profit orders")
+	cmd.Flags().Bool(flagPostOnly, false, "cancel the gte order instead of matching it as a taker")
 	return cmd
 }
 
@@ -183,6 +209,99 @@ func cancelOrderCmd(cdc *wire.Codec) *cobra.Command {
 	return cmd
 }
 
+func cancelAllOrdersCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel-all [-l <trading pair>] [-s <side>]",
+		Short: "Cancel all the open orders, optionally only those of one trading pair and/or one side",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := txbuilder.NewTxBuilderFromCLI().WithCodec(cdc)
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(types.GetAccountDecoder(cdc))
+			from, err := cliCtx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			symbol := viper.GetString(flagSymbol)
+			if symbol != "" {
+				err = validatePairSymbol(symbol)
+				if err != nil {
+					return err
+				}
+			}
+			side := int8(viper.GetInt(flagSide))
+			msg := order.NewCancelAllOrdersMsg(from, symbol, side)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			if cliCtx.GenerateOnly {
+				return txutils.PrintUnsignedStdTx(txBldr, cliCtx, []sdk.Msg{msg})
+			}
+
+			err = txutils.CompleteAndBroadcastTxCli(txBldr, cliCtx, []sdk.Msg{msg})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Msg [%v] was sent.\n", msg)
+			return nil
+		},
+	}
+	cmd.Flags().StringP(flagSymbol, "l", "", "the listed trading pair, such as ADA_BNB, all trading pairs if empty")
+	cmd.Flags().StringP(flagSide, "s", "", "side (buy as 1 or sell as 2) of the orders, both sides if empty")
+	return cmd
+}
+
+func amendOrderCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "amend -l <trading pair> -f <ref order id> -p <price> -q <qty>",
+		Short: "Amend the price and/or quantity of an open order",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := txbuilder.NewTxBuilderFromCLI().WithCodec(cdc)
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(types.GetAccountDecoder(cdc))
+			from, err := cliCtx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			symbol := viper.GetString(flagSymbol)
+			err = validatePairSymbol(symbol)
+			if err != nil {
+				return err
+			}
+			refId := viper.GetString(flagRefId)
+			if refId == "" {
+				return errors.New("please input reference order id")
+			}
+			price, err := utils.ParsePrice(viper.GetString(flagPrice))
+			if err != nil {
+				return err
+			}
+			qty, err := utils.ParsePrice(viper.GetString(flagQty))
+			if err != nil {
+				return err
+			}
+			msg := order.NewAmendOrderMsg(from, symbol, refId, price, qty)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			if cliCtx.GenerateOnly {
+				return txutils.PrintUnsignedStdTx(txBldr, cliCtx, []sdk.Msg{msg})
+			}
+
+			err = txutils.CompleteAndBroadcastTxCli(txBldr, cliCtx, []sdk.Msg{msg})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Msg [%v] was sent.\n", msg)
+			return nil
+		},
+	}
+	cmd.Flags().StringP(flagSymbol, "l", "", "the listed trading pair, such as ADA_BNB")
+	cmd.Flags().StringP(flagRefId, "f", "", "id string of the order")
+	cmd.Flags().StringP(flagPrice, "p", "", "new price of the order")
+	cmd.Flags().StringP(flagQty, "q", "", "new quantity of the order, including the filled part")
+	return cmd
+}
+
 func validatePairSymbol(symbol string) error {
 	return store.ValidatePairSymbol(symbol)
 }
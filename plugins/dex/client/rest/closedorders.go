@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+// ClosedOrdersReqHandler creates an http request handler to show the recent closed orders of an address,
+// optionally only those of one trading pair and at most limit of them
+func ClosedOrdersReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		addr := r.FormValue("address")
+		symbol := r.FormValue("symbol")
+
+		if _, err := types.AccAddressFromBech32(addr); err != nil {
+			throw(w, http.StatusInternalServerError, fmt.Errorf("addr is not a valid Bech32 address"))
+			return
+		}
+		if symbol != "" {
+			if err := store.ValidatePairSymbol(symbol); err != nil {
+				throw(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+		limit, err := parseHistoryLimit(r.FormValue("limit"))
+		if err != nil {
+			throw(w, http.StatusExpectationFailed, err)
+			return
+		}
+
+		closedOrders, err := store.GetClosedOrders(cdc, ctx, addr)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+		res := make([]store.ClosedOrder, 0, len(closedOrders))
+		for _, o := range closedOrders {
+			if len(res) == limit {
+				break
+			}
+			if symbol == "" || strings.EqualFold(o.Symbol, symbol) {
+				res = append(res, o)
+			}
+		}
+		err = json.NewEncoder(w).Encode(res)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+}
+
+// parseHistoryLimit returns -1 if the limit is not given
+func parseHistoryLimit(limitStr string) (int, error) {
+	if limitStr == "" {
+		return -1, nil
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid limit: %s", limitStr)
+	}
+	return limit, nil
+}
@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+const (
+	defaultKlinesLimit = 300
+	maxKlinesLimit     = 1000
+)
+
+// KlinesReqHandler creates an http request handler to show the latest klines of a trading pair in an interval
+func KlinesReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.FormValue("symbol")
+		if err := store.ValidatePairSymbol(symbol); err != nil {
+			throw(w, http.StatusNotFound, err)
+			return
+		}
+		interval := r.FormValue("interval")
+		limit := defaultKlinesLimit
+		if limitStr := r.FormValue("limit"); limitStr != "" {
+			var err error
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 || limit > maxKlinesLimit {
+				throw(w, http.StatusExpectationFailed, fmt.Errorf("invalid limit, should be in (0, %d]", maxKlinesLimit))
+				return
+			}
+		}
+
+		klines, err := store.GetKlines(cdc, ctx, symbol, interval, limit)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+		err = json.NewEncoder(w).Encode(klines)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+}
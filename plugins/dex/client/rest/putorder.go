@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
@@ -22,12 +23,15 @@ import (
 // PutOrderReqHandler creates an http request handler to create a new order transaction and return its binary tx
 func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName string) http.HandlerFunc {
 	type formParams struct {
-		address string
-		pair    string
-		side    string
-		price   string
-		qty     string
-		tif     string
+		address  string
+		pair     string
+		side     string
+		price    string
+		qty      string
+		tif      string
+		otype    string
+		stop     string
+		postOnly string
 	}
 
 	type response struct {
@@ -49,7 +53,8 @@ func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName st
 		if strings.TrimSpace(params.side) == "" {
 			return false
 		}
-		if strings.TrimSpace(params.price) == "" {
+		// market orders have no price
+		if strings.TrimSpace(params.price) == "" && !strings.EqualFold(strings.TrimSpace(params.otype), "market") {
 			return false
 		}
 		if strings.TrimSpace(params.qty) == "" {
@@ -66,12 +71,15 @@ func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName st
 	return func(w http.ResponseWriter, r *http.Request) {
 		// parse application/x-www-form-urlencoded or multipart/form-data form params
 		params := formParams{
-			address: r.FormValue("address"),
-			pair:    r.FormValue("pair"),
-			side:    r.FormValue("side"),
-			price:   r.FormValue("price"),
-			qty:     r.FormValue("qty"),
-			tif:     r.FormValue("tif"),
+			address:  r.FormValue("address"),
+			pair:     r.FormValue("pair"),
+			side:     r.FormValue("side"),
+			price:    r.FormValue("price"),
+			qty:      r.FormValue("qty"),
+			tif:      r.FormValue("tif"),
+			otype:    r.FormValue("type"),
+			stop:     r.FormValue("stop_price"),
+			postOnly: r.FormValue("post_only"),
 		}
 
 		if !validateFormParams(params) {
@@ -110,10 +118,22 @@ func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName st
 		}
 		pair := strings.ToUpper(params.pair)
 
-		price, err := utils.ParsePrice(params.price)
-		if err != nil {
-			throw(w, http.StatusInternalServerError, err)
-			return
+		otype := order.OrderType.LIMIT
+		if strings.TrimSpace(params.otype) != "" {
+			otype, err = order.OrderTypeStringToOrderTypeCode(params.otype)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, err)
+				return
+			}
+		}
+
+		var price int64
+		if otype != order.OrderType.MARKET {
+			price, err = utils.ParsePrice(params.price)
+			if err != nil {
+				throw(w, http.StatusInternalServerError, err)
+				return
+			}
 		}
 
 		qty, err := utils.ParsePrice(params.qty)
@@ -136,10 +156,30 @@ func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName st
 
 		seq := account.GetSequence()
 		id := order.GenerateOrderID(seq, addr)
-		msg := order.NewNewOrderMsg(addr, id, side, pair, price, qty)
-
-		if tif > -1 {
-			msg.TimeInForce = tif
+		var msg order.NewOrderMsg
+		if otype == order.OrderType.MARKET {
+			msg = order.NewMarketOrderMsg(addr, id, side, pair, qty)
+		} else {
+			msg = order.NewNewOrderMsg(addr, id, side, pair, price, qty)
+			if order.IsConditionalOrderType(otype) {
+				stopPrice, err := utils.ParsePrice(params.stop)
+				if err != nil {
+					throw(w, http.StatusExpectationFailed, err)
+					return
+				}
+				msg.OrderType = otype
+				msg.StopPrice = stopPrice
+			}
+			if tif > -1 {
+				msg.TimeInForce = tif
+			}
+			if strings.TrimSpace(params.postOnly) != "" {
+				msg.PostOnly, err = strconv.ParseBool(params.postOnly)
+				if err != nil {
+					throw(w, http.StatusExpectationFailed, err)
+					return
+				}
+			}
 		}
 		msgs := []sdk.Msg{msg}
 
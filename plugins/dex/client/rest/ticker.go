@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/plugins/dex/utils"
+	"github.com/bnb-chain/node/wire"
+)
+
+// TickerReqHandler creates an http request handler to show the 24h tickers of a trading pair,
+// or of all the trading pairs including the mini ones if the symbol is not given
+func TickerReqHandler(cdc *wire.Codec, ctx context.CLIContext, dexPrefix, miniPrefix string) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		tickers := make([]store.Ticker, 0)
+		if symbol := r.FormValue("symbol"); symbol != "" {
+			if err := store.ValidatePairSymbol(symbol); err != nil {
+				throw(w, http.StatusNotFound, err)
+				return
+			}
+			prefix := dexPrefix
+			if utils.IsMiniTokenTradingPair(symbol) {
+				prefix = miniPrefix
+			}
+			var err error
+			tickers, err = store.GetTickers(cdc, ctx, prefix, symbol)
+			if err != nil {
+				throw(w, http.StatusInternalServerError, err)
+				return
+			}
+		} else {
+			for _, prefix := range []string{dexPrefix, miniPrefix} {
+				prefixTickers, err := store.GetTickers(cdc, ctx, prefix, "")
+				if err != nil {
+					throw(w, http.StatusInternalServerError, err)
+					return
+				}
+				tickers = append(tickers, prefixTickers...)
+			}
+		}
+
+		err := json.NewEncoder(w).Encode(tickers)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+// TradesReqHandler creates an http request handler to show the recent trades of a trading pair,
+// optionally only those since fromHeight and at most limit of them
+func TradesReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.FormValue("symbol")
+		if err := store.ValidatePairSymbol(symbol); err != nil {
+			throw(w, http.StatusNotFound, err)
+			return
+		}
+		var fromHeight int64
+		if fromHeightStr := r.FormValue("fromHeight"); fromHeightStr != "" {
+			var err error
+			fromHeight, err = strconv.ParseInt(fromHeightStr, 10, 64)
+			if err != nil || fromHeight < 0 {
+				throw(w, http.StatusExpectationFailed, fmt.Errorf("invalid fromHeight: %s", fromHeightStr))
+				return
+			}
+		}
+		limit, err := parseHistoryLimit(r.FormValue("limit"))
+		if err != nil {
+			throw(w, http.StatusExpectationFailed, err)
+			return
+		}
+
+		trades, err := store.GetTrades(cdc, ctx, symbol, fromHeight)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+		if limit >= 0 && len(trades) > limit {
+			trades = trades[:limit]
+		}
+		err = json.NewEncoder(w).Encode(trades)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+}
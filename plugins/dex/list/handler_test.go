@@ -45,6 +45,7 @@ func MakeKeepers(cdc *codec.Codec) (ms sdkStore.CommitMultiStore, dexKeeper *ord
 	accKey := sdk.NewKVStoreKey("acc")
 	pairKey := sdk.NewKVStoreKey("pair")
 	tokenKey := sdk.NewKVStoreKey("token")
+	tokenMetadataKey := sdk.NewKVStoreKey("token_metadata")
 	paramKey := sdk.NewKVStoreKey("param")
 	paramTKey := sdk.NewTransientStoreKey("t_param")
 	stakeKey := sdk.NewKVStoreKey("stake")
@@ -57,6 +58,7 @@ func MakeKeepers(cdc *codec.Codec) (ms sdkStore.CommitMultiStore, dexKeeper *ord
 	ms.MountStoreWithDB(accKey, sdk.StoreTypeIAVL, memDB)
 	ms.MountStoreWithDB(pairKey, sdk.StoreTypeIAVL, memDB)
 	ms.MountStoreWithDB(tokenKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(tokenMetadataKey, sdk.StoreTypeIAVL, memDB)
 	ms.MountStoreWithDB(paramKey, sdk.StoreTypeIAVL, memDB)
 	ms.MountStoreWithDB(stakeKey, sdk.StoreTypeIAVL, memDB)
 	ms.MountStoreWithDB(stakeRewardKey, sdk.StoreTypeIAVL, memDB)
@@ -68,7 +70,7 @@ func MakeKeepers(cdc *codec.Codec) (ms sdkStore.CommitMultiStore, dexKeeper *ord
 	pairMapper := store.NewTradingPairMapper(cdc, pairKey)
 	dexKeeper = order.NewDexKeeper(common.DexStoreKey, accKeeper, pairMapper, codespacer.RegisterNext(dexTypes.DefaultCodespace), 2, cdc, false)
 
-	tokenMapper = tokens.NewMapper(cdc, tokenKey)
+	tokenMapper = tokens.NewMapper(cdc, tokenKey, tokenMetadataKey)
 
 	paramsKeeper := params.NewKeeper(cdc, paramKey, paramTKey)
 	bankKeeper := bank.NewBaseKeeper(accKeeper)
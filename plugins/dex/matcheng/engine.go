@@ -18,6 +18,15 @@ type MatchEng struct {
 	// in order to determine the trade price. Though it is saved as int64,
 	// it would be converted into a float when the match engine is created.
 	PriceLimitPct float64
+	// MarketProtectionPct is a percentage use to calculate the range of price
+	// market orders are allowed to trade at, based on LastTradePrice.
+	// It defaults to PriceLimitPct and can be set before any order is placed.
+	MarketProtectionPct float64
+	// market orders waiting for the next match, in the sequence they are added
+	marketOrders   []*MarketOrder
+	marketOrderIdx map[string]*MarketOrder
+	// the price the market orders are placed at for the next match
+	marketPrice int64
 	// all the below are buffers
 	overLappedLevel []OverLappedLevel
 	buyBuf          []PriceLevel
@@ -32,18 +41,21 @@ type MatchEng struct {
 // NewMatchEng constructs a new MatchEng.
 func NewMatchEng(pairSymbol string, basePrice, lotSize int64, priceLimit float64) *MatchEng {
 	return &MatchEng{
-		LastMatchHeight: 0,
-		Book:            NewOrderBookOnULList(10000, 16),
-		LotSize:         lotSize,
-		PriceLimitPct:   priceLimit,
-		overLappedLevel: make([]OverLappedLevel, 0, 16),
-		buyBuf:          make([]PriceLevel, 16),
-		sellBuf:         make([]PriceLevel, 16),
-		maxExec:         LevelIndex{0, make([]int, 8)},
-		leastSurplus:    SurplusIndex{LevelIndex{math.MaxInt64, make([]int, 8)}, make([]int64, 8)},
-		Trades:          make([]Trade, 0, 64),
-		LastTradePrice:  basePrice,
-		logger:          log.With("module", "matcheng", "pair", pairSymbol),
+		LastMatchHeight:     0,
+		Book:                NewOrderBookOnULList(10000, 16),
+		LotSize:             lotSize,
+		PriceLimitPct:       priceLimit,
+		MarketProtectionPct: priceLimit,
+		marketOrders:        make([]*MarketOrder, 0),
+		marketOrderIdx:      make(map[string]*MarketOrder),
+		overLappedLevel:     make([]OverLappedLevel, 0, 16),
+		buyBuf:              make([]PriceLevel, 16),
+		sellBuf:             make([]PriceLevel, 16),
+		maxExec:             LevelIndex{0, make([]int, 8)},
+		leastSurplus:        SurplusIndex{LevelIndex{math.MaxInt64, make([]int, 8)}, make([]int64, 8)},
+		Trades:              make([]Trade, 0, 64),
+		LastTradePrice:      basePrice,
+		logger:              log.With("module", "matcheng", "pair", pairSymbol),
 	}
 }
 
@@ -223,8 +235,12 @@ func (me *MatchEng) MatchBeforeGalileo(height int64) bool {
 	return true
 }
 
-//DropFilledOrder() would clear the order to remove
-func (me *MatchEng) DropFilledOrder() (droppedIds []string) {
+// DropFilledOrder() would clear the order to remove
+func (me *MatchEng) DropFilledOrder() []string {
+	return me.dropFilledMarketOrders(me.dropFilledOrder())
+}
+
+func (me *MatchEng) dropFilledOrder() (droppedIds []string) {
 	droppedIds = make([]string, 0, len(me.overLappedLevel)<<1)
 	toRemoveStartIdx := 0
 	toRemoveEndIdx := 0
@@ -13,7 +13,12 @@ import (
 )
 
 func (me *MatchEng) Match(height int64) bool {
+	me.placeMarketOrders()
 	success := me.runMatch(height)
+	if success {
+		me.settleMarketOrders()
+	}
+	me.marketPrice = 0
 	if sdk.IsUpgrade(upgrade.BEP19) {
 		me.LastMatchHeight = height
 	}
@@ -34,6 +39,7 @@ func (me *MatchEng) runMatch(height int64) bool {
 	if index < 0 {
 		return false
 	}
+	tradePrice, index = me.preferMarketPrice(tradePrice, index)
 
 	if err := me.dropRedundantQty(index); err != nil {
 		me.logger.Error("dropRedundantQty failed", "error", err)
@@ -52,6 +58,61 @@ func (me *MatchEng) runMatch(height int64) bool {
 	return true
 }
 
+// OrderExecution is the expected result of an order in the next match
+type OrderExecution struct {
+	Id      string
+	IsTaker bool
+	Qty     int64 // quantity to be filled
+}
+
+// PreviewMatch works out which orders would be filled in the next match and by how much,
+// without filling any order or changing the state of the engine. Orders not in the result
+// would not be filled. It returns false if the next match would fail, or it is before BEP19
+// when the taker side is not determined. The market orders are placed into the order book
+// as they would be in the next match.
+func (me *MatchEng) PreviewMatch() ([]OrderExecution, bool) {
+	if !sdk.IsUpgrade(upgrade.BEP19) {
+		return nil, false
+	}
+	me.placeMarketOrders()
+	r := me.Book.GetOverlappedRange(&me.overLappedLevel, &me.buyBuf, &me.sellBuf)
+	if r <= 0 {
+		return nil, true
+	}
+	prepareMatch(&me.overLappedLevel)
+	tradePrice, index := getTradePrice(&me.overLappedLevel, &me.maxExec, &me.leastSurplus, me.LastTradePrice, me.PriceLimitPct)
+	if index < 0 {
+		return nil, false
+	}
+	_, index = me.preferMarketPrice(tradePrice, index)
+	if err := me.dropRedundantQty(index); err != nil {
+		return nil, false
+	}
+	takerSide, err := me.determineTakerSide(index)
+	if err != nil {
+		return nil, false
+	}
+
+	executions := make([]OrderExecution, 0)
+	for i := 0; i <= index; i++ {
+		l := &me.overLappedLevel[i]
+		for j := range l.BuyOrders {
+			o := &l.BuyOrders[j]
+			isTaker := takerSide == BUYSIDE && j >= l.BuyTakerStartIdx
+			executions = append(executions, OrderExecution{o.Id, isTaker, o.nxtTrade})
+		}
+	}
+	for i := len(me.overLappedLevel) - 1; i >= index; i-- {
+		l := &me.overLappedLevel[i]
+		for j := range l.SellOrders {
+			o := &l.SellOrders[j]
+			isTaker := takerSide == SELLSIDE && j >= l.SellTakerStartIdx
+			executions = append(executions, OrderExecution{o.Id, isTaker, o.nxtTrade})
+		}
+	}
+	return executions, true
+}
+
 func (me *MatchEng) dropRedundantQty(tradePriceLevelIdx int) error {
 	tradePriceLevel := me.overLappedLevel[tradePriceLevelIdx]
 	totalExec := tradePriceLevel.AccumulatedExecutions
@@ -61,20 +122,35 @@ func (me *MatchEng) dropRedundantQty(tradePriceLevelIdx int) error {
 		return nil
 	}
 
+	// it can be proved that redundant qty only exists in the last non-empty line of the overlapped buy price level,
+	// or the first non-empty line of the overlapped sell price level, if the trade price has the least surplus.
+	// Otherwise, i.e. the price planned for market orders, it's dropped from the lines one after another.
 	if compareBuy(qBuy, totalExec) > 0 {
+		toDrop := qBuy - totalExec
 		for i := tradePriceLevelIdx; i >= 0; i-- {
-			// it can be proved that redundant qty only exists in the last non-empty line of the overlapped buy price level
-			if me.overLappedLevel[i].BuyTotal != 0 {
-				return dropRedundantQty(me.overLappedLevel[i].BuyOrders, qBuy-totalExec, me.LotSize)
+			l := &me.overLappedLevel[i]
+			if l.BuyTotal == 0 {
+				continue
 			}
+			if compareBuy(l.BuyTotal, toDrop) >= 0 {
+				return dropRedundantQty(l.BuyOrders, toDrop, me.LotSize)
+			}
+			dropAllQty(l.BuyOrders)
+			toDrop -= l.BuyTotal
 		}
 	} else if compareBuy(qSell, totalExec) > 0 {
+		toDrop := qSell - totalExec
 		length := len(me.overLappedLevel)
 		for i := tradePriceLevelIdx; i < length; i++ {
-			// it can be proved that redundant qty only exists in the first non-empty line of the overlapped sell price level
-			if me.overLappedLevel[i].SellTotal != 0 {
-				return dropRedundantQty(me.overLappedLevel[i].SellOrders, qSell-totalExec, me.LotSize)
+			l := &me.overLappedLevel[i]
+			if l.SellTotal == 0 {
+				continue
 			}
+			if compareBuy(l.SellTotal, toDrop) >= 0 {
+				return dropRedundantQty(l.SellOrders, toDrop, me.LotSize)
+			}
+			dropAllQty(l.SellOrders)
+			toDrop -= l.SellTotal
 		}
 	}
 	return fmt.Errorf("internal error! invalud AccumulatedExecutions found, "+
@@ -122,6 +198,12 @@ func dropRedundantQty(orders []OrderPart, toDropQty, lotSize int64) error {
 	return nil
 }
 
+func dropAllQty(orders []OrderPart) {
+	for i := range orders {
+		orders[i].nxtTrade = 0
+	}
+}
+
 func findTakerStartIdx(lastMatchHeight int64, orders []OrderPart) (idx int, makerTotal int64) {
 	i, k := 0, len(orders)
 	for ; i < k; i++ {
@@ -0,0 +1,292 @@
+package matcheng
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/bnb-chain/node/common/utils"
+	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+// MarketOrder is a market order waiting for the next match. It is not in the order book until the match,
+// when it is placed at the price the match is planned to be concluded at, so it's filled at the concluded price.
+type MarketOrder struct {
+	Id   string
+	Side int8
+	Time int64
+	// Qty is the quote asset amount to spend for a buy order, and the base asset quantity to sell for a sell order
+	Qty int64
+	// CumQty is the quote asset amount spent for a buy order, and the base asset quantity sold for a sell order
+	CumQty int64
+	// Price is the price it's placed at in the order book, 0 if it's not placed
+	Price int64
+}
+
+func (o *MarketOrder) orderPart() OrderPart {
+	return OrderPart{Id: o.Id, Time: o.Time, Qty: o.Qty, CumQty: o.CumQty}
+}
+
+// MarketProtectionBand returns the range of prices market orders are allowed to be filled at,
+// i.e. LastTradePrice moved by MarketProtectionPct on both sides.
+func (me *MatchEng) MarketProtectionBand() (lower, upper int64) {
+	refPrice := float64(me.LastTradePrice)
+	lower = int64(math.Ceil(refPrice * (1.0 - me.MarketProtectionPct)))
+	upper = int64(math.Floor(refPrice * (1.0 + me.MarketProtectionPct)))
+	if lower <= 0 {
+		lower = 1
+	}
+	return lower, upper
+}
+
+// AddMarketOrder adds a market order to be matched in the next round.
+func (me *MatchEng) AddMarketOrder(id string, side int8, time, qty int64) error {
+	if _, ok := me.marketOrderIdx[id]; ok {
+		return fmt.Errorf("Order %s has existed in the market orders.", id)
+	}
+	o := &MarketOrder{Id: id, Side: side, Time: time, Qty: qty}
+	me.marketOrders = append(me.marketOrders, o)
+	me.marketOrderIdx[id] = o
+	return nil
+}
+
+// HasMarketOrder returns true if the order is a market order waiting for the match.
+func (me *MatchEng) HasMarketOrder(id string) bool {
+	_, ok := me.marketOrderIdx[id]
+	return ok
+}
+
+// GetOrder returns the order in the order book, or the market order waiting for the match.
+// The quantities of a market buy order are in the quote asset, see MarketOrder.
+func (me *MatchEng) GetOrder(id string, side int8, price int64) (OrderPart, error) {
+	if o, ok := me.marketOrderIdx[id]; ok {
+		return o.orderPart(), nil
+	}
+	return me.Book.GetOrder(id, side, price)
+}
+
+// RemoveOrder removes the order from the order book, or the market order waiting for the match.
+// The quantities of a market buy order are in the quote asset, see MarketOrder.
+func (me *MatchEng) RemoveOrder(id string, side int8, price int64) (OrderPart, error) {
+	if o, ok := me.marketOrderIdx[id]; ok {
+		me.removeMarketOrder(o)
+		return o.orderPart(), nil
+	}
+	return me.Book.RemoveOrder(id, side, price)
+}
+
+// RemoveMarketOrders removes all the market orders waiting for the match.
+func (me *MatchEng) RemoveMarketOrders(callback func(OrderPart)) {
+	for _, o := range me.marketOrders {
+		if me.marketOrderIdx[o.Id] != o {
+			continue
+		}
+		me.removeMarketOrder(o)
+		callback(o.orderPart())
+	}
+}
+
+func (me *MatchEng) removeMarketOrder(o *MarketOrder) {
+	if o.Price > 0 {
+		// the error is ignored as a filled order has been dropped from the order book already
+		_, _ = me.Book.RemoveOrder(o.Id, o.Side, o.Price)
+		o.Price = 0
+	}
+	delete(me.marketOrderIdx, o.Id)
+	if len(me.marketOrderIdx) == 0 {
+		me.marketOrders = me.marketOrders[:0]
+	}
+}
+
+// placeMarketOrders places the market orders into the order book at the price the next match is planned to be
+// concluded at. It can be called more than once before the match, as the orders placed are taken out first.
+func (me *MatchEng) placeMarketOrders() {
+	me.marketPrice = 0
+	if len(me.marketOrderIdx) == 0 {
+		return
+	}
+	orders := me.marketOrders[:0]
+	for _, o := range me.marketOrders {
+		if me.marketOrderIdx[o.Id] != o {
+			continue
+		}
+		if o.Price > 0 {
+			_, _ = me.Book.RemoveOrder(o.Id, o.Side, o.Price)
+			o.Price = 0
+		}
+		orders = append(orders, o)
+	}
+	me.marketOrders = orders
+
+	price := me.planMarketPrice()
+	if price <= 0 {
+		// no execution in the protection band, the market orders would expire
+		return
+	}
+	for _, o := range me.marketOrders {
+		qty := o.Qty - o.CumQty
+		if o.Side == BUYSIDE {
+			qty = marketBuyQty(qty, price, me.LotSize)
+		}
+		if qty <= 0 {
+			continue
+		}
+		if pl := me.Book.GetPriceLevel(price, o.Side); pl != nil && pl.TotalLeavesQty()+qty < 0 {
+			// overflow, same as the check on placing a limit order
+			continue
+		}
+		if _, err := me.Book.InsertOrder(o.Id, o.Side, o.Time, price, qty); err == nil {
+			o.Price = price
+		}
+	}
+	me.marketPrice = price
+}
+
+// planMarketPrice works out the price the next match would be concluded at, taking the market orders as
+// limit orders of any price in the protection band, which is sized at the price for a market buy order.
+// The prices of the limit orders in the band and the last trade price are the candidates, and it's chosen
+// with the same rules as the match. 0 is returned if there would be no execution.
+//
+// The market orders are then placed at the price, so they are only counted at and on one side of it, and
+// a market buy order would buy less below it. So the execution at any price of the order book is no more than
+// the planned one, while the execution at the planned price is the same, which makes it one of the prices
+// with the max execution of the match.
+func (me *MatchEng) planMarketPrice() int64 {
+	lower, upper := me.MarketProtectionBand()
+	if lower > upper {
+		return 0
+	}
+	buys, sells := me.Book.GetAllLevels()
+	prices := make([]int64, 0, len(buys)+len(sells)+1)
+	prices = append(prices, me.LastTradePrice)
+	for _, levels := range [][]PriceLevel{buys, sells} {
+		for _, l := range levels {
+			if l.Price >= lower && l.Price <= upper {
+				prices = append(prices, l.Price)
+			}
+		}
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i] > prices[j] })
+
+	planned := make([]OverLappedLevel, 0, len(prices))
+	var limitBuy int64
+	buyIdx := 0
+	for i, price := range prices {
+		if i > 0 && price == prices[i-1] {
+			continue
+		}
+		// buys are sorted by descending price
+		for ; buyIdx < len(buys) && buys[buyIdx].Price >= price; buyIdx++ {
+			limitBuy = addSaturated(limitBuy, totalLeavesQty(buys[buyIdx].Orders))
+		}
+		var limitSell int64
+		// sells are sorted by ascending price
+		for j := 0; j < len(sells) && sells[j].Price <= price; j++ {
+			limitSell = addSaturated(limitSell, totalLeavesQty(sells[j].Orders))
+		}
+		accBuy, accSell := limitBuy, limitSell
+		for _, o := range me.marketOrders {
+			if o.Side == BUYSIDE {
+				accBuy = addSaturated(accBuy, marketBuyQty(o.Qty-o.CumQty, price, me.LotSize))
+			} else {
+				accSell = addSaturated(accSell, o.Qty-o.CumQty)
+			}
+		}
+		planned = append(planned, OverLappedLevel{
+			Price:                 price,
+			AccumulatedBuy:        accBuy,
+			AccumulatedSell:       accSell,
+			AccumulatedExecutions: utils.MinInt(accBuy, accSell),
+			BuySellSurplus:        accBuy - accSell,
+		})
+	}
+
+	price, index := getTradePrice(&planned, &me.maxExec, &me.leastSurplus, me.LastTradePrice, me.PriceLimitPct)
+	if index < 0 || planned[index].AccumulatedExecutions <= 0 {
+		return 0
+	}
+	return price
+}
+
+// preferMarketPrice concludes the match at the price planned for the market orders if it's one of the prices
+// with the max execution, so the market orders are filled at the concluded price.
+func (me *MatchEng) preferMarketPrice(tradePrice int64, index int) (int64, int) {
+	if me.marketPrice <= 0 {
+		return tradePrice, index
+	}
+	for _, i := range me.maxExec.index {
+		if me.overLappedLevel[i].Price == me.marketPrice {
+			return me.marketPrice, i
+		}
+	}
+	return tradePrice, index
+}
+
+// settleMarketOrders adds the trades of the last match to the market orders
+func (me *MatchEng) settleMarketOrders() {
+	if len(me.marketOrderIdx) == 0 {
+		return
+	}
+	for i := range me.Trades {
+		t := &me.Trades[i]
+		if o, ok := me.marketOrderIdx[t.Bid]; ok {
+			o.CumQty += dexUtils.CalBigNotionalInt64(t.LastPx, t.LastQty)
+		}
+		if o, ok := me.marketOrderIdx[t.Sid]; ok {
+			o.CumQty += t.LastQty
+		}
+	}
+}
+
+// dropFilledMarketOrders removes the filled market sell orders. The market buy orders are kept, even if they
+// are filled in the order book, as the quote asset left is only released when they are removed.
+func (me *MatchEng) dropFilledMarketOrders(droppedIds []string) []string {
+	if len(me.marketOrderIdx) == 0 {
+		return droppedIds
+	}
+	kept := droppedIds[:0]
+	for _, id := range droppedIds {
+		if o, ok := me.marketOrderIdx[id]; ok {
+			if o.Side == BUYSIDE {
+				continue
+			}
+			o.Price = 0 // dropped from the order book already
+			me.removeMarketOrder(o)
+		}
+		kept = append(kept, id)
+	}
+	return kept
+}
+
+// marketBuyQty returns the base asset quantity, rounded to lot size, the quote asset amount can buy at the price
+func marketBuyQty(quote, price, lotSize int64) int64 {
+	if quote <= 0 || price <= 0 {
+		return 0
+	}
+	var qty big.Int
+	qty.Quo(qty.Mul(big.NewInt(quote), big.NewInt(1e8)), big.NewInt(price))
+	res := int64(math.MaxInt64)
+	if qty.IsInt64() {
+		res = qty.Int64()
+	}
+	if lotSize > 0 {
+		res = res / lotSize * lotSize
+	}
+	return res
+}
+
+func totalLeavesQty(orders []OrderPart) int64 {
+	var s int64
+	for i := range orders {
+		s = addSaturated(s, orders[i].LeavesQty())
+	}
+	return s
+}
+
+func addSaturated(a, b int64) int64 {
+	if a+b < 0 {
+		return math.MaxInt64
+	}
+	return a + b
+}
@@ -1015,3 +1015,134 @@ func TestMatchEng_Match(t *testing.T) {
 		},
 	}}, sells)
 }
+
+func TestMatchEng_MarketProtectionBand(t *testing.T) {
+	me := NewMatchEng("AAA_BNB", 1000, 5, 0.05)
+	lower, upper := me.MarketProtectionBand()
+	assert.Equal(t, int64(950), lower)
+	assert.Equal(t, int64(1050), upper)
+	me.MarketProtectionPct = 0.1
+	lower, upper = me.MarketProtectionBand()
+	assert.Equal(t, int64(900), lower)
+	assert.Equal(t, int64(1100), upper)
+}
+
+func TestMatchEng_MarketOrder(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+	assert := assert.New(t)
+
+	// the market buy order is filled against the makers at their prices, up to the planned price
+	me := NewMatchEng(DefaultPairSymbol, 10e8, 1e6, 0.05)
+	me.Book.InsertOrder("s1", SELLSIDE, 90, 10.2e8, 5e8)
+	me.Book.InsertOrder("s2", SELLSIDE, 90, 10.4e8, 5e8)
+	me.Book.InsertOrder("s3", SELLSIDE, 90, 11e8, 5e8) // out of the protection band
+	me.LastMatchHeight = 99
+	assert.NoError(me.AddMarketOrder("m1", BUYSIDE, 100, 80e8))
+	assert.Error(me.AddMarketOrder("m1", BUYSIDE, 100, 80e8))
+
+	assert.True(me.Match(100))
+	assert.Equal([]Trade{
+		{Sid: "s1", LastPx: 10.2e8, LastQty: 5e8, BuyCumQty: 5e8, SellCumQty: 5e8, Bid: "m1", TickType: BuyTaker},
+		{Sid: "s2", LastPx: 10.4e8, LastQty: 2.69e8, BuyCumQty: 7.69e8, SellCumQty: 2.69e8, Bid: "m1", TickType: BuyTaker},
+	}, me.Trades)
+	assert.Equal(int64(10.4e8), me.LastTradePrice)
+	ord, err := me.GetOrder("m1", BUYSIDE, 0)
+	assert.NoError(err)
+	assert.Equal(OrderPart{Id: "m1", Time: 100, Qty: 80e8, CumQty: 51e8 + 27.976e8}, ord)
+
+	// the filled market buy order is kept to release the quote asset left
+	assert.Equal([]string{"s1"}, me.DropFilledOrder())
+	assert.True(me.HasMarketOrder("m1"))
+	ord, err = me.RemoveOrder("m1", BUYSIDE, 0)
+	assert.NoError(err)
+	assert.Equal(int64(1.024e8), ord.LeavesQty())
+	assert.False(me.HasMarketOrder("m1"))
+	buys, sells := me.Book.GetAllLevels()
+	assert.Empty(buys)
+	assert.Equal(2, len(sells))
+
+	// the market orders are filled at the concluded price with the new orders
+	me = NewMatchEng(DefaultPairSymbol, 10e8, 1e6, 0.05)
+	me.Book.InsertOrder("s1", SELLSIDE, 100, 10e8, 3e8)
+	me.Book.InsertOrder("s2", SELLSIDE, 100, 10.3e8, 3e8)
+	me.LastMatchHeight = 99
+	assert.NoError(me.AddMarketOrder("m1", BUYSIDE, 100, 100e8))
+	executions, ok := me.PreviewMatch()
+	assert.True(ok)
+	assert.Equal(3, len(executions))
+	assert.True(me.Match(100))
+	assert.Equal(2, len(me.Trades))
+	for _, trade := range me.Trades {
+		assert.Equal(int64(10.3e8), trade.LastPx)
+		assert.Equal("m1", trade.Bid)
+	}
+	assert.Equal(int64(10.3e8), me.LastTradePrice)
+	ord, err = me.RemoveOrder("m1", BUYSIDE, 0)
+	assert.NoError(err)
+	assert.Equal(int64(61.8e8), ord.CumQty)
+
+	// no execution in the protection band, the market order is not placed
+	me = NewMatchEng(DefaultPairSymbol, 10e8, 1e6, 0.05)
+	me.Book.InsertOrder("b1", BUYSIDE, 90, 9e8, 3e8)
+	me.LastMatchHeight = 99
+	assert.NoError(me.AddMarketOrder("m1", SELLSIDE, 100, 2e8))
+	assert.True(me.Match(100))
+	assert.Empty(me.Trades)
+	ord, err = me.RemoveOrder("m1", SELLSIDE, 0)
+	assert.NoError(err)
+	assert.Equal(OrderPart{Id: "m1", Time: 100, Qty: 2e8}, ord)
+	_, err = me.RemoveOrder("m1", SELLSIDE, 0)
+	assert.Error(err)
+}
+
+func TestMatchEng_PreviewMatch(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+
+	assert := assert.New(t)
+	me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
+	me.Book = NewOrderBookOnULList(4, 2)
+	executions, ok := me.PreviewMatch()
+	assert.True(ok)
+	assert.Empty(executions)
+
+	me.Book.InsertOrder("1", SELLSIDE, 90, 100, 5)
+	me.Book.InsertOrder("3", SELLSIDE, 91, 100, 10)
+	me.Book.InsertOrder("5", SELLSIDE, 91, 100, 5)
+	me.Book.InsertOrder("7", SELLSIDE, 91, 100, 50)
+	me.Book.InsertOrder("9", SELLSIDE, 91, 110, 50)
+	me.Book.InsertOrder("2", BUYSIDE, 92, 90, 5)
+	me.Book.InsertOrder("4", BUYSIDE, 93, 80, 30)
+	me.Book.InsertOrder("11", SELLSIDE, 100, 90, 30)
+	me.Book.InsertOrder("13", SELLSIDE, 100, 80, 10)
+	me.Book.InsertOrder("15", SELLSIDE, 100, 80, 40)
+	me.Book.InsertOrder("17", SELLSIDE, 100, 80, 20)
+	me.Book.InsertOrder("12", BUYSIDE, 100, 110, 110)
+	me.Book.InsertOrder("14", BUYSIDE, 100, 100, 10)
+	me.Book.InsertOrder("16", BUYSIDE, 100, 100, 20)
+	me.LastMatchHeight = 99
+
+	executions, ok = me.PreviewMatch()
+	assert.True(ok)
+	executionById := make(map[string]OrderExecution)
+	for _, e := range executions {
+		executionById[e.Id] = e
+	}
+	assert.Equal(OrderExecution{"12", true, 110}, executionById["12"])
+	assert.Equal(OrderExecution{"14", true, 10}, executionById["14"])
+	assert.Equal(OrderExecution{"16", true, 20}, executionById["16"])
+	assert.Equal(OrderExecution{"13", false, 10}, executionById["13"])
+	assert.Equal(OrderExecution{"1", false, 5}, executionById["1"])
+	assert.Equal(OrderExecution{"7", false, 25}, executionById["7"])
+	assert.Equal(OrderExecution{"5", false, 0}, executionById["5"])
+	_, ok = executionById["2"]
+	assert.False(ok)
+
+	// previewing changes nothing of the following match
+	assert.Equal(int64(99), me.LastMatchHeight)
+	assert.Equal(int64(100), me.LastTradePrice)
+	assert.True(me.Match(100))
+	assert.Equal(9, len(me.Trades))
+	assert.Equal(int64(100), me.LastTradePrice)
+}
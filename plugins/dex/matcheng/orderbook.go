@@ -19,6 +19,7 @@ type OrderBookInterface interface {
 	InsertPriceLevel(p *PriceLevel, side int8) error
 	GetOrder(id string, side int8, price int64) (OrderPart, error)
 	RemoveOrder(id string, side int8, price int64) (OrderPart, error)
+	UpdateOrder(id string, side int8, price int64, qty int64, cumQty int64) (OrderPart, error)
 	RemoveOrders(beforeTime int64, side int8, cb func(OrderPart)) error
 	RemoveOrdersBasedOnPriceLevel(expireTime int64, forceExpireTime int64, priceLevelsToReserve int, side int8, removeCallback func(ord OrderPart)) error
 	UpdateForEachPriceLevel(side int8, updater LevelIter)
@@ -130,6 +131,16 @@ func (ob *OrderBookOnULList) RemoveOrder(id string, side int8, price int64) (Ord
 	return op, ok
 }
 
+// UpdateOrder updates the quantity and the cumulative filled quantity of the order without changing its place in the price level
+func (ob *OrderBookOnULList) UpdateOrder(id string, side int8, price int64, qty int64, cumQty int64) (OrderPart, error) {
+	q := ob.getSideQueue(side)
+	var pl *PriceLevel
+	if pl = q.GetPriceLevel(price); pl == nil {
+		return OrderPart{}, fmt.Errorf("order price %d doesn't exist at side %d.", price, side)
+	}
+	return pl.updateOrder(id, qty, cumQty)
+}
+
 func (ob *OrderBookOnULList) RemoveOrders(beforeTime int64, side int8, cb func(OrderPart)) error {
 	ob.UpdateForEachPriceLevel(side, func(pl *PriceLevel, levelIndex int) {
 		pl.removeOrders(beforeTime, cb)
@@ -540,6 +540,24 @@ func TestOrderBookOnBTree_RemoveOrder(t *testing.T) {
 		printOrderQueueString(l.sellQueue, SELLSIDE), "Level at 1000 be removed.")
 }
 
+func TestOrderBookOnULList_UpdateOrder(t *testing.T) {
+	book := NewOrderBookOnULList(16, 4)
+	book.InsertOrder("1", BUYSIDE, 10000, 1000, 10000)
+	book.InsertOrder("2", BUYSIDE, 10001, 1000, 10000)
+	ord, err := book.UpdateOrder("1", BUYSIDE, 1000, 5000, 2000)
+	require.NoError(t, err)
+	require.Equal(t, OrderPart{"1", 10000, 5000, 2000, 0}, ord)
+	buys, _ := book.GetAllLevels()
+	require.Len(t, buys, 1)
+	require.Equal(t, "1", buys[0].Orders[0].Id, "the order should keep its priority")
+	require.Equal(t, int64(3000+10000), buys[0].TotalLeavesQty())
+
+	_, err = book.UpdateOrder("3", BUYSIDE, 1000, 5000, 0)
+	require.Error(t, err)
+	_, err = book.UpdateOrder("1", BUYSIDE, 1001, 5000, 0)
+	require.Error(t, err)
+}
+
 func TestOrderBookOnULList_RemoveOrders(t *testing.T) {
 	book := NewOrderBookOnULList(16, 4)
 	book.InsertOrder("1", BUYSIDE, 10000, 1000, 10000)
@@ -139,6 +139,20 @@ func (l *PriceLevel) removeOrders(beforeTime int64, callback func(OrderPart)) {
 	l.Orders = l.Orders[i:]
 }
 
+// updateOrder updates the quantity and the cumulative filled quantity of the order in place,
+// so the order keeps its priority in the price level
+func (l *PriceLevel) updateOrder(id string, qty int64, cumQty int64) (OrderPart, error) {
+	for i := range l.Orders {
+		if l.Orders[i].Id == id {
+			l.Orders[i].Qty = qty
+			l.Orders[i].CumQty = cumQty
+			return l.Orders[i], nil
+		}
+	}
+	// not found
+	return OrderPart{}, fmt.Errorf("order %s doesn't exist.", id)
+}
+
 func (l *PriceLevel) getOrder(id string) (OrderPart, error) {
 	for _, o := range l.Orders {
 		if o.Id == id {
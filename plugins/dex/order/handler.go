@@ -33,6 +33,20 @@ func NewHandler(dexKeeper *DexKeeper) sdk.Handler {
 			return handleNewOrder(ctx, dexKeeper, msg)
 		case CancelOrderMsg:
 			return handleCancelOrder(ctx, dexKeeper, msg)
+		case BatchNewOrderMsg:
+			if sdk.IsUpgrade(upgrade.BEP151) {
+				return sdk.ErrMsgNotSupported("BatchNewOrderMsg disabled in BEP-151").Result()
+			}
+			return handleBatchNewOrder(ctx, dexKeeper, msg)
+		case BatchCancelOrderMsg:
+			return handleBatchCancelOrder(ctx, dexKeeper, msg)
+		case CancelAllOrdersMsg:
+			return handleCancelAllOrders(ctx, dexKeeper, msg)
+		case AmendOrderMsg:
+			if sdk.IsUpgrade(upgrade.BEP151) {
+				return sdk.ErrMsgNotSupported("AmendOrderMsg disabled in BEP-151").Result()
+			}
+			return handleAmendOrder(ctx, dexKeeper, msg)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized dex msg type: %v", reflect.TypeOf(msg).Name())
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -40,77 +54,173 @@ func NewHandler(dexKeeper *DexKeeper) sdk.Handler {
 	}
 }
 
+// validateMiniTokenQty checks the quantity of an order of a mini token pair is no less than
+// MiniTokenMinExecutionAmount, unless it sells all the balance given.
+func validateMiniTokenQty(msg NewOrderMsg, balance int64) error {
+	baseAssetSymbol, quoteAssetSymbol := utils.TradingPair2AssetsSafe(strings.ToUpper(msg.Symbol))
+	if !sdk.IsUpgrade(sdk.BEP8) || !isMiniSymbolPair(baseAssetSymbol, quoteAssetSymbol) {
+		return nil
+	}
+	var quantityBigEnough bool
+	if msg.Side == Side.BUY {
+		quantityBigEnough = msg.Quantity >= common.MiniTokenMinExecutionAmount
+	} else if msg.Side == Side.SELL {
+		quantityBigEnough = (msg.Quantity >= common.MiniTokenMinExecutionAmount) || balance == msg.Quantity
+	}
+	if !quantityBigEnough {
+		return fmt.Errorf("quantity is too small, the min quantity is %d or total free balance of the mini token",
+			common.MiniTokenMinExecutionAmount)
+	}
+	return nil
+}
+
+// validateQtyAndLockBalance checks the free balance of the account is enough for the order and locks it
 func validateQtyAndLockBalance(ctx sdk.Context, keeper *DexKeeper, acc common.NamedAccount, msg NewOrderMsg) error {
-	symbol := strings.ToUpper(msg.Symbol)
-	baseAssetSymbol, quoteAssetSymbol := utils.TradingPair2AssetsSafe(symbol)
-	notional := utils.CalBigNotionalInt64(msg.Price, msg.Quantity)
+	toLock, err := validateQty(keeper, acc, msg)
+	if err != nil {
+		return err
+	}
+	lockBalance(ctx, keeper, acc, toLock[0])
+	return nil
+}
 
+// validateQty checks the free balance of the account is enough for all the given orders together, and
+// returns the coins to lock for each of them
+func validateQty(keeper *DexKeeper, acc common.NamedAccount, msgs ...NewOrderMsg) ([]sdk.Coins, error) {
 	// note: the check sequence is well designed.
 	freeBalance := acc.GetCoins()
 
-	if sdk.IsUpgrade(sdk.BEP8) && isMiniSymbolPair(baseAssetSymbol, quoteAssetSymbol) {
-		var quantityBigEnough bool
-		if msg.Side == Side.BUY {
-			quantityBigEnough = msg.Quantity >= common.MiniTokenMinExecutionAmount
-		} else if msg.Side == Side.SELL {
-			quantityBigEnough = (msg.Quantity >= common.MiniTokenMinExecutionAmount) || freeBalance.AmountOf(symbol) == msg.Quantity
+	var toLockCoins sdk.Coins
+	toLock := make([]sdk.Coins, len(msgs))
+	// qty of the previous buy orders of the same batch on each price level
+	pendingQty := make(map[string]int64)
+	for i, msg := range msgs {
+		symbol := strings.ToUpper(msg.Symbol)
+		baseAssetSymbol, quoteAssetSymbol := utils.TradingPair2AssetsSafe(symbol)
+		notional := utils.CalBigNotionalInt64(msg.Price, msg.Quantity)
+		isMarketBuy := msg.OrderType == OrderType.MARKET && msg.Side == Side.BUY
+		if isMarketBuy {
+			// a market buy order locks the quote asset amount it specifies
+			notional = msg.Quantity
 		}
-		if !quantityBigEnough {
-			return fmt.Errorf("quantity is too small, the min quantity is %d or total free balance of the mini token",
-				common.MiniTokenMinExecutionAmount)
+
+		// the base asset quantity of a market buy order is only known when it's matched
+		if !isMarketBuy {
+			if err := validateMiniTokenQty(msg, freeBalance.AmountOf(symbol)); err != nil {
+				return nil, err
+			}
 		}
-	}
 
-	var toLockCoins sdk.Coins
-	if msg.Side == Side.BUY {
-		// for buy orders,
-		// 1. total notional == ToLock(quoteAsset) <= FreeBalance(quoteAsset) <= TotalSupply(quoteAsset) < Max(int64)
-		// 2. check whether the qty on this price level will overflow.
+		if msg.Side == Side.BUY {
+			// for buy orders,
+			// 1. total notional == ToLock(quoteAsset) <= FreeBalance(quoteAsset) <= TotalSupply(quoteAsset) < Max(int64)
+			// 2. check whether the qty on this price level will overflow.
+			// a market buy order is not on any price level until it's matched, which is checked by the match engine.
 
-		if freeBalance.AmountOf(quoteAssetSymbol) < notional {
-			return errors.New("do not have enough token to lock")
-		}
+			if freeBalance.AmountOf(quoteAssetSymbol)-toLockCoins.AmountOf(quoteAssetSymbol) < notional {
+				return nil, errors.New("do not have enough token to lock")
+			}
+			if isMarketBuy {
+				toLock[i] = sdk.Coins{{Denom: quoteAssetSymbol, Amount: notional}}
+				toLockCoins = toLockCoins.Plus(toLock[i])
+				continue
+			}
 
-		pl := keeper.GetPriceLevel(symbol, msg.Side, msg.Price)
-		totalQty := msg.Quantity
-		if pl != nil {
-			totalQty += pl.TotalLeavesQty()
-		}
-		if totalQty < 0 {
-			// overflow, this is a implicit requirement from the match engine.
-			return errors.New("order quantity is too large to be placed on this price level")
-		}
+			level := fmt.Sprintf("%s-%d", symbol, msg.Price)
+			pl := keeper.GetPriceLevel(symbol, msg.Side, msg.Price)
+			totalQty := msg.Quantity + pendingQty[level]
+			if pl != nil {
+				totalQty += pl.TotalLeavesQty()
+			}
+			if totalQty < 0 {
+				// overflow, this is a implicit requirement from the match engine.
+				return nil, errors.New("order quantity is too large to be placed on this price level")
+			}
+			pendingQty[level] += msg.Quantity
 
-		toLockCoins = sdk.Coins{{Denom: quoteAssetSymbol, Amount: notional}}
-	} else {
-		// for sell orders,
-		// 1. total qty == ToLock(baseAsset) <= FreeBalance(baseAsset) <= TotalSupply(baseAsset) < Max(int64)
-		// 2. For a sell order, total notional on one price level is allowed to overflow.
-		// This order won't be fully filled as the buyer does not have such huge tokens to pay for it.
+			toLock[i] = sdk.Coins{{Denom: quoteAssetSymbol, Amount: notional}}
+		} else {
+			// for sell orders,
+			// 1. total qty == ToLock(baseAsset) <= FreeBalance(baseAsset) <= TotalSupply(baseAsset) < Max(int64)
+			// 2. For a sell order, total notional on one price level is allowed to overflow.
+			// This order won't be fully filled as the buyer does not have such huge tokens to pay for it.
 
-		if freeBalance.AmountOf(baseAssetSymbol) < msg.Quantity {
-			return errors.New("do not have enough token to lock")
-		}
+			if freeBalance.AmountOf(baseAssetSymbol)-toLockCoins.AmountOf(baseAssetSymbol) < msg.Quantity {
+				return nil, errors.New("do not have enough token to lock")
+			}
 
-		toLockCoins = sdk.Coins{{Denom: baseAssetSymbol, Amount: msg.Quantity}}
+			toLock[i] = sdk.Coins{{Denom: baseAssetSymbol, Amount: msg.Quantity}}
+		}
+		toLockCoins = toLockCoins.Plus(toLock[i])
 	}
+	return toLock, nil
+}
 
-	_ = acc.SetCoins(freeBalance.Minus(toLockCoins))
+// lockBalance moves the coins from the free balance of the account to its locked balance
+func lockBalance(ctx sdk.Context, keeper *DexKeeper, acc common.NamedAccount, toLockCoins sdk.Coins) {
+	_ = acc.SetCoins(acc.GetCoins().Minus(toLockCoins))
 	acc.SetLockedCoins(acc.GetLockedCoins().Plus(toLockCoins))
 	keeper.am.SetAccount(ctx, acc)
-	return nil
 }
 
 func handleNewOrder(
 	ctx sdk.Context, dexKeeper *DexKeeper, msg NewOrderMsg,
 ) sdk.Result {
+	acc := dexKeeper.am.GetAccount(ctx, msg.Sender).(common.NamedAccount)
+	msg, sdkErr := prepareNewOrder(ctx, dexKeeper, msg, func() string {
+		return GenerateOrderID(acc.GetSequence(), msg.Sender)
+	})
+	if sdkErr != nil {
+		return sdkErr.Result()
+	}
+
+	// the following is done in the app's checkstate / deliverstate, so it's safe to ignore isCheckTx
+	err := validateQtyAndLockBalance(ctx, dexKeeper, acc, msg)
+	if err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+	}
+
+	if sdkErr := placeOrder(ctx, dexKeeper, msg); sdkErr != nil {
+		return sdkErr.Result()
+	}
 
-	if _, ok := dexKeeper.OrderExists(msg.Symbol, msg.Id); ok {
+	response := NewOrderResponse{
+		OrderID: msg.Id,
+	}
+	serialized, err := json.Marshal(&response)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).Result()
+	}
+
+	return sdk.Result{
+		Data: serialized,
+	}
+}
+
+// prepareNewOrder checks the new order before its balance is locked and returns the order to be placed,
+// which differs from the given one for a market order. expectedID gives the ID the order should have.
+func prepareNewOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg NewOrderMsg, expectedID func() string) (NewOrderMsg, sdk.Error) {
+	_, ok := dexKeeper.OrderExists(msg.Symbol, msg.Id)
+	if !ok {
+		_, ok = dexKeeper.TriggerOrderExists(msg.Symbol, msg.Id)
+	}
+	if ok {
 		errString := fmt.Sprintf("Duplicated order [%v] on symbol [%v]", msg.Id, msg.Symbol)
-		return sdk.NewError(types.DefaultCodespace, types.CodeDuplicatedOrder, errString).Result()
+		return msg, sdk.NewError(types.DefaultCodespace, types.CodeDuplicatedOrder, errString)
+	}
+
+	if msg.OrderType == OrderType.MARKET && !sdk.IsUpgrade(upgrade.MarketOrder) {
+		return msg, sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, "market order is not supported yet")
+	}
+
+	if (IsConditionalOrderType(msg.OrderType) || msg.StopPrice != 0) && !sdk.IsUpgrade(upgrade.ConditionalOrder) {
+		return msg, sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, "conditional order is not supported yet")
+	}
+
+	if (msg.TimeInForce == TimeInForce.FOK || msg.PostOnly) && !sdk.IsUpgrade(upgrade.FOKAndPostOnly) {
+		return msg, sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, "FOK and post-only orders are not supported yet")
 	}
 
-	acc := dexKeeper.am.GetAccount(ctx, msg.Sender).(common.NamedAccount)
 	if !ctx.IsReCheckTx() {
 		//for recheck:
 		// 1. sequence is verified in anteHandler
@@ -118,93 +228,110 @@ func handleNewOrder(
 		// 3. trading pair is verified
 		// 4. price/qty may have odd tick size/lot size, but it can be handled as
 		//    other existing orders.
-		err := validateOrder(ctx, dexKeeper, acc, msg)
+		err := validateOrderWithID(ctx, dexKeeper, msg, expectedID)
 
 		if err != nil {
-			return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+			return msg, sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error())
 		}
 	}
+	return msg, nil
+}
 
-	// the following is done in the app's checkstate / deliverstate, so it's safe to ignore isCheckTx
-	err := validateQtyAndLockBalance(ctx, dexKeeper, acc, msg)
-	if err != nil {
-		return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
-	}
-
+// placeOrder inserts the order whose balance has been locked into the order book, or the trigger book
+// for a conditional order.
+func placeOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg NewOrderMsg) sdk.Error {
 	// this is done in memory! we must not run this block in checktx or simulate!
-	if ctx.IsDeliverTx() { // only subtract coins & insert into OB during DeliverTx
-		if txHash, ok := ctx.Value(baseapp.TxHashKey).(string); ok {
-			blockHeader := ctx.BlockHeader()
-			height := blockHeader.Height
-			timestamp := blockHeader.Time.UnixNano()
-			var txSource int64
-			upgrade.UpgradeBEP10(func() {
-				txSource = 0
-			}, func() {
-				if txSrc, ok := ctx.Value(baseapp.TxSourceKey).(int64); ok {
-					txSource = txSrc
-				} else {
-					dexKeeper.logger.Error("cannot get txSource from ctx")
-				}
-			})
-			msg := OrderInfo{
-				msg,
-				height, timestamp,
-				height, timestamp,
-				0, txHash, txSource}
-
-			err := dexKeeper.AddOrder(msg, false)
-
-			if err != nil {
-				return sdk.NewError(types.DefaultCodespace, types.CodeFailInsertOrder, err.Error()).Result()
-			}
+	if !ctx.IsDeliverTx() { // only insert into OB during DeliverTx
+		return nil
+	}
+	txHash := mustGetTxHash(ctx)
+	blockHeader := ctx.BlockHeader()
+	height := blockHeader.Height
+	timestamp := blockHeader.Time.UnixNano()
+	var txSource int64
+	upgrade.UpgradeBEP10(func() {
+		txSource = 0
+	}, func() {
+		if txSrc, ok := ctx.Value(baseapp.TxSourceKey).(int64); ok {
+			txSource = txSrc
 		} else {
-			panic("cannot get txHash from ctx")
+			dexKeeper.logger.Error("cannot get txSource from ctx")
 		}
+	})
+	info := OrderInfo{
+		msg,
+		height, timestamp,
+		height, timestamp,
+		0, txHash, txSource}
+
+	var err error
+	if IsConditionalOrderType(info.OrderType) {
+		// conditional orders wait in the trigger book until the stop price is crossed
+		err = dexKeeper.AddTriggerOrder(info, false)
+	} else {
+		err = dexKeeper.AddOrder(info, false)
 	}
 
-	response := NewOrderResponse{
-		OrderID: msg.Id,
-	}
-	serialized, err := json.Marshal(&response)
 	if err != nil {
-		return sdk.ErrInternal(err.Error()).Result()
-	}
-
-	return sdk.Result{
-		Data: serialized,
+		return sdk.NewError(types.DefaultCodespace, types.CodeFailInsertOrder, err.Error())
 	}
+	return nil
 }
 
 // Handle CancelOffer -
 func handleCancelOrder(
 	ctx sdk.Context, dexKeeper *DexKeeper, msg CancelOrderMsg,
 ) sdk.Result {
+	fee, sdkErr := cancelOrder(ctx, dexKeeper, msg, true)
+	if sdkErr != nil {
+		return sdkErr.Result()
+	}
+	if ctx.IsDeliverTx() {
+		// add fee to pool, even it's free
+		fees.Pool.AddFee(mustGetTxHash(ctx), fee)
+	}
+	return sdk.Result{}
+}
+
+// cancelOrder cancels the order and charges the cancel fee from the sender if chargeFee is set. The fee is
+// returned to be added into the fee pool by the caller, as the pool keeps one fee per tx.
+func cancelOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg CancelOrderMsg, chargeFee bool) (sdk.Fee, sdk.Error) {
 	origOrd, ok := dexKeeper.OrderExists(msg.Symbol, msg.RefId)
+	isTriggerOrder := false
+	if !ok {
+		origOrd, ok = dexKeeper.TriggerOrderExists(msg.Symbol, msg.RefId)
+		isTriggerOrder = ok
+	}
 
 	//only check whether there exists order to cancel
 	if !ok {
 		errString := fmt.Sprintf("Failed to find order [%v]", msg.RefId)
-		return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, errString).Result()
+		return sdk.Fee{}, sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, errString)
 	}
 
 	// only can cancel their own order
 	if !reflect.DeepEqual(msg.Sender, origOrd.Sender) {
 		errString := fmt.Sprintf("Order [%v] does not belong to transaction sender", msg.RefId)
-		return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, errString).Result()
+		return sdk.Fee{}, sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, errString)
 	}
 
-	ord, err := dexKeeper.GetOrder(origOrd.Id, origOrd.Symbol, origOrd.Side, origOrd.Price)
-	if err != nil {
-		return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, err.Error()).Result()
+	var ord me.OrderPart
+	if isTriggerOrder {
+		ord = triggerOrderPart(&origOrd)
+	} else {
+		var err error
+		ord, err = dexKeeper.GetOrder(origOrd.Id, origOrd.Symbol, origOrd.Side, origOrd.Price)
+		if err != nil {
+			return sdk.Fee{}, sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, err.Error())
+		}
 	}
 	transfer := TransferFromCanceled(ord, origOrd, false)
 	sdkError := dexKeeper.doTransfer(ctx, &transfer)
 	if sdkError != nil {
-		return sdkError.Result()
+		return sdk.Fee{}, sdkError
 	}
 	fee := sdk.Fee{}
-	if !transfer.FeeFree() {
+	if chargeFee && !transfer.FeeFree() {
 		acc := dexKeeper.am.GetAccount(ctx, msg.Sender)
 		fee = dexKeeper.FeeManager.CalcFixedFee(acc.GetCoins(), transfer.eventType, transfer.inAsset, dexKeeper.GetEngines())
 		_ = acc.SetCoins(acc.GetCoins().Minus(fee.Tokens))
@@ -213,38 +340,161 @@ func handleCancelOrder(
 
 	// this is done in memory! we must not run this block in checktx or simulate!
 	if ctx.IsDeliverTx() {
-		if txHash, ok := ctx.Value(baseapp.TxHashKey).(string); !ok {
-			panic("cannot get txHash from ctx")
-		} else {
-			// add fee to pool, even it's free
-			fees.Pool.AddFee(txHash, fee)
+		changeType, remove := Canceled, dexKeeper.RemoveOrder
+		if isTriggerOrder {
+			changeType, remove = TriggerCanceled, dexKeeper.RemoveTriggerOrder
 		}
 		//remove order from cache and order book
-		err := dexKeeper.RemoveOrder(origOrd.Id, origOrd.Symbol, func(ord me.OrderPart) {
+		err := remove(origOrd.Id, origOrd.Symbol, func(ord me.OrderPart) {
 			if dexKeeper.ShouldPublishOrder() {
-				change := OrderChange{msg.RefId, Canceled, fee.String(), nil}
+				change := OrderChange{msg.RefId, changeType, fee.String(), nil, nil}
 				dexKeeper.UpdateOrderChangeSync(change, msg.Symbol)
 				dexKeeper.updateRoundOrderFee(string(msg.Sender), fee)
 			}
 		})
 		if err != nil {
-			return sdk.NewError(types.DefaultCodespace, types.CodeFailCancelOrder, err.Error()).Result()
+			return sdk.Fee{}, sdk.NewError(types.DefaultCodespace, types.CodeFailCancelOrder, err.Error())
+		}
+	}
+
+	return fee, nil
+}
+
+// handleAmendOrder changes the price and/or quantity of an open order in place. Only the delta of the
+// locked balance is locked or unlocked, and no cancel fee is charged.
+func handleAmendOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg AmendOrderMsg) sdk.Result {
+	origOrd, ok := dexKeeper.OrderExists(msg.Symbol, msg.RefId)
+	if !ok {
+		errString := fmt.Sprintf("Failed to find order [%v]", msg.RefId)
+		return sdk.NewError(types.DefaultCodespace, types.CodeFailAmendOrder, errString).Result()
+	}
+	if !reflect.DeepEqual(msg.Sender, origOrd.Sender) {
+		errString := fmt.Sprintf("Order [%v] does not belong to transaction sender", msg.RefId)
+		return sdk.NewError(types.DefaultCodespace, types.CodeFailAmendOrder, errString).Result()
+	}
+	if msg.Price == origOrd.Price && msg.Quantity == origOrd.Quantity {
+		return types.ErrInvalidOrderParam("Quantity", "Neither price nor quantity is changed").Result()
+	}
+
+	ord, err := dexKeeper.GetOrder(origOrd.Id, origOrd.Symbol, origOrd.Side, origOrd.Price)
+	if err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeFailAmendOrder, err.Error()).Result()
+	}
+	if msg.Quantity <= ord.CumQty {
+		errString := fmt.Sprintf("Quantity(%d) should be larger than the filled quantity(%d)", msg.Quantity, ord.CumQty)
+		return types.ErrInvalidOrderParam("Quantity", errString).Result()
+	}
+
+	amended := origOrd.NewOrderMsg
+	amended.Price, amended.Quantity = msg.Price, msg.Quantity
+	if !ctx.IsReCheckTx() {
+		err = validateOrderWithID(ctx, dexKeeper, amended, func() string { return amended.Id })
+		if err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+		}
+	}
+
+	// the same quantity check as a new order, where the balance to sell all includes what's locked by the order
+	acc := dexKeeper.am.GetAccount(ctx, msg.Sender)
+	baseAssetSymbol, _ := utils.TradingPair2AssetsSafe(strings.ToUpper(amended.Symbol))
+	sellAll := acc.GetCoins().AmountOf(baseAssetSymbol) + origOrd.Quantity
+	if err = validateMiniTokenQty(amended, sellAll); err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+	}
+
+	// the following is done in the app's checkstate / deliverstate, so it's safe to ignore isCheckTx
+	err = lockAmendedBalance(ctx, dexKeeper, origOrd, amended, ord.CumQty)
+	if err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+	}
+
+	// this is done in memory! we must not run this block in checktx or simulate!
+	if ctx.IsDeliverTx() {
+		height, timestamp := ctx.BlockHeader().Height, ctx.BlockHeader().Time.UnixNano()
+		_, err = dexKeeper.AmendOrder(msg.Symbol, msg.RefId, msg.Price, msg.Quantity, height, timestamp, false)
+		if err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeFailAmendOrder, err.Error()).Result()
+		}
+		if dexKeeper.ShouldPublishOrder() {
+			amendment := &OrderAmendment{PrevPrice: origOrd.Price, PrevQuantity: origOrd.Quantity}
+			change := OrderChange{msg.RefId, Amended, "", nil, amendment}
+			dexKeeper.UpdateOrderChangeSync(change, msg.Symbol)
 		}
 	}
 
 	return sdk.Result{}
 }
 
+// lockAmendedBalance locks or unlocks the delta of the balance locked by the unfilled part of the order
+// after it's amended. The locked balance is computed the same way as it's unlocked when the order is canceled.
+func lockAmendedBalance(ctx sdk.Context, keeper *DexKeeper, origOrd OrderInfo, amended NewOrderMsg, cumQty int64) error {
+	symbol := strings.ToUpper(amended.Symbol)
+	baseAssetSymbol, quoteAssetSymbol := utils.TradingPair2AssetsSafe(symbol)
+
+	var asset string
+	var delta int64
+	if amended.Side == Side.BUY {
+		if amended.Price != origOrd.Price || amended.Quantity > origOrd.Quantity {
+			pl := keeper.GetPriceLevel(symbol, amended.Side, amended.Price)
+			totalQty := amended.Quantity - cumQty
+			if pl != nil {
+				totalQty += pl.TotalLeavesQty()
+			}
+			if totalQty < 0 {
+				// overflow, this is a implicit requirement from the match engine.
+				return errors.New("order quantity is too large to be placed on this price level")
+			}
+		}
+		locked := utils.CalBigNotionalInt64(origOrd.Price, origOrd.Quantity) - utils.CalBigNotionalInt64(origOrd.Price, cumQty)
+		toLock := utils.CalBigNotionalInt64(amended.Price, amended.Quantity) - utils.CalBigNotionalInt64(amended.Price, cumQty)
+		asset, delta = quoteAssetSymbol, toLock-locked
+	} else {
+		asset, delta = baseAssetSymbol, amended.Quantity-origOrd.Quantity
+	}
+	if delta == 0 {
+		return nil
+	}
+
+	acc := keeper.am.GetAccount(ctx, amended.Sender).(common.NamedAccount)
+	if delta > 0 {
+		if acc.GetCoins().AmountOf(asset) < delta {
+			return errors.New("do not have enough token to lock")
+		}
+		coins := sdk.Coins{sdk.NewCoin(asset, delta)}
+		_ = acc.SetCoins(acc.GetCoins().Minus(coins))
+		acc.SetLockedCoins(acc.GetLockedCoins().Plus(coins))
+	} else {
+		coins := sdk.Coins{sdk.NewCoin(asset, -delta)}
+		_ = acc.SetCoins(acc.GetCoins().Plus(coins))
+		acc.SetLockedCoins(acc.GetLockedCoins().Minus(coins))
+	}
+	keeper.am.SetAccount(ctx, acc)
+	return nil
+}
+
+func mustGetTxHash(ctx sdk.Context) string {
+	txHash, ok := ctx.Value(baseapp.TxHashKey).(string)
+	if !ok {
+		panic("cannot get txHash from ctx")
+	}
+	return txHash
+}
+
 func validateOrder(ctx sdk.Context, dexKeeper *DexKeeper, acc sdk.Account, msg NewOrderMsg) error {
+	return validateOrderWithID(ctx, dexKeeper, msg, func() string {
+		return GenerateOrderID(acc.GetSequence(), msg.Sender)
+	})
+}
+
+// validateOrderWithID validates the order, whose ID should be the one given by expectedID
+func validateOrderWithID(ctx sdk.Context, dexKeeper *DexKeeper, msg NewOrderMsg, expectedID func() string) error {
 	baseAsset, quoteAsset, err := utils.TradingPair2Assets(msg.Symbol)
 	if err != nil {
 		return err
 	}
 
-	seq := acc.GetSequence()
-	expectedID := GenerateOrderID(seq, msg.Sender)
-	if expectedID != msg.Id {
-		return fmt.Errorf("the order ID(%s) given did not match the expected one: `%s`", msg.Id, expectedID)
+	if id := expectedID(); id != msg.Id {
+		return fmt.Errorf("the order ID(%s) given did not match the expected one: `%s`", msg.Id, id)
 	}
 
 	pair, err := dexKeeper.PairMapper.GetTradingPair(ctx, baseAsset, quoteAsset)
@@ -252,6 +502,10 @@ func validateOrder(ctx sdk.Context, dexKeeper *DexKeeper, acc sdk.Account, msg N
 		return err
 	}
 
+	if msg.OrderType == OrderType.MARKET {
+		return validateMarketOrder(msg, pair)
+	}
+
 	if msg.Quantity <= 0 || msg.Quantity%pair.LotSize.ToInt64() != 0 {
 		return fmt.Errorf("quantity(%v) is not rounded to lotSize(%v)", msg.Quantity, pair.LotSize.ToInt64())
 	}
@@ -260,6 +514,10 @@ func validateOrder(ctx sdk.Context, dexKeeper *DexKeeper, acc sdk.Account, msg N
 		return fmt.Errorf("price(%v) is not rounded to tickSize(%v)", msg.Price, pair.TickSize.ToInt64())
 	}
 
+	if IsConditionalOrderType(msg.OrderType) && (msg.StopPrice <= 0 || msg.StopPrice%pair.TickSize.ToInt64() != 0) {
+		return fmt.Errorf("stop price(%v) is not rounded to tickSize(%v)", msg.StopPrice, pair.TickSize.ToInt64())
+	}
+
 	if sdk.IsUpgrade(upgrade.LotSizeOptimization) {
 		if utils.IsUnderMinNotional(msg.Price, msg.Quantity) {
 			return errors.New("notional value of the order is too small")
@@ -272,3 +530,15 @@ func validateOrder(ctx sdk.Context, dexKeeper *DexKeeper, acc sdk.Account, msg N
 
 	return nil
 }
+
+// validateMarketOrder checks the quantity of a market order, which is the quote asset amount to spend for
+// a buy order, and the base asset quantity to sell for a sell order. It has no price.
+func validateMarketOrder(msg NewOrderMsg, pair types.TradingPair) error {
+	if msg.Side == Side.BUY {
+		return nil
+	}
+	if msg.Quantity <= 0 || msg.Quantity%pair.LotSize.ToInt64() != 0 {
+		return fmt.Errorf("quantity(%v) is not rounded to lotSize(%v)", msg.Quantity, pair.LotSize.ToInt64())
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package order
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
+
+	common "github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/dex/types"
+)
+
+// handleBatchNewOrder places the orders of the batch that pass the checks of a single NewOrderMsg.
+// The balance of all the accepted orders is checked at once, and the tx fails if it's not enough. An order failed
+// to be placed into the order book is reported in its item, and only the balance of the orders placed is locked.
+// The tx fails if no order is placed.
+func handleBatchNewOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg BatchNewOrderMsg) sdk.Result {
+	acc := dexKeeper.am.GetAccount(ctx, msg.Sender).(common.NamedAccount)
+	seq := acc.GetSequence()
+
+	results := make([]BatchItemResult, len(msg.Orders))
+	accepted := make([]NewOrderMsg, 0, len(msg.Orders))
+	acceptedIdx := make([]int, 0, len(msg.Orders))
+	var firstErr sdk.Error
+	for i, order := range msg.Orders {
+		index := i
+		results[i].OrderID = order.Id
+		order, sdkErr := prepareNewOrder(ctx, dexKeeper, order, func() string {
+			return GenerateBatchOrderID(seq, msg.Sender, index)
+		})
+		if sdkErr != nil {
+			results[i].Code, results[i].Log = sdkErr.ABCICode(), sdkErr.ABCILog()
+			if firstErr == nil {
+				firstErr = sdkErr
+			}
+			continue
+		}
+		accepted = append(accepted, order)
+		acceptedIdx = append(acceptedIdx, i)
+	}
+	if len(accepted) == 0 {
+		return firstErr.Result()
+	}
+
+	// the following is done in the app's checkstate / deliverstate, so it's safe to ignore isCheckTx
+	toLock, err := validateQty(dexKeeper, acc, accepted...)
+	if err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+	}
+
+	// the orders placed stay in the order book even if a later one fails, so nothing can fail after the first one
+	// is placed except the placement of the others, which is reported per item
+	var lockedCoins sdk.Coins
+	placed := 0
+	for i, order := range accepted {
+		if sdkErr := placeOrder(ctx, dexKeeper, order); sdkErr != nil {
+			results[acceptedIdx[i]].Code, results[acceptedIdx[i]].Log = sdkErr.ABCICode(), sdkErr.ABCILog()
+			if firstErr == nil {
+				firstErr = sdkErr
+			}
+			continue
+		}
+		lockedCoins = lockedCoins.Plus(toLock[i])
+		placed++
+	}
+	if placed == 0 {
+		return firstErr.Result()
+	}
+	lockBalance(ctx, dexKeeper, acc, lockedCoins)
+
+	if ctx.IsDeliverTx() && dexKeeper.ShouldPublishOrder() {
+		for i, result := range results {
+			if !result.IsOK() {
+				order := msg.Orders[i]
+				dexKeeper.UpdateOrderChangeSync(OrderChange{Id: order.Id, Tpe: FailedBlocking, MsgForFailedTx: order}, order.Symbol)
+			}
+		}
+	}
+
+	return batchResult(BatchResponse{Results: results})
+}
+
+// handleBatchCancelOrder cancels the orders of the batch one by one. The cancel fee is charged for each
+// canceled order and the sum is added into the fee pool for the tx. The tx fails if no order is canceled.
+func handleBatchCancelOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg BatchCancelOrderMsg) sdk.Result {
+	results := make([]BatchItemResult, len(msg.Cancels))
+	fee := sdk.Fee{}
+	canceled := 0
+	var firstErr sdk.Error
+	for i, cancel := range msg.Cancels {
+		results[i].OrderID = cancel.RefId
+		itemFee, sdkErr := cancelOrder(ctx, dexKeeper, cancel, true)
+		if sdkErr != nil {
+			results[i].Code, results[i].Log = sdkErr.ABCICode(), sdkErr.ABCILog()
+			if firstErr == nil {
+				firstErr = sdkErr
+			}
+			continue
+		}
+		fee.AddFee(itemFee)
+		canceled++
+	}
+	if canceled == 0 {
+		return firstErr.Result()
+	}
+
+	if ctx.IsDeliverTx() {
+		// add fee to pool, even it's free
+		fees.Pool.AddFee(mustGetTxHash(ctx), fee)
+		if dexKeeper.ShouldPublishOrder() {
+			for i, result := range results {
+				if !result.IsOK() {
+					cancel := msg.Cancels[i]
+					dexKeeper.UpdateOrderChangeSync(OrderChange{Id: cancel.RefId, Tpe: FailedBlocking, MsgForFailedTx: cancel}, cancel.Symbol)
+				}
+			}
+		}
+	}
+
+	return batchResult(BatchResponse{Results: results})
+}
+
+// handleCancelAllOrders cancels at most MaxCancelAllOrders open orders of the sender selected by the msg one by one.
+// The cancel fee is charged for each canceled order and the sum is added into the fee pool for the tx. The number of
+// the selected orders left open is given in BatchResponse.Remaining. The tx fails if no order is canceled.
+func handleCancelAllOrders(ctx sdk.Context, dexKeeper *DexKeeper, msg CancelAllOrdersMsg) sdk.Result {
+	cancels, remaining := ordersToCancelAll(dexKeeper, msg)
+	if len(cancels) == 0 {
+		return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, "No open order to cancel").Result()
+	}
+
+	results := make([]BatchItemResult, len(cancels))
+	fee := sdk.Fee{}
+	canceled := 0
+	var firstErr sdk.Error
+	for i, cancel := range cancels {
+		results[i].OrderID = cancel.RefId
+		itemFee, sdkErr := cancelOrder(ctx, dexKeeper, cancel, true)
+		if sdkErr != nil {
+			results[i].Code, results[i].Log = sdkErr.ABCICode(), sdkErr.ABCILog()
+			if firstErr == nil {
+				firstErr = sdkErr
+			}
+			continue
+		}
+		fee.AddFee(itemFee)
+		canceled++
+	}
+	if canceled == 0 {
+		return firstErr.Result()
+	}
+
+	if ctx.IsDeliverTx() {
+		// add fee to pool, even it's free
+		fees.Pool.AddFee(mustGetTxHash(ctx), fee)
+		if dexKeeper.ShouldPublishOrder() {
+			for i, result := range results {
+				if !result.IsOK() {
+					cancel := cancels[i]
+					dexKeeper.UpdateOrderChangeSync(OrderChange{Id: cancel.RefId, Tpe: FailedBlocking, MsgForFailedTx: cancel}, cancel.Symbol)
+				}
+			}
+		}
+	}
+
+	return batchResult(BatchResponse{Results: results, Remaining: remaining})
+}
+
+// ordersToCancelAll returns the cancels of the open orders and conditional orders of the sender that match
+// the symbol and side of the msg, at most MaxCancelAllOrders of them, and the number of the matched orders beyond
+// that. Symbols are sorted and orders are sorted by placement, so the same orders are canceled in all nodes and
+// in replay.
+func ordersToCancelAll(dexKeeper *DexKeeper, msg CancelAllOrdersMsg) (cancels []CancelOrderMsg, remaining int) {
+	symbols := make([]string, 0, len(dexKeeper.engines))
+	for symbol := range dexKeeper.engines {
+		if msg.Symbol == "" || strings.EqualFold(symbol, msg.Symbol) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+
+	matchSide := func(side int8) bool {
+		return msg.Side == 0 || msg.Side == side
+	}
+	cancels = make([]CancelOrderMsg, 0)
+	add := func(symbol, id string) {
+		if len(cancels) < MaxCancelAllOrders {
+			cancels = append(cancels, NewCancelOrderMsg(msg.Sender, symbol, id))
+		} else {
+			remaining++
+		}
+	}
+	for _, symbol := range symbols {
+		openOrders := dexKeeper.GetOpenOrders(symbol, msg.Sender)
+		sort.Slice(openOrders, func(i, j int) bool {
+			if openOrders[i].CreatedHeight != openOrders[j].CreatedHeight {
+				return openOrders[i].CreatedHeight < openOrders[j].CreatedHeight
+			}
+			return openOrders[i].Id < openOrders[j].Id
+		})
+		for _, openOrder := range openOrders {
+			if info, ok := dexKeeper.OrderExists(symbol, openOrder.Id); ok && matchSide(info.Side) {
+				add(symbol, openOrder.Id)
+			}
+		}
+		for _, triggerOrder := range dexKeeper.GetTriggerOrders(symbol, msg.Sender) {
+			if matchSide(triggerOrder.Side) {
+				add(symbol, triggerOrder.Id)
+			}
+		}
+	}
+	return cancels, remaining
+}
+
+func batchResult(resp BatchResponse) sdk.Result {
+	serialized, err := json.Marshal(resp)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).Result()
+	}
+	return sdk.Result{
+		Data: serialized,
+	}
+}
@@ -0,0 +1,194 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
+
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/types"
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+func TestHandler_BatchNewOrderAndCancel(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	pair := dextypes.NewTradingPair("ABC-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	handler := NewHandler(keeper)
+
+	// the order with a wrong id is rejected, the others are placed
+	ctx = ctx.WithValue(baseapp.TxHashKey, "BATCH1")
+	batch := NewBatchNewOrderMsg(addr, []NewOrderMsg{
+		NewNewOrderMsg(addr, GenerateBatchOrderID(0, addr, 0), Side.BUY, "ABC-000_BNB", 1e8, 1e8),
+		NewNewOrderMsg(addr, GenerateBatchOrderID(0, addr, 5), Side.BUY, "ABC-000_BNB", 1e8, 1e8),
+		NewNewOrderMsg(addr, GenerateBatchOrderID(0, addr, 2), Side.BUY, "ABC-000_BNB", 1.1e8, 1e8),
+	})
+	require.Nil(t, batch.ValidateBasic())
+	res := handler(ctx, batch)
+	require.True(t, res.IsOK(), res.Log)
+	resp, err := decodeBatchResponse(res.Data)
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 3)
+	require.True(t, resp.Results[0].IsOK())
+	require.Equal(t, sdk.ToABCICode(dextypes.DefaultCodespace, dextypes.CodeInvalidOrderParam), resp.Results[1].Code)
+	require.True(t, resp.Results[2].IsOK())
+	require.Len(t, keeper.GetAllOrdersForPair("ABC-000_BNB"), 2)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 2.1e8)}, acc.(types.NamedAccount).GetLockedCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 97.9e8)}, acc.GetCoins())
+
+	// each order can be locked alone, but not both of them
+	_ = acc.SetSequence(1)
+	am.SetAccount(ctx, acc)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "BATCH2")
+	batch = NewBatchNewOrderMsg(addr, []NewOrderMsg{
+		NewNewOrderMsg(addr, GenerateBatchOrderID(1, addr, 0), Side.BUY, "ABC-000_BNB", 1e8, 60e8),
+		NewNewOrderMsg(addr, GenerateBatchOrderID(1, addr, 1), Side.BUY, "ABC-000_BNB", 1e8, 60e8),
+	})
+	res = handler(ctx, batch)
+	require.Equal(t, sdk.ToABCICode(dextypes.DefaultCodespace, dextypes.CodeInvalidOrderParam), res.Code)
+	require.Len(t, keeper.GetAllOrdersForPair("ABC-000_BNB"), 2)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 2.1e8)}, acc.(types.NamedAccount).GetLockedCoins())
+
+	// the cancel fee is charged for each canceled order
+	ctx = ctx.WithValue(baseapp.TxHashKey, "BATCH3")
+	cancel := NewBatchCancelOrderMsg(addr, []CancelOrderMsg{
+		NewCancelOrderMsg(addr, "ABC-000_BNB", GenerateBatchOrderID(0, addr, 0)),
+		NewCancelOrderMsg(addr, "ABC-000_BNB", GenerateBatchOrderID(0, addr, 1)),
+		NewCancelOrderMsg(addr, "ABC-000_BNB", GenerateBatchOrderID(0, addr, 2)),
+	})
+	require.Nil(t, cancel.ValidateBasic())
+	res = handler(ctx, cancel)
+	require.True(t, res.IsOK(), res.Log)
+	resp, err = decodeBatchResponse(res.Data)
+	require.NoError(t, err)
+	require.True(t, resp.Results[0].IsOK())
+	require.Equal(t, sdk.ToABCICode(dextypes.DefaultCodespace, dextypes.CodeFailLocateOrderToCancel), resp.Results[1].Code)
+	require.True(t, resp.Results[2].IsOK())
+	require.Len(t, keeper.GetAllOrdersForPair("ABC-000_BNB"), 0)
+	require.Equal(t, sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 4e4)}, sdk.FeeForProposer), *fees.Pool.GetFee("BATCH3"))
+	acc = am.GetAccount(ctx, addr)
+	require.True(t, acc.(types.NamedAccount).GetLockedCoins().IsZero())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 100e8-4e4)}, acc.GetCoins())
+
+	// nothing is canceled, the tx fails
+	ctx = ctx.WithValue(baseapp.TxHashKey, "BATCH4")
+	res = handler(ctx, NewBatchCancelOrderMsg(addr, []CancelOrderMsg{
+		NewCancelOrderMsg(addr, "ABC-000_BNB", GenerateBatchOrderID(0, addr, 0)),
+	}))
+	require.Equal(t, sdk.ToABCICode(dextypes.DefaultCodespace, dextypes.CodeFailLocateOrderToCancel), res.Code)
+	fees.Pool.Clear()
+}
+
+func TestHandler_BatchNewOrderPlaceFailed(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	for _, base := range []string{"ABC-000", "XYZ-000"} {
+		pair := dextypes.NewTradingPair(base, "BNB", 1e8)
+		require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+		keeper.AddEngine(pair)
+	}
+	// the order of XYZ-000_BNB passes the checks but fails to be placed into the order book
+	delete(keeper.engines, "XYZ-000_BNB")
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	handler := NewHandler(keeper)
+
+	// only the balance of the order placed is locked
+	ctx = ctx.WithValue(baseapp.TxHashKey, "BATCH1")
+	res := handler(ctx, NewBatchNewOrderMsg(addr, []NewOrderMsg{
+		NewNewOrderMsg(addr, GenerateBatchOrderID(0, addr, 0), Side.BUY, "ABC-000_BNB", 1e8, 1e8),
+		NewNewOrderMsg(addr, GenerateBatchOrderID(0, addr, 1), Side.BUY, "XYZ-000_BNB", 1e8, 2e8),
+	}))
+	require.True(t, res.IsOK(), res.Log)
+	resp, err := decodeBatchResponse(res.Data)
+	require.NoError(t, err)
+	require.True(t, resp.Results[0].IsOK())
+	require.Equal(t, sdk.ToABCICode(dextypes.DefaultCodespace, dextypes.CodeFailInsertOrder), resp.Results[1].Code)
+	require.Len(t, keeper.GetAllOrdersForPair("ABC-000_BNB"), 1)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1e8)}, acc.(types.NamedAccount).GetLockedCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 99e8)}, acc.GetCoins())
+
+	// nothing is placed, the tx fails
+	_ = acc.SetSequence(1)
+	am.SetAccount(ctx, acc)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "BATCH2")
+	res = handler(ctx, NewBatchNewOrderMsg(addr, []NewOrderMsg{
+		NewNewOrderMsg(addr, GenerateBatchOrderID(1, addr, 0), Side.BUY, "XYZ-000_BNB", 1e8, 1e8),
+	}))
+	require.Equal(t, sdk.ToABCICode(dextypes.DefaultCodespace, dextypes.CodeFailInsertOrder), res.Code)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1e8)}, acc.(types.NamedAccount).GetLockedCoins())
+}
+
+func TestHandler_CancelAllOrders(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e8))
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	_, other := testutils.NewAccount(ctx, am, 0)
+	for i := 0; i < MaxCancelAllOrders+2; i++ {
+		msg := NewNewOrderMsg(addr, GenerateOrderID(int64(i), addr), Side.BUY, "ABC-000_BNB", 1e8, 1e8)
+		require.Nil(t, keeper.AddOrder(OrderInfo{msg, 1, 0, 1, 0, 0, "", 0}, false))
+	}
+	msg := NewNewOrderMsg(addr, GenerateOrderID(1000, addr), Side.SELL, "ABC-000_BNB", 2e8, 1e8)
+	require.Nil(t, keeper.AddOrder(OrderInfo{msg, 1, 0, 1, 0, 0, "", 0}, false))
+	msg = NewNewOrderMsg(addr, GenerateOrderID(1001, addr), Side.BUY, "XYZ-000_BNB", 1e8, 1e8)
+	require.Nil(t, keeper.AddOrder(OrderInfo{msg, 1, 0, 1, 0, 0, "", 0}, false))
+	msg = NewNewOrderMsg(other.GetAddress(), GenerateOrderID(0, other.GetAddress()), Side.BUY, "ABC-000_BNB", 1e8, 1e8)
+	require.Nil(t, keeper.AddOrder(OrderInfo{msg, 1, 0, 1, 0, 0, "", 0}, false))
+	acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{
+		sdk.NewCoin("ABC-000", 1e8),
+		sdk.NewCoin("BNB", (MaxCancelAllOrders+3)*1e8),
+	}.Sort())
+	am.SetAccount(ctx, acc)
+	handler := NewHandler(keeper)
+
+	// only the buy orders of ABC-000_BNB are canceled, at most MaxCancelAllOrders of them, each is charged
+	ctx = ctx.WithValue(baseapp.TxHashKey, "CANCELALL1")
+	res := handler(ctx, NewCancelAllOrdersMsg(addr, "abc-000_bnb", Side.BUY))
+	require.True(t, res.IsOK(), res.Log)
+	resp, err := decodeBatchResponse(res.Data)
+	require.NoError(t, err)
+	require.Len(t, resp.Results, MaxCancelAllOrders)
+	require.Equal(t, 2, resp.Remaining)
+	require.Equal(t, GenerateOrderID(0, addr), resp.Results[0].OrderID)
+	require.Len(t, keeper.GetOpenOrders("ABC-000_BNB", addr), 3)
+	require.Len(t, keeper.GetOpenOrders("ABC-000_BNB", other.GetAddress()), 1)
+	require.Len(t, keeper.GetOpenOrders("XYZ-000_BNB", addr), 1)
+	require.Equal(t, sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", MaxCancelAllOrders*2e4)}, sdk.FeeForProposer),
+		*fees.Pool.GetFee("CANCELALL1"))
+
+	// all the remaining orders are canceled
+	ctx = ctx.WithValue(baseapp.TxHashKey, "CANCELALL2")
+	res = handler(ctx, NewCancelAllOrdersMsg(addr, "", 0))
+	require.True(t, res.IsOK(), res.Log)
+	resp, err = decodeBatchResponse(res.Data)
+	require.NoError(t, err)
+	require.Zero(t, resp.Remaining)
+	require.Len(t, keeper.GetOpenOrders("ABC-000_BNB", addr), 0)
+	require.Len(t, keeper.GetOpenOrders("XYZ-000_BNB", addr), 0)
+	require.Equal(t, sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 8e4)}, sdk.FeeForProposer), *fees.Pool.GetFee("CANCELALL2"))
+	acc = am.GetAccount(ctx, addr)
+	require.True(t, acc.(types.NamedAccount).GetLockedCoins().IsZero())
+	require.Equal(t, sdk.Coins{
+		sdk.NewCoin("ABC-000", 1e8),
+		sdk.NewCoin("BNB", (MaxCancelAllOrders+103)*1e8-(MaxCancelAllOrders+4)*2e4),
+	}.Sort(), acc.GetCoins())
+
+	res = handler(ctx, NewCancelAllOrdersMsg(addr, "", 0))
+	require.Equal(t, sdk.ToABCICode(dextypes.DefaultCodespace, dextypes.CodeFailLocateOrderToCancel), res.Code)
+	fees.Pool.Clear()
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/cosmos/cosmos-sdk/baseapp"
 	cstore "github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
@@ -14,6 +15,9 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/testutils"
+	ctypes "github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/plugins/dex/store"
 	"github.com/bnb-chain/node/plugins/dex/types"
 	dextypes "github.com/bnb-chain/node/plugins/dex/types"
@@ -192,3 +196,188 @@ func TestHandler_ValidateOrder_MaxNotional(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, "notional value of the order is too large(cannot fit in int64)", err.Error())
 }
+
+func TestHandler_MarketOrder(t *testing.T) {
+	ctx, am, keeper := setup()
+	pair := types.NewTradingPair("ABC-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	handler := NewHandler(keeper)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "MARKET")
+
+	msg := NewMarketOrderMsg(addr, GenerateOrderID(0, addr), Side.BUY, "ABC-000_BNB", 10e8)
+	res := handler(ctx, msg)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeInvalidOrderParam), res.Code)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.MarketOrder, -1)
+	defer resetChainVersion()
+	// the quote asset amount to spend is locked as it is
+	res = handler(ctx, msg)
+	require.True(t, res.IsOK(), res.Log)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, int64(10e8), acc.(ctypes.NamedAccount).GetLockedCoins().AmountOf("BNB"))
+	require.Equal(t, int64(90e8), acc.GetCoins().AmountOf("BNB"))
+	info, ok := keeper.OrderExists("ABC-000_BNB", msg.Id)
+	require.True(t, ok)
+	require.Equal(t, int64(0), info.Price)
+	require.Equal(t, int64(10e8), info.Quantity)
+	require.True(t, keeper.engines["ABC-000_BNB"].HasMarketOrder(msg.Id))
+
+	// canceling it releases the quote asset
+	res = handler(ctx, NewCancelOrderMsg(addr, "ABC-000_BNB", msg.Id))
+	require.True(t, res.IsOK(), res.Log)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, int64(0), acc.(ctypes.NamedAccount).GetLockedCoins().AmountOf("BNB"))
+	require.False(t, keeper.engines["ABC-000_BNB"].HasMarketOrder(msg.Id))
+}
+
+func TestHandler_ConditionalOrder(t *testing.T) {
+	ctx, am, keeper := setup()
+	pair := types.NewTradingPair("ABC-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	handler := NewHandler(keeper)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "CONDITIONAL")
+
+	msg := NewConditionalOrderMsg(addr, GenerateOrderID(0, addr), OrderType.STOP_LIMIT, Side.BUY, "ABC-000_BNB", 1.1e8, 1.2e8, 1e8)
+	res := handler(ctx, msg)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeInvalidOrderParam), res.Code)
+	limit := NewNewOrderMsg(addr, GenerateOrderID(0, addr), Side.BUY, "ABC-000_BNB", 1e8, 1e8)
+	limit.StopPrice = 1.1e8
+	res = handler(ctx, limit)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeInvalidOrderParam), res.Code)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.ConditionalOrder, -1)
+	defer resetChainVersion()
+	res = handler(ctx, msg)
+	require.True(t, res.IsOK(), res.Log)
+	_, ok := keeper.TriggerOrderExists("ABC-000_BNB", msg.Id)
+	require.True(t, ok)
+}
+
+func TestHandler_FOKAndPostOnly(t *testing.T) {
+	ctx, am, keeper := setup()
+	pair := types.NewTradingPair("ABC-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	handler := NewHandler(keeper)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "FOK")
+
+	fok := NewNewOrderMsg(addr, GenerateOrderID(0, addr), Side.BUY, "ABC-000_BNB", 1e8, 1e8)
+	fok.TimeInForce = TimeInForce.FOK
+	res := handler(ctx, fok)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeInvalidOrderParam), res.Code)
+	_, acc2 := testutils.NewAccount(ctx, am, 100e8)
+	addr2 := acc2.GetAddress()
+	postOnly := NewNewOrderMsg(addr2, GenerateOrderID(0, addr2), Side.BUY, "ABC-000_BNB", 1e8, 1e8)
+	postOnly.PostOnly = true
+	res = handler(ctx, postOnly)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeInvalidOrderParam), res.Code)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.FOKAndPostOnly, -1)
+	defer resetChainVersion()
+	res = handler(ctx, fok)
+	require.True(t, res.IsOK(), res.Log)
+	res = handler(ctx.WithValue(baseapp.TxHashKey, "POSTONLY"), postOnly)
+	require.True(t, res.IsOK(), res.Log)
+}
+
+func TestHandler_AmendOrder(t *testing.T) {
+	ctx, am, keeper := setup()
+	pair := types.NewTradingPair("ABC-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	_, other := testutils.NewAccount(ctx, am, 100e8)
+	handler := NewHandler(keeper)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "AMEND")
+
+	id1, id2 := GenerateOrderID(0, addr), GenerateOrderID(1, addr)
+	res := handler(ctx, NewNewOrderMsg(addr, id1, Side.BUY, "ABC-000_BNB", 1e8, 3e8))
+	require.True(t, res.IsOK(), res.Log)
+	acc = am.GetAccount(ctx, addr)
+	_ = acc.SetSequence(1)
+	am.SetAccount(ctx, acc)
+	res = handler(ctx, NewNewOrderMsg(addr, id2, Side.BUY, "ABC-000_BNB", 1e8, 1e8))
+	require.True(t, res.IsOK(), res.Log)
+	levelOrders := func(price int64) []string {
+		ids := make([]string, 0)
+		if pl := keeper.GetPriceLevel("ABC-000_BNB", Side.BUY, price); pl != nil {
+			for _, o := range pl.Orders {
+				ids = append(ids, o.Id)
+			}
+		}
+		return ids
+	}
+	requireLocked := func(locked int64) {
+		acc := am.GetAccount(ctx, addr).(ctypes.NamedAccount)
+		require.Equal(t, locked, acc.GetLockedCoins().AmountOf("BNB"))
+		require.Equal(t, 100e8-locked, acc.GetCoins().AmountOf("BNB"))
+	}
+	requireLocked(4e8)
+
+	// reducing the quantity keeps the priority
+	res = handler(ctx, NewAmendOrderMsg(addr, "ABC-000_BNB", id1, 1e8, 2e8))
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, []string{id1, id2}, levelOrders(1e8))
+	requireLocked(3e8)
+	info, _ := keeper.OrderExists("ABC-000_BNB", id1)
+	require.Equal(t, int64(2e8), info.Quantity)
+
+	// increasing the quantity moves the order to the back
+	res = handler(ctx, NewAmendOrderMsg(addr, "ABC-000_BNB", id1, 1e8, 3e8))
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, []string{id2, id1}, levelOrders(1e8))
+	requireLocked(4e8)
+
+	// changing the price moves the order to the new price level
+	res = handler(ctx, NewAmendOrderMsg(addr, "ABC-000_BNB", id2, 1.1e8, 1e8))
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, []string{id1}, levelOrders(1e8))
+	require.Equal(t, []string{id2}, levelOrders(1.1e8))
+	requireLocked(4.1e8)
+
+	// not enough balance to lock
+	res = handler(ctx, NewAmendOrderMsg(addr, "ABC-000_BNB", id1, 1e8, 100e8))
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeInvalidOrderParam), res.Code)
+	requireLocked(4.1e8)
+
+	// only the owner can amend the order
+	res = handler(ctx, NewAmendOrderMsg(other.GetAddress(), "ABC-000_BNB", id1, 1e8, 1e8))
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeFailAmendOrder), res.Code)
+	res = handler(ctx, NewAmendOrderMsg(addr, "ABC-000_BNB", GenerateOrderID(2, addr), 1e8, 1e8))
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeFailAmendOrder), res.Code)
+}
+
+func TestHandler_AmendOrder_MiniToken(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP8, -1)
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	pair := types.NewTradingPair("XYZ-000M", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	handler := NewHandler(keeper)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "AMEND")
+
+	id := GenerateOrderID(0, addr)
+	res := handler(ctx, NewNewOrderMsg(addr, id, Side.BUY, "XYZ-000M_BNB", 1e8, 2e8))
+	require.True(t, res.IsOK(), res.Log)
+
+	// the amended quantity is checked the same as a new order
+	res = handler(ctx, NewAmendOrderMsg(addr, "XYZ-000M_BNB", id, 1e8, ctypes.MiniTokenMinExecutionAmount/2))
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeInvalidOrderParam), res.Code)
+	require.Contains(t, res.Log, "quantity is too small")
+	info, _ := keeper.OrderExists("XYZ-000M_BNB", id)
+	require.Equal(t, int64(2e8), info.Quantity)
+	res = handler(ctx, NewAmendOrderMsg(addr, "XYZ-000M_BNB", id, 1e8, ctypes.MiniTokenMinExecutionAmount))
+	require.True(t, res.IsOK(), res.Log)
+}
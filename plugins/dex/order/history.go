@@ -0,0 +1,203 @@
+package order
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// OrderHistory is a bounded in-memory index of the recent closed orders of each account and the recent
+// trades of each trading pair. It's fed from the order changes and the trades of each block, so that the
+// node can serve the history queries without an external indexer. It's not a part of the state and starts
+// empty when the node restarts.
+type OrderHistory struct {
+	mtx                       sync.RWMutex
+	maxClosedOrders           int // the accounts whose orders closed least recently are evicted beyond it
+	maxClosedOrdersPerAccount int
+	tradeBlocks               int64 // trades older than tradeBlocks blocks are pruned
+	maxTradesPerPair          int
+
+	closedOrders    map[string]*list.Element // bech32 address -> element of *accountClosedOrders in accounts
+	accounts        *list.List               // the accounts in the order their latest orders closed, the least recent first
+	numClosedOrders int
+	trades          map[string][]store.Trade // symbol -> trades, the oldest first
+}
+
+type accountClosedOrders struct {
+	addr   string
+	orders []store.ClosedOrder // the oldest first
+}
+
+// closedOrder is an order closed in a block and the change closing it
+type closedOrder struct {
+	info   *OrderInfo
+	status ChangeType
+}
+
+func NewOrderHistory(maxClosedOrders, maxClosedOrdersPerAccount int, tradeBlocks int64, maxTradesPerPair int) *OrderHistory {
+	return &OrderHistory{
+		maxClosedOrders:           maxClosedOrders,
+		maxClosedOrdersPerAccount: maxClosedOrdersPerAccount,
+		tradeBlocks:               tradeBlocks,
+		maxTradesPerPair:          maxTradesPerPair,
+		closedOrders:              make(map[string]*list.Element),
+		accounts:                  list.New(),
+		trades:                    make(map[string][]store.Trade),
+	}
+}
+
+// closedOrdersOfBlock returns the orders closed by the order changes and the trades of a block. The fully
+// filled orders are not in the order changes and are derived from the trades.
+func closedOrdersOfBlock(trades map[string][]me.Trade, changes OrderChanges, getInfo func(id string) *OrderInfo) []closedOrder {
+	closed := make([]closedOrder, 0)
+	for _, change := range changes {
+		if change.Tpe.IsOpen() {
+			continue
+		}
+		if info := getInfo(change.Id); info != nil {
+			closed = append(closed, closedOrder{info, change.Tpe})
+		}
+	}
+	isFilled := make(map[string]bool)
+	markFilled := func(id string) {
+		if info := getInfo(id); info != nil && info.CumQty == info.Quantity && !isFilled[id] {
+			isFilled[id] = true
+			closed = append(closed, closedOrder{info, FullyFill})
+		}
+	}
+	for _, symbolTrades := range trades {
+		for _, t := range symbolTrades {
+			markFilled(t.Bid)
+			markFilled(t.Sid)
+		}
+	}
+	return closed
+}
+
+// update records the trades and the closed orders of the block at height
+func (h *OrderHistory) update(height, timestamp int64, trades map[string][]me.Trade, changes OrderChanges, getInfo func(id string) *OrderInfo) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	for symbol, symbolTrades := range trades {
+		for i, t := range symbolTrades {
+			trade := store.Trade{
+				Id:            fmt.Sprintf("%d-%d", height, i),
+				Symbol:        symbol,
+				Price:         utils.Fixed8(t.LastPx),
+				Quantity:      utils.Fixed8(t.LastQty),
+				BuyerOrderId:  t.Bid,
+				SellerOrderId: t.Sid,
+				TickType:      t.TickType,
+				Height:        height,
+				Timestamp:     timestamp,
+			}
+			if info := getInfo(t.Bid); info != nil {
+				trade.Buyer = info.Sender.String()
+			}
+			if info := getInfo(t.Sid); info != nil {
+				trade.Seller = info.Sender.String()
+			}
+			h.trades[symbol] = append(h.trades[symbol], trade)
+		}
+	}
+	for symbol := range h.trades {
+		h.pruneTrades(symbol, height)
+	}
+
+	for _, closed := range closedOrdersOfBlock(trades, changes, getInfo) {
+		h.addClosedOrder(closed.info, closed.status, height, timestamp)
+	}
+}
+
+func (h *OrderHistory) addClosedOrder(info *OrderInfo, status ChangeType, height, timestamp int64) {
+	addr := info.Sender.String()
+	elem, ok := h.closedOrders[addr]
+	if ok {
+		h.accounts.MoveToBack(elem)
+	} else {
+		elem = h.accounts.PushBack(&accountClosedOrders{addr: addr})
+		h.closedOrders[addr] = elem
+	}
+	account := elem.Value.(*accountClosedOrders)
+	account.orders = append(account.orders, store.ClosedOrder{
+		Id:               info.Id,
+		Symbol:           info.Symbol,
+		Side:             info.Side,
+		OrderType:        info.OrderType,
+		Price:            utils.Fixed8(info.Price),
+		Quantity:         utils.Fixed8(info.Quantity),
+		CumQty:           utils.Fixed8(info.CumQty),
+		Status:           status.String(),
+		CreatedHeight:    info.CreatedHeight,
+		CreatedTimestamp: info.CreatedTimestamp,
+		ClosedHeight:     height,
+		ClosedTimestamp:  timestamp,
+	})
+	h.numClosedOrders++
+	if len(account.orders) > h.maxClosedOrdersPerAccount {
+		h.numClosedOrders -= len(account.orders) - h.maxClosedOrdersPerAccount
+		account.orders = append([]store.ClosedOrder(nil), account.orders[len(account.orders)-h.maxClosedOrdersPerAccount:]...)
+	}
+
+	// evict the oldest orders of the accounts whose orders closed least recently
+	for h.numClosedOrders > h.maxClosedOrders {
+		front := h.accounts.Front()
+		lru := front.Value.(*accountClosedOrders)
+		if excess := h.numClosedOrders - h.maxClosedOrders; excess < len(lru.orders) {
+			lru.orders = append([]store.ClosedOrder(nil), lru.orders[excess:]...)
+			h.numClosedOrders -= excess
+		} else {
+			h.numClosedOrders -= len(lru.orders)
+			h.accounts.Remove(front)
+			delete(h.closedOrders, lru.addr)
+		}
+	}
+}
+
+func (h *OrderHistory) pruneTrades(symbol string, height int64) {
+	trades := h.trades[symbol]
+	start := 0
+	for start < len(trades) && trades[start].Height <= height-h.tradeBlocks {
+		start++
+	}
+	if len(trades)-start > h.maxTradesPerPair {
+		start = len(trades) - h.maxTradesPerPair
+	}
+	if start == len(trades) {
+		delete(h.trades, symbol)
+	} else if start > 0 {
+		h.trades[symbol] = append([]store.Trade(nil), trades[start:]...)
+	}
+}
+
+// ClosedOrders returns the recent closed orders of the address, the latest first
+func (h *OrderHistory) ClosedOrders(addr string) []store.ClosedOrder {
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+	var orders []store.ClosedOrder
+	if elem, ok := h.closedOrders[addr]; ok {
+		orders = elem.Value.(*accountClosedOrders).orders
+	}
+	res := make([]store.ClosedOrder, 0, len(orders))
+	for i := len(orders) - 1; i >= 0; i-- {
+		res = append(res, orders[i])
+	}
+	return res
+}
+
+// Trades returns the recent trades of the pair since fromHeight, the latest first
+func (h *OrderHistory) Trades(symbol string, fromHeight int64) []store.Trade {
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+	trades := h.trades[symbol]
+	res := make([]store.Trade, 0)
+	for i := len(trades) - 1; i >= 0 && trades[i].Height >= fromHeight; i-- {
+		res = append(res, trades[i])
+	}
+	return res
+}
@@ -0,0 +1,106 @@
+package order
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+)
+
+func TestOrderHistory_Update(t *testing.T) {
+	_, buyer := testutils.PrivAndAddr()
+	_, seller := testutils.PrivAndAddr()
+	buy := &OrderInfo{NewOrderMsg: NewNewOrderMsg(buyer, "b-1", Side.BUY, "XYZ-000_BNB", 1e8, 2e8), CumQty: 2e8}
+	sell := &OrderInfo{NewOrderMsg: NewNewOrderMsg(seller, "s-1", Side.SELL, "XYZ-000_BNB", 1e8, 3e8), CumQty: 2e8}
+	canceled := &OrderInfo{NewOrderMsg: NewNewOrderMsg(buyer, "b-2", Side.BUY, "XYZ-000_BNB", 1e8, 1e8)}
+	infos := OrderInfoForPublish{"b-1": buy, "s-1": sell, "b-2": canceled}
+	getInfo := func(id string) *OrderInfo { return infos[id] }
+
+	history := NewOrderHistory(10, 2, 10, 3)
+	trades := map[string][]me.Trade{
+		"XYZ-000_BNB": {
+			{Bid: "b-1", Sid: "s-1", LastPx: 1e8, LastQty: 1e8},
+			{Bid: "b-1", Sid: "s-1", LastPx: 1e8, LastQty: 1e8},
+		},
+	}
+	changes := OrderChanges{{Id: "b-2", Tpe: Canceled}, {Id: "s-1", Tpe: PartialFill}}
+	history.update(100, 1000, trades, changes, getInfo)
+
+	// the partially filled sell order is still open
+	require.Len(t, history.ClosedOrders(seller.String()), 0)
+	closed := history.ClosedOrders(buyer.String())
+	require.Len(t, closed, 2)
+	require.Equal(t, "b-1", closed[0].Id)
+	require.Equal(t, FullyFill.String(), closed[0].Status)
+	require.Equal(t, "b-2", closed[1].Id)
+	require.Equal(t, Canceled.String(), closed[1].Status)
+	require.Equal(t, int64(100), closed[1].ClosedHeight)
+
+	recent := history.Trades("XYZ-000_BNB", 0)
+	require.Len(t, recent, 2)
+	require.Equal(t, "100-1", recent[0].Id)
+	require.Equal(t, buyer.String(), recent[0].Buyer)
+	require.Equal(t, seller.String(), recent[0].Seller)
+	require.Equal(t, utils.Fixed8(1e8), recent[0].Quantity)
+
+	// only the latest closed orders of each account are kept
+	sell.CumQty = 3e8
+	trades = map[string][]me.Trade{"XYZ-000_BNB": {{Bid: "b-3", Sid: "s-1", LastPx: 1e8, LastQty: 1e8}}}
+	history.update(105, 1050, trades, OrderChanges{}, getInfo)
+	require.Len(t, history.ClosedOrders(seller.String()), 1)
+	require.Len(t, history.ClosedOrders(buyer.String()), 2)
+	history.update(106, 1060, nil, OrderChanges{{Id: "b-2", Tpe: Expired}}, getInfo)
+	closed = history.ClosedOrders(buyer.String())
+	require.Len(t, closed, 2)
+	require.Equal(t, Expired.String(), closed[0].Status)
+	require.Equal(t, "b-1", closed[1].Id)
+
+	// trades are pruned by the number of blocks and the number of trades
+	require.Len(t, history.Trades("XYZ-000_BNB", 0), 3)
+	require.Len(t, history.Trades("XYZ-000_BNB", 101), 1)
+	trades = map[string][]me.Trade{"XYZ-000_BNB": {{Bid: "b-3", Sid: "s-3", LastPx: 1e8, LastQty: 1e8}}}
+	history.update(110, 1100, trades, OrderChanges{}, getInfo)
+	recent = history.Trades("XYZ-000_BNB", 0)
+	require.Len(t, recent, 2)
+	require.Equal(t, int64(110), recent[0].Height)
+	require.Equal(t, int64(105), recent[1].Height)
+	history.update(120, 1200, nil, OrderChanges{}, getInfo)
+	require.Len(t, history.Trades("XYZ-000_BNB", 0), 0)
+}
+
+func TestOrderHistory_MaxClosedOrders(t *testing.T) {
+	accounts := make([]sdk.AccAddress, 3)
+	infos := make(OrderInfoForPublish)
+	for i := range accounts {
+		_, accounts[i] = testutils.PrivAndAddr()
+		for j := 0; j < 2; j++ {
+			id := fmt.Sprintf("%d-%d", i, j)
+			infos[id] = &OrderInfo{NewOrderMsg: NewNewOrderMsg(accounts[i], id, Side.BUY, "XYZ-000_BNB", 1e8, 1e8)}
+		}
+	}
+	getInfo := func(id string) *OrderInfo { return infos[id] }
+
+	history := NewOrderHistory(3, 2, 10, 3)
+	history.update(100, 1000, nil, OrderChanges{{Id: "0-0", Tpe: Canceled}, {Id: "1-0", Tpe: Canceled}}, getInfo)
+	history.update(101, 1010, nil, OrderChanges{{Id: "0-1", Tpe: Canceled}}, getInfo)
+	require.Len(t, history.ClosedOrders(accounts[0].String()), 2)
+	require.Len(t, history.ClosedOrders(accounts[1].String()), 1)
+
+	// the account whose orders closed least recently is evicted first
+	history.update(102, 1020, nil, OrderChanges{{Id: "2-0", Tpe: Canceled}}, getInfo)
+	require.Len(t, history.ClosedOrders(accounts[0].String()), 2)
+	require.Len(t, history.ClosedOrders(accounts[1].String()), 0)
+	require.Len(t, history.ClosedOrders(accounts[2].String()), 1)
+
+	// only the oldest orders of the least recent account are evicted if it's enough
+	history.update(103, 1030, nil, OrderChanges{{Id: "2-1", Tpe: Canceled}}, getInfo)
+	closed := history.ClosedOrders(accounts[0].String())
+	require.Len(t, closed, 1)
+	require.Equal(t, "0-1", closed[0].Id)
+	require.Len(t, history.ClosedOrders(accounts[2].String()), 2)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -59,11 +60,17 @@ type DexKeeper struct {
 	RoundOrderFees             FeeHolder // order (and trade) related fee of this round, str of addr bytes -> fee
 	CollectOrderInfoForPublish bool      //TODO separate for each order keeper
 	engines                    map[string]*me.MatchEng
+	triggerOrders              map[string]map[string]*OrderInfo // symbol -> order ID -> conditional order waiting to be triggered
 	pairsType                  map[string]SymbolPairType
 	logger                     tmlog.Logger
 	poolSize                   uint // number of concurrent channels, counted in the pow of 2
 	cdc                        *wire.Codec
 	OrderKeepers               []DexOrderKeeper
+	walDir                     string           // empty if the order book WAL is disabled
+	wal                        *orderbookWAL    // nil until the order book is recovered
+	history                    *OrderHistory    // nil if the order history is disabled
+	klines                     *KlineAggregator // nil if the klines are disabled
+	tickers                    *TickerStats     // nil if the 24h tickers are disabled
 }
 
 func NewDexKeeper(key sdk.StoreKey, am auth.AccountKeeper, tradingPairMapper store.TradingPairMapper, codespace sdk.CodespaceType, concurrency uint, cdc *wire.Codec, collectOrderInfoForPublish bool) *DexKeeper {
@@ -84,6 +91,7 @@ func NewDexKeeper(key sdk.StoreKey, am auth.AccountKeeper, tradingPairMapper sto
 		FeeManager:                 NewFeeManager(cdc, logger),
 		CollectOrderInfoForPublish: collectOrderInfoForPublish,
 		engines:                    make(map[string]*me.MatchEng),
+		triggerOrders:              make(map[string]map[string]*OrderInfo),
 		pairsType:                  make(map[string]SymbolPairType),
 		poolSize:                   concurrency,
 		cdc:                        cdc,
@@ -97,6 +105,92 @@ func (kp *DexKeeper) Init(ctx sdk.Context, blockInterval, daysBack int, blockSto
 	kp.InitRecentPrices(ctx)
 }
 
+// EnableOrderBookWAL makes the keeper write order book changes to the WAL under walDir,
+// so the order book can be recovered from the snapshot and WAL instead of replaying blocks.
+// Must be called before Init.
+func (kp *DexKeeper) EnableOrderBookWAL(walDir string) {
+	kp.walDir = walDir
+}
+
+// EnableOrderHistory keeps the recent closed orders and trades in the history. The order changes are
+// collected as for publication, as the history is fed from them.
+func (kp *DexKeeper) EnableOrderHistory(history *OrderHistory) {
+	kp.history = history
+	kp.CollectOrderInfoForPublish = true
+	for _, orderKeeper := range kp.OrderKeepers {
+		orderKeeper.enablePublish()
+	}
+}
+
+// GetOrderHistory returns nil if the order history is disabled
+func (kp *DexKeeper) GetOrderHistory() *OrderHistory {
+	return kp.history
+}
+
+// EnableKlines aggregates the trades of each match round into klines
+func (kp *DexKeeper) EnableKlines(klines *KlineAggregator) {
+	kp.klines = klines
+}
+
+// GetKlineAggregator returns nil if the klines are disabled
+func (kp *DexKeeper) GetKlineAggregator() *KlineAggregator {
+	return kp.klines
+}
+
+// EnableTickers keeps the rolling 24h statistics of the trades of each match round
+func (kp *DexKeeper) EnableTickers(tickers *TickerStats) {
+	kp.tickers = tickers
+}
+
+// UpdateTickers slides the 24h window of the tickers to the block time. It's called for every block, even if
+// there is no trade.
+func (kp *DexKeeper) UpdateTickers(timestamp int64) {
+	if kp.tickers == nil {
+		return
+	}
+	kp.tickers.advance(timestamp)
+}
+
+// GetTickerStats returns nil if the 24h tickers are disabled
+func (kp *DexKeeper) GetTickerStats() *TickerStats {
+	return kp.tickers
+}
+
+// GetTickers returns the 24h tickers with the best bid and ask of the pair, or of all the mini or the
+// non-mini pairs if pair is empty, sorted by symbol
+func (kp *DexKeeper) GetTickers(pair string, mini bool) []store.Ticker {
+	tickers := make([]store.Ticker, 0)
+	if kp.tickers == nil {
+		return tickers
+	}
+	symbols := make([]string, 0)
+	if pair != "" {
+		if _, ok := kp.engines[pair]; ok {
+			symbols = append(symbols, pair)
+		}
+	} else {
+		for symbol := range kp.engines {
+			if dexUtils.IsMiniTokenTradingPair(symbol) == mini {
+				symbols = append(symbols, symbol)
+			}
+		}
+		sort.Strings(symbols)
+	}
+	for _, symbol := range symbols {
+		eng := kp.engines[symbol]
+		ticker := kp.tickers.Ticker(symbol, eng.LastTradePrice)
+		eng.Book.ShowDepth(1, func(p *me.PriceLevel, levelIndex int) {
+			ticker.BidPrice = utils.Fixed8(p.Price)
+			ticker.BidQty = utils.Fixed8(p.TotalLeavesQty())
+		}, func(p *me.PriceLevel, levelIndex int) {
+			ticker.AskPrice = utils.Fixed8(p.Price)
+			ticker.AskQty = utils.Fixed8(p.TotalLeavesQty())
+		})
+		tickers = append(tickers, ticker)
+	}
+	return tickers
+}
+
 func (kp *DexKeeper) InitRecentPrices(ctx sdk.Context) {
 	kp.recentPrices = kp.PairMapper.GetRecentPrices(ctx, pricesStoreEvery, numPricesStored)
 }
@@ -262,6 +356,9 @@ func (kp *DexKeeper) AddEngine(pair dexTypes.TradingPair) *me.MatchEng {
 	symbol := strings.ToUpper(pair.GetSymbol())
 	eng := CreateMatchEng(symbol, pair.ListPrice.ToInt64(), pair.LotSize.ToInt64())
 	kp.engines[symbol] = eng
+	if _, ok := kp.triggerOrders[symbol]; !ok {
+		kp.triggerOrders[symbol] = make(map[string]*OrderInfo)
+	}
 	pairType := PairType.BEP2
 	if dexUtils.IsMiniTokenTradingPair(symbol) {
 		pairType = PairType.MINI
@@ -273,6 +370,7 @@ func (kp *DexKeeper) AddEngine(pair dexTypes.TradingPair) *me.MatchEng {
 			break
 		}
 	}
+	kp.wal.Write(PairListedWALMessage{Pair: pair})
 	return eng
 }
 
@@ -285,12 +383,20 @@ func (kp *DexKeeper) AddOrder(info OrderInfo, isRecovery bool) (err error) {
 		return
 	}
 
-	_, err = eng.Book.InsertOrder(info.Id, info.Side, info.CreatedHeight, info.Price, info.Quantity)
+	if info.OrderType == OrderType.MARKET {
+		// market orders are placed into the order book when they are matched
+		err = eng.AddMarketOrder(info.Id, info.Side, info.CreatedHeight, info.Quantity)
+	} else {
+		_, err = eng.Book.InsertOrder(info.Id, info.Side, info.CreatedHeight, info.Price, info.Quantity)
+	}
 	if err != nil {
 		return err
 	}
 
 	kp.mustGetOrderKeeper(symbol).addOrder(symbol, info, isRecovery)
+	if !isRecovery {
+		kp.wal.Write(OrderAddedWALMessage{Order: info})
+	}
 	kp.logger.Debug("Added orders", "symbol", symbol, "id", info.Id)
 	return nil
 }
@@ -306,6 +412,7 @@ func (kp *DexKeeper) RemoveOrder(id string, symbol string, postCancelHandler fun
 		if err != nil {
 			return err
 		}
+		kp.wal.Write(OrderRemovedWALMessage{Symbol: symbol, Id: id})
 		if postCancelHandler != nil {
 			postCancelHandler(ord)
 		}
@@ -314,6 +421,54 @@ func (kp *DexKeeper) RemoveOrder(id string, symbol string, postCancelHandler fun
 	return orderNotFound(symbol, id)
 }
 
+// AmendOrder changes the price and quantity of the open order. If only the quantity is reduced, the order keeps
+// its priority in the price level. Otherwise it's moved to the back of the new price level as an order placed at height.
+func (kp *DexKeeper) AmendOrder(symbol, id string, price, qty, height, timestamp int64, isRecovery bool) (OrderInfo, error) {
+	symbol = strings.ToUpper(symbol)
+	info, ok := kp.OrderExists(symbol, id)
+	if !ok {
+		return OrderInfo{}, orderNotFound(symbol, id)
+	}
+	if info.OrderType == OrderType.MARKET {
+		return OrderInfo{}, fmt.Errorf("market order [%v] can not be amended", id)
+	}
+	eng, ok := kp.engines[symbol]
+	if !ok {
+		return OrderInfo{}, orderNotFound(symbol, id)
+	}
+	ord, err := eng.Book.GetOrder(id, info.Side, info.Price)
+	if err != nil {
+		return OrderInfo{}, err
+	}
+	if qty <= ord.CumQty {
+		return OrderInfo{}, fmt.Errorf("quantity(%d) should be larger than the filled quantity(%d)", qty, ord.CumQty)
+	}
+
+	requeued := price != info.Price || qty > info.Quantity
+	if requeued {
+		if _, err = eng.Book.RemoveOrder(id, info.Side, info.Price); err != nil {
+			return OrderInfo{}, err
+		}
+		if _, err = eng.Book.InsertOrder(id, info.Side, height, price, qty); err != nil {
+			return OrderInfo{}, err
+		}
+	}
+	if _, err = eng.Book.UpdateOrder(id, info.Side, price, qty, ord.CumQty); err != nil {
+		return OrderInfo{}, err
+	}
+
+	info.Price = price
+	info.Quantity = qty
+	info.LastUpdatedHeight = height
+	info.LastUpdatedTimestamp = timestamp
+	kp.mustGetOrderKeeper(symbol).amendOrder(symbol, info, requeued)
+	if !isRecovery {
+		kp.wal.Write(OrderAmendedWALMessage{Symbol: symbol, Id: id, Price: price, Quantity: qty, Height: height, Timestamp: timestamp})
+	}
+	kp.logger.Debug("Amended order", "symbol", symbol, "id", id, "price", price, "qty", qty, "requeued", requeued)
+	return info, nil
+}
+
 func (kp *DexKeeper) GetOrder(id string, symbol string, side int8, price int64) (ord me.OrderPart, err error) {
 	symbol = strings.ToUpper(symbol)
 	_, ok := kp.OrderExists(symbol, id)
@@ -324,7 +479,7 @@ func (kp *DexKeeper) GetOrder(id string, symbol string, side int8, price int64)
 	if !ok {
 		return me.OrderPart{}, orderNotFound(symbol, id)
 	}
-	return eng.Book.GetOrder(id, side, price)
+	return eng.GetOrder(id, side, price)
 }
 
 func (kp *DexKeeper) OrderExists(symbol, id string) (OrderInfo, bool) {
@@ -435,6 +590,44 @@ func (kp *DexKeeper) GetOrderBooks(maxLevels int) ChangedPriceLevelsMap {
 	return res
 }
 
+// AddTouchedPriceLevels adds the price levels touched by the order changes and the trades of the block at height
+// into levels, so the changes of the levels deeper than the ones collected are published with their quantities
+func (kp *DexKeeper) AddTouchedPriceLevels(levels ChangedPriceLevelsMap, height int64) {
+	touch := func(info *OrderInfo, price int64) {
+		pls, ok := levels[info.Symbol]
+		if !ok {
+			return
+		}
+		side := pls.Buys
+		if info.Side == Side.SELL {
+			side = pls.Sells
+		}
+		if _, ok := side[price]; ok {
+			return
+		}
+		if pl := kp.GetPriceLevel(info.Symbol, info.Side, price); pl != nil {
+			side[price] = pl.TotalLeavesQty()
+		}
+	}
+	for _, change := range kp.GetAllOrderChanges() {
+		if info := kp.getOrderInfoForPub(change.Id); info != nil {
+			touch(info, info.Price)
+			if change.Amendment != nil {
+				touch(info, change.Amendment.PrevPrice)
+			}
+		}
+	}
+	for _, trades := range kp.getAllLastTrades(height) {
+		for _, t := range trades {
+			for _, id := range []string{t.Bid, t.Sid} {
+				if info := kp.getOrderInfoForPub(id); info != nil {
+					touch(info, info.Price)
+				}
+			}
+		}
+	}
+}
+
 func (kp *DexKeeper) GetPriceLevel(pair string, side int8, price int64) *me.PriceLevel {
 	if eng, ok := kp.engines[pair]; ok {
 		return eng.Book.GetPriceLevel(price, side)
@@ -501,12 +694,14 @@ func (kp *DexKeeper) doTransfer(ctx sdk.Context, tran *Transfer) sdk.Error {
 	return nil
 }
 
-func (kp *DexKeeper) ClearAfterMatch() {
+func (kp *DexKeeper) ClearAfterMatch(height int64) {
 	for _, orderKeeper := range kp.OrderKeepers {
 		if orderKeeper.supportUpgradeVersion() {
 			orderKeeper.clearAfterMatch()
 		}
 	}
+	// all the order book changes of this height are written, make sure they are on disk before commit
+	kp.wal.WriteSync(EndHeightWALMessage{Height: height})
 }
 
 func (kp *DexKeeper) StoreTradePrices(ctx sdk.Context) {
@@ -776,6 +971,9 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 				orders := allOrders[symbol]
 				expire(orders, engine, me.BUYSIDE)
 				expire(orders, engine, me.SELLSIDE)
+				kp.expireTriggerOrders(symbol, expireHeight, func(tran Transfer) {
+					transferChs[channelHash(tran.accAddress, concurrency)] <- tran
+				})
 			}
 		}, func() {
 			for _, transferCh := range transferChs {
@@ -945,6 +1143,7 @@ func (kp *DexKeeper) DelistTradingPair(ctx sdk.Context, symbol string, postAlloc
 	}
 
 	delete(kp.engines, symbol)
+	delete(kp.triggerOrders, symbol)
 	kp.deleteRecentPrices(ctx, symbol)
 	kp.mustGetOrderKeeper(symbol).deleteOrdersForPair(symbol)
 
@@ -966,7 +1165,7 @@ func (kp *DexKeeper) expireAllOrders(ctx sdk.Context, symbol string) []chan Tran
 		ordersOfSymbol = dexOrderKeeper.getAllOrdersForPair(symbol)
 	}
 
-	orderNum := len(ordersOfSymbol)
+	orderNum := len(ordersOfSymbol) + len(kp.triggerOrders[symbol])
 	if orderNum == 0 {
 		kp.logger.Info("no orders to expire", "symbol", symbol)
 		return nil
@@ -980,23 +1179,24 @@ func (kp *DexKeeper) expireAllOrders(ctx sdk.Context, symbol string) []chan Tran
 		transferChs[i] = make(chan Transfer, channelSize)
 	}
 
-	expire := func(orders map[string]*OrderInfo, engine *me.MatchEng, side int8) {
-		_ = engine.Book.RemoveOrders(math.MaxInt64, side, func(ord me.OrderPart) {
-			// gen transfer
-			if ordMsg, ok := orders[ord.Id]; ok && ordMsg != nil {
-				h := channelHash(ordMsg.Sender, concurrency)
-				transferChs[h] <- TransferFromExpired(ord, *ordMsg)
-			} else {
-				kp.logger.Error("failed to locate order to remove in order book", "oid", ord.Id)
-			}
-		})
+	removeCallback := func(ord me.OrderPart) {
+		// gen transfer
+		if ordMsg, ok := ordersOfSymbol[ord.Id]; ok && ordMsg != nil {
+			h := channelHash(ordMsg.Sender, concurrency)
+			transferChs[h] <- TransferFromExpired(ord, *ordMsg)
+		} else {
+			kp.logger.Error("failed to locate order to remove in order book", "oid", ord.Id)
+		}
 	}
 
 	go func() {
 		engine := kp.engines[symbol]
-		orders := ordersOfSymbol
-		expire(orders, engine, me.BUYSIDE)
-		expire(orders, engine, me.SELLSIDE)
+		_ = engine.Book.RemoveOrders(math.MaxInt64, me.BUYSIDE, removeCallback)
+		_ = engine.Book.RemoveOrders(math.MaxInt64, me.SELLSIDE, removeCallback)
+		engine.RemoveMarketOrders(removeCallback)
+		kp.expireTriggerOrders(symbol, math.MaxInt64, func(tran Transfer) {
+			transferChs[channelHash(tran.accAddress, concurrency)] <- tran
+		})
 
 		for _, transferCh := range transferChs {
 			close(transferCh)
@@ -1081,6 +1281,55 @@ func (kp *DexKeeper) GetAllOrderChanges() OrderChanges {
 	return res
 }
 
+// UpdateKlines aggregates the trades of the block into the klines. It's called for every block, even if there
+// is no trade, so the klines are closed once their intervals are passed.
+func (kp *DexKeeper) UpdateKlines(height, timestamp int64) {
+	if kp.klines == nil {
+		return
+	}
+	kp.klines.update(timestamp, kp.getAllLastTrades(height))
+}
+
+// UpdateOrderHistory records the trades and the closed orders of the block into the order history.
+// It should be called after the block is matched and before the order changes are cleared.
+func (kp *DexKeeper) UpdateOrderHistory(height, timestamp int64) {
+	if kp.history == nil {
+		return
+	}
+	kp.history.update(height, timestamp, kp.getAllLastTrades(height), kp.GetAllOrderChanges(), kp.getOrderInfoForPub)
+}
+
+// RemoveClosedOrderInfosForPub removes the infos of the orders closed in the block at height. It's done by the
+// publisher after the orders are published, otherwise it should be done after the order history is updated.
+func (kp *DexKeeper) RemoveClosedOrderInfosForPub(height int64) {
+	for _, closed := range closedOrdersOfBlock(kp.getAllLastTrades(height), kp.GetAllOrderChanges(), kp.getOrderInfoForPub) {
+		kp.RemoveOrderInfosForPub(closed.info.Symbol, closed.info.Id)
+	}
+}
+
+// getAllLastTrades returns the trades of every symbol matched in the block at height
+func (kp *DexKeeper) getAllLastTrades(height int64) map[string][]me.Trade {
+	trades := make(map[string][]me.Trade)
+	for symbol := range kp.engines {
+		if symbolTrades, _ := kp.GetLastTrades(height, symbol); len(symbolTrades) > 0 {
+			trades[symbol] = symbolTrades
+		}
+	}
+	return trades
+}
+
+func (kp *DexKeeper) getOrderInfoForPub(id string) *OrderInfo {
+	for _, orderKeeper := range kp.OrderKeepers {
+		if !orderKeeper.supportUpgradeVersion() {
+			continue
+		}
+		if info, ok := orderKeeper.getOrderInfosForPub()[id]; ok {
+			return info
+		}
+	}
+	return nil
+}
+
 func (kp *DexKeeper) UpdateOrderChangeSync(change OrderChange, symbol string) {
 	if dexOrderKeeper, err := kp.getOrderKeeper(symbol); err == nil {
 		dexOrderKeeper.appendOrderChangeSync(change)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
 )
 
 func (kp *DexKeeper) SelectSymbolsToMatch(height int64, matchAllSymbols bool) []string {
@@ -31,6 +32,7 @@ func (kp *DexKeeper) MatchAndAllocateSymbols(ctx sdk.Context, postAlloTransHandl
 	blockHeader := ctx.BlockHeader()
 	timestamp := blockHeader.Time.UnixNano()
 
+	kp.TriggerOrders(blockHeader.Height, timestamp, false)
 	symbolsToMatch := kp.SelectSymbolsToMatch(blockHeader.Height, matchAllSymbols)
 
 	kp.logger.Info("symbols to match", "symbols", symbolsToMatch)
@@ -43,7 +45,21 @@ func (kp *DexKeeper) MatchAndAllocateSymbols(ctx sdk.Context, postAlloTransHandl
 
 	totalFee := kp.allocateAndCalcFee(ctx, tradeOuts, postAlloTransHandler)
 	fees.Pool.AddAndCommitFee("MATCH", totalFee)
-	kp.ClearAfterMatch()
+	kp.writeMatchResultToWAL(blockHeader.Height, timestamp, matchAllSymbols, symbolsToMatch)
+	kp.ClearAfterMatch(blockHeader.Height)
+}
+
+func (kp *DexKeeper) writeMatchResultToWAL(height, timestamp int64, matchAllSymbols bool, symbolsToMatch []string) {
+	if kp.wal == nil {
+		return
+	}
+	results := make([]SymbolMatchResult, 0, len(symbolsToMatch))
+	for _, symbol := range symbolsToMatch {
+		if eng, ok := kp.engines[symbol]; ok && eng.LastMatchHeight == height {
+			results = append(results, SymbolMatchResult{symbol, eng.LastTradePrice, int64(len(eng.Trades))})
+		}
+	}
+	kp.wal.Write(MatchResultWALMessage{height, timestamp, matchAllSymbols, results})
 }
 
 // please note if distributeTrade this method will work in async mode, otherwise in sync mode.
@@ -91,6 +107,7 @@ func (kp *DexKeeper) matchAndDistributeTrades(distributeTrade bool, height, time
 }
 
 func (kp *DexKeeper) MatchSymbols(height, timestamp int64, matchAllSymbols bool) {
+	kp.TriggerOrders(height, timestamp, true)
 	symbolsToMatch := kp.SelectSymbolsToMatch(height, matchAllSymbols)
 	kp.logger.Debug("symbols to match", "symbols", symbolsToMatch)
 
@@ -100,7 +117,7 @@ func (kp *DexKeeper) MatchSymbols(height, timestamp int64, matchAllSymbols bool)
 		kp.matchAndDistributeTrades(false, height, timestamp, symbolsToMatch)
 	}
 
-	kp.ClearAfterMatch()
+	kp.ClearAfterMatch(height)
 }
 
 func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, timestamp int64, distributeTrade bool,
@@ -109,10 +126,14 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 	concurrency := len(tradeOuts)
 	orderKeeper := kp.mustGetOrderKeeper(symbol)
 	orders := orderKeeper.getAllOrdersForPair(symbol)
+	kp.dropUnqualifiedRoundOrders(symbol, distributeTrade, tradeOuts)
 	// please note there is no logging in matching, expecting to see the order book details
 	// from the exchange's order book stream.
 	if engine.Match(height) {
 		kp.logger.Debug("Match finish:", "symbol", symbol, "lastTradePrice", engine.LastTradePrice)
+		if kp.tickers != nil {
+			kp.tickers.addTrades(symbol, timestamp, engine.Trades)
+		}
 		for i := range engine.Trades {
 			t := &engine.Trades[i]
 			updateOrderMsg(orders[t.Bid], t.BuyCumQty, height, timestamp)
@@ -142,7 +163,7 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 		for _, id := range thisRoundIds {
 			msg := orders[id]
 			delete(orders, id)
-			if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
+			if ord, err := engine.RemoveOrder(id, msg.Side, msg.Price); err == nil {
 				kp.logger.Info("Removed due to match failure", "ordID", msg.Id)
 				if distributeTrade {
 					c := channelHash(msg.Sender, concurrency)
@@ -155,7 +176,7 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 			// let the order status publisher publish these abnormal
 			// order status change outs.
 			if kp.CollectOrderInfoForPublish {
-				orderKeeper.appendOrderChangeSync(OrderChange{id, FailedMatching, "", nil})
+				orderKeeper.appendOrderChangeSync(OrderChange{id, FailedMatching, "", nil, nil})
 			}
 		}
 		return // no need to handle IOC
@@ -164,7 +185,7 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 	for _, id := range iocIDs {
 		if msg, ok := orders[id]; ok {
 			delete(orders, id)
-			if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
+			if ord, err := engine.RemoveOrder(id, msg.Side, msg.Price); err == nil {
 				kp.logger.Debug("Removed unclosed IOC order", "ordID", msg.Id)
 				if distributeTrade {
 					c := channelHash(msg.Sender, concurrency)
@@ -177,6 +198,86 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 	}
 }
 
+// dropUnqualifiedRoundOrders removes the new orders of this round that cannot be matched as they request
+// before matching: a post-only order is canceled if it would be filled as a taker, and a fill-or-kill
+// order expires if it would not be fully filled at the concluded price. Removing orders may change
+// the concluded price and the allocation among takers, so the check is repeated after the removal.
+// All the failed post-only orders are removed at once, while fill-or-kill orders are removed one
+// by one in placement order, as the removal of one may let the others be fully filled.
+func (kp *DexKeeper) dropUnqualifiedRoundOrders(symbol string, distributeTrade bool, tradeOuts []chan Transfer) {
+	engine := kp.engines[symbol]
+	orderKeeper := kp.mustGetOrderKeeper(symbol)
+	orders := orderKeeper.getAllOrdersForPair(symbol)
+	candidates := make([]string, 0)
+	for _, id := range orderKeeper.getRoundOrdersForPair(symbol) {
+		if msg, ok := orders[id]; ok && (msg.PostOnly || msg.TimeInForce == TimeInForce.FOK) {
+			candidates = append(candidates, id)
+		}
+	}
+
+	for len(candidates) > 0 {
+		executions, ok := engine.PreviewMatch()
+		if !ok {
+			return // match the orders as they are
+		}
+		executionById := make(map[string]me.OrderExecution, len(executions))
+		for _, execution := range executions {
+			executionById[execution.Id] = execution
+		}
+
+		remaining := make([]string, 0, len(candidates))
+		postOnlyDropped := false
+		var toKill *OrderInfo
+		for _, id := range candidates {
+			msg, ok := orders[id]
+			if !ok {
+				continue // killed in the last check
+			}
+			execution := executionById[id]
+			if msg.PostOnly && execution.IsTaker && execution.Qty > 0 {
+				kp.dropRoundOrder(symbol, msg, PostOnlyCanceled, distributeTrade, tradeOuts)
+				postOnlyDropped = true
+				continue
+			}
+			if toKill == nil && msg.TimeInForce == TimeInForce.FOK && execution.Qty < msg.Quantity-msg.CumQty {
+				toKill = msg
+			}
+			remaining = append(remaining, id)
+		}
+		// fill-or-kill orders are checked again once the post-only orders are removed
+		if !postOnlyDropped {
+			if toKill == nil {
+				return
+			}
+			kp.dropRoundOrder(symbol, toKill, IocNoFill, distributeTrade, tradeOuts)
+		}
+		candidates = remaining
+	}
+}
+
+func (kp *DexKeeper) dropRoundOrder(symbol string, msg *OrderInfo, changeType ChangeType, distributeTrade bool,
+	tradeOuts []chan Transfer) {
+	orderKeeper := kp.mustGetOrderKeeper(symbol)
+	delete(orderKeeper.getAllOrdersForPair(symbol), msg.Id)
+	ord, err := kp.engines[symbol].RemoveOrder(msg.Id, msg.Side, msg.Price)
+	if err != nil {
+		kp.logger.Error("Failed to remove unqualified order, may be fatal!", "orderID", msg.Id)
+		return
+	}
+	kp.logger.Debug("Removed unqualified order", "ordID", msg.Id, "reason", changeType.String())
+	if changeType == PostOnlyCanceled {
+		if distributeTrade {
+			tradeOuts[channelHash(msg.Sender, len(tradeOuts))] <- TransferFromCanceled(ord, *msg, true)
+		}
+		if kp.CollectOrderInfoForPublish {
+			orderKeeper.appendOrderChangeSync(OrderChange{msg.Id, PostOnlyCanceled, "", nil, nil})
+		}
+	} else if distributeTrade {
+		// the expire change of fill-or-kill order is published with its fee by the transfer handler
+		tradeOuts[channelHash(msg.Sender, len(tradeOuts))] <- TransferFromExpired(ord, *msg)
+	}
+}
+
 // Run as postConsume procedure of async, no concurrent updates of orders map
 func updateOrderMsg(order *OrderInfo, cumQty, height, timestamp int64) {
 	order.CumQty = cumQty
@@ -12,7 +12,6 @@ import (
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	dbm "github.com/tendermint/tendermint/libs/db"
-	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/state"
 	tmstore "github.com/tendermint/tendermint/store"
 	tmtypes "github.com/tendermint/tendermint/types"
@@ -48,6 +47,10 @@ func genActiveOrdersSnapshotKey(height int64) string {
 	return fmt.Sprintf("activeorders_%v", height)
 }
 
+func genTriggerOrdersSnapshotKey(height int64) string {
+	return fmt.Sprintf("triggerorders_%v", height)
+}
+
 func compressAndSave(snapshot interface{}, cdc *wire.Codec, key string, kv sdk.KVStore) error {
 	bytes, err := cdc.MarshalBinaryLengthPrefixed(snapshot)
 	if err != nil {
@@ -99,7 +102,36 @@ func (kp *DexKeeper) SnapShotOrderBook(ctx sdk.Context, height int64) (effectedS
 	key := genActiveOrdersSnapshotKey(height)
 	effectedStoreKeys = append(effectedStoreKeys, key)
 	ctx.Logger().Info("Saving active orders", "height", height)
-	return effectedStoreKeys, compressAndSave(snapshot, kp.cdc, key, kvstore)
+	if err = compressAndSave(snapshot, kp.cdc, key, kvstore); err != nil {
+		return nil, err
+	}
+	// conditional orders are only saved when there are some, so the state is unchanged without them
+	triggerIds := make([]string, 0)
+	for symbol, orderMap := range kp.triggerOrders {
+		for id := range orderMap {
+			idSymbolMap[id] = symbol
+			triggerIds = append(triggerIds, id)
+		}
+	}
+	if len(triggerIds) > 0 {
+		sort.Strings(triggerIds)
+		triggerOrders := make([]OrderInfo, len(triggerIds))
+		for i, id := range triggerIds {
+			triggerOrders[i] = *kp.triggerOrders[idSymbolMap[id]][id]
+		}
+		key := genTriggerOrdersSnapshotKey(height)
+		effectedStoreKeys = append(effectedStoreKeys, key)
+		ctx.Logger().Info("Saving trigger orders", "height", height)
+		if err = compressAndSave(ActiveOrders{Orders: triggerOrders}, kp.cdc, key, kvstore); err != nil {
+			return nil, err
+		}
+	}
+
+	// changes after this breathe block are written to a new WAL file based on this snapshot
+	if err = kp.wal.Rotate(height); err != nil {
+		return nil, err
+	}
+	return effectedStoreKeys, nil
 }
 
 func (kp *DexKeeper) LoadOrderBookSnapshot(ctx sdk.Context, latestBlockHeight int64, timeOfLatestBlock time.Time, blockInterval, daysBack int) (int64, error) {
@@ -166,6 +198,27 @@ func (kp *DexKeeper) LoadOrderBookSnapshot(ctx sdk.Context, latestBlockHeight in
 		}
 		ctx.Logger().Info("Successfully Loaded order snapshot", "pair", pair)
 	}
+	if bz := kvStore.Get([]byte(genTriggerOrdersSnapshotKey(height))); bz != nil {
+		b := bytes.NewBuffer(bz)
+		var bw bytes.Buffer
+		r, err := zlib.NewReader(b)
+		if err != nil {
+			panic(fmt.Sprintf("failed to unzip snapshort for trigger orders at height %d, err: %v", height, err))
+		}
+		_, _ = io.Copy(&bw, r)
+		var to ActiveOrders
+		err = kp.cdc.UnmarshalBinaryLengthPrefixed(bw.Bytes(), &to)
+		if err != nil {
+			panic(fmt.Sprintf("failed to unmarshal snapshort for trigger orders at height %d", height))
+		}
+		for _, m := range to.Orders {
+			if err := kp.AddTriggerOrder(m, true); err != nil {
+				panic(fmt.Sprintf("failed to reload trigger order [%s], err: %v", m.Id, err))
+			}
+		}
+		ctx.Logger().Info("Recovered trigger orders", "count", len(to.Orders))
+	}
+
 	key := genActiveOrdersSnapshotKey(height)
 	bz := kvStore.Get([]byte(key))
 	if bz == nil {
@@ -193,8 +246,9 @@ func (kp *DexKeeper) LoadOrderBookSnapshot(ctx sdk.Context, latestBlockHeight in
 	return height, nil
 }
 
-func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, stateDB dbm.DB, txDecoder sdk.TxDecoder,
+func (kp *DexKeeper) replayOneBlocks(ctx sdk.Context, block *tmtypes.Block, stateDB dbm.DB, txDecoder sdk.TxDecoder,
 	height int64, timestamp time.Time) {
+	logger := ctx.Logger()
 	if block == nil {
 		logger.Error("No block is loaded. Ignore replay for orderbook")
 		return
@@ -218,39 +272,89 @@ func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, st
 			panic(err)
 		}
 		txHash := cmn.HexBytes(tmhash.Sum(txBytes))
+		replayNewOrder := func(msg NewOrderMsg) {
+			var txSource int64
+			upgrade.UpgradeBEP10(nil, func() {
+				if stdTx, ok := tx.(auth.StdTx); ok {
+					txSource = stdTx.GetSource()
+				} else {
+					logger.Error("tx is not an auth.StdTx", "txhash", txHash.String())
+				}
+			})
+			orderInfo := OrderInfo{
+				msg,
+				height, t,
+				height, t,
+				0, txHash.String(), txSource}
+			var err error
+			if IsConditionalOrderType(msg.OrderType) {
+				err = kp.AddTriggerOrder(orderInfo, true)
+			} else {
+				err = kp.AddOrder(orderInfo, true)
+			}
+			if err != nil {
+				logger.Error("Failed to replay NreOrderMsg", "err", err)
+			}
+			logger.Info("Added Order", "order", msg)
+		}
+		replayCancelOrder := func(msg CancelOrderMsg) {
+			remove := kp.RemoveOrder
+			if _, ok := kp.TriggerOrderExists(msg.Symbol, msg.RefId); ok {
+				remove = kp.RemoveTriggerOrder
+			}
+			err := remove(msg.RefId, msg.Symbol, func(ord me.OrderPart) {
+				if kp.CollectOrderInfoForPublish {
+					bnclog.Debug("deleted order from order changes map", "orderId", msg.RefId, "isRecovery", true)
+					kp.RemoveOrderInfosForPub(msg.Symbol, msg.RefId)
+				}
+			})
+			if err != nil {
+				logger.Error("Failed to replay cancel msg", "err", err)
+			}
+			logger.Info("Canceled Order", "order", msg)
+		}
+		// only the items of a batch that succeeded are replayed
+		batchItemOK := func() func(int) bool {
+			resp, err := decodeBatchResponse(abciRes.DeliverTx[idx].Data)
+			if err != nil {
+				panic(fmt.Errorf("failed to decode batch response when replay block at height %d, err %v", height, err))
+			}
+			return func(i int) bool {
+				return i < len(resp.Results) && resp.Results[i].IsOK()
+			}
+		}
+
 		msgs := tx.GetMsgs()
 		for _, m := range msgs {
 			switch msg := m.(type) {
 			case NewOrderMsg:
-				var txSource int64
-				upgrade.UpgradeBEP10(nil, func() {
-					if stdTx, ok := tx.(auth.StdTx); ok {
-						txSource = stdTx.GetSource()
-					} else {
-						logger.Error("tx is not an auth.StdTx", "txhash", txHash.String())
+				replayNewOrder(msg)
+			case CancelOrderMsg:
+				replayCancelOrder(msg)
+			case BatchNewOrderMsg:
+				ok := batchItemOK()
+				for i, order := range msg.Orders {
+					if ok(i) {
+						replayNewOrder(order)
 					}
-				})
-				orderInfo := OrderInfo{
-					msg,
-					height, t,
-					height, t,
-					0, txHash.String(), txSource}
-				err := kp.AddOrder(orderInfo, true)
-				if err != nil {
-					logger.Error("Failed to replay NreOrderMsg", "err", err)
 				}
-				logger.Info("Added Order", "order", msg)
-			case CancelOrderMsg:
-				err := kp.RemoveOrder(msg.RefId, msg.Symbol, func(ord me.OrderPart) {
-					if kp.CollectOrderInfoForPublish {
-						bnclog.Debug("deleted order from order changes map", "orderId", msg.RefId, "isRecovery", true)
-						kp.RemoveOrderInfosForPub(msg.Symbol, msg.RefId)
+			case BatchCancelOrderMsg:
+				ok := batchItemOK()
+				for i, cancel := range msg.Cancels {
+					if ok(i) {
+						replayCancelOrder(cancel)
 					}
-				})
-				if err != nil {
-					logger.Error("Failed to replay cancel msg", "err", err)
 				}
-				logger.Info("Canceled Order", "order", msg)
+			case AmendOrderMsg:
+				if _, err := kp.AmendOrder(msg.Symbol, msg.RefId, msg.Price, msg.Quantity, height, t, true); err != nil {
+					logger.Error("Failed to replay amend msg", "err", err)
+				}
+			case CancelAllOrdersMsg:
+				// the order book is the same as when the msg was delivered, so are the orders to cancel
+				cancels, _ := ordersToCancelAll(kp, msg)
+				for _, cancel := range cancels {
+					replayCancelOrder(cancel)
+				}
 			case dextypes.ListMiniMsg:
 				kp.engines[dexutils.Assets2TradingPair(msg.BaseAssetSymbol, msg.QuoteAssetSymbol)].LastMatchHeight = 0
 			case dextypes.ListMsg:
@@ -268,12 +372,108 @@ func (kp *DexKeeper) ReplayOrdersFromBlock(ctx sdk.Context, bc *tmstore.BlockSto
 		block := bc.LoadBlock(i)
 		ctx.Logger().Info("Relaying block for order book", "height", i)
 		upgrade.Mgr.SetHeight(i)
-		kp.replayOneBlocks(ctx.Logger(), block, stateDb, txDecoder, i, block.Time)
+		kp.replayOneBlocks(ctx, block, stateDb, txDecoder, i, block.Time)
 	}
 	return nil
 }
 
+// ReplayOrdersFromWAL rebuilds the order book changes of (breatheHeight, lastHeight] from the WAL.
+// An error is returned if the WAL is missing or does not cover every height, or the replay does not
+// reproduce the WAL. The order book may have been changed partially then, so it has to be reset and
+// recovered by ReplayOrdersFromBlock.
+func (kp *DexKeeper) ReplayOrdersFromWAL(ctx sdk.Context, breatheHeight, lastHeight int64) error {
+	heights, err := searchForHeights(kp.walDir, breatheHeight, lastHeight, &WALSearchOptions{IgnoreDataCorruptionErrors: true})
+	if err != nil {
+		return err
+	}
+	logger := ctx.Logger()
+	for _, h := range heights {
+		logger.Info("Replaying WAL for order book", "height", h.height)
+		upgrade.Mgr.SetHeight(h.height)
+		for _, m := range h.msgs {
+			switch msg := m.(type) {
+			case OrderAddedWALMessage:
+				err = kp.AddOrder(msg.Order, true)
+			case OrderRemovedWALMessage:
+				err = kp.RemoveOrder(msg.Id, msg.Symbol, func(ord me.OrderPart) {
+					if kp.CollectOrderInfoForPublish {
+						bnclog.Debug("deleted order from order changes map", "orderId", msg.Id, "isRecovery", true)
+						kp.RemoveOrderInfosForPub(msg.Symbol, msg.Id)
+					}
+				})
+			case OrderAmendedWALMessage:
+				_, err = kp.AmendOrder(msg.Symbol, msg.Id, msg.Price, msg.Quantity, msg.Height, msg.Timestamp, true)
+			case TriggerOrderAddedWALMessage:
+				err = kp.AddTriggerOrder(msg.Order, true)
+			case TriggerOrderRemovedWALMessage:
+				err = kp.RemoveTriggerOrder(msg.Id, msg.Symbol, func(ord me.OrderPart) {
+					if kp.CollectOrderInfoForPublish {
+						kp.RemoveOrderInfosForPub(msg.Symbol, msg.Id)
+					}
+				})
+			case PairListedWALMessage:
+				if eng, ok := kp.engines[msg.Pair.GetSymbol()]; ok {
+					eng.LastMatchHeight = 0
+				} else {
+					kp.AddEngine(msg.Pair)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to replay %T at height %d: %v", m, h.height, err)
+			}
+		}
+		if h.match == nil {
+			kp.ClearAfterMatch(h.height)
+			continue
+		}
+		kp.MatchSymbols(h.height, h.match.Timestamp, h.match.MatchAllSymbols)
+		for _, res := range h.match.Results {
+			eng, ok := kp.engines[res.Symbol]
+			if !ok || eng.LastMatchHeight != h.height || eng.LastTradePrice != res.LastTradePrice ||
+				int64(len(eng.Trades)) != res.TradeCount {
+				return fmt.Errorf("match result of %s at height %d mismatches with WAL", res.Symbol, h.height)
+			}
+		}
+	}
+	return nil
+}
+
+// resetOrderBook discards all the orders and match engines in memory, so the order book can be recovered again
+func (kp *DexKeeper) resetOrderBook() {
+	bep2OrderKeeper, miniOrderKeeper := NewBEP2OrderKeeper(), NewMiniOrderKeeper()
+	if kp.CollectOrderInfoForPublish {
+		bep2OrderKeeper.enablePublish()
+		miniOrderKeeper.enablePublish()
+	}
+	kp.OrderKeepers = []DexOrderKeeper{bep2OrderKeeper, miniOrderKeeper}
+	kp.engines = make(map[string]*me.MatchEng)
+	kp.triggerOrders = make(map[string]map[string]*OrderInfo)
+	kp.pairsType = make(map[string]SymbolPairType)
+	kp.RoundOrderFees = make(map[string]*sdk.Fee, 256)
+}
+
+func (kp *DexKeeper) openWAL(breatheHeight, lastHeight int64) {
+	if kp.wal != nil {
+		_ = kp.wal.Stop()
+		kp.wal = nil
+	}
+	wal, err := NewWAL(kp.walDir, breatheHeight)
+	if err != nil {
+		panic(fmt.Errorf("failed to open order book WAL: %v", err))
+	}
+	if err = wal.Start(); err != nil {
+		panic(fmt.Errorf("failed to start order book WAL: %v", err))
+	}
+	wal.WriteSync(RestartWALMessage{Height: lastHeight})
+	kp.wal = wal
+}
+
 func (kp *DexKeeper) initOrderBook(ctx sdk.Context, blockInterval, daysBack int, blockStore *tmstore.BlockStore, stateDB dbm.DB, lastHeight int64, txDecoder sdk.TxDecoder) {
+	// nothing should be written to WAL during recovery
+	if kp.wal != nil {
+		_ = kp.wal.Stop()
+		kp.wal = nil
+	}
 	var timeOfLatestBlock time.Time
 	if lastHeight == 0 {
 		timeOfLatestBlock = utils.Now()
@@ -286,9 +486,29 @@ func (kp *DexKeeper) initOrderBook(ctx sdk.Context, blockInterval, daysBack int,
 		panic(err)
 	}
 	logger := ctx.Logger().With("module", "dex")
-	logger.Info("Initialized Block Store for replay", "fromHeight", height, "toHeight", lastHeight)
-	err = kp.ReplayOrdersFromBlock(ctx.WithLogger(logger), blockStore, stateDB, lastHeight, height, txDecoder)
-	if err != nil {
-		panic(err)
+	replayed := false
+	if kp.walDir != "" && lastHeight > height {
+		err = kp.ReplayOrdersFromWAL(ctx.WithLogger(logger), height, lastHeight)
+		if err == nil {
+			replayed = true
+			logger.Info("Replayed order book from WAL", "fromHeight", height, "toHeight", lastHeight)
+		} else {
+			logger.Error("Failed to replay order book from WAL, fall back to replay blocks", "err", err)
+			// discard what's replayed from WAL and start over from the snapshot
+			kp.resetOrderBook()
+			if _, err = kp.LoadOrderBookSnapshot(ctx, lastHeight, timeOfLatestBlock, blockInterval, daysBack); err != nil {
+				panic(err)
+			}
+		}
+	}
+	if !replayed {
+		logger.Info("Initialized Block Store for replay", "fromHeight", height, "toHeight", lastHeight)
+		err = kp.ReplayOrdersFromBlock(ctx.WithLogger(logger), blockStore, stateDB, lastHeight, height, txDecoder)
+		if err != nil {
+			panic(err)
+		}
+	}
+	if kp.walDir != "" {
+		kp.openWAL(height, lastHeight)
 	}
 }
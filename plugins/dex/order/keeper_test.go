@@ -46,6 +46,10 @@ func MakeCodec() *wire.Codec {
 	types.RegisterWire(cdc)
 	cdc.RegisterConcrete(NewOrderMsg{}, "dex/NewOrder", nil)
 	cdc.RegisterConcrete(CancelOrderMsg{}, "dex/CancelOrder", nil)
+	cdc.RegisterConcrete(BatchNewOrderMsg{}, "dex/BatchNewOrder", nil)
+	cdc.RegisterConcrete(BatchCancelOrderMsg{}, "dex/BatchCancelOrder", nil)
+	cdc.RegisterConcrete(CancelAllOrdersMsg{}, "dex/CancelAllOrders", nil)
+	cdc.RegisterConcrete(AmendOrderMsg{}, "dex/AmendOrder", nil)
 
 	cdc.RegisterConcrete(OrderBookSnapshot{}, "dex/OrderBookSnapshot", nil)
 	cdc.RegisterConcrete(ActiveOrders{}, "dex/ActiveOrders", nil)
@@ -123,6 +127,120 @@ func TestKeeper_MatchFailure(t *testing.T) {
 	assert.Equal(7, i)
 }
 
+func TestKeeper_PostOnlyAndFillOrKill(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	defer resetChainVersion()
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, log.NewNopLogger())
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	msg := NewNewOrderMsg(accAdd, "s-1", Side.SELL, "XYZ-000_BNB", 100000, 2000000)
+	keeper.AddOrder(OrderInfo{msg, 1, 0, 1, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(1, 0, false)
+
+	// crosses the maker order, would be a taker
+	msg = NewNewOrderMsg(accAdd, "p-1", Side.BUY, "XYZ-000_BNB", 100000, 1000000)
+	msg.PostOnly = true
+	keeper.AddOrder(OrderInfo{msg, 2, 0, 2, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "p-2", Side.BUY, "XYZ-000_BNB", 99000, 1000000)
+	msg.PostOnly = true
+	keeper.AddOrder(OrderInfo{msg, 2, 0, 2, 0, 0, "", 0}, false)
+	// more than the sell side has
+	msg = NewNewOrderMsg(accAdd, "f-1", Side.BUY, "XYZ-000_BNB", 100000, 3000000)
+	msg.TimeInForce = TimeInForce.FOK
+	keeper.AddOrder(OrderInfo{msg, 2, 0, 2, 0, 0, "", 0}, false)
+	// can be fully filled once f-1 is killed
+	msg = NewNewOrderMsg(accAdd, "f-2", Side.BUY, "XYZ-000_BNB", 100000, 1000000)
+	msg.TimeInForce = TimeInForce.FOK
+	keeper.AddOrder(OrderInfo{msg, 2, 0, 2, 0, 0, "", 0}, false)
+
+	symbolsToMatch := keeper.SelectSymbolsToMatch(2, false)
+	tradeOuts := keeper.matchAndDistributeTrades(true, 2, 0, symbolsToMatch)
+	events := make(map[string]transferEventType)
+	for _, tradeOut := range tradeOuts {
+		for tr := range tradeOut {
+			if tr.eventType != eventFilled {
+				events[tr.Oid] = tr.eventType
+			}
+		}
+	}
+	assert.Equal(map[string]transferEventType{
+		"p-1": eventCancelForMatchFailure,
+		"f-1": eventIOCFullyExpire,
+	}, events)
+
+	orders := keeper.GetAllOrdersForPair("XYZ-000_BNB")
+	require.Len(t, orders, 2)
+	assert.Equal(int64(1000000), orders["s-1"].CumQty)
+	assert.Equal(int64(0), orders["p-2"].CumQty)
+	trades := keeper.engines["XYZ-000_BNB"].Trades
+	require.Len(t, trades, 1)
+	assert.Equal("f-2", trades[0].Bid)
+	assert.Equal(int64(1000000), trades[0].LastQty)
+	assert.Contains(keeper.GetOrderChanges(PairType.BEP2), OrderChange{"p-1", PostOnlyCanceled, "", nil, nil})
+}
+
+func TestKeeper_MarketOrder(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	defer resetChainVersion()
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, log.NewNopLogger())
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	msg := NewNewOrderMsg(accAdd, "s-1", Side.SELL, "XYZ-000_BNB", 1.02e8, 5e8)
+	keeper.AddOrder(OrderInfo{msg, 1, 0, 1, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(1, 0, false)
+
+	msg = NewMarketOrderMsg(accAdd, "m-1", Side.BUY, "XYZ-000_BNB", 3e8)
+	keeper.AddOrder(OrderInfo{msg, 2, 0, 2, 0, 0, "", 0}, false)
+	symbolsToMatch := keeper.SelectSymbolsToMatch(2, false)
+	tradeOuts := keeper.matchAndDistributeTrades(true, 2, 0, symbolsToMatch)
+	var spent, unlocked int64
+	// all the transfers are of the same account
+	for tr := range tradeOuts[channelHash(accAdd, len(tradeOuts))] {
+		if tr.Oid != "m-1" {
+			continue
+		}
+		if tr.eventType == eventFilled {
+			assert.Equal(tr.out, tr.unlock)
+			spent += tr.out
+		} else {
+			assert.Equal(eventIOCPartiallyExpire, tr.eventType)
+		}
+		unlocked += tr.unlock
+	}
+	// all the quote asset locked is either spent or released
+	assert.Equal(int64(3e8), unlocked)
+	assert.True(spent > 2.9e8)
+
+	trades := keeper.engines["XYZ-000_BNB"].Trades
+	require.Len(t, trades, 1)
+	assert.Equal("m-1", trades[0].Bid)
+	assert.Equal(int64(1.02e8), trades[0].LastPx)
+	orders := keeper.GetAllOrdersForPair("XYZ-000_BNB")
+	require.Len(t, orders, 1)
+	assert.Equal(trades[0].LastQty, orders["s-1"].CumQty)
+	assert.False(keeper.engines["XYZ-000_BNB"].HasMarketOrder("m-1"))
+
+	// market orders can not be amended
+	msg = NewMarketOrderMsg(accAdd, "m-2", Side.SELL, "XYZ-000_BNB", 1e8)
+	keeper.AddOrder(OrderInfo{msg, 3, 0, 3, 0, 0, "", 0}, false)
+	_, err := keeper.AmendOrder("XYZ-000_BNB", "m-2", 1e8, 2e8, 3, 0, false)
+	assert.Error(err)
+}
+
 func TestKeeper_MarkBreatheBlock(t *testing.T) {
 	assert := assert.New(t)
 	cdc := MakeCodec()
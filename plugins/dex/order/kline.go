@@ -0,0 +1,216 @@
+package order
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexutils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+type KlineInterval struct {
+	Name     string
+	Duration time.Duration
+}
+
+// KlineIntervals are the supported intervals of klines
+var KlineIntervals = []KlineInterval{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"1h", time.Hour},
+	{"1d", 24 * time.Hour},
+}
+
+func IsValidKlineInterval(interval string) bool {
+	for _, i := range KlineIntervals {
+		if i.Name == interval {
+			return true
+		}
+	}
+	return false
+}
+
+// KlineAggregator aggregates the trades of each match round into the klines of each symbol and interval.
+// An interval without any trade has no kline. The latest maxKlines klines of each symbol and interval are
+// kept in memory, and the closed ones are also written into db if it's given, so they survive a restart.
+type KlineAggregator struct {
+	mtx       sync.RWMutex
+	maxKlines int
+	db        dbm.DB // nil if the klines are not persisted
+
+	klines  map[string]map[string][]store.Kline // symbol -> interval -> klines, the oldest first, the last one may be open
+	updated []store.Kline                       // klines updated in the last block
+}
+
+func NewKlineAggregator(maxKlines int, db dbm.DB) *KlineAggregator {
+	agg := &KlineAggregator{
+		maxKlines: maxKlines,
+		db:        db,
+		klines:    make(map[string]map[string][]store.Kline),
+	}
+	if db != nil {
+		agg.load()
+	}
+	return agg
+}
+
+func klineKey(symbol, interval string, openTime int64) []byte {
+	return []byte(fmt.Sprintf("%s/%s/%020d", symbol, interval, openTime))
+}
+
+// load reads the persisted klines, which are iterated in the order of symbol, interval and open time
+func (agg *KlineAggregator) load() {
+	it := agg.db.Iterator(nil, nil)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		var kline store.Kline
+		if err := json.Unmarshal(it.Value(), &kline); err != nil {
+			continue
+		}
+		agg.append(kline)
+	}
+}
+
+// update aggregates the trades of the block at timestamp, and closes the klines whose interval is passed
+func (agg *KlineAggregator) update(timestamp int64, trades map[string][]me.Trade) {
+	agg.mtx.Lock()
+	defer agg.mtx.Unlock()
+
+	agg.updated = make([]store.Kline, 0)
+	blockTime := timestamp / int64(time.Millisecond)
+	symbols := make([]string, 0, len(agg.klines)+len(trades))
+	for symbol := range agg.klines {
+		symbols = append(symbols, symbol)
+	}
+	for symbol := range trades {
+		if _, ok := agg.klines[symbol]; !ok {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		if _, ok := agg.klines[symbol]; !ok {
+			agg.klines[symbol] = make(map[string][]store.Kline)
+		}
+		for _, interval := range KlineIntervals {
+			series := agg.klines[symbol][interval.Name]
+			if len(series) > 0 {
+				last := &series[len(series)-1]
+				if !last.Closed && blockTime > last.CloseTime {
+					last.Closed = true
+					agg.persist(*last)
+					agg.updated = append(agg.updated, *last)
+				}
+			}
+
+			symbolTrades := trades[symbol]
+			if len(symbolTrades) == 0 {
+				continue
+			}
+			intervalMs := int64(interval.Duration / time.Millisecond)
+			openTime := blockTime - blockTime%intervalMs
+			var kline store.Kline
+			if len(series) > 0 && !series[len(series)-1].Closed && series[len(series)-1].OpenTime == openTime {
+				kline = series[len(series)-1]
+				series = series[:len(series)-1]
+			} else {
+				kline = store.Kline{
+					Symbol:    symbol,
+					Interval:  interval.Name,
+					OpenTime:  openTime,
+					CloseTime: openTime + intervalMs - 1,
+					Open:      utils.Fixed8(symbolTrades[0].LastPx),
+					High:      utils.Fixed8(symbolTrades[0].LastPx),
+					Low:       utils.Fixed8(symbolTrades[0].LastPx),
+				}
+			}
+			for _, t := range symbolTrades {
+				addTradeToKline(&kline, t)
+			}
+			agg.klines[symbol][interval.Name] = series
+			agg.append(kline)
+			agg.updated = append(agg.updated, kline)
+		}
+	}
+}
+
+func addTradeToKline(kline *store.Kline, t me.Trade) {
+	price := utils.Fixed8(t.LastPx)
+	if price > kline.High {
+		kline.High = price
+	}
+	if price < kline.Low {
+		kline.Low = price
+	}
+	kline.Close = price
+	kline.Volume += utils.Fixed8(t.LastQty)
+	quoteVolume := kline.QuoteVolume + utils.Fixed8(dexutils.CalBigNotionalInt64(t.LastPx, t.LastQty))
+	if quoteVolume < kline.QuoteVolume {
+		// overflow
+		quoteVolume = math.MaxInt64
+	}
+	kline.QuoteVolume = quoteVolume
+	kline.NumberOfTrades++
+}
+
+// append adds the kline to the end of its series, and drops the oldest one if there are too many
+func (agg *KlineAggregator) append(kline store.Kline) {
+	if _, ok := agg.klines[kline.Symbol]; !ok {
+		agg.klines[kline.Symbol] = make(map[string][]store.Kline)
+	}
+	series := append(agg.klines[kline.Symbol][kline.Interval], kline)
+	if len(series) > agg.maxKlines {
+		for _, dropped := range series[:len(series)-agg.maxKlines] {
+			if agg.db != nil {
+				agg.db.Delete(klineKey(dropped.Symbol, dropped.Interval, dropped.OpenTime))
+			}
+		}
+		series = append([]store.Kline(nil), series[len(series)-agg.maxKlines:]...)
+	}
+	agg.klines[kline.Symbol][kline.Interval] = series
+}
+
+func (agg *KlineAggregator) persist(kline store.Kline) {
+	if agg.db == nil {
+		return
+	}
+	bz, err := json.Marshal(kline)
+	if err != nil {
+		return
+	}
+	agg.db.Set(klineKey(kline.Symbol, kline.Interval, kline.OpenTime), bz)
+}
+
+// Klines returns the latest limit klines of the symbol in the interval, the oldest first
+func (agg *KlineAggregator) Klines(symbol, interval string, limit int) []store.Kline {
+	agg.mtx.RLock()
+	defer agg.mtx.RUnlock()
+	series := agg.klines[symbol][interval]
+	if limit > 0 && len(series) > limit {
+		series = series[len(series)-limit:]
+	}
+	return append(make([]store.Kline, 0, len(series)), series...)
+}
+
+// Updated returns the klines updated or closed in the last block
+func (agg *KlineAggregator) Updated() []store.Kline {
+	agg.mtx.RLock()
+	defer agg.mtx.RUnlock()
+	return agg.updated
+}
+
+// Close closes the db of the persisted klines
+func (agg *KlineAggregator) Close() {
+	if agg.db != nil {
+		agg.db.Close()
+	}
+}
@@ -0,0 +1,63 @@
+package order
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+)
+
+func TestKlineAggregator_Update(t *testing.T) {
+	db := dbm.NewMemDB()
+	agg := NewKlineAggregator(2, db)
+	symbol := "XYZ-000_BNB"
+	minute := int64(time.Minute)
+
+	agg.update(10*minute+int64(time.Second), map[string][]me.Trade{
+		symbol: {{LastPx: 2e8, LastQty: 1e8}, {LastPx: 3e8, LastQty: 1e8}},
+	})
+	agg.update(10*minute+2*int64(time.Second), map[string][]me.Trade{
+		symbol: {{LastPx: 1e8, LastQty: 2e8}},
+	})
+	klines := agg.Klines(symbol, "1m", 10)
+	require.Len(t, klines, 1)
+	require.Equal(t, int64(10*60000), klines[0].OpenTime)
+	require.Equal(t, int64(11*60000-1), klines[0].CloseTime)
+	require.Equal(t, utils.Fixed8(2e8), klines[0].Open)
+	require.Equal(t, utils.Fixed8(3e8), klines[0].High)
+	require.Equal(t, utils.Fixed8(1e8), klines[0].Low)
+	require.Equal(t, utils.Fixed8(1e8), klines[0].Close)
+	require.Equal(t, utils.Fixed8(4e8), klines[0].Volume)
+	require.Equal(t, utils.Fixed8(7e8), klines[0].QuoteVolume)
+	require.Equal(t, int64(3), klines[0].NumberOfTrades)
+	require.False(t, klines[0].Closed)
+	// one open kline for each interval
+	require.Len(t, agg.Updated(), len(KlineIntervals))
+
+	// the 1m kline is closed by a block in the next interval even without any trade
+	agg.update(11*minute, nil)
+	require.Len(t, agg.Updated(), 1)
+	require.True(t, agg.Updated()[0].Closed)
+	require.True(t, agg.Klines(symbol, "1m", 10)[0].Closed)
+	require.False(t, agg.Klines(symbol, "5m", 10)[0].Closed)
+
+	agg.update(12*minute, map[string][]me.Trade{symbol: {{LastPx: 1e8, LastQty: 1e8}}})
+	agg.update(13*minute, map[string][]me.Trade{symbol: {{LastPx: 1e8, LastQty: 1e8}}})
+	klines = agg.Klines(symbol, "1m", 10)
+	require.Len(t, klines, 2)
+	require.Equal(t, int64(12*60000), klines[0].OpenTime)
+	require.Equal(t, int64(13*60000), klines[1].OpenTime)
+	require.Equal(t, int64(13*60000), agg.Klines(symbol, "1m", 1)[0].OpenTime)
+
+	// only the closed klines are persisted, and the dropped ones are deleted
+	reloaded := NewKlineAggregator(2, db)
+	klines = reloaded.Klines(symbol, "1m", 10)
+	require.Len(t, klines, 1)
+	require.Equal(t, int64(12*60000), klines[0].OpenTime)
+	require.True(t, klines[0].Closed)
+	require.Len(t, reloaded.Klines(symbol, "5m", 10), 0)
+}
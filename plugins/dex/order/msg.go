@@ -9,13 +9,22 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	txbuilder "github.com/cosmos/cosmos-sdk/x/auth/client/txbuilder"
 
+	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
 	"github.com/bnb-chain/node/plugins/dex/types"
 )
 
 const (
 	RouteNewOrder    = "orderNew"
 	RouteCancelOrder = "orderCancel"
+
+	CancelAllOrdersMsgType = "orderCancelAll"
+	AmendOrderMsgType      = "orderAmend"
+
+	// MaxCancelAllOrders caps the number of orders canceled by one CancelAllOrdersMsg, the remaining orders are
+	// left for the next msg and their number is given in the BatchResponse of the msg.
+	MaxCancelAllOrders = 100
 )
 
 // Side/TimeInForce/OrderType are const, following FIX protocol convention
@@ -56,55 +65,90 @@ func SideStringToSideCode(side string) (int8, error) {
 }
 
 const (
-	_           int8 = iota
-	orderMarket int8 = iota
-	orderLimit  int8 = iota
+	_               int8 = iota
+	orderMarket     int8 = iota
+	orderLimit      int8 = iota
+	orderStopLimit  int8 = iota
+	orderTakeProfit int8 = iota
 )
 
 // OrderType is an enum of order type options supported by the matching engine
 var OrderType = struct {
-	LIMIT  int8
-	MARKET int8
-}{orderLimit, orderMarket}
+	LIMIT       int8
+	MARKET      int8
+	STOP_LIMIT  int8
+	TAKE_PROFIT int8
+}{orderLimit, orderMarket, orderStopLimit, orderTakeProfit}
+
+var orderTypeNames = map[string]int8{
+	"LIMIT":       orderLimit,
+	"MARKET":      orderMarket,
+	"STOP_LIMIT":  orderStopLimit,
+	"TAKE_PROFIT": orderTakeProfit,
+}
 
 // IsValidOrderType validates that an order type is valid and supported by the matching engine
 func IsValidOrderType(ot int8) bool {
 	switch ot {
-	case OrderType.LIMIT: // only allow LIMIT for now.
+	case OrderType.LIMIT, OrderType.MARKET, OrderType.STOP_LIMIT, OrderType.TAKE_PROFIT:
 		return true
 	default:
 		return false
 	}
 }
 
+// IsConditionalOrderType returns true for the order types that wait in the trigger book
+// until the last trade price crosses their stop price.
+func IsConditionalOrderType(ot int8) bool {
+	return ot == OrderType.STOP_LIMIT || ot == OrderType.TAKE_PROFIT
+}
+
+// OrderTypeStringToOrderTypeCode converts a string like "LIMIT" to its internal order type code
+func OrderTypeStringToOrderTypeCode(ot string) (int8, error) {
+	upperOt := strings.ToUpper(ot)
+	if val, ok := orderTypeNames[upperOt]; ok {
+		return val, nil
+	}
+	return -1, errors.New("order type `" + upperOt + "` not found or supported")
+}
+
 const (
 	_      int8 = iota
 	tifGTE int8 = iota
 	_      int8 = iota
 	tifIOC int8 = iota
+	tifFOK int8 = iota
 )
 
 // TimeInForce is an enum of TIF (Time in Force) options supported by the matching engine
 var TimeInForce = struct {
 	GTE int8
 	IOC int8
-}{tifGTE, tifIOC}
+	FOK int8
+}{tifGTE, tifIOC, tifFOK}
 
 var timeInForceNames = map[string]int8{
 	"GTE": tifGTE,
 	"IOC": tifIOC,
+	"FOK": tifFOK,
 }
 
 // IsValidTimeInForce validates that a tif code is correct
 func IsValidTimeInForce(tif int8) bool {
 	switch tif {
-	case TimeInForce.GTE, TimeInForce.IOC:
+	case TimeInForce.GTE, TimeInForce.IOC, TimeInForce.FOK:
 		return true
 	default:
 		return false
 	}
 }
 
+// isImmediateTimeInForce returns true if the order is closed after its first match round,
+// i.e. the unfilled quantity of an IOC or FOK order expires.
+func isImmediateTimeInForce(tif int8) bool {
+	return tif == TimeInForce.IOC || tif == TimeInForce.FOK
+}
+
 // TifStringToTifCode converts a string like "GTE" to its internal tif code
 func TifStringToTifCode(tif string) (int8, error) {
 	upperTif := strings.ToUpper(tif)
@@ -125,6 +169,8 @@ type NewOrderMsg struct {
 	Price       int64          `json:"price"`
 	Quantity    int64          `json:"quantity"`
 	TimeInForce int8           `json:"timeinforce"`
+	StopPrice   int64          `json:"stopprice,omitempty"` // only for conditional orders
+	PostOnly    bool           `json:"postonly,omitempty"`  // canceled instead of being matched as a taker
 }
 
 // NewNewOrderMsg constructs a new NewOrderMsg
@@ -142,6 +188,37 @@ func NewNewOrderMsg(sender sdk.AccAddress, id string, side int8,
 	}
 }
 
+// NewMarketOrderMsg constructs a new market NewOrderMsg.
+// For a buy order qty is the amount of quote asset to spend, for a sell order it is the quantity of base asset to sell.
+func NewMarketOrderMsg(sender sdk.AccAddress, id string, side int8, symbol string, qty int64) NewOrderMsg {
+	return NewOrderMsg{
+		Sender:      sender,
+		Id:          id,
+		Symbol:      symbol,
+		OrderType:   OrderType.MARKET,
+		Side:        side,
+		Quantity:    qty,
+		TimeInForce: TimeInForce.IOC, // market order can only be IOC
+	}
+}
+
+// NewConditionalOrderMsg constructs a new stop-limit or take-profit NewOrderMsg.
+// The order is placed into the order book as a limit order once the last trade price crosses stopPrice.
+func NewConditionalOrderMsg(sender sdk.AccAddress, id string, orderType, side int8,
+	symbol string, stopPrice, price, qty int64) NewOrderMsg {
+	return NewOrderMsg{
+		Sender:      sender,
+		Id:          id,
+		Symbol:      symbol,
+		OrderType:   orderType,
+		Side:        side,
+		Price:       price,
+		Quantity:    qty,
+		TimeInForce: TimeInForce.GTE, // default
+		StopPrice:   stopPrice,
+	}
+}
+
 // NewNewOrderMsgAuto constructs a new NewOrderMsg and auto-assigns its order ID
 func NewNewOrderMsgAuto(txBuilder txbuilder.TxBuilder, sender sdk.AccAddress, side int8,
 	symbol string, price int64, qty int64) (NewOrderMsg, error) {
@@ -242,18 +319,46 @@ func (msg NewOrderMsg) ValidateBasic() sdk.Error {
 	if msg.Quantity <= 0 {
 		return types.ErrInvalidOrderParam("Quantity", fmt.Sprintf("Zero/Negative Number:%d", msg.Quantity))
 	}
-	if msg.Price <= 0 {
-		return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Zero/Negative Number:%d", msg.Price))
-	}
 	if !IsValidOrderType(msg.OrderType) {
 		return types.ErrInvalidOrderParam("OrderType", fmt.Sprintf("Invalid order type:%d", msg.OrderType))
 	}
+	if msg.OrderType == OrderType.MARKET {
+		if !sdk.IsUpgrade(upgrade.MarketOrder) {
+			return types.ErrInvalidOrderParam("OrderType", "Market order is not supported yet")
+		}
+		// a market buy order specifies the quote asset amount to spend in `Quantity`,
+		// a market sell order specifies the base asset quantity to sell.
+		if msg.Price != 0 {
+			return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Price must be 0 for market order:%d", msg.Price))
+		}
+		if msg.TimeInForce != TimeInForce.IOC {
+			return types.ErrInvalidOrderParam("TimeInForce", fmt.Sprintf("Market order must be IOC:%d", msg.TimeInForce))
+		}
+	} else if msg.Price <= 0 {
+		return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Zero/Negative Number:%d", msg.Price))
+	}
+	if (IsConditionalOrderType(msg.OrderType) || msg.StopPrice != 0) && !sdk.IsUpgrade(upgrade.ConditionalOrder) {
+		return types.ErrInvalidOrderParam("OrderType", "Conditional order is not supported yet")
+	}
+	if IsConditionalOrderType(msg.OrderType) {
+		if msg.StopPrice <= 0 {
+			return types.ErrInvalidOrderParam("StopPrice", fmt.Sprintf("Zero/Negative Number:%d", msg.StopPrice))
+		}
+	} else if msg.StopPrice != 0 {
+		return types.ErrInvalidOrderParam("StopPrice", fmt.Sprintf("StopPrice is only for conditional order:%d", msg.StopPrice))
+	}
 	if !IsValidSide(msg.Side) {
 		return types.ErrInvalidOrderParam("Side", fmt.Sprintf("Invalid side:%d", msg.Side))
 	}
 	if !IsValidTimeInForce(msg.TimeInForce) {
 		return types.ErrInvalidOrderParam("TimeInForce", fmt.Sprintf("Invalid TimeInForce:%d", msg.TimeInForce))
 	}
+	if (msg.TimeInForce == TimeInForce.FOK || msg.PostOnly) && !sdk.IsUpgrade(upgrade.FOKAndPostOnly) {
+		return types.ErrInvalidOrderParam("TimeInForce", "FOK and post-only orders are not supported yet")
+	}
+	if msg.PostOnly && msg.TimeInForce != TimeInForce.GTE {
+		return types.ErrInvalidOrderParam("TimeInForce", fmt.Sprintf("Post-only order must be GTE:%d", msg.TimeInForce))
+	}
 
 	return nil
 }
@@ -268,3 +373,116 @@ func (msg CancelOrderMsg) ValidateBasic() sdk.Error {
 	}
 	return nil
 }
+
+var _ sdk.Msg = CancelAllOrdersMsg{}
+
+// CancelAllOrdersMsg cancels all the open orders of the sender, optionally only those of one symbol and/or one side
+type CancelAllOrdersMsg struct {
+	Sender sdk.AccAddress `json:"sender"`
+	Symbol string         `json:"symbol,omitempty"` // empty for all symbols
+	Side   int8           `json:"side,omitempty"`   // 0 for both sides
+}
+
+// NewCancelAllOrdersMsg constructs a new CancelAllOrdersMsg
+func NewCancelAllOrdersMsg(sender sdk.AccAddress, symbol string, side int8) CancelAllOrdersMsg {
+	return CancelAllOrdersMsg{
+		Sender: sender,
+		Symbol: symbol,
+		Side:   side,
+	}
+}
+
+// nolint
+func (msg CancelAllOrdersMsg) Route() string                { return RouteCancelOrder }
+func (msg CancelAllOrdersMsg) Type() string                 { return CancelAllOrdersMsgType }
+func (msg CancelAllOrdersMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.Sender} }
+func (msg CancelAllOrdersMsg) String() string {
+	return fmt.Sprintf("CancelAllOrdersMsg{Sender:%v, Symbol: %s, Side: %d}", msg.Sender, msg.Symbol, msg.Side)
+}
+
+// GetSignBytes - Get the bytes for the message signer to sign on
+func (msg CancelAllOrdersMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg CancelAllOrdersMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
+
+// ValidateBasic is used to quickly disqualify obviously invalid messages quickly
+func (msg CancelAllOrdersMsg) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrUnknownAddress(msg.Sender.String()).TraceSDK("")
+	}
+	if msg.Side != 0 && !IsValidSide(msg.Side) {
+		return types.ErrInvalidOrderParam("Side", fmt.Sprintf("Invalid side:%d", msg.Side))
+	}
+	return nil
+}
+
+var _ sdk.Msg = AmendOrderMsg{}
+
+// AmendOrderMsg changes the price and/or quantity of an open order
+type AmendOrderMsg struct {
+	Sender   sdk.AccAddress `json:"sender"`
+	Symbol   string         `json:"symbol"`
+	RefId    string         `json:"refid"`
+	Price    int64          `json:"price"`
+	Quantity int64          `json:"quantity"`
+}
+
+// NewAmendOrderMsg constructs a new AmendOrderMsg
+func NewAmendOrderMsg(sender sdk.AccAddress, symbol, refId string, price, qty int64) AmendOrderMsg {
+	return AmendOrderMsg{
+		Sender:   sender,
+		Symbol:   symbol,
+		RefId:    refId,
+		Price:    price,
+		Quantity: qty,
+	}
+}
+
+// nolint
+func (msg AmendOrderMsg) Route() string                { return RouteNewOrder }
+func (msg AmendOrderMsg) Type() string                 { return AmendOrderMsgType }
+func (msg AmendOrderMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.Sender} }
+func (msg AmendOrderMsg) String() string {
+	return fmt.Sprintf("AmendOrderMsg{Sender:%v, RefId: %s, Price: %d, Quantity: %d}", msg.Sender, msg.RefId, msg.Price, msg.Quantity)
+}
+
+// GetSignBytes - Get the bytes for the message signer to sign on
+func (msg AmendOrderMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg AmendOrderMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
+
+// ValidateBasic is used to quickly disqualify obviously invalid messages quickly
+func (msg AmendOrderMsg) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrUnknownAddress(msg.Sender.String()).TraceSDK("")
+	}
+	if err := store.ValidatePairSymbol(msg.Symbol); err != nil {
+		return types.ErrInvalidOrderParam("Symbol", err.Error())
+	}
+	if len(msg.RefId) == 0 || !strings.Contains(msg.RefId, "-") {
+		return types.ErrInvalidOrderParam("RefId", fmt.Sprintf("Invalid ref ID:%s", msg.RefId))
+	}
+	if msg.Price <= 0 {
+		return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Zero/Negative Number:%d", msg.Price))
+	}
+	if msg.Quantity <= 0 {
+		return types.ErrInvalidOrderParam("Quantity", fmt.Sprintf("Zero/Negative Number:%d", msg.Quantity))
+	}
+	return nil
+}
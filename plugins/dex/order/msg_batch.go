@@ -0,0 +1,198 @@
+package order
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
+	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
+
+	"github.com/bnb-chain/node/plugins/dex/types"
+)
+
+const (
+	BatchNewOrderMsgType    = "batchOrderNew"
+	BatchCancelOrderMsgType = "batchOrderCancel"
+
+	// MaxBatchSize is the max number of orders or cancels carried by one batch message
+	MaxBatchSize = 20
+)
+
+// BatchFeeCalculatorGen generates the fee calculator of the batch messages, which charges the fixed fee
+// of the message type for each order or cancel carried by the batch
+var BatchFeeCalculatorGen = fees.FeeCalculatorGenerator(func(params param.FeeParam) fees.FeeCalculator {
+	fixedFeeParam, ok := params.(*param.FixedFeeParams)
+	if !ok {
+		panic("Generator received unexpected param type")
+	}
+	if fixedFeeParam.Fee <= 0 || fixedFeeParam.FeeFor == sdk.FeeFree {
+		return fees.FreeFeeCalculator()
+	}
+
+	return fees.FeeCalculator(func(msg sdk.Msg) sdk.Fee {
+		var items int64
+		switch msg := msg.(type) {
+		case BatchNewOrderMsg:
+			items = int64(len(msg.Orders))
+		case BatchCancelOrderMsg:
+			items = int64(len(msg.Cancels))
+		default:
+			panic("unexpected msg for BatchFeeCalculator")
+		}
+		return sdk.NewFee(sdk.Coins{sdk.NewCoin(sdk.NativeTokenSymbol, fixedFeeParam.Fee*items)}, fixedFeeParam.FeeFor)
+	})
+})
+
+// GenerateBatchOrderID generates the ID of the order at index of a batch, in the format of <address>-<sequence>-<index>
+func GenerateBatchOrderID(sequence int64, addr sdk.AccAddress, index int) string {
+	return fmt.Sprintf("%X-%d-%d", addr, sequence, index)
+}
+
+// BatchItemResult is the result of one order or cancel of a batch message
+type BatchItemResult struct {
+	OrderID string           `json:"order_id"`
+	Code    sdk.ABCICodeType `json:"code"`
+	Log     string           `json:"log,omitempty"`
+}
+
+func (r BatchItemResult) IsOK() bool {
+	return r.Code == sdk.ABCICodeOK
+}
+
+// BatchResponse is the data of the result of a batch message, with one item for each order or cancel in order
+type BatchResponse struct {
+	Results []BatchItemResult `json:"results"`
+	// Remaining is the number of the orders selected by a CancelAllOrdersMsg beyond MaxCancelAllOrders,
+	// which are left open for the next msg
+	Remaining int `json:"remaining,omitempty"`
+}
+
+func decodeBatchResponse(data []byte) (BatchResponse, error) {
+	var resp BatchResponse
+	err := json.Unmarshal(data, &resp)
+	return resp, err
+}
+
+var _ sdk.Msg = BatchNewOrderMsg{}
+
+// BatchNewOrderMsg places several orders of one sender in a single tx.
+// Each order is validated on its own and the result is given in BatchResponse,
+// while the balance of all the accepted orders is locked at once.
+type BatchNewOrderMsg struct {
+	Sender sdk.AccAddress `json:"sender"`
+	Orders []NewOrderMsg  `json:"orders"`
+}
+
+// NewBatchNewOrderMsg constructs a new BatchNewOrderMsg
+func NewBatchNewOrderMsg(sender sdk.AccAddress, orders []NewOrderMsg) BatchNewOrderMsg {
+	return BatchNewOrderMsg{
+		Sender: sender,
+		Orders: orders,
+	}
+}
+
+// nolint
+func (msg BatchNewOrderMsg) Route() string                { return RouteNewOrder }
+func (msg BatchNewOrderMsg) Type() string                 { return BatchNewOrderMsgType }
+func (msg BatchNewOrderMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.Sender} }
+func (msg BatchNewOrderMsg) String() string {
+	return fmt.Sprintf("BatchNewOrderMsg{Sender: %v, Orders: %d}", msg.Sender, len(msg.Orders))
+}
+func (msg BatchNewOrderMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
+
+// GetSignBytes - Get the bytes for the message signer to sign on
+func (msg BatchNewOrderMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// ValidateBasic is used to quickly disqualify obviously invalid messages quickly
+func (msg BatchNewOrderMsg) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrUnknownAddress(msg.Sender.String()).TraceSDK("")
+	}
+	if len(msg.Orders) == 0 || len(msg.Orders) > MaxBatchSize {
+		return types.ErrInvalidOrderParam("Orders", fmt.Sprintf("Number of orders should be in [1, %d]:%d", MaxBatchSize, len(msg.Orders)))
+	}
+	ids := make(map[string]struct{}, len(msg.Orders))
+	for i, order := range msg.Orders {
+		if !order.Sender.Equals(msg.Sender) {
+			return types.ErrInvalidOrderParam("Sender", fmt.Sprintf("Sender of order %d is not the batch sender", i))
+		}
+		if _, ok := ids[order.Id]; ok {
+			return types.ErrInvalidOrderParam("Id", fmt.Sprintf("Duplicated order ID:%s", order.Id))
+		}
+		ids[order.Id] = struct{}{}
+		if err := order.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ sdk.Msg = BatchCancelOrderMsg{}
+
+// BatchCancelOrderMsg cancels several orders of one sender in a single tx.
+// Each cancel is handled on its own and the result is given in BatchResponse.
+type BatchCancelOrderMsg struct {
+	Sender  sdk.AccAddress   `json:"sender"`
+	Cancels []CancelOrderMsg `json:"cancels"`
+}
+
+// NewBatchCancelOrderMsg constructs a new BatchCancelOrderMsg
+func NewBatchCancelOrderMsg(sender sdk.AccAddress, cancels []CancelOrderMsg) BatchCancelOrderMsg {
+	return BatchCancelOrderMsg{
+		Sender:  sender,
+		Cancels: cancels,
+	}
+}
+
+// nolint
+func (msg BatchCancelOrderMsg) Route() string                { return RouteCancelOrder }
+func (msg BatchCancelOrderMsg) Type() string                 { return BatchCancelOrderMsgType }
+func (msg BatchCancelOrderMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.Sender} }
+func (msg BatchCancelOrderMsg) String() string {
+	return fmt.Sprintf("BatchCancelOrderMsg{Sender: %v, Cancels: %d}", msg.Sender, len(msg.Cancels))
+}
+func (msg BatchCancelOrderMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
+
+// GetSignBytes - Get the bytes for the message signer to sign on
+func (msg BatchCancelOrderMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// ValidateBasic is used to quickly disqualify obviously invalid messages quickly
+func (msg BatchCancelOrderMsg) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrUnknownAddress(msg.Sender.String()).TraceSDK("")
+	}
+	if len(msg.Cancels) == 0 || len(msg.Cancels) > MaxBatchSize {
+		return types.ErrInvalidOrderParam("Cancels", fmt.Sprintf("Number of cancels should be in [1, %d]:%d", MaxBatchSize, len(msg.Cancels)))
+	}
+	refIds := make(map[string]struct{}, len(msg.Cancels))
+	for i, cancel := range msg.Cancels {
+		if !cancel.Sender.Equals(msg.Sender) {
+			return types.ErrInvalidOrderParam("Sender", fmt.Sprintf("Sender of cancel %d is not the batch sender", i))
+		}
+		if _, ok := refIds[cancel.RefId]; ok {
+			return types.ErrInvalidOrderParam("RefId", fmt.Sprintf("Duplicated ref ID:%s", cancel.RefId))
+		}
+		refIds[cancel.RefId] = struct{}{}
+		if err := cancel.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
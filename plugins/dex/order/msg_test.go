@@ -14,9 +14,11 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/context"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	txbuilder "github.com/cosmos/cosmos-sdk/x/auth/client/txbuilder"
+	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
 
 	cmn "github.com/bnb-chain/node/common"
 	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 func newCLIContext() context.CLIContext {
@@ -40,10 +42,12 @@ func TestIsValidSide(t *testing.T) {
 
 func TestIsValidOrderType(t *testing.T) {
 	assert := assert.New(t)
-	assert.False(IsValidOrderType(1))
+	assert.True(IsValidOrderType(1))
 	assert.True(IsValidOrderType(2))
+	assert.True(IsValidOrderType(3))
+	assert.True(IsValidOrderType(4))
 	assert.False(IsValidOrderType(0))
-	assert.False(IsValidOrderType(3))
+	assert.False(IsValidOrderType(5))
 }
 
 func TestIsValidTimeInForce(t *testing.T) {
@@ -52,6 +56,8 @@ func TestIsValidTimeInForce(t *testing.T) {
 	assert.False(IsValidTimeInForce(2))
 	assert.False(IsValidTimeInForce(0))
 	assert.True(IsValidTimeInForce(3))
+	assert.True(IsValidTimeInForce(4))
+	assert.False(IsValidTimeInForce(5))
 }
 
 func TestNewOrderMsg_ValidateBasic(t *testing.T) {
@@ -68,6 +74,73 @@ func TestNewOrderMsg_ValidateBasic(t *testing.T) {
 	msg = NewNewOrderMsg(acct, "addr-1", 2, "BTC.B_BNB", 355, 10)
 	msg.TimeInForce = 5
 	assert.Regexp(regexp.MustCompile(".*Invalid TimeInForce.*"), msg.ValidateBasic().Error())
+
+	msg = NewMarketOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 100)
+	assert.Regexp(regexp.MustCompile(".*Market order is not supported yet.*"), msg.ValidateBasic().Error())
+	upgrade.Mgr.AddUpgradeHeight(upgrade.MarketOrder, -1)
+	defer resetChainVersion()
+	assert.Nil(msg.ValidateBasic())
+	msg.Price = 355
+	assert.Regexp(regexp.MustCompile(".*Price must be 0 for market order.*"), msg.ValidateBasic().Error())
+	msg = NewMarketOrderMsg(acct, "addr-1", 2, "BTC.B_BNB", 100)
+	msg.TimeInForce = TimeInForce.GTE
+	assert.Regexp(regexp.MustCompile(".*Market order must be IOC.*"), msg.ValidateBasic().Error())
+
+	msg = NewConditionalOrderMsg(acct, "addr-1", OrderType.STOP_LIMIT, 1, "BTC.B_BNB", 350, 355, 100)
+	assert.Regexp(regexp.MustCompile(".*Conditional order is not supported yet.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.StopPrice = 350
+	assert.Regexp(regexp.MustCompile(".*Conditional order is not supported yet.*"), msg.ValidateBasic().Error())
+	upgrade.Mgr.AddUpgradeHeight(upgrade.ConditionalOrder, -1)
+	msg = NewConditionalOrderMsg(acct, "addr-1", OrderType.STOP_LIMIT, 1, "BTC.B_BNB", 350, 355, 100)
+	assert.Nil(msg.ValidateBasic())
+	msg = NewConditionalOrderMsg(acct, "addr-1", OrderType.TAKE_PROFIT, 2, "BTC.B_BNB", 0, 355, 100)
+	assert.Regexp(regexp.MustCompile(".*StopPrice.*Zero/Negative Number.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.StopPrice = 350
+	assert.Regexp(regexp.MustCompile(".*StopPrice is only for conditional order.*"), msg.ValidateBasic().Error())
+
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.TimeInForce = TimeInForce.FOK
+	assert.Regexp(regexp.MustCompile(".*FOK and post-only orders are not supported yet.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.PostOnly = true
+	assert.Regexp(regexp.MustCompile(".*FOK and post-only orders are not supported yet.*"), msg.ValidateBasic().Error())
+	upgrade.Mgr.AddUpgradeHeight(upgrade.FOKAndPostOnly, -1)
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.TimeInForce = TimeInForce.FOK
+	assert.Nil(msg.ValidateBasic())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.PostOnly = true
+	assert.Nil(msg.ValidateBasic())
+	msg.TimeInForce = TimeInForce.IOC
+	assert.Regexp(regexp.MustCompile(".*Post-only order must be GTE.*"), msg.ValidateBasic().Error())
+}
+
+func TestNewOrderMsg_PostOnlyAndFOKEncoding(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	_, acct := testutils.PrivAndAddr()
+	msg := NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	// sign bytes of an order without the new options are not changed
+	assert.NotContains(string(msg.GetSignBytes()), "postonly")
+
+	msg.PostOnly = true
+	assert.Contains(string(msg.GetSignBytes()), `"postonly":true`)
+	bz, err := cdc.MarshalBinaryLengthPrefixed(msg)
+	assert.Nil(err)
+	var decoded NewOrderMsg
+	assert.Nil(cdc.UnmarshalBinaryLengthPrefixed(bz, &decoded))
+	assert.Equal(msg, decoded)
+
+	msg.PostOnly = false
+	msg.TimeInForce = TimeInForce.FOK
+	assert.Contains(string(msg.GetSignBytes()), `"timeinforce":4`)
+	bz, err = cdc.MarshalBinaryLengthPrefixed(msg)
+	assert.Nil(err)
+	decoded = NewOrderMsg{}
+	assert.Nil(cdc.UnmarshalBinaryLengthPrefixed(bz, &decoded))
+	assert.Equal(msg, decoded)
 }
 
 func TestCancelOrderMsg_ValidateBasic(t *testing.T) {
@@ -76,6 +149,87 @@ func TestCancelOrderMsg_ValidateBasic(t *testing.T) {
 	assert.NotNil(msg.ValidateBasic())
 }
 
+func TestBatchNewOrderMsg_ValidateBasic(t *testing.T) {
+	assert := assert.New(t)
+	_, acct := testutils.PrivAndAddr()
+	_, other := testutils.PrivAndAddr()
+	order := func(sender sdk.AccAddress, index int) NewOrderMsg {
+		return NewNewOrderMsg(sender, GenerateBatchOrderID(1, sender, index), Side.BUY, "BTC.B_BNB", 355, 100)
+	}
+
+	assert.Nil(NewBatchNewOrderMsg(acct, []NewOrderMsg{order(acct, 0), order(acct, 1)}).ValidateBasic())
+	assert.NotNil(NewBatchNewOrderMsg(acct, nil).ValidateBasic())
+	assert.NotNil(NewBatchNewOrderMsg(acct, []NewOrderMsg{order(acct, 0), order(other, 1)}).ValidateBasic())
+	assert.NotNil(NewBatchNewOrderMsg(acct, []NewOrderMsg{order(acct, 0), order(acct, 0)}).ValidateBasic())
+	invalid := order(acct, 0)
+	invalid.Quantity = 0
+	assert.NotNil(NewBatchNewOrderMsg(acct, []NewOrderMsg{invalid}).ValidateBasic())
+	orders := make([]NewOrderMsg, MaxBatchSize+1)
+	for i := range orders {
+		orders[i] = order(acct, i)
+	}
+	assert.NotNil(NewBatchNewOrderMsg(acct, orders).ValidateBasic())
+	assert.Nil(NewBatchNewOrderMsg(acct, orders[:MaxBatchSize]).ValidateBasic())
+}
+
+func TestBatchCancelOrderMsg_ValidateBasic(t *testing.T) {
+	assert := assert.New(t)
+	_, acct := testutils.PrivAndAddr()
+	_, other := testutils.PrivAndAddr()
+
+	assert.Nil(NewBatchCancelOrderMsg(acct, []CancelOrderMsg{
+		NewCancelOrderMsg(acct, "XYZ_BNB", "order-1"), NewCancelOrderMsg(acct, "XYZ_BNB", "order-2")}).ValidateBasic())
+	assert.NotNil(NewBatchCancelOrderMsg(acct, nil).ValidateBasic())
+	assert.NotNil(NewBatchCancelOrderMsg(acct, []CancelOrderMsg{
+		NewCancelOrderMsg(acct, "XYZ_BNB", "order-1"), NewCancelOrderMsg(other, "XYZ_BNB", "order-2")}).ValidateBasic())
+	assert.NotNil(NewBatchCancelOrderMsg(acct, []CancelOrderMsg{
+		NewCancelOrderMsg(acct, "XYZ_BNB", "order-1"), NewCancelOrderMsg(acct, "XYZ_BNB", "order-1")}).ValidateBasic())
+}
+
+func TestBatchFeeCalculatorGen(t *testing.T) {
+	assert := assert.New(t)
+	_, acct := testutils.PrivAndAddr()
+	batchNew := NewBatchNewOrderMsg(acct, []NewOrderMsg{
+		NewNewOrderMsg(acct, GenerateBatchOrderID(1, acct, 0), Side.BUY, "BTC.B_BNB", 355, 100),
+		NewNewOrderMsg(acct, GenerateBatchOrderID(1, acct, 1), Side.BUY, "BTC.B_BNB", 355, 100),
+	})
+	batchCancel := NewBatchCancelOrderMsg(acct, []CancelOrderMsg{
+		NewCancelOrderMsg(acct, "XYZ_BNB", "order-1"),
+		NewCancelOrderMsg(acct, "XYZ_BNB", "order-2"),
+		NewCancelOrderMsg(acct, "XYZ_BNB", "order-3"),
+	})
+	assert.Equal(BatchNewOrderMsgType, batchNew.Type())
+	assert.Equal(BatchCancelOrderMsgType, batchCancel.Type())
+
+	calculator := BatchFeeCalculatorGen(&param.FixedFeeParams{MsgType: BatchNewOrderMsgType, Fee: 1e6, FeeFor: sdk.FeeForProposer})
+	assert.Equal(sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 2e6)}, sdk.FeeForProposer), calculator(batchNew))
+	assert.Equal(sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 3e6)}, sdk.FeeForProposer), calculator(batchCancel))
+	calculator = BatchFeeCalculatorGen(&param.FixedFeeParams{MsgType: BatchNewOrderMsgType, Fee: 0, FeeFor: sdk.FeeFree})
+	assert.Equal(sdk.FeeFree, calculator(batchNew).Type)
+}
+
+func TestCancelAllOrdersMsg_ValidateBasic(t *testing.T) {
+	assert := assert.New(t)
+	_, acct := testutils.PrivAndAddr()
+	assert.Nil(NewCancelAllOrdersMsg(acct, "", 0).ValidateBasic())
+	assert.Nil(NewCancelAllOrdersMsg(acct, "XYZ_BNB", Side.SELL).ValidateBasic())
+	assert.NotNil(NewCancelAllOrdersMsg(acct, "XYZ_BNB", 3).ValidateBasic())
+	assert.NotNil(NewCancelAllOrdersMsg(sdk.AccAddress{}, "", 0).ValidateBasic())
+}
+
+func TestAmendOrderMsg_ValidateBasic(t *testing.T) {
+	assert := assert.New(t)
+	_, acct := testutils.PrivAndAddr()
+	assert.Nil(NewAmendOrderMsg(acct, "XYZ-000_BNB", "order-1", 1e8, 1e8).ValidateBasic())
+	assert.NotNil(NewAmendOrderMsg(acct, "XYZ-000_BNB", "order1", 1e8, 1e8).ValidateBasic())
+	assert.NotNil(NewAmendOrderMsg(acct, "XYZ-000_BNB", "order-1", 0, 1e8).ValidateBasic())
+	assert.NotNil(NewAmendOrderMsg(acct, "XYZ-000_BNB", "order-1", 1e8, -1).ValidateBasic())
+	assert.NotNil(NewAmendOrderMsg(sdk.AccAddress{}, "XYZ-000_BNB", "order-1", 1e8, 1e8).ValidateBasic())
+	assert.NotNil(NewAmendOrderMsg(acct, "", "order-1", 1e8, 1e8).ValidateBasic())
+	assert.NotNil(NewAmendOrderMsg(acct, "XYZBNB", "order-1", 1e8, 1e8).ValidateBasic())
+	assert.NotNil(NewAmendOrderMsg(acct, "XYZ_B-NB", "order-1", 1e8, 1e8).ValidateBasic())
+}
+
 func TestGenerateOrderId(t *testing.T) {
 	viper.SetDefault(client.FlagSequence, "5")
 	viper.SetDefault(client.FlagChainID, "mychaindid")
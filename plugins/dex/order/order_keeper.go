@@ -24,6 +24,7 @@ type DexOrderKeeper interface {
 	addOrder(symbol string, info OrderInfo, isRecovery bool)
 	reloadOrder(symbol string, orderInfo *OrderInfo, height int64)
 	removeOrder(dexKeeper *DexKeeper, id string, symbol string) (ord me.OrderPart, err error)
+	amendOrder(symbol string, info OrderInfo, requeued bool)
 	orderExists(symbol, id string) (OrderInfo, bool)
 	getOpenOrders(pair string, addr sdk.AccAddress) []store.OpenOrder
 	getAllOrders() map[string]map[string]*OrderInfo
@@ -46,6 +47,7 @@ type DexOrderKeeper interface {
 	clearOrderChanges()
 	getOrderInfosForPub() OrderInfoForPublish
 	removeOrderInfosForPub(orderId string)
+	addOrderInfoForPub(info *OrderInfo)
 
 	support(pair string) bool
 	supportUpgradeVersion() bool
@@ -84,7 +86,7 @@ func NewBaseOrderKeeper(moduleName string) BaseOrderKeeper {
 
 func (kp *BaseOrderKeeper) addOrder(symbol string, info OrderInfo, isRecovery bool) {
 	if kp.collectOrderInfoForPublish {
-		change := OrderChange{info.Id, Ack, "", nil}
+		change := OrderChange{info.Id, Ack, "", nil, nil}
 		// deliberately not add this message to orderChanges
 		if !isRecovery {
 			kp.orderChanges = append(kp.orderChanges, change)
@@ -104,7 +106,7 @@ func (kp *BaseOrderKeeper) addRoundOrders(symbol string, info OrderInfo) {
 		newIds := make([]string, 0, 16)
 		kp.roundOrders[symbol] = append(newIds, info.Id)
 	}
-	if info.TimeInForce == TimeInForce.IOC {
+	if isImmediateTimeInForce(info.TimeInForce) {
 		kp.roundIOCOrders[symbol] = append(kp.roundIOCOrders[symbol], info.Id)
 	}
 }
@@ -128,7 +130,26 @@ func (kp *BaseOrderKeeper) removeOrder(dexKeeper *DexKeeper, id string, symbol s
 		return me.OrderPart{}, orderNotFound(symbol, id)
 	}
 	delete(kp.allOrders[symbol], id)
-	return eng.Book.RemoveOrder(id, ordMsg.Side, ordMsg.Price)
+	return eng.RemoveOrder(id, ordMsg.Side, ordMsg.Price)
+}
+
+// amendOrder updates the order info in place, which is shared with the order info for publish.
+// A requeued order is matched in this round as a new one.
+func (kp *BaseOrderKeeper) amendOrder(symbol string, info OrderInfo, requeued bool) {
+	order := kp.allOrders[symbol][info.Id]
+	*order = info
+	if kp.collectOrderInfoForPublish {
+		kp.orderInfosForPub[info.Id] = order
+	}
+	if !requeued {
+		return
+	}
+	for _, id := range kp.roundOrders[symbol] {
+		if id == info.Id {
+			return
+		}
+	}
+	kp.addRoundOrders(symbol, info)
 }
 
 func (kp *BaseOrderKeeper) deleteOrdersForPair(pair string) {
@@ -189,6 +210,10 @@ func (kp *BaseOrderKeeper) removeOrderInfosForPub(orderId string) {
 	delete(kp.orderInfosForPub, orderId)
 }
 
+func (kp *BaseOrderKeeper) addOrderInfoForPub(info *OrderInfo) {
+	kp.orderInfosForPub[info.Id] = info
+}
+
 func (kp *BaseOrderKeeper) getRoundOrdersForPair(pair string) []string {
 	return kp.roundOrders[pair]
 }
@@ -260,7 +285,7 @@ func (kp *BEP2OrderKeeper) reloadOrder(symbol string, orderInfo *OrderInfo, heig
 	kp.allOrders[symbol][orderInfo.Id] = orderInfo
 	if orderInfo.CreatedHeight == height {
 		kp.roundOrders[symbol] = append(kp.roundOrders[symbol], orderInfo.Id)
-		if orderInfo.TimeInForce == TimeInForce.IOC {
+		if isImmediateTimeInForce(orderInfo.TimeInForce) {
 			kp.roundIOCOrders[symbol] = append(kp.roundIOCOrders[symbol], orderInfo.Id)
 		}
 	}
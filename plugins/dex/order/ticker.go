@@ -0,0 +1,157 @@
+package order
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexutils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+const (
+	tickerWindow       = 24 * time.Hour
+	tickerBucketLength = time.Minute
+)
+
+type tickerBucket struct {
+	openTime    int64 // in milliseconds
+	open        int64
+	high        int64
+	low         int64
+	close       int64
+	volume      int64
+	quoteVolume int64
+	count       int64
+}
+
+// TickerStats keeps the trades of each trading pair in the rolling 24h window, aggregated into one-minute
+// buckets, so the window slides by minute. It's fed by the match of each symbol, which may run concurrently.
+// It's not a part of the state and starts empty when the node restarts.
+type TickerStats struct {
+	mtx      sync.RWMutex
+	buckets  map[string][]tickerBucket // symbol -> buckets, the oldest first
+	lastTime int64                     // the latest block time seen, in milliseconds
+}
+
+func NewTickerStats() *TickerStats {
+	return &TickerStats{
+		buckets: make(map[string][]tickerBucket),
+	}
+}
+
+// addTrades adds the trades of the symbol matched in the block at timestamp
+func (ts *TickerStats) addTrades(symbol string, timestamp int64, trades []me.Trade) {
+	if len(trades) == 0 {
+		return
+	}
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+
+	blockTime := timestamp / int64(time.Millisecond)
+	bucketMs := int64(tickerBucketLength / time.Millisecond)
+	openTime := blockTime - blockTime%bucketMs
+	buckets := ts.buckets[symbol]
+	if len(buckets) == 0 || buckets[len(buckets)-1].openTime != openTime {
+		buckets = append(buckets, tickerBucket{
+			openTime: openTime,
+			open:     trades[0].LastPx,
+			high:     trades[0].LastPx,
+			low:      trades[0].LastPx,
+		})
+	}
+	b := &buckets[len(buckets)-1]
+	for _, t := range trades {
+		if t.LastPx > b.high {
+			b.high = t.LastPx
+		}
+		if t.LastPx < b.low {
+			b.low = t.LastPx
+		}
+		b.close = t.LastPx
+		b.volume = safeAdd(b.volume, t.LastQty)
+		b.quoteVolume = safeAdd(b.quoteVolume, dexutils.CalBigNotionalInt64(t.LastPx, t.LastQty))
+		b.count++
+	}
+	ts.buckets[symbol] = pruneTickerBuckets(buckets, blockTime)
+}
+
+// advance slides the window of every symbol to the block at timestamp. It's called for every block, even if
+// there is no trade, so the trades slide out of the window once they are older than 24h.
+func (ts *TickerStats) advance(timestamp int64) {
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+
+	blockTime := timestamp / int64(time.Millisecond)
+	if blockTime <= ts.lastTime {
+		return
+	}
+	bucketMs := int64(tickerBucketLength / time.Millisecond)
+	minuteChanged := blockTime/bucketMs != ts.lastTime/bucketMs
+	ts.lastTime = blockTime
+	if !minuteChanged {
+		return
+	}
+	for symbol, buckets := range ts.buckets {
+		if buckets = pruneTickerBuckets(buckets, blockTime); len(buckets) == 0 {
+			delete(ts.buckets, symbol)
+		} else {
+			ts.buckets[symbol] = buckets
+		}
+	}
+}
+
+func safeAdd(a, b int64) int64 {
+	if c := a + b; c >= a {
+		return c
+	}
+	return math.MaxInt64
+}
+
+// pruneTickerBuckets drops the buckets out of the window ending at blockTime
+func pruneTickerBuckets(buckets []tickerBucket, blockTime int64) []tickerBucket {
+	start := 0
+	for start < len(buckets) && buckets[start].openTime <= blockTime-int64(tickerWindow/time.Millisecond) {
+		start++
+	}
+	if start == 0 {
+		return buckets
+	}
+	return append([]tickerBucket(nil), buckets[start:]...)
+}
+
+// Ticker returns the 24h statistics of the symbol till the latest block. The prices are all lastPrice if
+// there is no trade in the window. The best bid and ask are left to the caller.
+func (ts *TickerStats) Ticker(symbol string, lastPrice int64) store.Ticker {
+	ts.mtx.RLock()
+	defer ts.mtx.RUnlock()
+
+	ticker := store.Ticker{
+		Symbol:    symbol,
+		OpenTime:  ts.lastTime - int64(tickerWindow/time.Millisecond),
+		CloseTime: ts.lastTime,
+		Open:      utils.Fixed8(lastPrice),
+		High:      utils.Fixed8(lastPrice),
+		Low:       utils.Fixed8(lastPrice),
+		Close:     utils.Fixed8(lastPrice),
+	}
+	buckets := pruneTickerBuckets(ts.buckets[symbol], ts.lastTime)
+	for i, b := range buckets {
+		if i == 0 {
+			ticker.Open, ticker.High, ticker.Low = utils.Fixed8(b.open), utils.Fixed8(b.high), utils.Fixed8(b.low)
+		}
+		if utils.Fixed8(b.high) > ticker.High {
+			ticker.High = utils.Fixed8(b.high)
+		}
+		if utils.Fixed8(b.low) < ticker.Low {
+			ticker.Low = utils.Fixed8(b.low)
+		}
+		ticker.Close = utils.Fixed8(b.close)
+		ticker.Volume = utils.Fixed8(safeAdd(ticker.Volume.ToInt64(), b.volume))
+		ticker.QuoteVolume = utils.Fixed8(safeAdd(ticker.QuoteVolume.ToInt64(), b.quoteVolume))
+		ticker.Count += b.count
+	}
+	return ticker
+}
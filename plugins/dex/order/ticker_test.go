@@ -0,0 +1,56 @@
+package order
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+)
+
+func TestTickerStats_Ticker(t *testing.T) {
+	ts := NewTickerStats()
+	symbol := "XYZ-000_BNB"
+	hour := int64(time.Hour)
+
+	// no trade yet
+	ticker := ts.Ticker(symbol, 5e8)
+	require.Equal(t, utils.Fixed8(5e8), ticker.Open)
+	require.Equal(t, utils.Fixed8(5e8), ticker.Close)
+	require.Equal(t, int64(0), ticker.Count)
+
+	ts.addTrades(symbol, hour, []me.Trade{{LastPx: 2e8, LastQty: 1e8}, {LastPx: 3e8, LastQty: 1e8}})
+	ts.advance(hour)
+	ts.addTrades(symbol, 2*hour, []me.Trade{{LastPx: 1e8, LastQty: 2e8}})
+	ts.advance(2 * hour)
+	ticker = ts.Ticker(symbol, 1e8)
+	require.Equal(t, utils.Fixed8(2e8), ticker.Open)
+	require.Equal(t, utils.Fixed8(3e8), ticker.High)
+	require.Equal(t, utils.Fixed8(1e8), ticker.Low)
+	require.Equal(t, utils.Fixed8(1e8), ticker.Close)
+	require.Equal(t, utils.Fixed8(4e8), ticker.Volume)
+	require.Equal(t, utils.Fixed8(7e8), ticker.QuoteVolume)
+	require.Equal(t, int64(3), ticker.Count)
+	require.Equal(t, int64(2*time.Hour/time.Millisecond), ticker.CloseTime)
+
+	// the trades of the first hour slide out of the window
+	ts.addTrades("ABC-000_BNB", 25*hour, []me.Trade{{LastPx: 1e8, LastQty: 1e8}})
+	ts.advance(25 * hour)
+	ticker = ts.Ticker(symbol, 1e8)
+	require.Equal(t, utils.Fixed8(1e8), ticker.Open)
+	require.Equal(t, utils.Fixed8(1e8), ticker.High)
+	require.Equal(t, utils.Fixed8(2e8), ticker.Volume)
+	require.Equal(t, int64(1), ticker.Count)
+
+	// the window slides with the blocks without any trade
+	ts.advance(26*hour + int64(time.Minute))
+	ticker = ts.Ticker(symbol, 1e8)
+	require.Equal(t, int64(0), ticker.Count)
+	require.Equal(t, utils.Fixed8(0), ticker.Volume)
+	require.Equal(t, int64((26*time.Hour+time.Minute)/time.Millisecond), ticker.CloseTime)
+	_, ok := ts.buckets[symbol]
+	require.False(t, ok)
+	require.Len(t, ts.buckets["ABC-000_BNB"], 1)
+}
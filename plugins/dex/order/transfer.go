@@ -82,7 +82,13 @@ func TransferFromTrade(trade *me.Trade, symbol string, orderMap map[string]*Orde
 	origBuyPx := buyOrder.Price
 
 	quoteQty := utils.CalBigNotionalInt64(trade.LastPx, trade.LastQty)
-	unlock := utils.CalBigNotionalInt64(origBuyPx, trade.BuyCumQty) - utils.CalBigNotionalInt64(origBuyPx, trade.BuyCumQty-trade.LastQty)
+	var unlock int64
+	if buyOrder.OrderType == OrderType.MARKET {
+		// a market buy order locks the quote asset amount to spend, the left is unlocked when it's removed
+		unlock = quoteQty
+	} else {
+		unlock = utils.CalBigNotionalInt64(origBuyPx, trade.BuyCumQty) - utils.CalBigNotionalInt64(origBuyPx, trade.BuyCumQty-trade.LastQty)
+	}
 	return Transfer{
 			Oid:        trade.Sid,
 			eventType:  eventFilled,
@@ -113,13 +119,13 @@ func TransferFromTrade(trade *me.Trade, symbol string, orderMap map[string]*Orde
 func TransferFromExpired(ord me.OrderPart, ordMsg OrderInfo) Transfer {
 	var tranEventType transferEventType
 	if ord.CumQty != 0 {
-		if ordMsg.TimeInForce == TimeInForce.IOC {
+		if isImmediateTimeInForce(ordMsg.TimeInForce) {
 			tranEventType = eventIOCPartiallyExpire // IOC partially filled
 		} else {
 			tranEventType = eventPartiallyExpire
 		}
 	} else {
-		if ordMsg.TimeInForce == TimeInForce.IOC {
+		if isImmediateTimeInForce(ordMsg.TimeInForce) {
 			tranEventType = eventIOCFullyExpire
 		} else {
 			tranEventType = eventFullyExpire
@@ -152,7 +158,12 @@ func transferFromOrderRemoved(ord me.OrderPart, ordMsg OrderInfo, tranEventType
 	var unlockAsset string
 	if ordMsg.Side == Side.BUY {
 		unlockAsset = quoteAsset
-		unlock = utils.CalBigNotionalInt64(ordMsg.Price, ordMsg.Quantity) - utils.CalBigNotionalInt64(ordMsg.Price, ordMsg.Quantity-qty)
+		if ordMsg.OrderType == OrderType.MARKET {
+			// the quantities of a market buy order are in the quote asset
+			unlock = qty
+		} else {
+			unlock = utils.CalBigNotionalInt64(ordMsg.Price, ordMsg.Quantity) - utils.CalBigNotionalInt64(ordMsg.Price, ordMsg.Quantity-qty)
+		}
 	} else {
 		unlockAsset = baseAsset
 		unlock = qty
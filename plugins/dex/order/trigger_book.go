@@ -0,0 +1,180 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// Conditional (stop-limit / take-profit) orders wait in the trigger book of their symbol and
+// do not take part in matching. Before each match round, the orders whose stop price has been
+// crossed by the last trade price are moved into the order book as normal limit orders.
+// Balances are locked when a conditional order is placed, so triggering never fails for
+// insufficient balance.
+
+// shouldTrigger returns true if the conditional order should be placed into the order book
+// according to the last trade price.
+func shouldTrigger(info *OrderInfo, lastTradePrice int64) bool {
+	switch info.OrderType {
+	case OrderType.STOP_LIMIT:
+		if info.Side == Side.BUY {
+			return lastTradePrice >= info.StopPrice
+		}
+		return lastTradePrice <= info.StopPrice
+	case OrderType.TAKE_PROFIT:
+		if info.Side == Side.BUY {
+			return lastTradePrice <= info.StopPrice
+		}
+		return lastTradePrice >= info.StopPrice
+	default:
+		return false
+	}
+}
+
+// triggerOrderPart builds the OrderPart used to generate transfers for a conditional order
+// that is removed before being triggered, i.e. nothing is filled.
+func triggerOrderPart(info *OrderInfo) me.OrderPart {
+	return me.OrderPart{Id: info.Id, Time: info.CreatedHeight, Qty: info.Quantity}
+}
+
+// sortedTriggerOrderIds returns the ids of the given orders sorted by placement, which makes
+// the processing of the trigger book deterministic.
+func sortedTriggerOrderIds(orders map[string]*OrderInfo, filter func(*OrderInfo) bool) []string {
+	ids := make([]string, 0)
+	for id, info := range orders {
+		if filter(info) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := orders[ids[i]], orders[ids[j]]
+		if a.CreatedHeight != b.CreatedHeight {
+			return a.CreatedHeight < b.CreatedHeight
+		}
+		return a.Id < b.Id
+	})
+	return ids
+}
+
+func (kp *DexKeeper) AddTriggerOrder(info OrderInfo, isRecovery bool) error {
+	symbol := strings.ToUpper(info.Symbol)
+	orders, ok := kp.triggerOrders[symbol]
+	if !ok {
+		return fmt.Errorf("match engine of symbol %s doesn't exist", symbol)
+	}
+	orders[info.Id] = &info
+
+	if kp.CollectOrderInfoForPublish {
+		orderKeeper := kp.mustGetOrderKeeper(symbol)
+		orderKeeper.addOrderInfoForPub(&info)
+		if !isRecovery {
+			orderKeeper.appendOrderChangeSync(OrderChange{info.Id, Ack, "", nil, nil})
+		}
+	}
+	if !isRecovery {
+		kp.wal.Write(TriggerOrderAddedWALMessage{Order: info})
+	}
+	kp.logger.Debug("Added trigger order", "symbol", symbol, "id", info.Id)
+	return nil
+}
+
+func (kp *DexKeeper) RemoveTriggerOrder(id string, symbol string, postCancelHandler func(ord me.OrderPart)) error {
+	symbol = strings.ToUpper(symbol)
+	info, ok := kp.triggerOrders[symbol][id]
+	if !ok {
+		return orderNotFound(symbol, id)
+	}
+	delete(kp.triggerOrders[symbol], id)
+	kp.wal.Write(TriggerOrderRemovedWALMessage{Symbol: symbol, Id: id})
+	if postCancelHandler != nil {
+		postCancelHandler(triggerOrderPart(info))
+	}
+	return nil
+}
+
+func (kp *DexKeeper) TriggerOrderExists(symbol, id string) (OrderInfo, bool) {
+	if info, ok := kp.triggerOrders[strings.ToUpper(symbol)][id]; ok {
+		return *info, true
+	}
+	return OrderInfo{}, false
+}
+
+func (kp *DexKeeper) GetTriggerOrders(pair string, addr sdk.AccAddress) []store.TriggerOrder {
+	orders := kp.triggerOrders[pair]
+	ids := sortedTriggerOrderIds(orders, func(info *OrderInfo) bool {
+		return string(info.Sender.Bytes()) == string(addr.Bytes())
+	})
+	triggerOrders := make([]store.TriggerOrder, 0, len(ids))
+	for _, id := range ids {
+		order := orders[id]
+		triggerOrders = append(triggerOrders, store.TriggerOrder{
+			Id:               order.Id,
+			Symbol:           pair,
+			OrderType:        order.OrderType,
+			Side:             order.Side,
+			StopPrice:        utils.Fixed8(order.StopPrice),
+			Price:            utils.Fixed8(order.Price),
+			Quantity:         utils.Fixed8(order.Quantity),
+			TimeInForce:      order.TimeInForce,
+			CreatedHeight:    order.CreatedHeight,
+			CreatedTimestamp: order.CreatedTimestamp,
+		})
+	}
+	return triggerOrders
+}
+
+// GetAllTriggerOrders returns symbol -> order ID -> conditional order
+func (kp *DexKeeper) GetAllTriggerOrders() map[string]map[string]*OrderInfo {
+	return kp.triggerOrders
+}
+
+// TriggerOrders moves the conditional orders whose stop price has been crossed by the last trade
+// price of their symbol into the order book. A triggered order is placed as a new order of this
+// height, so it is matched as a taker in this round. Triggering is not written to the WAL since
+// it is reproduced by matching during replay.
+func (kp *DexKeeper) TriggerOrders(height, timestamp int64, isRecovery bool) {
+	for symbol, orders := range kp.triggerOrders {
+		eng, ok := kp.engines[symbol]
+		if !ok || len(orders) == 0 {
+			continue
+		}
+		ids := sortedTriggerOrderIds(orders, func(info *OrderInfo) bool {
+			return shouldTrigger(info, eng.LastTradePrice)
+		})
+		for _, id := range ids {
+			order := *orders[id]
+			delete(orders, id)
+			order.CreatedHeight = height
+			order.CreatedTimestamp = timestamp
+			order.LastUpdatedHeight = height
+			order.LastUpdatedTimestamp = timestamp
+			if err := kp.AddOrder(order, true); err != nil {
+				kp.logger.Error("Failed to trigger order", "symbol", symbol, "id", id, "err", err)
+				continue
+			}
+			kp.logger.Debug("Triggered order", "symbol", symbol, "id", id, "lastTradePrice", eng.LastTradePrice)
+			if kp.CollectOrderInfoForPublish && !isRecovery {
+				kp.mustGetOrderKeeper(symbol).appendOrderChangeSync(OrderChange{id, Triggered, "", nil, nil})
+			}
+		}
+	}
+}
+
+// expireTriggerOrders removes the conditional orders of the symbol placed before expireHeight
+func (kp *DexKeeper) expireTriggerOrders(symbol string, expireHeight int64, onExpired func(Transfer)) {
+	orders := kp.triggerOrders[symbol]
+	ids := sortedTriggerOrderIds(orders, func(info *OrderInfo) bool {
+		return info.CreatedHeight < expireHeight
+	})
+	for _, id := range ids {
+		info := orders[id]
+		delete(orders, id)
+		onExpired(TransferFromExpired(triggerOrderPart(info), *info))
+	}
+}
@@ -0,0 +1,148 @@
+package order
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
+
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/utils"
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+func TestShouldTrigger(t *testing.T) {
+	assert := assert.New(t)
+	stopBuy := &OrderInfo{NewOrderMsg: NewConditionalOrderMsg(nil, "1", OrderType.STOP_LIMIT, Side.BUY, "XYZ-000_BNB", 110, 111, 1)}
+	assert.False(shouldTrigger(stopBuy, 109))
+	assert.True(shouldTrigger(stopBuy, 110))
+	stopSell := &OrderInfo{NewOrderMsg: NewConditionalOrderMsg(nil, "2", OrderType.STOP_LIMIT, Side.SELL, "XYZ-000_BNB", 90, 89, 1)}
+	assert.False(shouldTrigger(stopSell, 91))
+	assert.True(shouldTrigger(stopSell, 90))
+	profitBuy := &OrderInfo{NewOrderMsg: NewConditionalOrderMsg(nil, "3", OrderType.TAKE_PROFIT, Side.BUY, "XYZ-000_BNB", 90, 90, 1)}
+	assert.False(shouldTrigger(profitBuy, 91))
+	assert.True(shouldTrigger(profitBuy, 89))
+	profitSell := &OrderInfo{NewOrderMsg: NewConditionalOrderMsg(nil, "4", OrderType.TAKE_PROFIT, Side.SELL, "XYZ-000_BNB", 110, 110, 1)}
+	assert.False(shouldTrigger(profitSell, 109))
+	assert.True(shouldTrigger(profitSell, 111))
+	limit := &OrderInfo{NewOrderMsg: NewNewOrderMsg(nil, "5", Side.BUY, "XYZ-000_BNB", 100, 1)}
+	assert.False(shouldTrigger(limit, 100))
+}
+
+func TestKeeper_TriggerOrders(t *testing.T) {
+	assert := assert.New(t)
+	keeper := MakeKeeper(MakeCodec())
+	ctx := sdk.NewContext(MakeCMS(nil), abci.Header{}, sdk.RunTxModeCheck, log.NewNopLogger())
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	msg := NewConditionalOrderMsg(accAdd, "1", OrderType.STOP_LIMIT, Side.BUY, "XYZ-000_BNB", 1.1e8, 1.12e8, 1e8)
+	require.Nil(t, keeper.AddTriggerOrder(OrderInfo{msg, 10, 100, 10, 100, 0, "", 0}, false))
+	msg = NewConditionalOrderMsg(accAdd, "2", OrderType.STOP_LIMIT, Side.SELL, "XYZ-000_BNB", 0.9e8, 0.89e8, 1e8)
+	require.Nil(t, keeper.AddTriggerOrder(OrderInfo{msg, 10, 100, 10, 100, 0, "", 0}, false))
+	msg = NewConditionalOrderMsg(accAdd, "3", OrderType.TAKE_PROFIT, Side.SELL, "XYZ-000_BNB", 1.05e8, 1.2e8, 1e8)
+	require.Nil(t, keeper.AddTriggerOrder(OrderInfo{msg, 11, 110, 11, 110, 0, "", 0}, false))
+
+	_, ok := keeper.TriggerOrderExists("XYZ-000_BNB", "1")
+	assert.True(ok)
+	_, ok = keeper.OrderExists("XYZ-000_BNB", "1")
+	assert.False(ok)
+	assert.Len(keeper.GetTriggerOrders("XYZ-000_BNB", accAdd), 3)
+
+	// nothing is triggered at the list price
+	keeper.MatchSymbols(12, 120, false)
+	assert.Len(keeper.GetAllOrdersForPair("XYZ-000_BNB"), 0)
+
+	keeper.engines["XYZ-000_BNB"].LastTradePrice = 1.1e8
+	keeper.MatchSymbols(13, 130, false)
+	orders := keeper.GetAllOrdersForPair("XYZ-000_BNB")
+	require.Len(t, orders, 2)
+	assert.Equal(int64(13), orders["1"].CreatedHeight)
+	assert.Equal(int64(130), orders["1"].CreatedTimestamp)
+	assert.Equal(OrderType.STOP_LIMIT, orders["1"].OrderType)
+	assert.Equal(int64(13), orders["3"].CreatedHeight)
+	buys, sells := keeper.engines["XYZ-000_BNB"].Book.GetAllLevels()
+	require.Len(t, buys, 1)
+	require.Len(t, sells, 1)
+	assert.Equal(int64(1.12e8), buys[0].Price)
+	assert.Equal(int64(1.2e8), sells[0].Price)
+
+	triggerOrders := keeper.GetTriggerOrders("XYZ-000_BNB", accAdd)
+	require.Len(t, triggerOrders, 1)
+	assert.Equal("2", triggerOrders[0].Id)
+	assert.Equal(utils.Fixed8(0.9e8), triggerOrders[0].StopPrice)
+
+	require.Nil(t, keeper.RemoveTriggerOrder("2", "XYZ-000_BNB", nil))
+	assert.Len(keeper.GetTriggerOrders("XYZ-000_BNB", accAdd), 0)
+	assert.NotNil(keeper.RemoveTriggerOrder("2", "XYZ-000_BNB", nil))
+}
+
+func TestKeeper_SnapShotTriggerOrders(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	ctx := sdk.NewContext(MakeCMS(nil), abci.Header{}, sdk.RunTxModeCheck, log.NewNopLogger())
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	// no trigger order, nothing is saved for the trigger book
+	keys, err := keeper.SnapShotOrderBook(ctx, 43)
+	require.Nil(t, err)
+	assert.NotContains(keys, genTriggerOrdersSnapshotKey(43))
+
+	msg := NewConditionalOrderMsg(accAdd, "1", OrderType.STOP_LIMIT, Side.BUY, "XYZ-000_BNB", 1.1e8, 1.12e8, 1e8)
+	info := OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}
+	require.Nil(t, keeper.AddTriggerOrder(info, false))
+	keys, err = keeper.SnapShotOrderBook(ctx, 43)
+	require.Nil(t, err)
+	assert.Contains(keys, genTriggerOrdersSnapshotKey(43))
+	keeper.MarkBreatheBlock(ctx, 43, time.Now())
+
+	keeper2 := MakeKeeper(cdc)
+	h, err := keeper2.LoadOrderBookSnapshot(ctx, 43, utils.Now(), 0, 10)
+	require.Nil(t, err)
+	assert.Equal(int64(43), h)
+	reloaded, ok := keeper2.TriggerOrderExists("XYZ-000_BNB", "1")
+	assert.True(ok)
+	assert.Equal(info, reloaded)
+	assert.Len(keeper2.GetAllOrdersForPair("XYZ-000_BNB"), 0)
+}
+
+func TestKeeper_ExpireTriggerOrders(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	addr := acc.GetAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e6))
+	msg := NewConditionalOrderMsg(addr, "1", OrderType.STOP_LIMIT, Side.BUY, "ABC-000_BNB", 2e6, 2e6, 2e6)
+	require.Nil(t, keeper.AddTriggerOrder(OrderInfo{msg, 10000, 0, 10000, 0, 0, "", 0}, false))
+	msg = NewConditionalOrderMsg(addr, "2", OrderType.STOP_LIMIT, Side.BUY, "ABC-000_BNB", 2e6, 2e6, 2e6)
+	require.Nil(t, keeper.AddTriggerOrder(OrderInfo{msg, 20000, 0, 20000, 0, 0, "", 0}, false))
+	acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 8e4)})
+	am.SetAccount(ctx, acc)
+
+	breathTime, _ := time.Parse(time.RFC3339, "2018-01-02T00:00:01Z")
+	keeper.MarkBreatheBlock(ctx, 15000, breathTime)
+	keeper.ExpireOrders(ctx, breathTime.AddDate(0, 0, 3), nil)
+
+	_, ok := keeper.TriggerOrderExists("ABC-000_BNB", "1")
+	require.False(t, ok)
+	_, ok = keeper.TriggerOrderExists("ABC-000_BNB", "2")
+	require.True(t, ok)
+	acc = am.GetAccount(ctx, acc.GetAddress())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 4e4)}, acc.(types.NamedAccount).GetLockedCoins())
+	require.Equal(t, sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 2e4)}, sdk.FeeForProposer), fees.Pool.BlockFees())
+	fees.Pool.Clear()
+}
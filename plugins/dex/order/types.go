@@ -11,15 +11,19 @@ import (
 type ChangeType uint8
 
 const (
-	Ack            ChangeType = iota // new order tx
-	Canceled                         // cancel order tx
-	Expired                          // expired for gte order
-	IocNoFill                        // ioc order is not filled expire
-	IocExpire                        // ioc order is partial filled expire
-	PartialFill                      // order is partial filled, derived from trade
-	FullyFill                        // order is fully filled, derived from trade
-	FailedBlocking                   // order tx is failed blocking, we only publish essential message
-	FailedMatching                   // order failed matching
+	Ack              ChangeType = iota // new order tx
+	Canceled                           // cancel order tx
+	Expired                            // expired for gte order
+	IocNoFill                          // ioc order is not filled expire
+	IocExpire                          // ioc order is partial filled expire
+	PartialFill                        // order is partial filled, derived from trade
+	FullyFill                          // order is fully filled, derived from trade
+	FailedBlocking                     // order tx is failed blocking, we only publish essential message
+	FailedMatching                     // order failed matching
+	Triggered                          // conditional order is triggered and placed into the order book
+	TriggerCanceled                    // conditional order is canceled before being triggered
+	PostOnlyCanceled                   // post-only order is canceled as it would be a taker
+	Amended                            // price and/or quantity of the order is amended
 )
 
 // True for should not remove order in these status from OrderInfoForPub
@@ -28,6 +32,8 @@ func (tpe ChangeType) IsOpen() bool {
 	// FailedBlocking tx doesn't effect OrderInfoForPub, should not be put into closedToPublish
 	return tpe == Ack ||
 		tpe == PartialFill ||
+		tpe == Triggered ||
+		tpe == Amended ||
 		tpe == FailedBlocking
 }
 
@@ -51,6 +57,14 @@ func (tpe ChangeType) String() string {
 		return "FailedBlocking"
 	case FailedMatching:
 		return "FailedMatching"
+	case Triggered:
+		return "Triggered"
+	case TriggerCanceled:
+		return "TriggerCanceled"
+	case PostOnlyCanceled:
+		return "PostOnlyCanceled"
+	case Amended:
+		return "Amended"
 	default:
 		return "Unknown"
 	}
@@ -75,7 +89,15 @@ type OrderChange struct {
 	Id             string
 	Tpe            ChangeType
 	SingleFee      string
-	MsgForFailedTx interface{} // pointer to NewOrderMsg or CancelOrderMsg
+	MsgForFailedTx interface{}     // pointer to NewOrderMsg or CancelOrderMsg
+	Amendment      *OrderAmendment // only for Amended
+}
+
+// OrderAmendment keeps the price and quantity of an order before it's amended,
+// so the publisher knows which price levels are changed.
+type OrderAmendment struct {
+	PrevPrice    int64
+	PrevQuantity int64
 }
 
 func (oc OrderChange) String() string {
@@ -1,36 +1,124 @@
 package order
 
 import (
+	"encoding/binary"
 	"fmt"
+	"hash/crc32"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 
 	amino "github.com/tendermint/go-amino"
-	cs "github.com/tendermint/tendermint/consensus"
 	cmn "github.com/tendermint/tendermint/libs/common"
-)
-
-type WALEncoder = cs.WALEncoder
-type WALDecoder = cs.WALDecoder
-type TimedWALMessage = cs.TimedWALMessage
 
-var NewWALEncoder = cs.NewWALEncoder
-var NewWALDeccoder = cs.NewWALDecoder
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
+)
 
 const (
 	// must be greater than 4K orders
-	// nolint:deadcode,unused,varcheck
 	maxMsgSizeBytes = 4 * 1024 * 1024 // 4MB
 	walFileName     = "orderbook_wal"
+	// number of WAL files kept on disk, the current one and the one based on the previous breathe block.
+	// the previous one is still needed if the node crashes between rotating and committing the breathe block.
+	walFilesToKeep = 2
 )
 
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+var walCdc = amino.NewCodec()
+
+func init() {
+	RegisterWALMessages(walCdc)
+}
+
 type WALMessage interface{}
 
 func RegisterWALMessages(cdc *amino.Codec) {
 	cdc.RegisterInterface((*WALMessage)(nil), nil)
+	cdc.RegisterConcrete(OrderAddedWALMessage{}, "dex/wal/OrderAdded", nil)
+	cdc.RegisterConcrete(OrderRemovedWALMessage{}, "dex/wal/OrderRemoved", nil)
+	cdc.RegisterConcrete(PairListedWALMessage{}, "dex/wal/PairListed", nil)
+	cdc.RegisterConcrete(MatchResultWALMessage{}, "dex/wal/MatchResult", nil)
+	cdc.RegisterConcrete(EndHeightWALMessage{}, "dex/wal/EndHeight", nil)
+	cdc.RegisterConcrete(RestartWALMessage{}, "dex/wal/Restart", nil)
+	cdc.RegisterConcrete(TriggerOrderAddedWALMessage{}, "dex/wal/TriggerOrderAdded", nil)
+	cdc.RegisterConcrete(TriggerOrderRemovedWALMessage{}, "dex/wal/TriggerOrderRemoved", nil)
+	cdc.RegisterConcrete(OrderAmendedWALMessage{}, "dex/wal/OrderAmended", nil)
+}
+
+// OrderAddedWALMessage is written when a new order is inserted into the order book during DeliverTx
+type OrderAddedWALMessage struct {
+	Order OrderInfo
+}
+
+// OrderRemovedWALMessage is written when an order is canceled during DeliverTx
+type OrderRemovedWALMessage struct {
+	Symbol string
+	Id     string
+}
+
+// OrderAmendedWALMessage is written when the price and/or quantity of an order is amended during DeliverTx
+type OrderAmendedWALMessage struct {
+	Symbol    string
+	Id        string
+	Price     int64
+	Quantity  int64
+	Height    int64
+	Timestamp int64
+}
+
+// TriggerOrderAddedWALMessage is written when a conditional order is inserted into the trigger book during DeliverTx.
+// Triggering is not written as it is replayed by matching.
+type TriggerOrderAddedWALMessage struct {
+	Order OrderInfo
+}
+
+// TriggerOrderRemovedWALMessage is written when a conditional order is canceled during DeliverTx
+type TriggerOrderRemovedWALMessage struct {
+	Symbol string
+	Id     string
+}
+
+// PairListedWALMessage is written when a new match engine is created for a listed trading pair
+type PairListedWALMessage struct {
+	Pair dexTypes.TradingPair
+}
+
+type SymbolMatchResult struct {
+	Symbol         string
+	LastTradePrice int64
+	TradeCount     int64
+}
+
+// MatchResultWALMessage is written after all the symbols of a block are matched,
+// the results are used to verify the order book rebuilt during replay.
+type MatchResultWALMessage struct {
+	Height          int64
+	Timestamp       int64
+	MatchAllSymbols bool
+	Results         []SymbolMatchResult
+}
+
+// EndHeightWALMessage marks all the changes of the height have been written
+type EndHeightWALMessage struct {
+	Height int64
+}
+
+// RestartWALMessage is written when the WAL is reopened after the node starts.
+// Any change written after the last committed height is abandoned.
+type RestartWALMessage struct {
+	Height int64
+}
+
+type TimedWALMessage struct {
+	Time time.Time  `json:"time"`
+	Msg  WALMessage `json:"msg"`
 }
 
 //--------------------------------------------------------
@@ -46,67 +134,70 @@ type WAL interface {
 	Wait()
 }
 
-// Write ahead logger writes order book snapshot and changes to disk after block execution.
-// Can be used for crash-recovery and deterministic replay
-
-// NOT USED YET
+var _ WAL = &orderbookWAL{}
 
+// Write ahead logger writes order book changes to disk during block execution.
+// Each file is based on the order book snapshot of one breathe block, so the order
+// book can be recovered by loading the snapshot and replaying the changes of the file.
 type orderbookWAL struct {
 	cmn.BaseService
 
+	dir  string
 	file *os.File
 
 	enc *WALEncoder
 }
 
-func locateLatestFile(dirPath string) (string, error) {
+func walFilePath(dirPath string, height int64) string {
+	return filepath.Join(dirPath, fmt.Sprintf("%s.%d", walFileName, height))
+}
+
+// listWALFiles returns the breathe block heights of all the WAL files in ascending order
+func listWALFiles(dirPath string) ([]int64, error) {
 	files, err := os.ReadDir(dirPath)
 	if err != nil {
-		return "", errors.Wrap(err, fmt.Sprintf("failed to list directory[%s] for WAL", dirPath))
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to list directory[%s] for WAL", dirPath))
 	}
-	latestName := ""
-	latestDate, _ := time.Parse("20060102", "20000101") //impossible old date
+	heights := make([]int64, 0, len(files))
 	for _, f := range files {
 		name := f.Name()
-		l := len(name)
-		if l < 9+len(walFileName) { //$walFileName.20060102
+		if !strings.HasPrefix(name, walFileName+".") {
 			continue
 		}
-		dateStr := name[l-8:]
-		d, err := time.Parse("20060102", dateStr)
+		height, err := strconv.ParseInt(name[len(walFileName)+1:], 10, 64)
 		if err != nil {
 			continue
 		}
-		if d.After(latestDate) {
-			latestName = name
-			latestDate = d
-		}
-	}
-	if latestName != "" { // locate one
-		return filepath.Join(dirPath, latestName), nil
-	} else {
-		return filepath.Join(dirPath, walFileName, time.Now().Format("20060102")), nil
+		heights = append(heights, height)
 	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, nil
 }
 
-func NewWAL(walPath string) (*orderbookWAL, error) {
-	dirPath := filepath.Dir(walPath)
-	err := cmn.EnsureDir(dirPath, 0700)
+// NewWAL opens the WAL file based on the snapshot of breathe block `height`.
+// The file is truncated to the end of the last complete message, so that a torn write
+// caused by a crash won't break the messages appended later.
+func NewWAL(walDir string, height int64) (*orderbookWAL, error) {
+	err := cmn.EnsureDir(walDir, 0700)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to ensure WAL directory is in place")
 	}
 
-	filePath, err := locateLatestFile(dirPath)
-
+	file, err := os.OpenFile(walFilePath(walDir, height), os.O_RDWR|os.O_CREATE, 0600)
 	if err != nil {
 		return nil, err
 	}
-
-	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
-	if err != nil {
+	validSize, _ := decodeAll(file)
+	if err = file.Truncate(validSize); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err = file.Seek(validSize, io.SeekStart); err != nil {
+		file.Close()
 		return nil, err
 	}
 	wal := &orderbookWAL{
+		dir:  walDir,
 		file: file,
 		enc:  NewWALEncoder(file),
 	}
@@ -114,12 +205,11 @@ func NewWAL(walPath string) (*orderbookWAL, error) {
 	return wal, nil
 }
 
-func (wal *orderbookWAL) Group() *os.File {
+func (wal *orderbookWAL) File() *os.File {
 	return wal.file
 }
 
 func (wal *orderbookWAL) OnStart() error {
-	//load existing order book.
 	return nil
 }
 
@@ -127,8 +217,36 @@ func (wal *orderbookWAL) OnStop() {
 	wal.file.Close()
 }
 
-// Write is called in newStep and for each receive on the
-// peerMsgQueue and the timeoutTicker.
+// Rotate switches to a new file based on the snapshot of breathe block `height`,
+// and removes the files which are too old to be replayed.
+func (wal *orderbookWAL) Rotate(height int64) error {
+	if wal == nil {
+		return nil
+	}
+	if err := wal.file.Sync(); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(walFilePath(wal.dir, height), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	wal.file.Close()
+	wal.file = file
+	wal.enc = NewWALEncoder(file)
+
+	heights, err := listWALFiles(wal.dir)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(heights)-walFilesToKeep; i++ {
+		if err := os.Remove(walFilePath(wal.dir, heights[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write appends the message to the WAL file.
 // NOTE: does not call fsync()
 func (wal *orderbookWAL) Write(msg WALMessage) {
 	if wal == nil {
@@ -137,12 +255,12 @@ func (wal *orderbookWAL) Write(msg WALMessage) {
 
 	// Write the wal message
 	if err := wal.enc.Encode(&TimedWALMessage{Time: time.Now(), Msg: msg}); err != nil {
-		panic(fmt.Sprintf("Error writing msg to consensus wal: %v \n\nMessage: %v", err, msg))
+		panic(fmt.Sprintf("Error writing msg to orderbook wal: %v \n\nMessage: %v", err, msg))
 	}
 }
 
-// WriteSync is called when we receive a msg from ourselves
-// so that we write to disk before sending signed messages.
+// WriteSync is called at the end of each height, so that all the order book changes
+// of the height are on disk before the block is committed.
 // NOTE: calls fsync()
 func (wal *orderbookWAL) WriteSync(msg WALMessage) {
 	if wal == nil {
@@ -151,12 +269,186 @@ func (wal *orderbookWAL) WriteSync(msg WALMessage) {
 
 	wal.Write(msg)
 	if err := wal.file.Sync(); err != nil {
-		panic(fmt.Sprintf("Error flushing consensus wal buf to file. Error: %v \n", err))
+		panic(fmt.Sprintf("Error flushing orderbook wal buf to file. Error: %v \n", err))
 	}
 }
 
-// WALSearchOptions are optional arguments to SearchForEndHeight.
+// WALSearchOptions are optional arguments to searchForHeights.
 type WALSearchOptions struct {
 	// IgnoreDataCorruptionErrors set to true will result in skipping data corruption errors.
 	IgnoreDataCorruptionErrors bool
 }
+
+// walHeight holds all the order book changes of one height
+type walHeight struct {
+	height int64
+	msgs   []WALMessage
+	match  *MatchResultWALMessage
+}
+
+// searchForHeights reads the WAL file based on the snapshot of breathe block `breatheHeight`
+// and returns the changes of every height in (breatheHeight, lastHeight].
+// An error is returned if the file is missing, corrupted or any height is not complete.
+func searchForHeights(walDir string, breatheHeight, lastHeight int64, options *WALSearchOptions) ([]walHeight, error) {
+	file, err := os.Open(walFilePath(walDir, breatheHeight))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	_, msgs, err := decodeAllMessages(file)
+	if err != nil && (options == nil || !options.IgnoreDataCorruptionErrors) {
+		return nil, err
+	}
+
+	completed := make([]walHeight, 0, lastHeight-breatheHeight)
+	pending := make([]WALMessage, 0)
+	var match *MatchResultWALMessage
+	for _, m := range msgs {
+		switch msg := m.Msg.(type) {
+		case RestartWALMessage:
+			// abandon the changes that have not been committed before the restart
+			pending = pending[:0]
+			match = nil
+			for len(completed) > 0 && completed[len(completed)-1].height > msg.Height {
+				completed = completed[:len(completed)-1]
+			}
+		case MatchResultWALMessage:
+			matchCp := msg
+			match = &matchCp
+		case EndHeightWALMessage:
+			if msg.Height > breatheHeight && msg.Height <= lastHeight {
+				completed = append(completed, walHeight{msg.Height, pending, match})
+			}
+			pending = make([]WALMessage, 0)
+			match = nil
+		default:
+			pending = append(pending, msg)
+		}
+	}
+
+	if int64(len(completed)) != lastHeight-breatheHeight {
+		return nil, fmt.Errorf("WAL covers %d heights, expected %d heights in (%d, %d]",
+			len(completed), lastHeight-breatheHeight, breatheHeight, lastHeight)
+	}
+	for i, h := range completed {
+		if h.height != breatheHeight+int64(i)+1 {
+			return nil, fmt.Errorf("height %d is missing in WAL", breatheHeight+int64(i)+1)
+		}
+	}
+	return completed, nil
+}
+
+func decodeAllMessages(rd io.Reader) (int64, []*TimedWALMessage, error) {
+	dec := NewWALDecoder(rd)
+	var validSize int64
+	msgs := make([]*TimedWALMessage, 0)
+	for {
+		msg, size, err := dec.Decode()
+		if err == io.EOF {
+			return validSize, msgs, nil
+		}
+		if err != nil {
+			return validSize, msgs, err
+		}
+		validSize += size
+		msgs = append(msgs, msg)
+	}
+}
+
+// decodeAll returns the size of the valid part of the file
+func decodeAll(rd io.Reader) (int64, error) {
+	validSize, _, err := decodeAllMessages(rd)
+	return validSize, err
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// A WALEncoder writes custom-encoded WAL messages to an output stream.
+// It shares the format of tendermint consensus WAL but uses the codec of order book WAL messages.
+//
+// Format: 4 bytes CRC sum + 4 bytes length + arbitrary-length value (go-amino encoded)
+type WALEncoder struct {
+	wr io.Writer
+}
+
+// NewWALEncoder returns a new encoder that writes to wr.
+func NewWALEncoder(wr io.Writer) *WALEncoder {
+	return &WALEncoder{wr}
+}
+
+// Encode writes the custom encoding of v to the stream.
+func (enc *WALEncoder) Encode(v *TimedWALMessage) error {
+	data := walCdc.MustMarshalBinaryBare(v)
+
+	crc := crc32.Checksum(data, crc32c)
+	length := uint32(len(data))
+	if length > maxMsgSizeBytes {
+		return fmt.Errorf("msg is too big: %d bytes, max: %d bytes", length, maxMsgSizeBytes)
+	}
+	totalLength := 8 + int(length)
+
+	msg := make([]byte, totalLength)
+	binary.BigEndian.PutUint32(msg[0:4], crc)
+	binary.BigEndian.PutUint32(msg[4:8], length)
+	copy(msg[8:], data)
+
+	_, err := enc.wr.Write(msg)
+	return err
+}
+
+// DataCorruptionError is an error that occurs if data on disk was corrupted.
+type DataCorruptionError struct {
+	cause error
+}
+
+func (e DataCorruptionError) Error() string {
+	return fmt.Sprintf("DataCorruptionError[%v]", e.cause)
+}
+
+// A WALDecoder reads and decodes custom-encoded WAL messages from an input stream.
+type WALDecoder struct {
+	rd io.Reader
+}
+
+// NewWALDecoder returns a new decoder that reads from rd.
+func NewWALDecoder(rd io.Reader) *WALDecoder {
+	return &WALDecoder{rd}
+}
+
+// Decode reads the next custom-encoded value from its reader and returns it with its encoded size.
+func (dec *WALDecoder) Decode() (*TimedWALMessage, int64, error) {
+	header := make([]byte, 8)
+	n, err := io.ReadFull(dec.rd, header)
+	if err == io.EOF {
+		return nil, 0, err
+	}
+	if err != nil {
+		return nil, 0, DataCorruptionError{fmt.Errorf("failed to read header: %v (read: %d)", err, n)}
+	}
+	crc := binary.BigEndian.Uint32(header[0:4])
+	length := binary.BigEndian.Uint32(header[4:8])
+	if length > maxMsgSizeBytes {
+		return nil, 0, DataCorruptionError{fmt.Errorf("length %d exceeded maximum possible value of %d bytes", length, maxMsgSizeBytes)}
+	}
+
+	data := make([]byte, length)
+	n, err = io.ReadFull(dec.rd, data)
+	if err != nil {
+		return nil, 0, DataCorruptionError{fmt.Errorf("failed to read data: %v (read: %d, wanted: %d)", err, n, length)}
+	}
+
+	// check checksum before decoding data
+	actualCRC := crc32.Checksum(data, crc32c)
+	if actualCRC != crc {
+		return nil, 0, DataCorruptionError{fmt.Errorf("checksums do not match: read: %v, actual: %v", crc, actualCRC)}
+	}
+
+	var res = new(TimedWALMessage)
+	err = walCdc.UnmarshalBinaryBare(data, res)
+	if err != nil {
+		return nil, 0, DataCorruptionError{fmt.Errorf("failed to decode data: %v", err)}
+	}
+
+	return res, int64(8 + length), nil
+}
@@ -0,0 +1,155 @@
+package order
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/utils"
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+func matchForWAL(keeper *DexKeeper, height int64) {
+	symbols := keeper.SelectSymbolsToMatch(height, false)
+	keeper.matchAndDistributeTrades(false, height, 0, symbols)
+	keeper.writeMatchResultToWAL(height, 0, false, symbols)
+	keeper.ClearAfterMatch(height)
+}
+
+func TestKeeper_ReplayOrdersFromWAL(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	walDir := t.TempDir()
+	keeper := MakeKeeper(cdc)
+	keeper.EnableOrderBookWAL(walDir)
+	keeper.openWAL(0, 0)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, logger)
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	msg := NewNewOrderMsg(accAdd, "123456", Side.BUY, "XYZ-000_BNB", 102000, 3000000)
+	keeper.AddOrder(OrderInfo{msg, 1, 0, 1, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "123457", Side.BUY, "XYZ-000_BNB", 10000, 1000000)
+	keeper.AddOrder(OrderInfo{msg, 1, 0, 1, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "123458", Side.SELL, "XYZ-000_BNB", 100000, 2000000)
+	keeper.AddOrder(OrderInfo{msg, 1, 0, 1, 0, 0, "", 0}, false)
+	matchForWAL(keeper, 1)
+
+	keeper.RemoveOrder("123457", "XYZ-000_BNB", nil)
+	msg = NewNewOrderMsg(accAdd, "123459", Side.SELL, "XYZ-000_BNB", 103000, 1000000)
+	keeper.AddOrder(OrderInfo{msg, 2, 0, 2, 0, 0, "", 0}, false)
+	matchForWAL(keeper, 2)
+
+	keeper2 := MakeKeeper(cdc)
+	keeper2.EnableOrderBookWAL(walDir)
+	h, err := keeper2.LoadOrderBookSnapshot(ctx, 2, utils.Now(), 0, 10)
+	require.Nil(t, err)
+	assert.Equal(int64(0), h)
+	require.Nil(t, keeper2.ReplayOrdersFromWAL(ctx, 0, 2))
+
+	orders := keeper2.GetAllOrdersForPair("XYZ-000_BNB")
+	assert.Equal(2, len(orders))
+	assert.Equal(int64(2000000), orders["123456"].CumQty)
+	assert.Equal(int64(103000), orders["123459"].Price)
+	assert.Equal(keeper.engines["XYZ-000_BNB"].LastTradePrice, keeper2.engines["XYZ-000_BNB"].LastTradePrice)
+	buys, sells := keeper.engines["XYZ-000_BNB"].Book.GetAllLevels()
+	buys2, sells2 := keeper2.engines["XYZ-000_BNB"].Book.GetAllLevels()
+	assert.Equal(buys, buys2)
+	assert.Equal(sells, sells2)
+
+	// height 3 is not covered by WAL
+	assert.NotNil(keeper2.ReplayOrdersFromWAL(ctx, 0, 3))
+}
+
+func TestKeeper_ReplayOrdersFromWAL_Mismatch(t *testing.T) {
+	cdc := MakeCodec()
+	walDir := t.TempDir()
+	keeper := MakeKeeper(cdc)
+	keeper.EnableOrderBookWAL(walDir)
+	keeper.openWAL(0, 0)
+	cms := MakeCMS(nil)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, log.NewNopLogger())
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	msg := NewNewOrderMsg(accAdd, "123456", Side.BUY, "XYZ-000_BNB", 102000, 3000000)
+	keeper.AddOrder(OrderInfo{msg, 1, 0, 1, 0, 0, "", 0}, false)
+	matchForWAL(keeper, 1)
+	// height 2 claims a trade which can not be reproduced
+	keeper.wal.Write(MatchResultWALMessage{2, 0, false, []SymbolMatchResult{{"XYZ-000_BNB", 102000, 1}}})
+	keeper.ClearAfterMatch(2)
+
+	keeper2 := MakeKeeper(cdc)
+	keeper2.EnableOrderBookWAL(walDir)
+	_, err := keeper2.LoadOrderBookSnapshot(ctx, 2, utils.Now(), 0, 10)
+	require.Nil(t, err)
+	err = keeper2.ReplayOrdersFromWAL(ctx, 0, 2)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "mismatches with WAL")
+	// the order replayed from height 1 is discarded with the reset
+	require.Len(t, keeper2.GetAllOrdersForPair("XYZ-000_BNB"), 1)
+	keeper2.resetOrderBook()
+	_, err = keeper2.LoadOrderBookSnapshot(ctx, 2, utils.Now(), 0, 10)
+	require.Nil(t, err)
+	require.Len(t, keeper2.GetAllOrdersForPair("XYZ-000_BNB"), 0)
+}
+
+func TestWAL_AbandonUncommittedChanges(t *testing.T) {
+	walDir := t.TempDir()
+	keeper := MakeKeeper(MakeCodec())
+	keeper.EnableOrderBookWAL(walDir)
+	keeper.openWAL(0, 0)
+	cms := MakeCMS(nil)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, log.NewNopLogger())
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+	matchForWAL(keeper, 1)
+
+	// height 2 is written to WAL but the node crashes before committing it
+	msg := NewNewOrderMsg(accAdd, "123456", Side.BUY, "XYZ-000_BNB", 102000, 3000000)
+	keeper.AddOrder(OrderInfo{msg, 2, 0, 2, 0, 0, "", 0}, false)
+	matchForWAL(keeper, 2)
+	// and a torn write is left at the end of the file
+	_, err := keeper.wal.File().Write([]byte{1, 2, 3})
+	require.Nil(t, err)
+
+	// restart from height 1
+	keeper.openWAL(0, 1)
+	matchForWAL(keeper, 2)
+
+	heights, err := searchForHeights(walDir, 0, 2, nil)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(heights))
+	assert.Equal(t, int64(1), heights[0].height)
+	assert.Equal(t, int64(2), heights[1].height)
+	// the order added before restart is abandoned
+	assert.Equal(t, 0, len(heights[1].msgs))
+}
+
+func TestWAL_Rotate(t *testing.T) {
+	walDir := t.TempDir()
+	wal, err := NewWAL(walDir, 0)
+	require.Nil(t, err)
+	for _, h := range []int64{10, 20, 30} {
+		wal.WriteSync(EndHeightWALMessage{Height: h})
+		require.Nil(t, wal.Rotate(h))
+	}
+	heights, err := listWALFiles(walDir)
+	require.Nil(t, err)
+	assert.Equal(t, []int64{20, 30}, heights)
+}
@@ -69,3 +69,156 @@ func GetOpenOrders(cdc *wire.Codec, ctx context.CLIContext, pair string, addr st
 		return openOrders, err
 	}
 }
+
+func queryTriggerOrders(cdc *wire.Codec, ctx context.CLIContext, pair string, addr string) (*[]byte, error) {
+	path := fmt.Sprintf("dex/triggerorders/%s/%s", pair, addr)
+	if bz, err := ctx.Query(path, nil); err != nil {
+		return nil, err
+	} else {
+		return &bz, nil
+	}
+}
+
+func DecodeTriggerOrders(cdc *wire.Codec, bz *[]byte) ([]TriggerOrder, error) {
+	triggerOrders := make([]TriggerOrder, 0)
+	if err := cdc.UnmarshalBinaryLengthPrefixed(*bz, &triggerOrders); err != nil {
+		return nil, err
+	} else {
+		return triggerOrders, nil
+	}
+}
+
+func GetTriggerOrders(cdc *wire.Codec, ctx context.CLIContext, pair string, addr string) ([]TriggerOrder, error) {
+	if bz, err := queryTriggerOrders(cdc, ctx, pair, addr); err != nil {
+		return nil, err
+	} else if bz == nil {
+		return []TriggerOrder{}, nil
+	} else {
+		triggerOrders, err := DecodeTriggerOrders(cdc, bz)
+		return triggerOrders, err
+	}
+}
+
+func queryClosedOrders(cdc *wire.Codec, ctx context.CLIContext, addr string) (*[]byte, error) {
+	path := fmt.Sprintf("dex/orderhistory/%s", addr)
+	if bz, err := ctx.Query(path, nil); err != nil {
+		return nil, err
+	} else {
+		return &bz, nil
+	}
+}
+
+func DecodeClosedOrders(cdc *wire.Codec, bz *[]byte) ([]ClosedOrder, error) {
+	closedOrders := make([]ClosedOrder, 0)
+	if err := cdc.UnmarshalBinaryLengthPrefixed(*bz, &closedOrders); err != nil {
+		return nil, err
+	} else {
+		return closedOrders, nil
+	}
+}
+
+// GetClosedOrders returns the recent closed orders of the address kept by the node, the latest first
+func GetClosedOrders(cdc *wire.Codec, ctx context.CLIContext, addr string) ([]ClosedOrder, error) {
+	if bz, err := queryClosedOrders(cdc, ctx, addr); err != nil {
+		return nil, err
+	} else if bz == nil || len(*bz) == 0 {
+		return []ClosedOrder{}, nil
+	} else {
+		closedOrders, err := DecodeClosedOrders(cdc, bz)
+		return closedOrders, err
+	}
+}
+
+func queryTrades(cdc *wire.Codec, ctx context.CLIContext, pair string, fromHeight int64) (*[]byte, error) {
+	path := fmt.Sprintf("dex/trades/%s/%d", pair, fromHeight)
+	if bz, err := ctx.Query(path, nil); err != nil {
+		return nil, err
+	} else {
+		return &bz, nil
+	}
+}
+
+func DecodeTrades(cdc *wire.Codec, bz *[]byte) ([]Trade, error) {
+	trades := make([]Trade, 0)
+	if err := cdc.UnmarshalBinaryLengthPrefixed(*bz, &trades); err != nil {
+		return nil, err
+	} else {
+		return trades, nil
+	}
+}
+
+// GetTrades returns the recent trades of the pair since fromHeight kept by the node, the latest first
+func GetTrades(cdc *wire.Codec, ctx context.CLIContext, pair string, fromHeight int64) ([]Trade, error) {
+	if bz, err := queryTrades(cdc, ctx, pair, fromHeight); err != nil {
+		return nil, err
+	} else if bz == nil || len(*bz) == 0 {
+		return []Trade{}, nil
+	} else {
+		trades, err := DecodeTrades(cdc, bz)
+		return trades, err
+	}
+}
+
+func queryKlines(cdc *wire.Codec, ctx context.CLIContext, pair, interval string, limit int) (*[]byte, error) {
+	path := fmt.Sprintf("dex/klines/%s/%s/%d", pair, interval, limit)
+	if bz, err := ctx.Query(path, nil); err != nil {
+		return nil, err
+	} else {
+		return &bz, nil
+	}
+}
+
+func DecodeKlines(cdc *wire.Codec, bz *[]byte) ([]Kline, error) {
+	klines := make([]Kline, 0)
+	if err := cdc.UnmarshalBinaryLengthPrefixed(*bz, &klines); err != nil {
+		return nil, err
+	} else {
+		return klines, nil
+	}
+}
+
+// GetKlines returns the latest klines of the pair in the interval kept by the node, the oldest first
+func GetKlines(cdc *wire.Codec, ctx context.CLIContext, pair, interval string, limit int) ([]Kline, error) {
+	if bz, err := queryKlines(cdc, ctx, pair, interval, limit); err != nil {
+		return nil, err
+	} else if bz == nil || len(*bz) == 0 {
+		return []Kline{}, nil
+	} else {
+		klines, err := DecodeKlines(cdc, bz)
+		return klines, err
+	}
+}
+
+func queryTickers(cdc *wire.Codec, ctx context.CLIContext, prefix, pair string) (*[]byte, error) {
+	path := fmt.Sprintf("%s/ticker", prefix)
+	if pair != "" {
+		path = fmt.Sprintf("%s/%s", path, pair)
+	}
+	if bz, err := ctx.Query(path, nil); err != nil {
+		return nil, err
+	} else {
+		return &bz, nil
+	}
+}
+
+func DecodeTickers(cdc *wire.Codec, bz *[]byte) ([]Ticker, error) {
+	tickers := make([]Ticker, 0)
+	if err := cdc.UnmarshalBinaryLengthPrefixed(*bz, &tickers); err != nil {
+		return nil, err
+	} else {
+		return tickers, nil
+	}
+}
+
+// GetTickers returns the 24h tickers of the pair, or of all the pairs under the query prefix ("dex" or
+// "dex-mini") if pair is empty
+func GetTickers(cdc *wire.Codec, ctx context.CLIContext, prefix, pair string) ([]Ticker, error) {
+	if bz, err := queryTickers(cdc, ctx, prefix, pair); err != nil {
+		return nil, err
+	} else if bz == nil || len(*bz) == 0 {
+		return []Ticker{}, nil
+	} else {
+		tickers, err := DecodeTickers(cdc, bz)
+		return tickers, err
+	}
+}
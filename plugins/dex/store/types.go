@@ -31,6 +31,85 @@ type OpenOrder struct {
 	LastUpdatedTimestamp int64        `json:"lastUpdatedTimestamp"`
 }
 
+// TriggerOrder is a conditional order waiting in the trigger book
+type TriggerOrder struct {
+	Id               string       `json:"id"`
+	Symbol           string       `json:"symbol"`
+	OrderType        int8         `json:"orderType"`
+	Side             int8         `json:"side"`
+	StopPrice        utils.Fixed8 `json:"stopPrice"`
+	Price            utils.Fixed8 `json:"price"`
+	Quantity         utils.Fixed8 `json:"quantity"`
+	TimeInForce      int8         `json:"timeInForce"`
+	CreatedHeight    int64        `json:"createdHeight"`
+	CreatedTimestamp int64        `json:"createdTimestamp"`
+}
+
+// ClosedOrder is an order that is fully filled, canceled or expired
+type ClosedOrder struct {
+	Id               string       `json:"id"`
+	Symbol           string       `json:"symbol"`
+	Side             int8         `json:"side"`
+	OrderType        int8         `json:"orderType"`
+	Price            utils.Fixed8 `json:"price"`
+	Quantity         utils.Fixed8 `json:"quantity"`
+	CumQty           utils.Fixed8 `json:"cumQty"`
+	Status           string       `json:"status"`
+	CreatedHeight    int64        `json:"createdHeight"`
+	CreatedTimestamp int64        `json:"createdTimestamp"`
+	ClosedHeight     int64        `json:"closedHeight"`
+	ClosedTimestamp  int64        `json:"closedTimestamp"`
+}
+
+// Trade is a fill between a buy order and a sell order of a trading pair
+type Trade struct {
+	Id            string       `json:"id"`
+	Symbol        string       `json:"symbol"`
+	Price         utils.Fixed8 `json:"price"`
+	Quantity      utils.Fixed8 `json:"quantity"`
+	BuyerOrderId  string       `json:"buyerOrderId"`
+	SellerOrderId string       `json:"sellerOrderId"`
+	Buyer         string       `json:"buyer"`
+	Seller        string       `json:"seller"`
+	TickType      int8         `json:"tickType"`
+	Height        int64        `json:"height"`
+	Timestamp     int64        `json:"timestamp"`
+}
+
+// Kline is the OHLCV candlestick of a trading pair in an interval. Times are in milliseconds.
+type Kline struct {
+	Symbol         string       `json:"symbol"`
+	Interval       string       `json:"interval"`
+	OpenTime       int64        `json:"openTime"`
+	CloseTime      int64        `json:"closeTime"`
+	Open           utils.Fixed8 `json:"open"`
+	High           utils.Fixed8 `json:"high"`
+	Low            utils.Fixed8 `json:"low"`
+	Close          utils.Fixed8 `json:"close"`
+	Volume         utils.Fixed8 `json:"volume"`
+	QuoteVolume    utils.Fixed8 `json:"quoteVolume"`
+	NumberOfTrades int64        `json:"numberOfTrades"`
+	Closed         bool         `json:"closed"`
+}
+
+// Ticker is the rolling 24h statistics of a trading pair, the times are in milliseconds
+type Ticker struct {
+	Symbol      string       `json:"symbol"`
+	OpenTime    int64        `json:"openTime"`
+	CloseTime   int64        `json:"closeTime"`
+	Open        utils.Fixed8 `json:"open"`
+	High        utils.Fixed8 `json:"high"`
+	Low         utils.Fixed8 `json:"low"`
+	Close       utils.Fixed8 `json:"close"`
+	Volume      utils.Fixed8 `json:"volume"`
+	QuoteVolume utils.Fixed8 `json:"quoteVolume"`
+	Count       int64        `json:"count"`
+	BidPrice    utils.Fixed8 `json:"bidPrice"`
+	BidQty      utils.Fixed8 `json:"bidQty"`
+	AskPrice    utils.Fixed8 `json:"askPrice"`
+	AskQty      utils.Fixed8 `json:"askQty"`
+}
+
 type RecentPrice struct {
 	Pair  []string
 	Price []int64
@@ -18,6 +18,7 @@ const (
 	CodeFailLocateOrderToCancel sdk.CodeType = 405
 	CodeDuplicatedOrder         sdk.CodeType = 406
 	CodeInvalidProposal         sdk.CodeType = 407
+	CodeFailAmendOrder          sdk.CodeType = 408
 )
 
 // ErrIncorrectDexOperation - Error returned upon an incorrect guess
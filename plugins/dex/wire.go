@@ -13,6 +13,10 @@ func RegisterWire(cdc *wire.Codec) {
 
 	cdc.RegisterConcrete(order.NewOrderMsg{}, "dex/NewOrder", nil)
 	cdc.RegisterConcrete(order.CancelOrderMsg{}, "dex/CancelOrder", nil)
+	cdc.RegisterConcrete(order.BatchNewOrderMsg{}, "dex/BatchNewOrder", nil)
+	cdc.RegisterConcrete(order.BatchCancelOrderMsg{}, "dex/BatchCancelOrder", nil)
+	cdc.RegisterConcrete(order.CancelAllOrdersMsg{}, "dex/CancelAllOrders", nil)
+	cdc.RegisterConcrete(order.AmendOrderMsg{}, "dex/AmendOrder", nil)
 
 	cdc.RegisterConcrete(types.ListMsg{}, "dex/ListMsg", nil)
 	cdc.RegisterConcrete(types.TradingPair{}, "dex/TradingPair", nil)
@@ -8,14 +8,22 @@ import (
 
 func AddCommands(cmd *cobra.Command, cdc *codec.Codec) {
 	ownerShipCmd := &cobra.Command{
-		Use:   "validator-ownership",
-		Short: "validator-ownership commands",
+		Use:     "validator-ownership",
+		Aliases: []string{"migrate"},
+		Short:   "validator-ownership commands",
 		Long: `validator-ownership commands is a tool to help BSC validator operator create a mapping signature to New Validator on BSC
 		# For example:
 		bnbcli validator-ownership sign-validator-ownership \
 		 --bsc-operator-address 0x45737bAf95D995a963ab3a7c9AC66fC7A63ad76E \
 		 --from bsc-operator \
-		 --chain-id Binance-Chain-Tigris`,
+		 --chain-id Binance-Chain-Tigris
+
+		# verify a signed ownership against the validators of a snapshot exported by bnbchaind export-fusion:
+		bnbcli migrate verify --request ./ownership.json --snapshot ./fusion --chain-id Binance-Chain-Tigris
+
+		# verify all the signed ownerships in a directory and generate the manifest of all the validators:
+		bnbcli migrate manifest --requests ./ownerships --snapshot ./fusion \
+		 --chain-id Binance-Chain-Tigris --output ./manifest.json`,
 	}
 
 	ownerShipCmd.AddCommand(
@@ -23,6 +31,10 @@ func AddCommands(cmd *cobra.Command, cdc *codec.Codec) {
 			SignValidatorOwnerShipCmd(cdc),
 		)...,
 	)
+	ownerShipCmd.AddCommand(
+		VerifyValidatorOwnerShipCmd(cdc),
+		OwnershipManifestCmd(cdc),
+	)
 
 	cmd.AddCommand(ownerShipCmd)
 }
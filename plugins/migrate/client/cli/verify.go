@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/bnb-chain/node/plugins/migrate"
+	tokenRecover "github.com/bnb-chain/node/plugins/recover"
+)
+
+const (
+	flagRequest  = "request"
+	flagRequests = "requests"
+	flagSnapshot = "snapshot"
+	flagOutput   = "output"
+
+	txJSONPrefix = "TX JSON: "
+)
+
+func VerifyValidatorOwnerShipCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "verify-validator-ownership",
+		Aliases: []string{"verify"},
+		Short:   "verify a signed validator ownership against the validator operators of an exported snapshot",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validators, _, err := tokenRecover.ReadSnapshotValidators(cdc, viper.GetString(flagSnapshot))
+			if err != nil {
+				return err
+			}
+			tx, err := readSignedRequest(cdc, viper.GetString(flagRequest))
+			if err != nil {
+				return err
+			}
+
+			claim, validator, err := migrate.VerifyValidatorOwnerShipOf(tx, viper.GetString(client.FlagChainID), validators)
+			if err != nil {
+				return err
+			}
+			bz, err := json.MarshalIndent(migrate.ValidatorOwnership{Validator: validator, Claim: claim}, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagRequest, "", "the file of the TX JSON printed by sign-validator-ownership")
+	cmd.Flags().String(flagSnapshot, "", "the directory of the snapshot exported by bnbchaind export-fusion")
+	cmd.Flags().String(client.FlagChainID, "", "chain id the request was signed with")
+	_ = cmd.MarkFlagRequired(flagRequest)
+	_ = cmd.MarkFlagRequired(flagSnapshot)
+	_ = cmd.MarkFlagRequired(client.FlagChainID)
+
+	return cmd
+}
+
+func OwnershipManifestCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "ownership-manifest",
+		Aliases: []string{"manifest"},
+		Short:   "verify all the signed validator ownerships in a directory and generate the manifest of all the validators",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validators, snapshotManifest, err := tokenRecover.ReadSnapshotValidators(cdc, viper.GetString(flagSnapshot))
+			if err != nil {
+				return err
+			}
+
+			files, err := filepath.Glob(filepath.Join(viper.GetString(flagRequests), "*.json"))
+			if err != nil {
+				return err
+			}
+			sort.Strings(files)
+			requests := make([]migrate.OwnershipRequest, 0, len(files))
+			for _, file := range files {
+				tx, err := readSignedRequest(cdc, file)
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %v", file, err)
+				}
+				requests = append(requests, migrate.OwnershipRequest{Source: filepath.Base(file), Tx: tx})
+			}
+
+			manifest := migrate.BuildOwnershipManifest(viper.GetString(client.FlagChainID), snapshotManifest.Height,
+				validators, requests)
+			bz, err := json.MarshalIndent(manifest, "", "  ")
+			if err != nil {
+				return err
+			}
+			if output := viper.GetString(flagOutput); output != "" {
+				if err = os.WriteFile(output, bz, 0644); err != nil {
+					return err
+				}
+			} else {
+				fmt.Println(string(bz))
+			}
+
+			claimed := 0
+			for _, validator := range manifest.Validators {
+				if validator.Claim != nil {
+					claimed++
+				}
+			}
+			fmt.Fprintf(os.Stderr, "validators: %d, claimed: %d, rejected requests: %d\n",
+				len(manifest.Validators), claimed, len(manifest.Rejected))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagRequests, "", "the directory of the TX JSON files printed by sign-validator-ownership")
+	cmd.Flags().String(flagSnapshot, "", "the directory of the snapshot exported by bnbchaind export-fusion")
+	cmd.Flags().String(flagOutput, "", "the file to write the manifest to, default to stdout")
+	cmd.Flags().String(client.FlagChainID, "", "chain id the requests were signed with")
+	_ = cmd.MarkFlagRequired(flagRequests)
+	_ = cmd.MarkFlagRequired(flagSnapshot)
+	_ = cmd.MarkFlagRequired(client.FlagChainID)
+
+	return cmd
+}
+
+// readSignedRequest reads the signed request from a file containing either the TX JSON only or the whole output
+// of sign-validator-ownership
+func readSignedRequest(cdc *codec.Codec, file string) (auth.StdTx, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return auth.StdTx{}, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(bz))
+	scanner.Buffer(make([]byte, 0, len(bz)), len(bz)+1)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); bytes.HasPrefix(line, []byte(txJSONPrefix)) {
+			bz = line[len(txJSONPrefix):]
+			break
+		}
+	}
+
+	var tx auth.StdTx
+	if err = cdc.UnmarshalJSON(bz, &tx); err != nil {
+		return auth.StdTx{}, err
+	}
+	return tx, nil
+}
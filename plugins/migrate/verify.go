@@ -0,0 +1,154 @@
+package migrate
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	tokenRecover "github.com/bnb-chain/node/plugins/recover"
+)
+
+// OwnershipClaim is a verified ValidatorOwnerShip signed by the operator of a validator, the fields are the same as
+// the ones printed by sign-validator-ownership so they can be submitted to BSC directly
+type OwnershipClaim struct {
+	BSCOperatorAddress string        `json:"bsc_operator_address"` // lower case hex
+	SignMessage        string        `json:"sign_message"`
+	SignMessageHash    hexutil.Bytes `json:"sign_message_hash"` // sha256 of the sign message
+	Signature          hexutil.Bytes `json:"signature"`
+	PubKey             hexutil.Bytes `json:"pub_key"` // compressed secp256k1 public key of the operator
+}
+
+// ValidatorOwnership is a validator with its ownership claim, the claim is nil if the operator hasn't signed one
+type ValidatorOwnership struct {
+	Validator tokenRecover.ValidatorEntry `json:"validator"`
+	Claim     *OwnershipClaim             `json:"claim"`
+}
+
+// RejectedRequest is a signed request which can't be verified against any validator
+type RejectedRequest struct {
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+}
+
+// OwnershipManifest is the ownership claims of all the validators of a state export
+type OwnershipManifest struct {
+	ChainID    string               `json:"chain_id"`
+	Height     int64                `json:"height"` // the height of the state export
+	Validators []ValidatorOwnership `json:"validators"`
+	Rejected   []RejectedRequest    `json:"rejected"`
+}
+
+// OwnershipRequest is a signed ValidatorOwnerShip with where it comes from, e.g. the file name
+type OwnershipRequest struct {
+	Source string
+	Tx     auth.StdTx
+}
+
+// VerifyValidatorOwnerShip verifies the signature of a signed ValidatorOwnerShip and returns the claim and the
+// address of the signer, which should be the operator of a validator
+func VerifyValidatorOwnerShip(tx auth.StdTx, chainID string) (*OwnershipClaim, sdk.ValAddress, error) {
+	msgs := tx.GetMsgs()
+	if len(msgs) != 1 {
+		return nil, nil, fmt.Errorf("expected 1 msg in the request, got %d", len(msgs))
+	}
+	msg, ok := msgs[0].(ValidatorOwnerShip)
+	if !ok {
+		return nil, nil, fmt.Errorf("expected ValidatorOwnerShip, got %T", msgs[0])
+	}
+	if msg.BSCOperatorAddress == (common.Address{}) {
+		return nil, nil, errors.New("the bsc operator address is empty")
+	}
+
+	sigs := tx.GetSignatures()
+	if len(sigs) != 1 {
+		return nil, nil, fmt.Errorf("expected 1 signature of the request, got %d", len(sigs))
+	}
+	sig := sigs[0]
+	pubKey, ok := sig.PubKey.(secp256k1.PubKeySecp256k1)
+	if !ok {
+		return nil, nil, fmt.Errorf("expected secp256k1 public key of the signer, got %T", sig.PubKey)
+	}
+	signBytes := auth.StdSignBytes(chainID, sig.AccountNumber, sig.Sequence, msgs, tx.Memo, tx.Source, tx.Data)
+	if !pubKey.VerifyBytes(signBytes, sig.Signature) {
+		return nil, nil, errors.New("invalid signature of the request")
+	}
+
+	hash := sha256.Sum256(signBytes)
+	return &OwnershipClaim{
+		BSCOperatorAddress: strings.ToLower(msg.BSCOperatorAddress.Hex()),
+		SignMessage:        string(signBytes),
+		SignMessageHash:    hash[:],
+		Signature:          sig.Signature,
+		PubKey:             hexutil.Bytes(pubKey),
+	}, sdk.ValAddress(pubKey.Address()), nil
+}
+
+// VerifyValidatorOwnerShipOf verifies the signed ValidatorOwnerShip and returns the validator whose operator signs it
+func VerifyValidatorOwnerShipOf(tx auth.StdTx, chainID string, validators []tokenRecover.ValidatorEntry) (
+	*OwnershipClaim, tokenRecover.ValidatorEntry, error) {
+	claim, operator, err := VerifyValidatorOwnerShip(tx, chainID)
+	if err != nil {
+		return nil, tokenRecover.ValidatorEntry{}, err
+	}
+	for _, validator := range validators {
+		if validator.OperatorAddr.Equals(operator) {
+			return claim, validator, nil
+		}
+	}
+	return nil, tokenRecover.ValidatorEntry{}, fmt.Errorf("the signer %s is not the operator of any validator", operator)
+}
+
+// BuildOwnershipManifest verifies the requests against the validators of a state export at the height, every
+// validator is listed in the manifest with its claim, and the requests failing the verification are rejected
+func BuildOwnershipManifest(chainID string, height int64, validators []tokenRecover.ValidatorEntry,
+	requests []OwnershipRequest) OwnershipManifest {
+	manifest := OwnershipManifest{
+		ChainID:    chainID,
+		Height:     height,
+		Validators: make([]ValidatorOwnership, 0, len(validators)),
+		Rejected:   make([]RejectedRequest, 0),
+	}
+	// the operator of a validator on BC and the one on a side chain may be the same
+	indexes := make(map[string][]int)
+	for i, validator := range validators {
+		manifest.Validators = append(manifest.Validators, ValidatorOwnership{Validator: validator})
+		indexes[string(validator.OperatorAddr)] = append(indexes[string(validator.OperatorAddr)], i)
+	}
+
+	for _, request := range requests {
+		claim, operator, err := VerifyValidatorOwnerShip(request.Tx, chainID)
+		if err != nil {
+			manifest.Rejected = append(manifest.Rejected, RejectedRequest{Source: request.Source, Reason: err.Error()})
+			continue
+		}
+		if len(indexes[string(operator)]) == 0 {
+			manifest.Rejected = append(manifest.Rejected, RejectedRequest{Source: request.Source,
+				Reason: fmt.Sprintf("the signer %s is not the operator of any validator", operator)})
+			continue
+		}
+		// the validators of the operator are claimed together, the claim is rejected if any of them is claimed
+		var existing *OwnershipClaim
+		for _, i := range indexes[string(operator)] {
+			if manifest.Validators[i].Claim != nil {
+				existing = manifest.Validators[i].Claim
+				break
+			}
+		}
+		if existing != nil {
+			manifest.Rejected = append(manifest.Rejected, RejectedRequest{Source: request.Source,
+				Reason: fmt.Sprintf("duplicated claim of %s, already claimed to %s", operator, existing.BSCOperatorAddress)})
+			continue
+		}
+		for _, i := range indexes[string(operator)] {
+			manifest.Validators[i].Claim = claim
+		}
+	}
+	return manifest
+}
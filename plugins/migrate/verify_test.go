@@ -0,0 +1,92 @@
+package migrate
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	tokenRecover "github.com/bnb-chain/node/plugins/recover"
+)
+
+const testChainID = "Binance-Chain-Tigris"
+
+func signOwnerShip(t *testing.T, privKey secp256k1.PrivKeySecp256k1, chainID string, bscOperator common.Address) auth.StdTx {
+	msgs := []sdk.Msg{NewValidatorOwnerShipMsg(bscOperator)}
+	sig, err := privKey.Sign(auth.StdSignBytes(chainID, 1, 2, msgs, "", 0, nil))
+	require.NoError(t, err)
+	return auth.NewStdTx(msgs, []auth.StdSignature{{
+		PubKey:        privKey.PubKey(),
+		Signature:     sig,
+		AccountNumber: 1,
+		Sequence:      2,
+	}}, "", 0, nil)
+}
+
+func TestVerifyValidatorOwnerShip(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	operator := sdk.ValAddress(privKey.PubKey().Address())
+	bscOperator := common.HexToAddress("0x45737bAf95D995a963ab3a7c9AC66fC7A63ad76E")
+	validators := []tokenRecover.ValidatorEntry{
+		{OperatorAddr: sdk.ValAddress(secp256k1.GenPrivKey().PubKey().Address()), Moniker: "other"},
+		{OperatorAddr: operator, Moniker: "owner", Status: sdk.Bonded},
+	}
+
+	tx := signOwnerShip(t, privKey, testChainID, bscOperator)
+	claim, validator, err := VerifyValidatorOwnerShipOf(tx, testChainID, validators)
+	require.NoError(t, err)
+	require.Equal(t, "owner", validator.Moniker)
+	require.Equal(t, "0x45737baf95d995a963ab3a7c9ac66fc7a63ad76e", claim.BSCOperatorAddress)
+	require.Equal(t, string(auth.StdSignBytes(testChainID, 1, 2, tx.GetMsgs(), "", 0, nil)), claim.SignMessage)
+	require.Len(t, claim.SignMessageHash, 32)
+	require.EqualValues(t, privKey.PubKey().(secp256k1.PubKeySecp256k1), claim.PubKey)
+
+	// signed for another chain
+	_, _, err = VerifyValidatorOwnerShipOf(tx, "Binance-Chain-Ganges", validators)
+	require.EqualError(t, err, "invalid signature of the request")
+
+	// signed by an account which is not an operator
+	_, _, err = VerifyValidatorOwnerShipOf(signOwnerShip(t, secp256k1.GenPrivKey(), testChainID, bscOperator),
+		testChainID, validators)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not the operator of any validator")
+
+	_, _, err = VerifyValidatorOwnerShip(signOwnerShip(t, privKey, testChainID, common.Address{}), testChainID)
+	require.EqualError(t, err, "the bsc operator address is empty")
+}
+
+func TestBuildOwnershipManifest(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	operator := sdk.ValAddress(privKey.PubKey().Address())
+	validators := []tokenRecover.ValidatorEntry{
+		{OperatorAddr: operator, Moniker: "bc"},
+		{OperatorAddr: sdk.ValAddress(secp256k1.GenPrivKey().PubKey().Address()), Moniker: "unclaimed"},
+		{OperatorAddr: operator, Moniker: "bsc", SideChainId: "bsc"},
+	}
+
+	requests := []OwnershipRequest{
+		{Source: "valid.json", Tx: signOwnerShip(t, privKey, testChainID, common.HexToAddress("0x01"))},
+		{Source: "duplicated.json", Tx: signOwnerShip(t, privKey, testChainID, common.HexToAddress("0x02"))},
+		{Source: "unknown.json", Tx: signOwnerShip(t, secp256k1.GenPrivKey(), testChainID, common.HexToAddress("0x03"))},
+		{Source: "wrong_chain.json", Tx: signOwnerShip(t, privKey, "Binance-Chain-Ganges", common.HexToAddress("0x04"))},
+	}
+
+	manifest := BuildOwnershipManifest(testChainID, 100, validators, requests)
+	require.Equal(t, testChainID, manifest.ChainID)
+	require.Equal(t, int64(100), manifest.Height)
+	require.Len(t, manifest.Validators, 3)
+	require.NotNil(t, manifest.Validators[0].Claim)
+	require.Equal(t, "0x0000000000000000000000000000000000000001", manifest.Validators[0].Claim.BSCOperatorAddress)
+	require.Nil(t, manifest.Validators[1].Claim)
+	require.Equal(t, manifest.Validators[0].Claim, manifest.Validators[2].Claim)
+
+	require.Len(t, manifest.Rejected, 3)
+	require.Equal(t, "duplicated.json", manifest.Rejected[0].Source)
+	require.Contains(t, manifest.Rejected[0].Reason, "duplicated claim")
+	require.Equal(t, "unknown.json", manifest.Rejected[1].Source)
+	require.Equal(t, "wrong_chain.json", manifest.Rejected[2].Source)
+	require.Equal(t, "invalid signature of the request", manifest.Rejected[2].Reason)
+}
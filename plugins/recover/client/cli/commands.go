@@ -18,6 +18,11 @@ func AddCommands(cmd *cobra.Command, cdc *codec.Codec) {
 		 --recipient 0x5b38da6a701c568545dcfcb03fcb875f56beddc4 \
 		 --from user1 \
 		 --chain-id Binance-Chain-Tigris
+
+		bnbcli recover proof \
+		 --address bnb1... \
+		 --symbol BNB \
+		 --snapshot ./fusion-export
 		`,
 	}
 
@@ -26,6 +31,10 @@ func AddCommands(cmd *cobra.Command, cdc *codec.Codec) {
 			SignTokenRecoverRequestCmd(cdc),
 		)...,
 	)
+	recoverCmd.AddCommand(
+		ProofCmd(cdc),
+		VerifyTokenRecoverRequestCmd(cdc),
+	)
 
 	cmd.AddCommand(recoverCmd)
 }
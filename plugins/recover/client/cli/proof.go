@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	airdrop "github.com/bnb-chain/node/plugins/recover"
+)
+
+const (
+	flagAddress  = "address"
+	flagSymbol   = "symbol"
+	flagSnapshot = "snapshot"
+	flagRequest  = "request"
+	flagProof    = "proof"
+	flagRoot     = "root"
+)
+
+func ProofCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proof",
+		Short: "get the merkle proof of the balance of a token owned by an address from an exported snapshot",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, err := sdk.AccAddressFromBech32(viper.GetString(flagAddress))
+			if err != nil {
+				return err
+			}
+			symbol := strings.ToUpper(viper.GetString(flagSymbol))
+			if symbol == "" {
+				return fmt.Errorf("--%s is required", flagSymbol)
+			}
+
+			leaves, _, err := airdrop.ReadSnapshotLeaves(cdc, viper.GetString(flagSnapshot))
+			if err != nil {
+				return err
+			}
+			proof, err := airdrop.NewMerkleProof(leaves, addr, symbol)
+			if err != nil {
+				return err
+			}
+
+			bz, err := json.MarshalIndent(proof, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagAddress, "", "owner address")
+	cmd.Flags().String(flagSymbol, "", "token symbol")
+	cmd.Flags().String(flagSnapshot, "", "the directory of the snapshot exported by bnbchaind export-fusion")
+	_ = cmd.MarkFlagRequired(flagAddress)
+	_ = cmd.MarkFlagRequired(flagSnapshot)
+
+	return cmd
+}
+
+func VerifyTokenRecoverRequestCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-token-recover-request",
+		Short: "verify a signed token recover request and the merkle proof of the requested balance offline",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := os.ReadFile(viper.GetString(flagRequest))
+			if err != nil {
+				return err
+			}
+			var tx auth.StdTx
+			if err = cdc.UnmarshalJSON(bz, &tx); err != nil {
+				return err
+			}
+
+			bz, err = os.ReadFile(viper.GetString(flagProof))
+			if err != nil {
+				return err
+			}
+			var proof airdrop.MerkleProof
+			if err = json.Unmarshal(bz, &proof); err != nil {
+				return err
+			}
+
+			root, err := trustedMerkleRoot(cdc)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(proof.Root, root) {
+				return fmt.Errorf("root 0x%x of the proof mismatches the trusted root 0x%x", proof.Root, root)
+			}
+
+			chainID := viper.GetString(client.FlagChainID)
+			if err = airdrop.VerifyTokenRecoverRequest(tx, chainID, root, proof.Path); err != nil {
+				return err
+			}
+			fmt.Println("the token recover request is valid")
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagRequest, "", "the file of the TX JSON printed by sign-token-recover-request")
+	cmd.Flags().String(flagProof, "", "the file of the merkle proof printed by proof")
+	cmd.Flags().String(client.FlagChainID, "", "chain id the request was signed with")
+	cmd.Flags().String(flagRoot, "", "the trusted hex encoded merkle root of the snapshot")
+	cmd.Flags().String(flagSnapshot, "", "the directory of the snapshot exported by bnbchaind export-fusion to read the trusted merkle root from, used if --root is not set")
+	_ = cmd.MarkFlagRequired(flagRequest)
+	_ = cmd.MarkFlagRequired(flagProof)
+	_ = cmd.MarkFlagRequired(client.FlagChainID)
+
+	return cmd
+}
+
+// trustedMerkleRoot returns the merkle root the proof is verified against, the root carried by the proof itself can
+// not be trusted
+func trustedMerkleRoot(cdc *codec.Codec) ([]byte, error) {
+	rootHex, dir := viper.GetString(flagRoot), viper.GetString(flagSnapshot)
+	switch {
+	case rootHex != "" && dir != "":
+		return nil, fmt.Errorf("only one of --%s and --%s can be set", flagRoot, flagSnapshot)
+	case rootHex == "" && dir == "":
+		return nil, fmt.Errorf("either --%s or --%s is required", flagRoot, flagSnapshot)
+	case dir != "":
+		manifest, err := airdrop.ReadSnapshotManifest(cdc, dir)
+		if err != nil {
+			return nil, err
+		}
+		rootHex = manifest.MerkleRoot
+	}
+
+	root, err := hex.DecodeString(strings.TrimPrefix(rootHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid merkle root %s: %v", rootHex, err)
+	}
+	if len(root) == 0 {
+		return nil, errors.New("the merkle root is empty")
+	}
+	return root, nil
+}
@@ -0,0 +1,147 @@
+package recover
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BalanceLeaf is the balance of a token owned by an address at the exported height, the token recovery on BSC proves
+// the inclusion of the leaf in the merkle tree of the snapshot
+type BalanceLeaf struct {
+	Address sdk.AccAddress `json:"address"`
+	Symbol  string         `json:"symbol"`
+	Amount  int64          `json:"amount"`
+}
+
+// Encode returns abi.encodePacked(address, bytes32(symbol), uint256(amount)), which is consistent with the sign data
+// of TokenRecoverRequest
+func (leaf BalanceLeaf) Encode() []byte {
+	var symbol [32]byte
+	copy(symbol[:], leaf.Symbol)
+
+	bz := make([]byte, 0, len(leaf.Address)+64)
+	bz = append(bz, leaf.Address...)
+	bz = append(bz, symbol[:]...)
+	bz = append(bz, big.NewInt(leaf.Amount).FillBytes(make([]byte, 32))...)
+	return bz
+}
+
+// Hash returns the keccak256 hash of the encoded leaf
+func (leaf BalanceLeaf) Hash() []byte {
+	return crypto.Keccak256(leaf.Encode())
+}
+
+func (leaf BalanceLeaf) String() string {
+	return fmt.Sprintf("%s:%s:%d", leaf.Address, leaf.Symbol, leaf.Amount)
+}
+
+type BalanceLeaves []BalanceLeaf
+
+func (l BalanceLeaves) Len() int {
+	return len(l)
+}
+func (l BalanceLeaves) Swap(i, j int) {
+	l[i], l[j] = l[j], l[i]
+}
+func (l BalanceLeaves) Less(i, j int) bool {
+	return lessLeaf(l[i], l[j])
+}
+
+func lessLeaf(a, b BalanceLeaf) bool {
+	if c := bytes.Compare(a.Address, b.Address); c != 0 {
+		return c < 0
+	}
+	return a.Symbol < b.Symbol
+}
+
+// hashPair hashes the two nodes in the sorted order so that a proof doesn't need to carry the position of the nodes,
+// which is the same as the MerkleProof library of OpenZeppelin
+func hashPair(a, b []byte) []byte {
+	if bytes.Compare(a, b) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256(a, b)
+}
+
+// buildMerkleLevels returns all the levels of the merkle tree from the leaf hashes to the root, the last node of a
+// level with an odd number of nodes is promoted to the next level
+func buildMerkleLevels(hashes [][]byte) [][][]byte {
+	levels := [][][]byte{hashes}
+	for level := hashes; len(level) > 1; {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+			} else {
+				next = append(next, hashPair(level[i], level[i+1]))
+			}
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return levels
+}
+
+// sortLeaves returns a copy of the leaves sorted by address and symbol, and the hashes of them
+func sortLeaves(leaves BalanceLeaves) (BalanceLeaves, [][]byte) {
+	sorted := make(BalanceLeaves, len(leaves))
+	copy(sorted, leaves)
+	sort.Sort(sorted)
+
+	hashes := make([][]byte, 0, len(sorted))
+	for _, leaf := range sorted {
+		hashes = append(hashes, leaf.Hash())
+	}
+	return sorted, hashes
+}
+
+// MerkleRoot returns the merkle root of the leaves which are sorted by address and symbol, the root of no leaves is
+// 32 zero bytes
+func MerkleRoot(leaves BalanceLeaves) []byte {
+	if len(leaves) == 0 {
+		return make([]byte, 32)
+	}
+	_, hashes := sortLeaves(leaves)
+	levels := buildMerkleLevels(hashes)
+	return levels[len(levels)-1][0]
+}
+
+// NewMerkleProof returns the proof of the balance of the symbol owned by the address
+func NewMerkleProof(leaves BalanceLeaves, addr sdk.AccAddress, symbol string) (*MerkleProof, error) {
+	sorted, hashes := sortLeaves(leaves)
+	target := BalanceLeaf{Address: addr, Symbol: symbol}
+	index := sort.Search(len(sorted), func(i int) bool {
+		return !lessLeaf(sorted[i], target)
+	})
+	if index == len(sorted) || !sorted[index].Address.Equals(addr) || sorted[index].Symbol != symbol {
+		return nil, fmt.Errorf("no balance of %s owned by %s in the snapshot", symbol, addr)
+	}
+
+	levels := buildMerkleLevels(hashes)
+	proof := &MerkleProof{
+		Leaf: sorted[index],
+		Root: levels[len(levels)-1][0],
+	}
+	for _, level := range levels[:len(levels)-1] {
+		// the promoted node has no sibling
+		if sibling := index ^ 1; sibling < len(level) {
+			proof.Path = append(proof.Path, level[sibling])
+		}
+		index /= 2
+	}
+	return proof, nil
+}
+
+// VerifyMerkleProof verifies the leaf is included in the merkle tree of the root by the path
+func VerifyMerkleProof(root []byte, leaf BalanceLeaf, path [][]byte) bool {
+	hash := leaf.Hash()
+	for _, sibling := range path {
+		hash = hashPair(hash, sibling)
+	}
+	return bytes.Equal(hash, root)
+}
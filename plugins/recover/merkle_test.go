@@ -0,0 +1,70 @@
+package recover
+
+import (
+	"encoding/hex"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func newLeaves() BalanceLeaves {
+	addr1, _ := sdk.AccAddressFromHex("5B38Da6a701c568545dCfcB03FcB875f56beddC4")
+	addr2, _ := sdk.AccAddressFromHex("AB8483F64d9C6d1EcF9b849Ae677dD3315835cb2")
+	return BalanceLeaves{
+		{Address: addr2, Symbol: "BNB", Amount: 100},
+		{Address: addr1, Symbol: "XYZ-000", Amount: 2e8},
+		{Address: addr1, Symbol: "BNB", Amount: 1e8},
+	}
+}
+
+func TestBalanceLeaf_Encode(t *testing.T) {
+	leaf := newLeaves()[0]
+	require.Equal(t, "ab8483f64d9c6d1ecf9b849ae677dd3315835cb2"+
+		"424e420000000000000000000000000000000000000000000000000000000000"+
+		"0000000000000000000000000000000000000000000000000000000000000064",
+		hex.EncodeToString(leaf.Encode()))
+	require.Equal(t, crypto.Keccak256(leaf.Encode()), leaf.Hash())
+}
+
+func TestMerkleRoot(t *testing.T) {
+	require.Equal(t, make([]byte, 32), MerkleRoot(nil))
+
+	leaves := newLeaves()
+	require.Equal(t, leaves[0].Hash(), MerkleRoot(leaves[:1]))
+
+	// the leaves are sorted by address and symbol, the odd node is promoted
+	expected := hashPair(hashPair(leaves[2].Hash(), leaves[1].Hash()), leaves[0].Hash())
+	require.Equal(t, expected, MerkleRoot(leaves))
+
+	// the order of the input doesn't matter
+	reversed := BalanceLeaves{leaves[2], leaves[1], leaves[0]}
+	require.Equal(t, expected, MerkleRoot(reversed))
+
+	leaves[0].Amount++
+	require.NotEqual(t, expected, MerkleRoot(leaves))
+}
+
+func TestNewMerkleProof(t *testing.T) {
+	leaves := newLeaves()
+	for n := 1; n <= len(leaves); n++ {
+		root := MerkleRoot(leaves[:n])
+		for _, leaf := range leaves[:n] {
+			proof, err := NewMerkleProof(leaves[:n], leaf.Address, leaf.Symbol)
+			require.NoError(t, err)
+			require.Equal(t, leaf, proof.Leaf)
+			require.Equal(t, root, proof.Root)
+			require.True(t, proof.Verify())
+
+			tampered := leaf
+			tampered.Amount++
+			require.False(t, VerifyMerkleProof(root, tampered, proof.Path))
+		}
+	}
+
+	_, err := NewMerkleProof(leaves, leaves[0].Address, "XYZ-000")
+	require.Error(t, err)
+	_, err = NewMerkleProof(nil, leaves[0].Address, "BNB")
+	require.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package recover
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// MerkleProof proves a balance leaf is included in the merkle tree of an exported snapshot
+type MerkleProof struct {
+	Leaf BalanceLeaf
+	Path [][]byte // the sibling hashes from the leaf to the root
+	Root []byte
+}
+
+// merkleProofJSON is the json form of MerkleProof, the bytes are 0x prefixed hex strings which can be passed to the
+// recovery contract on BSC directly
+type merkleProofJSON struct {
+	Leaf         BalanceLeaf     `json:"leaf"`
+	LeafEncoding hexutil.Bytes   `json:"leaf_encoding"`
+	LeafHash     hexutil.Bytes   `json:"leaf_hash"`
+	Path         []hexutil.Bytes `json:"path"`
+	Root         hexutil.Bytes   `json:"root"`
+}
+
+func (proof MerkleProof) MarshalJSON() ([]byte, error) {
+	out := merkleProofJSON{
+		Leaf:         proof.Leaf,
+		LeafEncoding: proof.Leaf.Encode(),
+		LeafHash:     proof.Leaf.Hash(),
+		Path:         make([]hexutil.Bytes, 0, len(proof.Path)),
+		Root:         proof.Root,
+	}
+	for _, hash := range proof.Path {
+		out.Path = append(out.Path, hash)
+	}
+	return json.Marshal(out)
+}
+
+func (proof *MerkleProof) UnmarshalJSON(bz []byte) error {
+	var in merkleProofJSON
+	if err := json.Unmarshal(bz, &in); err != nil {
+		return err
+	}
+	proof.Leaf = in.Leaf
+	proof.Root = in.Root
+	proof.Path = make([][]byte, 0, len(in.Path))
+	for _, hash := range in.Path {
+		proof.Path = append(proof.Path, hash)
+	}
+	return nil
+}
+
+// Verify verifies the leaf is included in the merkle tree of the root
+func (proof MerkleProof) Verify() bool {
+	return VerifyMerkleProof(proof.Root, proof.Leaf, proof.Path)
+}
+
+// VerifyTokenRecoverRequest verifies the signature of a signed TokenRecoverRequest and that the requested balance of
+// the signer is included in the merkle tree of the root by the path, without connecting to any node
+func VerifyTokenRecoverRequest(tx auth.StdTx, chainID string, root []byte, path [][]byte) error {
+	msgs := tx.GetMsgs()
+	if len(msgs) != 1 {
+		return fmt.Errorf("expected 1 msg in the request, got %d", len(msgs))
+	}
+	msg, ok := msgs[0].(TokenRecoverRequest)
+	if !ok {
+		return fmt.Errorf("expected TokenRecoverRequest, got %T", msgs[0])
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return errors.New(err.Error())
+	}
+	if msg.Amount > math.MaxInt64 {
+		return fmt.Errorf("amount %d of the request overflows", msg.Amount)
+	}
+
+	sigs := tx.GetSignatures()
+	if len(sigs) != 1 {
+		return fmt.Errorf("expected 1 signature of the request, got %d", len(sigs))
+	}
+	sig := sigs[0]
+	if sig.PubKey == nil {
+		return errors.New("the public key of the signer is missing")
+	}
+	signBytes := auth.StdSignBytes(chainID, sig.AccountNumber, sig.Sequence, msgs, tx.Memo, tx.Source, tx.Data)
+	if !sig.PubKey.VerifyBytes(signBytes, sig.Signature) {
+		return errors.New("invalid signature of the request")
+	}
+
+	leaf := BalanceLeaf{
+		Address: sdk.AccAddress(sig.PubKey.Address()),
+		Symbol:  msg.TokenSymbol,
+		Amount:  int64(msg.Amount),
+	}
+	if !VerifyMerkleProof(root, leaf, path) {
+		return fmt.Errorf("the balance %s is not included in the snapshot", leaf)
+	}
+	return nil
+}
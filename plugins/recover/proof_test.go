@@ -0,0 +1,60 @@
+package recover
+
+import (
+	"encoding/json"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+func signTokenRecoverRequest(t *testing.T, priv secp256k1.PrivKeySecp256k1, chainID string, msg TokenRecoverRequest) auth.StdTx {
+	msgs := []sdk.Msg{msg}
+	sig, err := priv.Sign(auth.StdSignBytes(chainID, 1, 0, msgs, "", 0, nil))
+	require.NoError(t, err)
+	return auth.NewStdTx(msgs, []auth.StdSignature{{PubKey: priv.PubKey(), Signature: sig, AccountNumber: 1}}, "", 0, nil)
+}
+
+func TestMerkleProof_JSON(t *testing.T) {
+	leaves := newLeaves()
+	proof, err := NewMerkleProof(leaves, leaves[1].Address, leaves[1].Symbol)
+	require.NoError(t, err)
+
+	bz, err := json.Marshal(proof)
+	require.NoError(t, err)
+	var decoded MerkleProof
+	require.NoError(t, json.Unmarshal(bz, &decoded))
+	require.Equal(t, *proof, decoded)
+	require.True(t, decoded.Verify())
+}
+
+func TestVerifyTokenRecoverRequest(t *testing.T) {
+	priv := secp256k1.GenPrivKey()
+	addr := sdk.AccAddress(priv.PubKey().Address())
+	leaves := append(newLeaves(), BalanceLeaf{Address: addr, Symbol: "BNB", Amount: 5e8})
+	proof, err := NewMerkleProof(leaves, addr, "BNB")
+	require.NoError(t, err)
+
+	recipient := "0x5b38da6a701c568545dcfcb03fcb875f56beddc4"
+	tx := signTokenRecoverRequest(t, priv, "test-chain", NewTokenRecoverRequestMsg("BNB", 5e8, recipient))
+	require.NoError(t, VerifyTokenRecoverRequest(tx, "test-chain", proof.Root, proof.Path))
+
+	// signed with another chain id
+	require.Error(t, VerifyTokenRecoverRequest(tx, "other-chain", proof.Root, proof.Path))
+
+	// the amount mismatches the snapshot
+	tx = signTokenRecoverRequest(t, priv, "test-chain", NewTokenRecoverRequestMsg("BNB", 6e8, recipient))
+	require.Error(t, VerifyTokenRecoverRequest(tx, "test-chain", proof.Root, proof.Path))
+
+	// the msg is modified after signing
+	tx = signTokenRecoverRequest(t, priv, "test-chain", NewTokenRecoverRequestMsg("BNB", 5e8, recipient))
+	tx.Msgs[0] = NewTokenRecoverRequestMsg("BNB", 5e8, "0xab8483f64d9c6d1ecf9b849ae677dd3315835cb2")
+	require.Error(t, VerifyTokenRecoverRequest(tx, "test-chain", proof.Root, proof.Path))
+
+	// the balance of another user
+	other := secp256k1.GenPrivKey()
+	tx = signTokenRecoverRequest(t, other, "test-chain", NewTokenRecoverRequestMsg("BNB", 5e8, recipient))
+	require.Error(t, VerifyTokenRecoverRequest(tx, "test-chain", proof.Root, proof.Path))
+}
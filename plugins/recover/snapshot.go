@@ -0,0 +1,239 @@
+package recover
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/tokens/swap"
+	"github.com/bnb-chain/node/plugins/tokens/timelock"
+	"github.com/bnb-chain/node/wire"
+)
+
+const (
+	SnapshotManifestFile     = "manifest.json"
+	DefaultSnapshotChunkSize = 10000
+
+	SnapshotSectionAccounts   = "accounts"
+	SnapshotSectionTokens     = "tokens"
+	SnapshotSectionMiniTokens = "mini_tokens"
+	SnapshotSectionTimeLocks  = "time_locks"
+	SnapshotSectionVestings   = "vestings"
+	SnapshotSectionSwaps      = "swaps"
+	SnapshotSectionValidators = "validators"
+	SnapshotSectionLeaves     = "leaves"
+)
+
+// AccountBalance is the balance of an account at the exported height
+type AccountBalance struct {
+	Address sdk.AccAddress `json:"address"`
+	Free    sdk.Coins      `json:"free"`
+	Frozen  sdk.Coins      `json:"frozen"`
+	Locked  sdk.Coins      `json:"locked"`
+	Flags   uint64         `json:"flags"`
+}
+
+// TimeLockEntry is a time lock record with the address it's kept under
+type TimeLockEntry struct {
+	Owner  sdk.AccAddress          `json:"owner"`
+	Record timelock.TimeLockRecord `json:"record"`
+}
+
+// SwapEntry is an open HTLT with its swap id
+type SwapEntry struct {
+	SwapID swap.SwapBytes  `json:"swap_id"`
+	Swap   swap.AtomicSwap `json:"swap"`
+}
+
+// ValidatorEntry is a validator of BC or a side chain, its operator signs the ownership claim of the validator on BSC
+type ValidatorEntry struct {
+	OperatorAddr sdk.ValAddress `json:"operator_address"`
+	Moniker      string         `json:"moniker"`
+	SideChainId  string         `json:"side_chain_id,omitempty"`
+	Status       sdk.BondStatus `json:"status"`
+	Jailed       bool           `json:"jailed"`
+}
+
+// FusionSnapshot is the full state exported at a height for the token recovery after BC fusion, every section is
+// sorted so that the exported files are deterministic
+type FusionSnapshot struct {
+	Height     int64
+	CommitHash []byte
+	Accounts   []AccountBalance
+	Tokens     []*types.Token
+	MiniTokens []*types.MiniToken
+	TimeLocks  []TimeLockEntry
+	Vestings   []timelock.VestingRecord
+	Swaps      []SwapEntry
+	Validators []ValidatorEntry
+	// Leaves are the balances of the users including the coins in the time locks, vestings and open swaps
+	Leaves BalanceLeaves
+}
+
+// SnapshotChunk describes a file of the exported snapshot
+type SnapshotChunk struct {
+	Section string `json:"section"`
+	File    string `json:"file"`
+	Entries int    `json:"entries"`
+	Hash    string `json:"hash"` // hex encoded sha256 of the file content
+}
+
+// SnapshotManifest is written to the manifest file of the exported snapshot
+type SnapshotManifest struct {
+	Height     int64           `json:"height"`
+	CommitHash string          `json:"commit_hash"` // hex encoded app hash of the exported height
+	MerkleRoot string          `json:"merkle_root"` // hex encoded merkle root of the balance leaves
+	Leaves     int             `json:"leaves"`
+	Chunks     []SnapshotChunk `json:"chunks"`
+}
+
+// WriteSnapshot writes every section of the snapshot into chunk files of at most chunkSize entries and the manifest
+// describing them to the directory
+func WriteSnapshot(cdc *wire.Codec, dir string, snapshot *FusionSnapshot, chunkSize int) (*SnapshotManifest, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size should be positive")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	manifest := &SnapshotManifest{
+		Height:     snapshot.Height,
+		CommitHash: hex.EncodeToString(snapshot.CommitHash),
+		MerkleRoot: hex.EncodeToString(MerkleRoot(snapshot.Leaves)),
+		Leaves:     len(snapshot.Leaves),
+	}
+
+	sections := []struct {
+		name    string
+		size    int
+		entries func(from, to int) interface{}
+	}{
+		{SnapshotSectionAccounts, len(snapshot.Accounts), func(from, to int) interface{} { return snapshot.Accounts[from:to] }},
+		{SnapshotSectionTokens, len(snapshot.Tokens), func(from, to int) interface{} { return snapshot.Tokens[from:to] }},
+		{SnapshotSectionMiniTokens, len(snapshot.MiniTokens), func(from, to int) interface{} { return snapshot.MiniTokens[from:to] }},
+		{SnapshotSectionTimeLocks, len(snapshot.TimeLocks), func(from, to int) interface{} { return snapshot.TimeLocks[from:to] }},
+		{SnapshotSectionVestings, len(snapshot.Vestings), func(from, to int) interface{} { return snapshot.Vestings[from:to] }},
+		{SnapshotSectionSwaps, len(snapshot.Swaps), func(from, to int) interface{} { return snapshot.Swaps[from:to] }},
+		{SnapshotSectionValidators, len(snapshot.Validators), func(from, to int) interface{} { return snapshot.Validators[from:to] }},
+		{SnapshotSectionLeaves, len(snapshot.Leaves), func(from, to int) interface{} { return snapshot.Leaves[from:to] }},
+	}
+	for _, section := range sections {
+		for from, i := 0, 0; from < section.size; from, i = from+chunkSize, i+1 {
+			to := from + chunkSize
+			if to > section.size {
+				to = section.size
+			}
+			chunk, err := writeSnapshotFile(cdc, dir, fmt.Sprintf("%s-%05d.json", section.name, i), section.entries(from, to))
+			if err != nil {
+				return nil, err
+			}
+			chunk.Section = section.name
+			chunk.Entries = to - from
+			manifest.Chunks = append(manifest.Chunks, chunk)
+		}
+	}
+
+	if _, err := writeSnapshotFile(cdc, dir, SnapshotManifestFile, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func writeSnapshotFile(cdc *wire.Codec, dir, name string, obj interface{}) (SnapshotChunk, error) {
+	bz, err := wire.MarshalJSONIndent(cdc, obj)
+	if err != nil {
+		return SnapshotChunk{}, err
+	}
+	if err = os.WriteFile(filepath.Join(dir, name), bz, 0644); err != nil {
+		return SnapshotChunk{}, err
+	}
+	hash := sha256.Sum256(bz)
+	return SnapshotChunk{File: name, Hash: hex.EncodeToString(hash[:])}, nil
+}
+
+// ReadSnapshotLeaves reads the balance leaves of the snapshot exported to the directory, the hashes of the chunk files
+// and the merkle root are checked against the manifest
+func ReadSnapshotLeaves(cdc *wire.Codec, dir string) (BalanceLeaves, *SnapshotManifest, error) {
+	var leaves BalanceLeaves
+	manifest, err := readSnapshotSection(cdc, dir, SnapshotSectionLeaves, func(bz []byte) error {
+		var entries BalanceLeaves
+		if err := cdc.UnmarshalJSON(bz, &entries); err != nil {
+			return err
+		}
+		leaves = append(leaves, entries...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(leaves) != manifest.Leaves {
+		return nil, nil, fmt.Errorf("expected %d leaves in the snapshot, got %d", manifest.Leaves, len(leaves))
+	}
+	if root := hex.EncodeToString(MerkleRoot(leaves)); root != manifest.MerkleRoot {
+		return nil, nil, fmt.Errorf("merkle root %s of the leaves mismatches the manifest %s", root, manifest.MerkleRoot)
+	}
+	return leaves, manifest, nil
+}
+
+// ReadSnapshotValidators reads the validators of the snapshot exported to the directory, the hashes of the chunk files
+// are checked against the manifest
+func ReadSnapshotValidators(cdc *wire.Codec, dir string) ([]ValidatorEntry, *SnapshotManifest, error) {
+	var validators []ValidatorEntry
+	manifest, err := readSnapshotSection(cdc, dir, SnapshotSectionValidators, func(bz []byte) error {
+		var entries []ValidatorEntry
+		if err := cdc.UnmarshalJSON(bz, &entries); err != nil {
+			return err
+		}
+		validators = append(validators, entries...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return validators, manifest, nil
+}
+
+// ReadSnapshotManifest reads the manifest of the snapshot exported to the directory
+func ReadSnapshotManifest(cdc *wire.Codec, dir string) (*SnapshotManifest, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, SnapshotManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var manifest SnapshotManifest
+	if err = cdc.UnmarshalJSON(bz, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// readSnapshotSection reads the manifest and passes the content of every chunk file of the section to decode in order
+func readSnapshotSection(cdc *wire.Codec, dir, section string, decode func(bz []byte) error) (*SnapshotManifest, error) {
+	manifest, err := ReadSnapshotManifest(cdc, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, chunk := range manifest.Chunks {
+		if chunk.Section != section {
+			continue
+		}
+		bz, err := os.ReadFile(filepath.Join(dir, chunk.File))
+		if err != nil {
+			return nil, err
+		}
+		if hash := sha256.Sum256(bz); hex.EncodeToString(hash[:]) != chunk.Hash {
+			return nil, fmt.Errorf("hash of %s mismatches the manifest", chunk.File)
+		}
+		if err = decode(bz); err != nil {
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
@@ -0,0 +1,92 @@
+package recover
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/wire"
+)
+
+func TestWriteSnapshot(t *testing.T) {
+	cdc := wire.NewCodec()
+	leaves := newLeaves()
+	token, err := types.NewToken("New BNB", "NNB-000", 1e8, leaves[0].Address, false)
+	require.NoError(t, err)
+	snapshot := &FusionSnapshot{
+		Height:     100,
+		CommitHash: []byte{1, 2, 3},
+		Accounts: []AccountBalance{
+			{Address: leaves[2].Address, Free: sdk.Coins{sdk.NewCoin("BNB", 1e8)}},
+			{Address: leaves[0].Address, Free: sdk.Coins{sdk.NewCoin("BNB", 100)}},
+		},
+		Tokens: []*types.Token{token},
+		Leaves: leaves,
+	}
+
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	manifest, err := WriteSnapshot(cdc, dir1, snapshot, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), manifest.Height)
+	require.Equal(t, "010203", manifest.CommitHash)
+	require.Equal(t, hex.EncodeToString(MerkleRoot(leaves)), manifest.MerkleRoot)
+	require.Equal(t, 3, manifest.Leaves)
+
+	// 1 chunk of accounts, 1 chunk of tokens and 2 chunks of leaves
+	require.Len(t, manifest.Chunks, 4)
+	require.Equal(t, SnapshotChunk{Section: SnapshotSectionLeaves, File: "leaves-00001.json", Entries: 1,
+		Hash: manifest.Chunks[3].Hash}, manifest.Chunks[3])
+
+	_, err = WriteSnapshot(cdc, dir2, snapshot, 2)
+	require.NoError(t, err)
+	for _, file := range []string{SnapshotManifestFile, "accounts-00000.json", "tokens-00000.json", "leaves-00000.json", "leaves-00001.json"} {
+		bz1, err := os.ReadFile(filepath.Join(dir1, file))
+		require.NoError(t, err)
+		bz2, err := os.ReadFile(filepath.Join(dir2, file))
+		require.NoError(t, err)
+		require.Equal(t, bz1, bz2, file)
+	}
+
+	_, err = WriteSnapshot(cdc, dir1, snapshot, 0)
+	require.Error(t, err)
+}
+
+func TestReadSnapshotManifest(t *testing.T) {
+	cdc := wire.NewCodec()
+	dir := t.TempDir()
+	manifest, err := WriteSnapshot(cdc, dir, &FusionSnapshot{Height: 100, Leaves: newLeaves()}, 2)
+	require.NoError(t, err)
+
+	read, err := ReadSnapshotManifest(cdc, dir)
+	require.NoError(t, err)
+	require.Equal(t, manifest, read)
+
+	_, err = ReadSnapshotManifest(cdc, t.TempDir())
+	require.Error(t, err)
+}
+
+func TestReadSnapshotLeaves(t *testing.T) {
+	cdc := wire.NewCodec()
+	leaves := newLeaves()
+	dir := t.TempDir()
+	manifest, err := WriteSnapshot(cdc, dir, &FusionSnapshot{Height: 100, Leaves: leaves}, 2)
+	require.NoError(t, err)
+
+	read, readManifest, err := ReadSnapshotLeaves(cdc, dir)
+	require.NoError(t, err)
+	require.Equal(t, leaves, read)
+	require.Equal(t, manifest, readManifest)
+
+	// a modified chunk file is rejected
+	file := filepath.Join(dir, "leaves-00001.json")
+	bz, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, append(bz, ' '), 0644))
+	_, _, err = ReadSnapshotLeaves(cdc, dir)
+	require.Error(t, err)
+}
@@ -12,7 +12,7 @@ import (
 	"github.com/bnb-chain/node/common/types"
 )
 
-func createAbciQueryHandler(mapper Mapper, prefix string) types.AbciQueryHandler {
+func createAbciQueryHandler(mapper Mapper, holderIndex *HolderIndex, prefix string) types.AbciQueryHandler {
 	queryPrefix := prefix
 	var isMini bool
 	switch queryPrefix {
@@ -116,6 +116,84 @@ func createAbciQueryHandler(mapper Mapper, prefix string) types.AbciQueryHandler
 				Code:  uint32(sdk.ABCICodeOK),
 				Value: bz,
 			}
+		case "holders": // args: ["tokens", "holders", <symbol>, <limit>(optional)]
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log: fmt.Sprintf(
+						"%s %s query requires a symbol path arg",
+						queryPrefix, path[1]),
+				}
+			}
+			if holderIndex == nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "token holders are not enabled on this node",
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			token, err := mapper.GetToken(ctx, path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			limit := holderIndex.MaxTop()
+			if len(path) > 3 {
+				limit, err = strconv.Atoi(path[3])
+				if err != nil || limit <= 0 {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeInternal),
+						Log:  "unable to parse limit",
+					}
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(holderIndex.Holders(token.GetSymbol(), limit))
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "metadata": // args: ["tokens", "metadata", <symbol>]
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log: fmt.Sprintf(
+						"%s %s query requires a symbol path arg",
+						queryPrefix, path[1]),
+				}
+			}
+			if isMini {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "metadata is only available for BEP2 tokens",
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			metadata, err := mapper.GetTokenMetadata(ctx, path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(metadata)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
 		default:
 			return &abci.ResponseQuery{
 				Code: uint32(sdk.ABCICodeOK),
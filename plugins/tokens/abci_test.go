@@ -14,11 +14,13 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	bca "github.com/bnb-chain/node/app"
+	"github.com/bnb-chain/node/common/testutils"
 	common "github.com/bnb-chain/node/common/types"
 )
 
 // util objects
 var (
+	home         = testutils.SetupTempHome() // has to be set before the app is created
 	db           = dbm.NewMemDB()
 	logger       = log.NewTMLogger(os.Stdout)
 	app          = bca.NewBNBBeaconChain(logger, db, os.Stdout)
@@ -30,6 +32,12 @@ var (
 	token2       = token2Ptr
 )
 
+func TestMain(m *testing.M) {
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
 func Test_Tokens_ABCI_GetInfo_Success(t *testing.T) {
 	path := "/tokens/info/XXX-000" // XXX created below
 
@@ -270,3 +278,69 @@ func Test_Tokens_ABCI_GetTokens_Error_InvalidLimit(t *testing.T) {
 
 	assert.False(t, sdk.ABCICodeType(res.Code).IsOK())
 }
+
+func Test_Tokens_ABCI_GetHolders_Error_NotEnabled(t *testing.T) {
+	path := "/tokens/holders/XXX-000"
+
+	ctx := app.NewContext(sdk.RunTxModeCheck, abci.Header{})
+	err := app.TokenMapper.NewToken(ctx, token1)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	query := abci.RequestQuery{
+		Path: path,
+		Data: []byte(""),
+	}
+	res := app.Query(query)
+
+	assert.False(t, sdk.ABCICodeType(res.Code).IsOK())
+	assert.Equal(t, "token holders are not enabled on this node", res.Log)
+}
+
+func Test_Tokens_ABCI_GetMetadata_Success(t *testing.T) {
+	path := "/tokens/metadata/XXX-000"
+
+	ctx := app.NewContext(sdk.RunTxModeCheck, abci.Header{})
+	err := app.TokenMapper.NewToken(ctx, token1)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	metadata := common.TokenMetadata{
+		Description:     "the xxx token",
+		Website:         "https://www.xxx.com",
+		DisplayDecimals: 2,
+		SocialLinks:     []common.SocialLink{{Name: "telegram", URL: "https://t.me/xxx"}},
+	}
+	err = app.TokenMapper.SetTokenMetadata(ctx, "XXX-000", metadata)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	query := abci.RequestQuery{
+		Path: path,
+		Data: []byte(""),
+	}
+	res := app.Query(query)
+
+	var actual common.TokenMetadata
+	err = app.GetCodec().UnmarshalBinaryLengthPrefixed(res.Value, &actual)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	assert.True(t, sdk.ABCICodeType(res.Code).IsOK())
+	assert.Equal(t, metadata, actual)
+}
+
+func Test_Tokens_ABCI_GetMetadata_Error_NotFound(t *testing.T) {
+	path := "/tokens/metadata/XXY-000" // will not exist!
+
+	query := abci.RequestQuery{
+		Path: path,
+		Data: []byte(""),
+	}
+	res := app.Query(query)
+
+	assert.False(t, sdk.ABCICodeType(res.Code).IsOK())
+}
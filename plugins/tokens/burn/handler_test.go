@@ -22,12 +22,12 @@ import (
 )
 
 func setup() (sdk.Context, sdk.Handler, sdk.Handler, auth.AccountKeeper, store.Mapper) {
-	ms, capKey1, capKey2 := testutils.SetupMultiStoreForUnitTest()
+	ms, capKey1, capKey2, capKey3 := testutils.SetupThreeMultiStoreForUnitTest()
 	cdc := wire.NewCodec()
 	cdc.RegisterInterface((*types.IToken)(nil), nil)
 	cdc.RegisterConcrete(&types.Token{}, "bnbchain/Token", nil)
 	cdc.RegisterConcrete(&types.MiniToken{}, "bnbchain/MiniToken", nil)
-	tokenMapper := store.NewMapper(cdc, capKey1)
+	tokenMapper := store.NewMapper(cdc, capKey1, capKey3)
 	accountKeeper := auth.NewAccountKeeper(cdc, capKey2, types.ProtoAppAccount)
 	bankKeeper := bank.NewBaseKeeper(accountKeeper)
 	handler := NewHandler(tokenMapper, bankKeeper)
@@ -32,19 +32,26 @@ func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
 			timeLockCmd(cmdr),
 			timeUnlockCmd(cmdr),
 			timeRelockCmd(cmdr),
+			vestingLockCmd(cmdr),
+			vestingClaimCmd(cmdr),
+			vestingRevokeCmd(cmdr),
 			initiateHTLTCmd(cmdr),
 			depositHTLTCmd(cmdr),
 			claimHTLTCmd(cmdr),
 			refundHTLTCmd(cmdr),
 			transferOwnershipCmd(cmdr),
+			setTokenMetadataCmd(cmdr),
 		)...)
 
 	tokenCmd.AddCommand(
 		client.GetCommands(
 			listTokensCmd,
 			getTokenInfoCmd(cmdr),
+			getTokenMetadataCmd(cmdr),
 			queryTimeLocksCmd(cmdr),
 			queryTimeLockCmd(cmdr),
+			queryVestingsCmd(cmdr),
+			queryVestingCmd(cmdr),
 			querySwapCmd(cmdr),
 			querySwapsByRecipientCmd(cmdr),
 			querySwapsByCreatorCmd(cmdr))...)
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/common/client"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/tokens/metadata"
+	"github.com/bnb-chain/node/wire"
+)
+
+const (
+	flagWebsite         = "website"
+	flagLogo            = "logo"
+	flagLogoHash        = "logo-hash"
+	flagDisplayDecimals = "display-decimals"
+	flagSocial          = "social"
+)
+
+func setTokenMetadataCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-metadata --symbol {symbol} --from {token owner address}",
+		Short: "set the metadata of a token, the metadata set before is replaced as a whole",
+		RunE:  cmdr.setTokenMetadata,
+	}
+
+	cmd.Flags().StringP(flagSymbol, "s", "", "symbol of the token")
+	cmd.Flags().String(flagDescription, "", "description of the token")
+	cmd.Flags().String(flagWebsite, "", "website of the token")
+	cmd.Flags().String(flagLogo, "", "path of the logo file, its sha256 hash is recorded as the logo hash")
+	cmd.Flags().String(flagLogoHash, "", "hex encoded sha256 hash of the logo content, ignored if --logo is given")
+	cmd.Flags().Int8(flagDisplayDecimals, types.TokenDecimals, "decimals to display the amounts with")
+	cmd.Flags().StringSlice(flagSocial, nil, "social links in the form of name=url, e.g. twitter=https://twitter.com/xyz")
+
+	return cmd
+}
+
+func (c Commander) setTokenMetadata(cmd *cobra.Command, args []string) error {
+	cliCtx, txBldr := client.PrepareCtx(c.Cdc)
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+
+	symbol := viper.GetString(flagSymbol)
+	if types.IsMiniTokenSymbol(symbol) {
+		return errors.New("metadata can only be set for BEP2 tokens, use set-uri-mini for mini-tokens")
+	}
+	if err = types.ValidateTokenSymbol(symbol); err != nil {
+		return err
+	}
+
+	logoHash := viper.GetString(flagLogoHash)
+	if logoPath := viper.GetString(flagLogo); len(logoPath) > 0 {
+		logo, err := os.ReadFile(logoPath)
+		if err != nil {
+			return err
+		}
+		hash := sha256.Sum256(logo)
+		logoHash = hex.EncodeToString(hash[:])
+	}
+
+	tokenMetadata := types.TokenMetadata{
+		Description:     viper.GetString(flagDescription),
+		Website:         viper.GetString(flagWebsite),
+		LogoHash:        strings.ToLower(logoHash),
+		DisplayDecimals: int8(viper.GetInt(flagDisplayDecimals)),
+	}
+	for _, social := range viper.GetStringSlice(flagSocial) {
+		parts := strings.SplitN(social, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid social link %s, it should be in the form of name=url", social)
+		}
+		tokenMetadata.SocialLinks = append(tokenMetadata.SocialLinks, types.SocialLink{Name: parts[0], URL: parts[1]})
+	}
+	if err = tokenMetadata.Validate(); err != nil {
+		return err
+	}
+
+	msg := metadata.NewSetTokenMetadataMsg(from, strings.ToUpper(symbol), tokenMetadata)
+	return client.SendOrPrintTx(cliCtx, txBldr, msg)
+}
+
+func getTokenMetadataCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metadata --symbol {symbol}",
+		Short: "Query the metadata of a token",
+		RunE:  cmdr.runGetTokenMetadata,
+	}
+
+	cmd.Flags().StringP(flagSymbol, "s", "", "symbol of the token")
+	return cmd
+}
+
+func (c Commander) runGetTokenMetadata(cmd *cobra.Command, args []string) error {
+	ctx := context.NewCLIContext().WithCodec(c.Cdc)
+
+	symbol := viper.GetString(flagSymbol)
+	if len(symbol) == 0 {
+		return errors.New("you must provide the symbol")
+	}
+
+	res, err := ctx.Query(fmt.Sprintf("tokens/metadata/%s", symbol), nil)
+	if err != nil {
+		return err
+	}
+
+	var tokenMetadata types.TokenMetadata
+	err = c.Cdc.UnmarshalBinaryLengthPrefixed(res, &tokenMetadata)
+	if err != nil {
+		return err
+	}
+
+	output, err := wire.MarshalJSONIndent(c.Cdc, tokenMetadata)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(output))
+	return nil
+}
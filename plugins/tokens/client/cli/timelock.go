@@ -22,6 +22,7 @@ const (
 	flagIncreaseAmountTo = "increase-amount-to"
 	flagExtendedLockTime = "extended-lock-time"
 	flagBroadcast        = "broadcast"
+	flagGrantor          = "grantor"
 )
 
 func timeLockCmd(cmdr Commander) *cobra.Command {
@@ -38,6 +39,10 @@ this command will output the tx to broadcast, but will not broadcast to any node
 if you want to broadcast the tx to blockchain, you need to specify --broadcast manually.
 
 $ CLI token time-lock --amount 100:BNB --from alice --description "time lock for some reason" --lock-time 1559805558 --broadcast
+
+the tokens can also be locked for a third-party beneficiary, only the beneficiary can unlock them after the lock time.
+
+$ CLI token time-lock --amount 100:BNB --from alice --beneficiary bnb1... --description "grant of bob" --lock-time 1559805558 --broadcast
 `),
 		RunE: cmdr.timeLock,
 	}
@@ -45,6 +50,7 @@ $ CLI token time-lock --amount 100:BNB --from alice --description "time lock for
 	cmd.Flags().String(flagAmount, "", "amount of tokens to lock")
 	cmd.Flags().Int64(flagLockTime, 0, "timestamp of lock time(second)")
 	cmd.Flags().String(flagDescription, "", "description of time lock")
+	cmd.Flags().String(flagBeneficiary, "", "address of the third-party beneficiary, default to the sender")
 	cmd.Flags().Bool(flagBroadcast, false, "broadcast tx")
 
 	return cmd
@@ -80,8 +86,16 @@ func (c Commander) timeLock(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("lock time(%s) should be after now", time.Unix(lockTime, 0).UTC().String())
 	}
 
+	var beneficiary sdk.AccAddress
+	if beneficiaryStr := viper.GetString(flagBeneficiary); len(beneficiaryStr) != 0 {
+		beneficiary, err = sdk.AccAddressFromBech32(beneficiaryStr)
+		if err != nil {
+			return err
+		}
+	}
+
 	// build message
-	msg := timelock.NewTimeLockMsg(from, description, amount, lockTime)
+	msg := timelock.NewBeneficiaryTimeLockMsg(from, beneficiary, description, amount, lockTime)
 	broadcast := viper.GetBool(flagBroadcast)
 	if !broadcast {
 		cliCtx.GenerateOnly = true
@@ -179,6 +193,7 @@ func timeUnlockCmd(cmdr Commander) *cobra.Command {
 	}
 
 	cmd.Flags().Int64(flagTimeLockId, 0, "time lock id")
+	cmd.Flags().String(flagGrantor, "", "address of the grantor if the time lock is locked for the sender by others")
 
 	return cmd
 }
@@ -196,8 +211,16 @@ func (c Commander) timeUnlock(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("time lock id should not less than %d", timelock.InitialRecordId)
 	}
 
+	var grantor sdk.AccAddress
+	if grantorStr := viper.GetString(flagGrantor); len(grantorStr) != 0 {
+		grantor, err = sdk.AccAddressFromBech32(grantorStr)
+		if err != nil {
+			return err
+		}
+	}
+
 	// build message
-	msg := timelock.NewTimeUnlockMsg(from, timeLockId)
+	msg := timelock.NewBeneficiaryTimeUnlockMsg(from, grantor, timeLockId)
 	return client.SendOrPrintTx(cliCtx, txBldr, msg)
 }
 
@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bnb-chain/node/common/client"
+	"github.com/bnb-chain/node/plugins/tokens/timelock"
+)
+
+const (
+	flagBeneficiary = "beneficiary"
+	flagStartTime   = "start-time"
+	flagCliffTime   = "cliff-time"
+	flagEndTime     = "end-time"
+	flagPeriod      = "period"
+	flagRevocable   = "revocable"
+	flagVestingId   = "vesting-id"
+)
+
+func vestingLockCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vesting-lock",
+		Short: "lock tokens and release them to a beneficiary by a vesting schedule",
+		Long: strings.TrimSpace(`
+Vesting lock is to lock an amount of tokens which are released to the beneficiary linearly every period from the start time to the end time, nothing is released before the cliff time.
+
+$ CLI token vesting-lock --amount 1200:XYZ-000 --from alice --beneficiary bnb1... --description "grant of bob" --start-time 1559805558 --cliff-time 1591427958 --end-time 1654499958 --period 2592000
+
+the cliff time defaults to the start time, which makes a linear vesting. the vesting can be revoked by the grantor if --revocable is specified, the tokens vested by then are released to the beneficiary and the rest are returned to the grantor.
+`),
+		RunE: cmdr.vestingLock,
+	}
+
+	cmd.Flags().String(flagAmount, "", "amount of tokens to lock")
+	cmd.Flags().String(flagBeneficiary, "", "address of the beneficiary")
+	cmd.Flags().String(flagDescription, "", "description of the vesting")
+	cmd.Flags().Int64(flagStartTime, 0, "timestamp of the vesting start time(second)")
+	cmd.Flags().Int64(flagCliffTime, 0, "timestamp of the vesting cliff time(second), default to the start time")
+	cmd.Flags().Int64(flagEndTime, 0, "timestamp of the vesting end time(second)")
+	cmd.Flags().Int64(flagPeriod, 0, "seconds between two releases")
+	cmd.Flags().Bool(flagRevocable, false, "whether the vesting can be revoked by the grantor")
+
+	return cmd
+}
+
+func (c Commander) vestingLock(cmd *cobra.Command, args []string) error {
+	cliCtx, txBldr := client.PrepareCtx(c.Cdc)
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+
+	beneficiary, err := sdk.AccAddressFromBech32(viper.GetString(flagBeneficiary))
+	if err != nil {
+		return err
+	}
+
+	amount, err := sdk.ParseCoins(viper.GetString(flagAmount))
+	if err != nil {
+		return err
+	}
+
+	startTime := viper.GetInt64(flagStartTime)
+	cliffTime := viper.GetInt64(flagCliffTime)
+	if cliffTime == 0 {
+		cliffTime = startTime
+	}
+
+	// build message
+	msg := timelock.NewVestingLockMsg(from, beneficiary, viper.GetString(flagDescription), amount,
+		startTime, cliffTime, viper.GetInt64(flagEndTime), viper.GetInt64(flagPeriod), viper.GetBool(flagRevocable))
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+	return client.SendOrPrintTx(cliCtx, txBldr, msg)
+}
+
+func vestingClaimCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vesting-claim",
+		Short: "claim the vested tokens",
+		RunE:  cmdr.vestingClaim,
+	}
+
+	cmd.Flags().Int64(flagVestingId, 0, "vesting id")
+
+	return cmd
+}
+
+func (c Commander) vestingClaim(cmd *cobra.Command, args []string) error {
+	cliCtx, txBldr := client.PrepareCtx(c.Cdc)
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+
+	vestingId := viper.GetInt64(flagVestingId)
+	if vestingId < timelock.InitialRecordId {
+		return fmt.Errorf("vesting id should not less than %d", timelock.InitialRecordId)
+	}
+
+	// build message
+	msg := timelock.NewVestingClaimMsg(from, vestingId)
+	return client.SendOrPrintTx(cliCtx, txBldr, msg)
+}
+
+func vestingRevokeCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vesting-revoke",
+		Short: "revoke a revocable vesting by its grantor",
+		RunE:  cmdr.vestingRevoke,
+	}
+
+	cmd.Flags().String(flagBeneficiary, "", "address of the beneficiary")
+	cmd.Flags().Int64(flagVestingId, 0, "vesting id")
+
+	return cmd
+}
+
+func (c Commander) vestingRevoke(cmd *cobra.Command, args []string) error {
+	cliCtx, txBldr := client.PrepareCtx(c.Cdc)
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+
+	beneficiary, err := sdk.AccAddressFromBech32(viper.GetString(flagBeneficiary))
+	if err != nil {
+		return err
+	}
+
+	vestingId := viper.GetInt64(flagVestingId)
+	if vestingId < timelock.InitialRecordId {
+		return fmt.Errorf("vesting id should not less than %d", timelock.InitialRecordId)
+	}
+
+	// build message
+	msg := timelock.NewVestingRevokeMsg(from, beneficiary, vestingId)
+	return client.SendOrPrintTx(cliCtx, txBldr, msg)
+}
+
+func queryVestingsCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-vestings",
+		Short: "query the vestings of a beneficiary",
+		RunE:  cmdr.queryVestings,
+	}
+
+	cmd.Flags().String(flagAddress, "", "address of the beneficiary to query")
+
+	return cmd
+}
+
+func (c Commander) queryVestings(cmd *cobra.Command, args []string) error {
+	cliCtx, _ := client.PrepareCtx(c.Cdc)
+
+	address, err := sdk.AccAddressFromBech32(viper.GetString(flagAddress))
+	if err != nil {
+		return err
+	}
+
+	params := timelock.QueryVestingsParams{
+		Account: address,
+	}
+
+	bz, err := c.Cdc.MarshalJSON(params)
+	if err != nil {
+		return err
+	}
+
+	res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", timelock.MsgRoute, timelock.QueryVestings), bz)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+	return nil
+}
+
+func queryVestingCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-vesting",
+		Short: "query vesting",
+		RunE:  cmdr.queryVesting,
+	}
+
+	cmd.Flags().String(flagAddress, "", "address of the beneficiary to query")
+	cmd.Flags().Int64(flagVestingId, 0, "vesting id")
+
+	return cmd
+}
+
+func (c Commander) queryVesting(cmd *cobra.Command, args []string) error {
+	cliCtx, _ := client.PrepareCtx(c.Cdc)
+
+	address, err := sdk.AccAddressFromBech32(viper.GetString(flagAddress))
+	if err != nil {
+		return err
+	}
+
+	vestingId := viper.GetInt64(flagVestingId)
+	if vestingId < timelock.InitialRecordId {
+		return fmt.Errorf("vesting id should not less than %d", timelock.InitialRecordId)
+	}
+
+	params := timelock.QueryVestingParams{
+		Account: address,
+		Id:      vestingId,
+	}
+
+	bz, err := c.Cdc.MarshalJSON(params)
+	if err != nil {
+		return err
+	}
+
+	res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", timelock.MsgRoute, timelock.QueryVesting), bz)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+	return nil
+}
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/plugins/tokens"
+	"github.com/bnb-chain/node/wire"
+)
+
+func getFusionStatus(ctx context.CLIContext, cdc *wire.Codec) (*tokens.FusionStatus, error) {
+	bz, err := ctx.Query("fusion/status", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var status tokens.FusionStatus
+	err = cdc.UnmarshalBinaryLengthPrefixed(bz, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetFusionStatusReqHandler creates an http request handler to get the progress of the refunds after the sunset
+// upgrades of BC fusion
+func GetFusionStatusReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	responseType := "application/json"
+
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, err := getFusionStatus(ctx, cdc)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		output, err := cdc.MarshalJSON(status)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", responseType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(output)
+	}
+}
@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/plugins/tokens/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+func getTokenHolders(ctx context.CLIContext, cdc *wire.Codec, symbol string, limit int, isMini bool) (*store.TokenHolders, error) {
+	var abciPrefix string
+	if isMini {
+		abciPrefix = "mini-tokens"
+	} else {
+		abciPrefix = "tokens"
+	}
+	path := fmt.Sprintf("%s/holders/%s", abciPrefix, symbol)
+	if limit > 0 {
+		path = fmt.Sprintf("%s/%d", path, limit)
+	}
+	bz, err := ctx.Query(path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var holders store.TokenHolders
+	err = cdc.UnmarshalBinaryLengthPrefixed(bz, &holders)
+	if err != nil {
+		return nil, err
+	}
+	return &holders, nil
+}
+
+// GetTokenHoldersReqHandler creates an http request handler to get the holder statistics and the top holders
+// of a token
+func GetTokenHoldersReqHandler(cdc *wire.Codec, ctx context.CLIContext, isMini bool) http.HandlerFunc {
+	responseType := "application/json"
+
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := mux.Vars(r)["symbol"]
+		if len(symbol) == 0 || len(symbol) > 100 {
+			throw(w, http.StatusExpectationFailed, errors.New("invalid symbol"))
+			return
+		}
+
+		// the node limits it to its max top holders if it's absent or too large
+		limit := 0
+		if limitStr := r.FormValue("limit"); limitStr != "" {
+			parsed, err := strconv.Atoi(limitStr)
+			if err != nil || parsed <= 0 || len(limitStr) > 100 {
+				throw(w, http.StatusExpectationFailed, errors.New("invalid limit"))
+				return
+			}
+			limit = parsed
+		}
+
+		holders, err := getTokenHolders(ctx, cdc, symbol, limit, isMini)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		output, err := cdc.MarshalJSON(holders)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", responseType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(output)
+	}
+}
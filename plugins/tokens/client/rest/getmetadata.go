@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/wire"
+)
+
+func getTokenMetadata(ctx context.CLIContext, cdc *wire.Codec, symbol string) (*types.TokenMetadata, error) {
+	bz, err := ctx.Query(fmt.Sprintf("tokens/metadata/%s", symbol), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata types.TokenMetadata
+	err = cdc.UnmarshalBinaryLengthPrefixed(bz, &metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// GetTokenMetadataReqHandler creates an http request handler to get the metadata of a BEP2 token
+func GetTokenMetadataReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	responseType := "application/json"
+
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := mux.Vars(r)["symbol"]
+		if len(symbol) == 0 || len(symbol) > 100 {
+			throw(w, http.StatusExpectationFailed, errors.New("invalid symbol"))
+			return
+		}
+
+		metadata, err := getTokenMetadata(ctx, cdc, symbol)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		output, err := cdc.MarshalJSON(metadata)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", responseType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(output)
+	}
+}
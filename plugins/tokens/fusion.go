@@ -0,0 +1,238 @@
+package tokens
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/tokens/swap"
+	"github.com/bnb-chain/node/plugins/tokens/timelock"
+)
+
+const (
+	FusionQueueTimeLocks   = "time_locks"
+	FusionQueueVestings    = "vestings"
+	FusionQueueSwaps       = "swaps"
+	FusionQueueDelegations = "delegations"
+
+	// MaxRefundDelegationsPerBlock is the number of delegations refunded by the stake module in a block after
+	// SecondSunset, it's not exported by the sdk
+	MaxRefundDelegationsPerBlock = 10
+
+	maxFusionFailures = 1000
+)
+
+// FusionFailure is the last failure of an item the sunset refunds failed to process. After SunsetRefundRetry it's
+// built from the refund queue, with the attempts made and the height of the next attempt, which is 0 once the refund
+// is given up.
+type FusionFailure struct {
+	Queue      string `json:"queue"`
+	Item       string `json:"item"`
+	Height     int64  `json:"height"`
+	Reason     string `json:"reason"`
+	Attempts   int64  `json:"attempts,omitempty"`
+	NextHeight int64  `json:"next_height,omitempty"`
+}
+
+// FusionQueue is the progress of a queue drained after a sunset upgrade
+type FusionQueue struct {
+	Name        string `json:"name"`
+	Remaining   int64  `json:"remaining"`
+	Failed      int64  `json:"failed"`
+	MaxPerBlock int64  `json:"max_per_block"`
+	// ProjectedDrainHeight is the height at which the items except the failed ones are processed, assuming every
+	// block processes MaxPerBlock items. It's 0 if there is nothing left or the stage is not scheduled.
+	ProjectedDrainHeight int64 `json:"projected_drain_height"`
+}
+
+// FusionStage is the progress of a sunset upgrade of BC fusion
+type FusionStage struct {
+	Name      string        `json:"name"`
+	Height    int64         `json:"height"` // math.MaxInt64 or 0 if the stage is not scheduled
+	Activated bool          `json:"activated"`
+	Queues    []FusionQueue `json:"queues"`
+}
+
+// FusionStatus is the result of the fusion/status query
+type FusionStatus struct {
+	Height   int64           `json:"height"`
+	Stages   []FusionStage   `json:"stages"`
+	Failures []FusionFailure `json:"failures"`
+}
+
+// FusionMonitor keeps the failures of the sunset refunds in EndBlocker in memory so they can be queried, an item is
+// removed once it's processed. The failures are local to the node and lost on restart, so they are only reported
+// before SunsetRefundRetry, the refund queue in the store is reported after it.
+type FusionMonitor struct {
+	mtx      sync.Mutex
+	failures map[string]FusionFailure // queue + item -> failure
+
+	statusMtx sync.Mutex
+	status    *FusionStatus // the status of the latest height queried
+}
+
+func NewFusionMonitor() *FusionMonitor {
+	return &FusionMonitor{failures: make(map[string]FusionFailure)}
+}
+
+func (m *FusionMonitor) RecordFailure(ctx sdk.Context, queue, item, reason string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	key := queue + "/" + item
+	if _, ok := m.failures[key]; !ok && len(m.failures) >= maxFusionFailures {
+		return
+	}
+	m.failures[key] = FusionFailure{Queue: queue, Item: item, Height: ctx.BlockHeight(), Reason: reason}
+}
+
+func (m *FusionMonitor) RecordSuccess(queue, item string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	delete(m.failures, queue+"/"+item)
+}
+
+// Failures returns the failures sorted by queue and item
+func (m *FusionMonitor) Failures() []FusionFailure {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	failures := make([]FusionFailure, 0, len(m.failures))
+	for _, failure := range m.failures {
+		failures = append(failures, failure)
+	}
+	sortFusionFailures(failures)
+	return failures
+}
+
+// Status returns the status of the height. It's got at most once per height since the remaining items are counted
+// by iterating the stores, the other queries of the height share the result.
+func (m *FusionMonitor) Status(height int64, get func() FusionStatus) FusionStatus {
+	m.statusMtx.Lock()
+	defer m.statusMtx.Unlock()
+	if m.status == nil || m.status.Height != height {
+		status := get()
+		m.status = &status
+	}
+	return *m.status
+}
+
+// GetFusionStatus returns the progress of the sunset upgrades, the delegations are counted by the caller since they
+// are kept in the side chain stores of the stake module. The failures are the refunds waiting for a retry or given up
+// in the refund queue once SunsetRefundRetry is activated, so every node reports the same ones.
+func GetFusionStatus(ctx sdk.Context, timelockKeeper timelock.Keeper, swapKeeper swap.Keeper, refundQueue RefundQueue,
+	monitor *FusionMonitor, remainingDelegations int64) FusionStatus {
+	height := ctx.BlockHeight()
+	var failures []FusionFailure
+	if sdk.IsUpgrade(upgrade.SunsetRefundRetry) {
+		failures = getRefundFailures(ctx, refundQueue)
+	} else {
+		failures = monitor.Failures()
+	}
+	failed := make(map[string]int64)
+	for _, failure := range failures {
+		failed[failure.Queue]++
+	}
+
+	secondSunsetHeight := sdk.UpgradeMgr.GetUpgradeHeight(upgrade.SecondSunset)
+	newQueue := func(name string, remaining, maxPerBlock int64) FusionQueue {
+		return FusionQueue{
+			Name:                 name,
+			Remaining:            remaining,
+			Failed:               failed[name],
+			MaxPerBlock:          maxPerBlock,
+			ProjectedDrainHeight: projectDrainHeight(height, secondSunsetHeight, remaining-failed[name], maxPerBlock),
+		}
+	}
+
+	return FusionStatus{
+		Height: height,
+		Stages: []FusionStage{
+			newFusionStage(upgrade.FirstSunset),
+			newFusionStage(upgrade.SecondSunset,
+				newQueue(FusionQueueTimeLocks, countIterator(timelockKeeper.GetTimeLockRecordIterator(ctx)), MaxUnlockItems),
+				newQueue(FusionQueueVestings, countIterator(timelockKeeper.GetVestingRecordIterator(ctx)), MaxUnlockItems),
+				newQueue(FusionQueueSwaps, countOpenSwaps(ctx, swapKeeper), MaxUnlockItems),
+				newQueue(FusionQueueDelegations, remainingDelegations, MaxRefundDelegationsPerBlock),
+			),
+			newFusionStage(upgrade.FinalSunset),
+		},
+		Failures: failures,
+	}
+}
+
+// getRefundFailures returns the refunds in the refund queue sorted by queue and item
+func getRefundFailures(ctx sdk.Context, refundQueue RefundQueue) []FusionFailure {
+	retries := append(refundQueue.GetRetries(ctx), refundQueue.GetDeadLetters(ctx)...)
+	failures := make([]FusionFailure, 0, len(retries))
+	for _, retry := range retries {
+		failures = append(failures, FusionFailure{
+			Queue:      retry.Queue,
+			Item:       retry.Item,
+			Height:     retry.LastHeight,
+			Reason:     retry.Reason,
+			Attempts:   retry.Attempts,
+			NextHeight: retry.NextHeight,
+		})
+	}
+	sortFusionFailures(failures)
+	return failures
+}
+
+func sortFusionFailures(failures []FusionFailure) {
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Queue != failures[j].Queue {
+			return failures[i].Queue < failures[j].Queue
+		}
+		return failures[i].Item < failures[j].Item
+	})
+}
+
+func newFusionStage(name string, queues ...FusionQueue) FusionStage {
+	if queues == nil {
+		queues = []FusionQueue{}
+	}
+	return FusionStage{
+		Name:      name,
+		Height:    sdk.UpgradeMgr.GetUpgradeHeight(name),
+		Activated: sdk.IsUpgrade(name),
+		Queues:    queues,
+	}
+}
+
+// projectDrainHeight returns the height at which the pending items are processed if the refunds start at the upgrade
+// height, EndBlocker of the next block is the first one to process them once the upgrade is activated
+func projectDrainHeight(height, upgradeHeight, pending, maxPerBlock int64) int64 {
+	if pending <= 0 || maxPerBlock <= 0 || upgradeHeight <= 0 || upgradeHeight == math.MaxInt64 {
+		return 0
+	}
+	start := height + 1
+	if upgradeHeight > start {
+		start = upgradeHeight
+	}
+	return start + (pending+maxPerBlock-1)/maxPerBlock - 1
+}
+
+func countIterator(iterator sdk.Iterator) int64 {
+	defer iterator.Close()
+	count := int64(0)
+	for ; iterator.Valid(); iterator.Next() {
+		count++
+	}
+	return count
+}
+
+func countOpenSwaps(ctx sdk.Context, swapKeeper swap.Keeper) int64 {
+	iterator := swapKeeper.GetSwapIterator(ctx)
+	defer iterator.Close()
+	count := int64(0)
+	for ; iterator.Valid(); iterator.Next() {
+		var atomicSwap swap.AtomicSwap
+		swapKeeper.CDC().MustUnmarshalBinaryBare(iterator.Value(), &atomicSwap)
+		if atomicSwap.Status == swap.Open {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,124 @@
+package tokens_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/tokens"
+	"github.com/bnb-chain/node/plugins/tokens/swap"
+	"github.com/bnb-chain/node/plugins/tokens/timelock"
+)
+
+func TestGetFusionStatus(t *testing.T) {
+	secondSunsetHeight := sdk.UpgradeMgr.GetUpgradeHeight(upgrade.SecondSunset)
+	sdk.UpgradeMgr.AddUpgradeHeight(upgrade.SecondSunset, 200)
+	defer sdk.UpgradeMgr.AddUpgradeHeight(upgrade.SecondSunset, secondSunsetHeight)
+
+	ctx := app.NewContext(sdk.RunTxModeCheck, abci.Header{Height: 100})
+	timeLockKeeper := timelock.NewKeeper(app.Codec, common.TimeLockStoreKey, app.CoinKeeper, app.AccountKeeper, timelock.DefaultCodespace)
+	swapKeeper := swap.NewKeeper(app.Codec, common.AtomicSwapStoreKey, app.CoinKeeper, nil, swap.DefaultCodespace)
+	_, acc := testutils.NewAccount(ctx, app.AccountKeeper, 100e8)
+	for i := 0; i < 15; i++ {
+		// the id of a time lock is the sequence of the account
+		acc = app.AccountKeeper.GetAccount(ctx, acc.GetAddress())
+		require.NoError(t, acc.SetSequence(int64(i)))
+		app.AccountKeeper.SetAccount(ctx, acc)
+		_, err := timeLockKeeper.TimeLock(ctx, acc.GetAddress(), "Test", sdk.Coins{sdk.NewCoin("BNB", 1e8)}, time.Now().Add(time.Hour))
+		require.Nil(t, err)
+	}
+
+	refundQueue := tokens.NewRefundQueue(app.Codec, common.TimeLockStoreKey)
+	monitor := tokens.NewFusionMonitor()
+	monitor.RecordFailure(ctx, tokens.FusionQueueTimeLocks, acc.GetAddress().String()+":1", "failed")
+	status := tokens.GetFusionStatus(ctx, timeLockKeeper, swapKeeper, refundQueue, monitor, 25)
+	require.Equal(t, int64(100), status.Height)
+	require.Len(t, status.Stages, 3)
+	require.Equal(t, upgrade.FirstSunset, status.Stages[0].Name)
+	require.Empty(t, status.Stages[0].Queues)
+
+	stage := status.Stages[1]
+	require.Equal(t, upgrade.SecondSunset, stage.Name)
+	require.Equal(t, int64(200), stage.Height)
+	require.False(t, stage.Activated)
+	// 14 time locks except the failed one take 2 blocks from the upgrade height
+	require.Equal(t, tokens.FusionQueue{Name: tokens.FusionQueueTimeLocks, Remaining: 15, Failed: 1,
+		MaxPerBlock: tokens.MaxUnlockItems, ProjectedDrainHeight: 201}, stage.Queues[0])
+	require.Equal(t, tokens.FusionQueue{Name: tokens.FusionQueueVestings, MaxPerBlock: tokens.MaxUnlockItems}, stage.Queues[1])
+	require.Equal(t, tokens.FusionQueue{Name: tokens.FusionQueueSwaps, MaxPerBlock: tokens.MaxUnlockItems}, stage.Queues[2])
+	require.Equal(t, tokens.FusionQueue{Name: tokens.FusionQueueDelegations, Remaining: 25,
+		MaxPerBlock: tokens.MaxRefundDelegationsPerBlock, ProjectedDrainHeight: 202}, stage.Queues[3])
+	require.Equal(t, []tokens.FusionFailure{{Queue: tokens.FusionQueueTimeLocks, Item: acc.GetAddress().String() + ":1",
+		Height: 100, Reason: "failed"}}, status.Failures)
+
+	monitor.RecordSuccess(tokens.FusionQueueTimeLocks, acc.GetAddress().String()+":1")
+	status = tokens.GetFusionStatus(ctx.WithBlockHeight(300), timeLockKeeper, swapKeeper, refundQueue, monitor, 0)
+	require.Empty(t, status.Failures)
+	// the refunds start from the next block once the upgrade is passed
+	require.Equal(t, int64(302), status.Stages[1].Queues[0].ProjectedDrainHeight)
+	require.Equal(t, int64(0), status.Stages[1].Queues[3].ProjectedDrainHeight)
+}
+
+func TestGetFusionStatus_RefundQueue(t *testing.T) {
+	defer setUpgradeHeights(1, upgrade.SecondSunset, upgrade.SunsetRefundRetry)()
+	sdk.UpgradeMgr.SetHeight(10)
+	ctx, _, timeLockKeeper, swapKeeper, queue := setupRefund(t)
+	ctx = ctx.WithBlockHeight(10)
+
+	// the failures of the node are not reported once the refund queue is kept in the store
+	monitor := tokens.NewFusionMonitor()
+	monitor.RecordFailure(ctx, tokens.FusionQueueTimeLocks, "local", "failed")
+	queue.Fail(ctx, tokens.FusionQueueVestings, []byte("vesting"), "vesting", "failed")
+	dead := tokens.RefundRetry{}
+	for i := 0; i < tokens.MaxRefundAttempts; i++ {
+		dead = queue.Fail(ctx, tokens.FusionQueueSwaps, []byte("swap"), "swap", "failed")
+	}
+	queue.Fail(ctx, tokens.FusionQueueTimeLocks, []byte("timelock"), "timelock", "failed")
+
+	status := tokens.GetFusionStatus(ctx, timeLockKeeper, swapKeeper, queue, monitor, 0)
+	require.Equal(t, []tokens.FusionFailure{
+		{Queue: tokens.FusionQueueSwaps, Item: "swap", Height: 10, Reason: "failed", Attempts: dead.Attempts},
+		{Queue: tokens.FusionQueueTimeLocks, Item: "timelock", Height: 10, Reason: "failed", Attempts: 1,
+			NextHeight: 10 + tokens.RefundRetryBackoff},
+		{Queue: tokens.FusionQueueVestings, Item: "vesting", Height: 10, Reason: "failed", Attempts: 1,
+			NextHeight: 10 + tokens.RefundRetryBackoff},
+	}, status.Failures)
+	for _, queue := range status.Stages[1].Queues[:3] {
+		require.Equal(t, int64(1), queue.Failed, queue.Name)
+	}
+}
+
+func TestFusionMonitor_Status(t *testing.T) {
+	monitor := tokens.NewFusionMonitor()
+	calls := 0
+	get := func(height int64) func() tokens.FusionStatus {
+		return func() tokens.FusionStatus {
+			calls++
+			return tokens.FusionStatus{Height: height}
+		}
+	}
+	require.Equal(t, int64(100), monitor.Status(100, get(100)).Height)
+	require.Equal(t, int64(100), monitor.Status(100, get(100)).Height)
+	require.Equal(t, 1, calls)
+	require.Equal(t, int64(101), monitor.Status(101, get(101)).Height)
+	require.Equal(t, 2, calls)
+}
+
+func TestFusionStatusQuery(t *testing.T) {
+	res := app.Query(abci.RequestQuery{Path: "/fusion/status"})
+	require.Equal(t, uint32(sdk.ABCICodeOK), res.Code, res.Log)
+	var status tokens.FusionStatus
+	require.NoError(t, app.Codec.UnmarshalBinaryLengthPrefixed(res.Value, &status))
+	require.Len(t, status.Stages, 3)
+
+	res = app.Query(abci.RequestQuery{Path: "/fusion/unknown"})
+	require.NotEqual(t, uint32(sdk.ABCICodeOK), res.Code)
+}
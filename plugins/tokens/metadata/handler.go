@@ -0,0 +1,48 @@
+package metadata
+
+import (
+	"reflect"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/log"
+	"github.com/bnb-chain/node/plugins/tokens/store"
+)
+
+func NewHandler(tokenMapper store.Mapper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case SetTokenMetadataMsg:
+			return handleSetTokenMetadata(ctx, tokenMapper, msg)
+		default:
+			errMsg := "Unrecognized msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleSetTokenMetadata(ctx sdk.Context, tokenMapper store.Mapper, msg SetTokenMetadataMsg) sdk.Result {
+	symbol := strings.ToUpper(msg.Symbol)
+	logger := log.With("module", "token", "symbol", symbol, "from", msg.From)
+
+	token, err := tokenMapper.GetToken(ctx, symbol)
+	if err != nil {
+		logger.Info("set token metadata failed", "reason", "invalid token symbol")
+		return sdk.ErrInvalidCoins(err.Error()).Result()
+	}
+
+	if !token.IsOwner(msg.From) {
+		logger.Info("set token metadata failed", "reason", "not token's owner")
+		return sdk.ErrUnauthorized("only the owner of the token can set the metadata").Result()
+	}
+
+	err = tokenMapper.SetTokenMetadata(ctx, symbol, msg.Metadata)
+	if err != nil {
+		logger.Error("set token metadata failed", "reason", "update metadata failed: "+err.Error())
+		return sdk.ErrInternal(err.Error()).Result()
+	}
+
+	logger.Info("finished setting token metadata")
+	return sdk.Result{}
+}
@@ -0,0 +1,129 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/tokens/issue"
+	"github.com/bnb-chain/node/plugins/tokens/store"
+	"github.com/bnb-chain/node/wire"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setup() (sdk.Context, sdk.Handler, sdk.Handler, auth.AccountKeeper, store.Mapper) {
+	ms, capKey1, capKey2, capKey3 := testutils.SetupThreeMultiStoreForUnitTest()
+	cdc := wire.NewCodec()
+	cdc.RegisterInterface((*types.IToken)(nil), nil)
+	cdc.RegisterConcrete(&types.Token{}, "bnbchain/Token", nil)
+	cdc.RegisterConcrete(&types.MiniToken{}, "bnbchain/MiniToken", nil)
+	tokenMapper := store.NewMapper(cdc, capKey1, capKey3)
+	accountKeeper := auth.NewAccountKeeper(cdc, capKey2, types.ProtoAppAccount)
+	bankKeeper := bank.NewBaseKeeper(accountKeeper)
+	handler := NewHandler(tokenMapper)
+	issueHandler := issue.NewHandler(tokenMapper, bankKeeper)
+
+	accountStore := ms.GetKVStore(capKey2)
+	accountStoreCache := auth.NewAccountStoreCache(cdc, accountStore, 10)
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid", Height: 1},
+		sdk.RunTxModeDeliver, log.NewNopLogger()).
+		WithAccountCache(auth.NewAccountCache(accountStoreCache))
+	return ctx, handler, issueHandler, accountKeeper, tokenMapper
+}
+
+func newMetadata() types.TokenMetadata {
+	return types.TokenMetadata{
+		Description:     "the new bnb",
+		Website:         "https://www.nnb.com",
+		LogoHash:        "a3c1b2e7c5f0e6d4b8a9c2d1e0f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1",
+		DisplayDecimals: 4,
+		SocialLinks:     []types.SocialLink{{Name: "twitter", URL: "https://twitter.com/nnb"}},
+	}
+}
+
+func TestHandleSetTokenMetadata(t *testing.T) {
+	ctx, handler, issueHandler, accountKeeper, tokenMapper := setup()
+	_, owner := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_, acc := testutils.NewAccount(ctx, accountKeeper, 100e8)
+
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000")
+	issueMsg := issue.NewIssueMsg(owner.GetAddress(), "New BNB", "NNB", 10000e8, false)
+	sdkResult := issueHandler(ctx, issueMsg)
+	require.Equal(t, true, sdkResult.Code.IsOK())
+
+	_, err := tokenMapper.GetTokenMetadata(ctx, "NNB-000")
+	require.Error(t, err)
+
+	// test wrong symbol
+	msg := NewSetTokenMetadataMsg(owner.GetAddress(), "NNB-001", newMetadata())
+	sdkResult = handler(ctx, msg)
+	require.Equal(t, false, sdkResult.Code.IsOK())
+
+	// test wrong owner
+	msg = NewSetTokenMetadataMsg(acc.GetAddress(), "NNB-000", newMetadata())
+	sdkResult = handler(ctx, msg)
+	require.Equal(t, false, sdkResult.Code.IsOK())
+	require.Contains(t, sdkResult.Log, "only the owner of the token can set the metadata")
+
+	msg = NewSetTokenMetadataMsg(owner.GetAddress(), "nnb-000", newMetadata())
+	sdkResult = handler(ctx, msg)
+	require.Equal(t, true, sdkResult.Code.IsOK())
+	metadata, err := tokenMapper.GetTokenMetadata(ctx, "NNB-000")
+	require.NoError(t, err)
+	require.Equal(t, newMetadata(), *metadata)
+
+	// the metadata is replaced as a whole
+	msg = NewSetTokenMetadataMsg(owner.GetAddress(), "NNB-000", types.TokenMetadata{Description: "updated"})
+	sdkResult = handler(ctx, msg)
+	require.Equal(t, true, sdkResult.Code.IsOK())
+	metadata, err = tokenMapper.GetTokenMetadata(ctx, "NNB-000")
+	require.NoError(t, err)
+	require.Equal(t, "updated", metadata.Description)
+	require.Empty(t, metadata.SocialLinks)
+
+	// the metadata is not listed as a token
+	tokens := tokenMapper.GetTokenList(ctx, true, false)
+	require.Len(t, tokens, 1)
+	require.Equal(t, "NNB-000", tokens[0].GetSymbol())
+}
+
+func TestSetTokenMetadataMsgValidateBasic(t *testing.T) {
+	addr := sdk.AccAddress(make([]byte, sdk.AddrLen))
+	require.Nil(t, NewSetTokenMetadataMsg(addr, "NNB-000", newMetadata()).ValidateBasic())
+	require.Nil(t, NewSetTokenMetadataMsg(addr, "NNB-000", types.TokenMetadata{}).ValidateBasic())
+
+	require.NotNil(t, NewSetTokenMetadataMsg(nil, "NNB-000", newMetadata()).ValidateBasic())
+	require.NotNil(t, NewSetTokenMetadataMsg(addr, "NNB-000M", newMetadata()).ValidateBasic())
+
+	invalid := []func(*types.TokenMetadata){
+		func(m *types.TokenMetadata) { m.Description = string(make([]byte, types.MaxTokenDescriptionLength+1)) },
+		func(m *types.TokenMetadata) { m.Website = "ftp://www.nnb.com" },
+		func(m *types.TokenMetadata) { m.Website = "www.nnb.com" },
+		func(m *types.TokenMetadata) { m.LogoHash = "a3c1" },
+		func(m *types.TokenMetadata) { m.LogoHash = "not a hash" },
+		func(m *types.TokenMetadata) { m.DisplayDecimals = 9 },
+		func(m *types.TokenMetadata) { m.DisplayDecimals = -1 },
+		func(m *types.TokenMetadata) { m.SocialLinks = append(m.SocialLinks, m.SocialLinks[0]) },
+		func(m *types.TokenMetadata) { m.SocialLinks[0].Name = "" },
+		func(m *types.TokenMetadata) { m.SocialLinks[0].URL = "javascript:alert(1)" },
+		func(m *types.TokenMetadata) {
+			for i := 0; i < types.MaxTokenSocialLinks; i++ {
+				m.SocialLinks = append(m.SocialLinks, types.SocialLink{Name: string(rune('a' + i)), URL: "https://nnb.com"})
+			}
+		},
+	}
+	for i, modify := range invalid {
+		metadata := newMetadata()
+		modify(&metadata)
+		require.NotNil(t, NewSetTokenMetadataMsg(addr, "NNB-000", metadata).ValidateBasic(), "case %d", i)
+	}
+}
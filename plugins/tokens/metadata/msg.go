@@ -0,0 +1,71 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+)
+
+const (
+	Route                   = "tokensMetadata"
+	SetTokenMetadataMsgType = "setTokenMetadata"
+
+	// SetTokenMetadataFee is the initial fee of SetTokenMetadataMsg, it can be changed by the fee change proposals
+	SetTokenMetadataFee = 1e6
+)
+
+var _ sdk.Msg = SetTokenMetadataMsg{}
+
+// SetTokenMetadataMsg replaces the metadata of a BEP2 token, it can only be sent by the token owner
+type SetTokenMetadataMsg struct {
+	From     sdk.AccAddress      `json:"from"`
+	Symbol   string              `json:"symbol"`
+	Metadata types.TokenMetadata `json:"metadata"`
+}
+
+func NewSetTokenMetadataMsg(from sdk.AccAddress, symbol string, metadata types.TokenMetadata) SetTokenMetadataMsg {
+	return SetTokenMetadataMsg{
+		From:     from,
+		Symbol:   symbol,
+		Metadata: metadata,
+	}
+}
+
+func (msg SetTokenMetadataMsg) Route() string  { return Route }
+func (msg SetTokenMetadataMsg) Type() string   { return SetTokenMetadataMsgType }
+func (msg SetTokenMetadataMsg) String() string { return fmt.Sprintf("SetTokenMetadataMsg{%#v}", msg) }
+
+func (msg SetTokenMetadataMsg) ValidateBasic() sdk.Error {
+	if len(msg.From) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("Invalid from address, expected address length is %d, actual length is %d", sdk.AddrLen, len(msg.From)))
+	}
+	if types.IsMiniTokenSymbol(msg.Symbol) {
+		return sdk.ErrInvalidCoins("metadata can only be set for BEP2 tokens")
+	}
+	if err := types.ValidateTokenSymbol(msg.Symbol); err != nil {
+		return sdk.ErrInvalidCoins(err.Error())
+	}
+	if err := msg.Metadata.Validate(); err != nil {
+		return sdk.ErrInvalidCoins(err.Error())
+	}
+	return nil
+}
+
+func (msg SetTokenMetadataMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg SetTokenMetadataMsg) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From}
+}
+
+func (msg SetTokenMetadataMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
@@ -2,6 +2,7 @@ package tokens
 
 import (
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -22,7 +23,7 @@ const miniAbciQueryPrefix = "mini-tokens"
 // InitPlugin initializes the plugin.
 func InitPlugin(
 	appp app.ChainApp, mapper Mapper, accKeeper auth.AccountKeeper, coinKeeper bank.Keeper,
-	timeLockKeeper timelock.Keeper, swapKeeper swap.Keeper) {
+	timeLockKeeper timelock.Keeper, swapKeeper swap.Keeper, holderIndex *HolderIndex) {
 	// add msg handlers
 	for route, handler := range Routes(mapper, accKeeper, coinKeeper, timeLockKeeper,
 		swapKeeper) {
@@ -30,8 +31,8 @@ func InitPlugin(
 	}
 
 	// add abci handlers
-	tokenHandler := createQueryHandler(mapper, abciQueryPrefix)
-	miniTokenHandler := createQueryHandler(mapper, miniAbciQueryPrefix)
+	tokenHandler := createQueryHandler(mapper, holderIndex, abciQueryPrefix)
+	miniTokenHandler := createQueryHandler(mapper, holderIndex, miniAbciQueryPrefix)
 	appp.RegisterQueryHandler(abciQueryPrefix, tokenHandler)
 	appp.RegisterQueryHandler(miniAbciQueryPrefix, miniTokenHandler)
 	RegisterUpgradeBeginBlocker(mapper)
@@ -47,19 +48,33 @@ func RegisterUpgradeBeginBlocker(mapper Mapper) {
 	})
 }
 
-func createQueryHandler(mapper Mapper, queryPrefix string) app.AbciQueryHandler {
-	return createAbciQueryHandler(mapper, queryPrefix)
+// holderIndex is nil if the token holder index is disabled
+func createQueryHandler(mapper Mapper, holderIndex *HolderIndex, queryPrefix string) app.AbciQueryHandler {
+	return createAbciQueryHandler(mapper, holderIndex, queryPrefix)
 }
 
 const (
 	MaxUnlockItems = 10
 )
 
-func EndBlocker(ctx sdk.Context, timelockKeeper timelock.Keeper, swapKeeper swap.Keeper) {
+// EndBlocker refunds the time locks, vestings and open swaps after SecondSunset, at most MaxUnlockItems of each in a
+// block. The failures are recorded in the monitor, and in the refund queue after SunsetRefundRetry so that they are
+// retried with a backoff instead of in every block.
+func EndBlocker(ctx sdk.Context, timelockKeeper timelock.Keeper, swapKeeper swap.Keeper, refundQueue RefundQueue,
+	monitor *FusionMonitor) {
 	if !sdk.IsUpgrade(sdk.SecondSunsetFork) {
 		return
 	}
 	logger := bnclog.With("module", "tokens")
+	retryEnabled := sdk.IsUpgrade(upgrade.SunsetRefundRetry)
+	fail := func(queue string, key []byte, item, reason string) {
+		monitor.RecordFailure(ctx, queue, item, reason)
+		if retryEnabled {
+			retry := refundQueue.Fail(ctx, queue, key, item, reason)
+			logger.Info("queue the failed refund", "queue", queue, "item", item, "attempts", retry.Attempts,
+				"nextHeight", retry.NextHeight)
+		}
+	}
 	logger.Info("unlock the time locks", "blockHeight", ctx.BlockHeight())
 
 	iterator := timelockKeeper.GetTimeLockRecordIterator(ctx)
@@ -67,18 +82,24 @@ func EndBlocker(ctx sdk.Context, timelockKeeper timelock.Keeper, swapKeeper swap
 	i := 0
 	failedCount := 0
 	for ; iterator.Valid(); iterator.Next() {
+		if retryEnabled && refundQueue.Contains(ctx, FusionQueueTimeLocks, iterator.Key()) {
+			continue
+		}
 		addr, id, err := timelock.ParseKeyRecord(iterator.Key())
 		if err != nil {
 			logger.Error("failed to parse timelock record", "error", err)
+			fail(FusionQueueTimeLocks, iterator.Key(), hex.EncodeToString(iterator.Key()), err.Error())
 			failedCount++
 			continue
 		}
 		err = timelockKeeper.TimeUnlock(ctx, addr, id, true)
 		if err != nil {
 			logger.Error("failed to unlock the time locks", "error", err)
+			fail(FusionQueueTimeLocks, iterator.Key(), fmt.Sprintf("%s:%d", addr, id), err.Error())
 			failedCount++
 			continue
 		}
+		monitor.RecordSuccess(FusionQueueTimeLocks, fmt.Sprintf("%s:%d", addr, id))
 		logger.Info("succeed to unlock the time locks", "addr", addr, "id", id)
 		i++
 		if i >= MaxUnlockItems {
@@ -87,11 +108,45 @@ func EndBlocker(ctx sdk.Context, timelockKeeper timelock.Keeper, swapKeeper swap
 	}
 	logger.Info("unlock the time locks done", "blockHeight", ctx.BlockHeight(), "succeed", i, "failed", failedCount)
 
+	vestingIterator := timelockKeeper.GetVestingRecordIterator(ctx)
+	defer vestingIterator.Close()
+	i = 0
+	failedCount = 0
+	for ; vestingIterator.Valid(); vestingIterator.Next() {
+		if retryEnabled && refundQueue.Contains(ctx, FusionQueueVestings, vestingIterator.Key()) {
+			continue
+		}
+		addr, id, err := timelock.ParseKeyVestingRecord(vestingIterator.Key())
+		if err != nil {
+			logger.Error("failed to parse vesting record", "error", err)
+			fail(FusionQueueVestings, vestingIterator.Key(), hex.EncodeToString(vestingIterator.Key()), err.Error())
+			failedCount++
+			continue
+		}
+		err = timelockKeeper.VestingRefund(ctx, addr, id)
+		if err != nil {
+			logger.Error("failed to refund the vestings", "error", err)
+			fail(FusionQueueVestings, vestingIterator.Key(), fmt.Sprintf("%s:%d", addr, id), err.Error())
+			failedCount++
+			continue
+		}
+		monitor.RecordSuccess(FusionQueueVestings, fmt.Sprintf("%s:%d", addr, id))
+		logger.Info("succeed to refund the vestings", "addr", addr, "id", id)
+		i++
+		if i >= MaxUnlockItems {
+			break
+		}
+	}
+	logger.Info("refund the vestings done", "blockHeight", ctx.BlockHeight(), "succeed", i, "failed", failedCount)
+
 	swapIterator := swapKeeper.GetSwapIterator(ctx)
 	defer swapIterator.Close()
 	i = 0
 	failedCount = 0
 	for ; swapIterator.Valid(); swapIterator.Next() {
+		if retryEnabled && refundQueue.Contains(ctx, FusionQueueSwaps, swapIterator.Key()) {
+			continue
+		}
 		var automaticSwap swap.AtomicSwap
 		swapKeeper.CDC().MustUnmarshalBinaryBare(swapIterator.Value(), &automaticSwap)
 		swapID := swapIterator.Key()[len(swap.HashKey):]
@@ -108,9 +163,11 @@ func EndBlocker(ctx sdk.Context, timelockKeeper timelock.Keeper, swapKeeper swap
 		})
 		if !result.IsOK() {
 			logger.Error("failed to refund swap", "swapId", swapID, "result", fmt.Sprintf("%+v", result))
+			fail(FusionQueueSwaps, swapIterator.Key(), hex.EncodeToString(swapID), result.Log)
 			failedCount++
 			continue
 		}
+		monitor.RecordSuccess(FusionQueueSwaps, hex.EncodeToString(swapID))
 
 		logger.Info("succeed to refund swap", "swapId", swapID, "swap", fmt.Sprintf("%+v", swapItem))
 		i++
@@ -119,6 +176,33 @@ func EndBlocker(ctx sdk.Context, timelockKeeper timelock.Keeper, swapKeeper swap
 		}
 	}
 	logger.Info("refund the swaps done", "blockHeight", ctx.BlockHeight(), "succeed", i, "failed", failedCount)
+
+	if retryEnabled {
+		retryRefunds(ctx, timelockKeeper, swapKeeper, refundQueue, monitor)
+	}
+}
+
+// retryRefunds retries the failed refunds whose backoff has passed
+func retryRefunds(ctx sdk.Context, timelockKeeper timelock.Keeper, swapKeeper swap.Keeper, refundQueue RefundQueue,
+	monitor *FusionMonitor) {
+	logger := bnclog.With("module", "tokens")
+	for _, retry := range refundQueue.GetDueRetries(ctx, MaxRefundRetryItems) {
+		if err := retryRefund(ctx, timelockKeeper, swapKeeper, retry); err != nil {
+			monitor.RecordFailure(ctx, retry.Queue, retry.Item, err.Error())
+			retry = refundQueue.Fail(ctx, retry.Queue, retry.Key, retry.Item, err.Error())
+			if retry.NextHeight == 0 {
+				logger.Error("give up the refund", "queue", retry.Queue, "item", retry.Item,
+					"attempts", retry.Attempts, "error", err)
+			} else {
+				logger.Error("failed to retry the refund", "queue", retry.Queue, "item", retry.Item,
+					"attempts", retry.Attempts, "nextHeight", retry.NextHeight, "error", err)
+			}
+			continue
+		}
+		refundQueue.Remove(ctx, retry.Queue, retry.Key)
+		monitor.RecordSuccess(retry.Queue, retry.Item)
+		logger.Info("succeed to retry the refund", "queue", retry.Queue, "item", retry.Item, "attempts", retry.Attempts+1)
+	}
 }
 
 // EndBreatheBlock processes the breathe block lifecycle event.
@@ -0,0 +1,190 @@
+package tokens
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/plugins/tokens/swap"
+	"github.com/bnb-chain/node/plugins/tokens/timelock"
+)
+
+const (
+	// MaxRefundAttempts is the number of failed attempts after which a refund is moved to the dead-letter list
+	MaxRefundAttempts = 5
+	// RefundRetryBackoff is the number of blocks before the first retry of a failed refund, it's doubled for every
+	// further attempt
+	RefundRetryBackoff = 100
+	// MaxRefundRetryItems is the max number of the retries in a block
+	MaxRefundRetryItems = MaxUnlockItems
+)
+
+var (
+	refundRetryKeyPrefix = []byte("refundRetry:")
+	refundDeadKeyPrefix  = []byte("refundDead:")
+)
+
+func keyRefundRetry(queue string, key []byte) []byte {
+	return append([]byte(fmt.Sprintf("%s%s:", refundRetryKeyPrefix, queue)), key...)
+}
+
+func keyRefundDead(queue string, key []byte) []byte {
+	return append([]byte(fmt.Sprintf("%s%s:", refundDeadKeyPrefix, queue)), key...)
+}
+
+// RefundRetry is a refund after SecondSunset which failed, it's either waiting for the next attempt or given up in
+// the dead-letter list
+type RefundRetry struct {
+	Queue      string `json:"queue"`
+	Key        []byte `json:"key"` // the store key of the time lock, vesting or swap
+	Item       string `json:"item"`
+	Attempts   int64  `json:"attempts"`
+	LastHeight int64  `json:"last_height"`
+	NextHeight int64  `json:"next_height"` // 0 in the dead-letter list
+	Reason     string `json:"reason"`
+}
+
+// RefundQueue keeps the failed refunds after SecondSunset in the store, so EndBlocker skips them instead of
+// retrying them in every block, and retries them with a backoff until they are moved to the dead-letter list
+type RefundQueue struct {
+	storeKey sdk.StoreKey
+	cdc      *codec.Codec
+}
+
+func NewRefundQueue(cdc *codec.Codec, key sdk.StoreKey) RefundQueue {
+	return RefundQueue{
+		storeKey: key,
+		cdc:      cdc,
+	}
+}
+
+// Contains returns whether the item is waiting for a retry or in the dead-letter list
+func (q RefundQueue) Contains(ctx sdk.Context, queue string, key []byte) bool {
+	store := ctx.KVStore(q.storeKey)
+	return store.Has(keyRefundRetry(queue, key)) || store.Has(keyRefundDead(queue, key))
+}
+
+// Fail records a failed attempt of the item, the item is moved to the dead-letter list once it has failed
+// MaxRefundAttempts times
+func (q RefundQueue) Fail(ctx sdk.Context, queue string, key []byte, item, reason string) RefundRetry {
+	store := ctx.KVStore(q.storeKey)
+	retryKey := keyRefundRetry(queue, key)
+	retry := RefundRetry{Queue: queue, Key: key, Item: item}
+	if bz := store.Get(retryKey); bz != nil {
+		q.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &retry)
+	}
+	retry.Attempts++
+	retry.LastHeight = ctx.BlockHeight()
+	retry.Reason = reason
+
+	if retry.Attempts >= MaxRefundAttempts {
+		retry.NextHeight = 0
+		store.Delete(retryKey)
+		store.Set(keyRefundDead(queue, key), q.cdc.MustMarshalBinaryLengthPrefixed(retry))
+		return retry
+	}
+	retry.NextHeight = ctx.BlockHeight() + RefundRetryBackoff<<(retry.Attempts-1)
+	store.Set(retryKey, q.cdc.MustMarshalBinaryLengthPrefixed(retry))
+	return retry
+}
+
+// Remove removes the item from the retries once it's refunded
+func (q RefundQueue) Remove(ctx sdk.Context, queue string, key []byte) {
+	ctx.KVStore(q.storeKey).Delete(keyRefundRetry(queue, key))
+}
+
+// GetDueRetries returns at most limit retries whose next attempt is due at the height of the context
+func (q RefundQueue) GetDueRetries(ctx sdk.Context, limit int) []RefundRetry {
+	retries := make([]RefundRetry, 0)
+	q.iterate(ctx, refundRetryKeyPrefix, func(retry RefundRetry) bool {
+		if retry.NextHeight <= ctx.BlockHeight() {
+			retries = append(retries, retry)
+		}
+		return len(retries) >= limit
+	})
+	return retries
+}
+
+// GetRetries returns all the items waiting for a retry
+func (q RefundQueue) GetRetries(ctx sdk.Context) []RefundRetry {
+	retries := make([]RefundRetry, 0)
+	q.iterate(ctx, refundRetryKeyPrefix, func(retry RefundRetry) bool {
+		retries = append(retries, retry)
+		return false
+	})
+	return retries
+}
+
+// GetDeadLetters returns the items which are not refunded after MaxRefundAttempts attempts
+func (q RefundQueue) GetDeadLetters(ctx sdk.Context) []RefundRetry {
+	retries := make([]RefundRetry, 0)
+	q.iterate(ctx, refundDeadKeyPrefix, func(retry RefundRetry) bool {
+		retries = append(retries, retry)
+		return false
+	})
+	return retries
+}
+
+func (q RefundQueue) iterate(ctx sdk.Context, prefix []byte, process func(retry RefundRetry) (stop bool)) {
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(q.storeKey), prefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var retry RefundRetry
+		q.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &retry)
+		if process(retry) {
+			return
+		}
+	}
+}
+
+// retryRefund refunds the item of a retry again, it succeeds if the item has been removed by other means
+func retryRefund(ctx sdk.Context, timelockKeeper timelock.Keeper, swapKeeper swap.Keeper, retry RefundRetry) error {
+	switch retry.Queue {
+	case FusionQueueTimeLocks:
+		addr, id, err := timelock.ParseKeyRecord(retry.Key)
+		if err != nil {
+			return err
+		}
+		if _, found := timelockKeeper.GetTimeLockRecord(ctx, addr, id); !found {
+			return nil
+		}
+		if sdkErr := timelockKeeper.TimeUnlock(ctx, addr, id, true); sdkErr != nil {
+			return sdkErr
+		}
+		return nil
+	case FusionQueueVestings:
+		addr, id, err := timelock.ParseKeyVestingRecord(retry.Key)
+		if err != nil {
+			return err
+		}
+		if _, found := timelockKeeper.GetVestingRecord(ctx, addr, id); !found {
+			return nil
+		}
+		if sdkErr := timelockKeeper.VestingRefund(ctx, addr, id); sdkErr != nil {
+			return sdkErr
+		}
+		return nil
+	case FusionQueueSwaps:
+		if !bytes.HasPrefix(retry.Key, swap.HashKey) {
+			return fmt.Errorf("invalid swap key %X", retry.Key)
+		}
+		swapID := retry.Key[len(swap.HashKey):]
+		swapItem := swapKeeper.GetSwap(ctx, swapID)
+		if swapItem == nil || swapItem.Status != swap.Open {
+			return nil
+		}
+		result := swap.HandleRefundHashTimerLockedTransferAfterBCFusion(ctx, swapKeeper, swap.RefundHTLTMsg{
+			From:   swapItem.From,
+			SwapID: swapID,
+		})
+		if !result.IsOK() {
+			return errors.New(result.Log)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown refund queue %s", retry.Queue)
+	}
+}
@@ -0,0 +1,161 @@
+package tokens_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	sdkstore "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	bca "github.com/bnb-chain/node/app"
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/tokens"
+	"github.com/bnb-chain/node/plugins/tokens/swap"
+	"github.com/bnb-chain/node/plugins/tokens/timelock"
+)
+
+func setupRefund(t *testing.T) (sdk.Context, auth.AccountKeeper, timelock.Keeper, swap.Keeper, tokens.RefundQueue) {
+	cdc := bca.MakeCodec()
+	ms := sdkstore.NewCommitMultiStore(dbm.NewMemDB())
+	for _, key := range []sdk.StoreKey{common.AccountStoreKey, common.TimeLockStoreKey, common.AtomicSwapStoreKey} {
+		ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+	}
+	require.NoError(t, ms.LoadLatestVersion())
+
+	accountCache := auth.NewAccountCache(auth.NewAccountStoreCache(cdc, ms.GetKVStore(common.AccountStoreKey), 10))
+	ctx := sdk.NewContext(ms, abci.Header{Time: time.Now()}, sdk.RunTxModeDeliver, log.NewNopLogger()).
+		WithAccountCache(accountCache)
+	accountKeeper := auth.NewAccountKeeper(cdc, common.AccountStoreKey, types.ProtoAppAccount)
+	coinKeeper := bank.NewBaseKeeper(accountKeeper)
+	timeLockKeeper := timelock.NewKeeper(cdc, common.TimeLockStoreKey, coinKeeper, accountKeeper, timelock.DefaultCodespace)
+	swapKeeper := swap.NewKeeper(cdc, common.AtomicSwapStoreKey, coinKeeper, nil, swap.DefaultCodespace)
+	return ctx, accountKeeper, timeLockKeeper, swapKeeper, tokens.NewRefundQueue(cdc, common.TimeLockStoreKey)
+}
+
+func setUpgradeHeights(height int64, names ...string) func() {
+	old := make(map[string]int64)
+	oldHeight := sdk.UpgradeMgr.GetHeight()
+	for _, name := range names {
+		old[name] = sdk.UpgradeMgr.GetUpgradeHeight(name)
+		sdk.UpgradeMgr.AddUpgradeHeight(name, height)
+	}
+	return func() {
+		for name, height := range old {
+			sdk.UpgradeMgr.AddUpgradeHeight(name, height)
+		}
+		sdk.UpgradeMgr.SetHeight(oldHeight)
+	}
+}
+
+func TestRefundQueue_Fail(t *testing.T) {
+	ctx, _, _, _, queue := setupRefund(t)
+	key := timelock.KeyRecord(newAddr(), 1)
+
+	ctx = ctx.WithBlockHeight(10)
+	retry := queue.Fail(ctx, tokens.FusionQueueTimeLocks, key, "item", "failed")
+	require.Equal(t, tokens.RefundRetry{Queue: tokens.FusionQueueTimeLocks, Key: key, Item: "item", Attempts: 1,
+		LastHeight: 10, NextHeight: 10 + tokens.RefundRetryBackoff, Reason: "failed"}, retry)
+	require.True(t, queue.Contains(ctx, tokens.FusionQueueTimeLocks, key))
+	require.False(t, queue.Contains(ctx, tokens.FusionQueueSwaps, key))
+	require.Empty(t, queue.GetDueRetries(ctx, tokens.MaxRefundRetryItems))
+	require.Len(t, queue.GetDueRetries(ctx.WithBlockHeight(retry.NextHeight), tokens.MaxRefundRetryItems), 1)
+
+	// the backoff is doubled for every attempt
+	retry = queue.Fail(ctx.WithBlockHeight(retry.NextHeight), tokens.FusionQueueTimeLocks, key, "item", "failed again")
+	require.Equal(t, int64(2), retry.Attempts)
+	require.Equal(t, int64(10+3*tokens.RefundRetryBackoff), retry.NextHeight)
+	require.Equal(t, "failed again", retry.Reason)
+
+	for i := retry.Attempts; i < tokens.MaxRefundAttempts; i++ {
+		retry = queue.Fail(ctx.WithBlockHeight(retry.NextHeight), tokens.FusionQueueTimeLocks, key, "item", "failed")
+	}
+	require.Equal(t, int64(tokens.MaxRefundAttempts), retry.Attempts)
+	require.Equal(t, int64(0), retry.NextHeight)
+	require.Empty(t, queue.GetRetries(ctx))
+	require.Equal(t, []tokens.RefundRetry{retry}, queue.GetDeadLetters(ctx))
+	require.True(t, queue.Contains(ctx, tokens.FusionQueueTimeLocks, key))
+
+	queue.Fail(ctx, tokens.FusionQueueSwaps, key, "swap", "failed")
+	require.Len(t, queue.GetRetries(ctx), 1)
+	queue.Remove(ctx, tokens.FusionQueueSwaps, key)
+	require.Empty(t, queue.GetRetries(ctx))
+}
+
+func TestEndBlocker_RefundRetry(t *testing.T) {
+	defer setUpgradeHeights(1, upgrade.SecondSunset, upgrade.SunsetRefundRetry)()
+	ctx, accountKeeper, timeLockKeeper, swapKeeper, queue := setupRefund(t)
+	monitor := tokens.NewFusionMonitor()
+
+	_, acc := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	require.NoError(t, acc.SetCoins(sdk.Coins{sdk.NewCoin("BNB", 100e8), sdk.NewCoin("XYZ-000", 100e8)}))
+	accountKeeper.SetAccount(ctx, acc)
+	for i, symbol := range []string{"BNB", "XYZ-000"} {
+		acc = accountKeeper.GetAccount(ctx, acc.GetAddress())
+		require.NoError(t, acc.SetSequence(int64(i)))
+		accountKeeper.SetAccount(ctx, acc)
+		_, err := timeLockKeeper.TimeLock(ctx, acc.GetAddress(), "Test", sdk.Coins{sdk.NewCoin(symbol, 1e8)}, time.Now().Add(time.Hour))
+		require.Nil(t, err)
+	}
+	// the unlock of the second record fails since the coins are missing
+	lockAcc := accountKeeper.GetAccount(ctx, timelock.TimeLockCoinsAccAddr)
+	require.NoError(t, lockAcc.SetCoins(sdk.Coins{sdk.NewCoin("BNB", 1e8)}))
+	accountKeeper.SetAccount(ctx, lockAcc)
+
+	sdk.UpgradeMgr.SetHeight(10)
+	tokens.EndBlocker(ctx.WithBlockHeight(10), timeLockKeeper, swapKeeper, queue, monitor)
+	_, found := timeLockKeeper.GetTimeLockRecord(ctx, acc.GetAddress(), 0)
+	require.False(t, found)
+	_, found = timeLockKeeper.GetTimeLockRecord(ctx, acc.GetAddress(), 1)
+	require.True(t, found)
+	retries := queue.GetRetries(ctx)
+	require.Len(t, retries, 1)
+	require.Equal(t, timelock.KeyRecord(acc.GetAddress(), 1), retries[0].Key)
+	require.Equal(t, int64(10+tokens.RefundRetryBackoff), retries[0].NextHeight)
+	require.Len(t, monitor.Failures(), 1)
+
+	// the queued record is skipped until its backoff passes
+	sdk.UpgradeMgr.SetHeight(11)
+	tokens.EndBlocker(ctx.WithBlockHeight(11), timeLockKeeper, swapKeeper, queue, monitor)
+	require.Equal(t, retries, queue.GetRetries(ctx))
+
+	height := retries[0].NextHeight
+	sdk.UpgradeMgr.SetHeight(height)
+	tokens.EndBlocker(ctx.WithBlockHeight(height), timeLockKeeper, swapKeeper, queue, monitor)
+	retries = queue.GetRetries(ctx)
+	require.Len(t, retries, 1)
+	require.Equal(t, int64(2), retries[0].Attempts)
+
+	// the retry succeeds once the coins are back
+	lockAcc = accountKeeper.GetAccount(ctx, timelock.TimeLockCoinsAccAddr)
+	require.NoError(t, lockAcc.SetCoins(sdk.Coins{sdk.NewCoin("XYZ-000", 1e8)}))
+	accountKeeper.SetAccount(ctx, lockAcc)
+	height = retries[0].NextHeight
+	sdk.UpgradeMgr.SetHeight(height)
+	tokens.EndBlocker(ctx.WithBlockHeight(height), timeLockKeeper, swapKeeper, queue, monitor)
+	_, found = timeLockKeeper.GetTimeLockRecord(ctx, acc.GetAddress(), 1)
+	require.False(t, found)
+	require.Empty(t, queue.GetRetries(ctx))
+	require.Empty(t, queue.GetDeadLetters(ctx))
+	require.Empty(t, monitor.Failures())
+	require.Equal(t, int64(100e8), accountKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("XYZ-000"))
+}
+
+func TestFusionRefundQueries(t *testing.T) {
+	for _, path := range []string{"/fusion/retries", "/fusion/dead-letters"} {
+		res := app.Query(abci.RequestQuery{Path: path})
+		require.Equal(t, uint32(sdk.ABCICodeOK), res.Code, res.Log)
+		var retries []tokens.RefundRetry
+		require.NoError(t, app.Codec.UnmarshalBinaryLengthPrefixed(res.Value, &retries))
+		require.Empty(t, retries)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/bnb-chain/node/plugins/tokens/burn"
 	"github.com/bnb-chain/node/plugins/tokens/freeze"
 	"github.com/bnb-chain/node/plugins/tokens/issue"
+	"github.com/bnb-chain/node/plugins/tokens/metadata"
 	"github.com/bnb-chain/node/plugins/tokens/ownership"
 	"github.com/bnb-chain/node/plugins/tokens/seturi"
 	"github.com/bnb-chain/node/plugins/tokens/store"
@@ -25,5 +26,6 @@ func Routes(tokenMapper store.Mapper, accKeeper auth.AccountKeeper, keeper bank.
 	routes[swap.AtomicSwapRoute] = swap.NewHandler(swapKeeper)
 	routes[seturi.SetURIRoute] = seturi.NewHandler(tokenMapper)
 	routes[ownership.Route] = ownership.NewHandler(tokenMapper, keeper)
+	routes[metadata.Route] = metadata.NewHandler(tokenMapper)
 	return routes
 }
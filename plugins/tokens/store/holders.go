@@ -0,0 +1,192 @@
+package store
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/bnb-chain/node/common/types"
+)
+
+// Holder is the balance of a token held by an address
+type Holder struct {
+	Address sdk.AccAddress `json:"address"`
+	Free    int64          `json:"free"`
+	Frozen  int64          `json:"frozen"`
+	Locked  int64          `json:"locked"`
+}
+
+func (h Holder) Total() int64 {
+	return h.Free + h.Frozen + h.Locked
+}
+
+// TokenHolders is the holder statistics of a token
+type TokenHolders struct {
+	Symbol      string   `json:"symbol"`
+	Holders     int64    `json:"holders"`
+	TotalFree   int64    `json:"total_free"`
+	TotalFrozen int64    `json:"total_frozen"`
+	TotalLocked int64    `json:"total_locked"`
+	Top         []Holder `json:"top"` // sorted by the total balance in descending order
+}
+
+type tokenHolders struct {
+	holders                             map[string]Holder // address bytes -> holder
+	totalFree, totalFrozen, totalLocked int64
+	top                                 []Holder // cache of the top holders, nil once the holders change
+}
+
+// HolderIndex indexes the holders of every token in memory. It's built from the account store on startup and
+// updated with the accounts changed in every block, so the holders of a token can be queried without scanning
+// the account store.
+type HolderIndex struct {
+	mtx      sync.Mutex
+	maxTop   int
+	tokens   map[string]*tokenHolders
+	accounts map[string][]string // address bytes -> symbols held
+}
+
+func NewHolderIndex(maxTop int) *HolderIndex {
+	return &HolderIndex{
+		maxTop:   maxTop,
+		tokens:   make(map[string]*tokenHolders),
+		accounts: make(map[string][]string),
+	}
+}
+
+func (index *HolderIndex) MaxTop() int {
+	return index.maxTop
+}
+
+// Build indexes all the accounts in the account store
+func (index *HolderIndex) Build(ctx sdk.Context, accountKeeper auth.AccountKeeper) {
+	accountKeeper.IterateAccounts(ctx, func(acc sdk.Account) bool {
+		if namedAcc, ok := acc.(types.NamedAccount); ok {
+			index.Update(acc.GetAddress(), namedAcc)
+		}
+		return false
+	})
+}
+
+// Update replaces the balances of the address with the account, acc is nil if the account is deleted
+func (index *HolderIndex) Update(addr sdk.AccAddress, acc types.NamedAccount) {
+	index.mtx.Lock()
+	defer index.mtx.Unlock()
+
+	key := string(addr.Bytes())
+	for _, symbol := range index.accounts[key] {
+		token := index.tokens[symbol]
+		holder := token.holders[key]
+		token.totalFree -= holder.Free
+		token.totalFrozen -= holder.Frozen
+		token.totalLocked -= holder.Locked
+		token.top = nil
+		delete(token.holders, key)
+		if len(token.holders) == 0 {
+			delete(index.tokens, symbol)
+		}
+	}
+	delete(index.accounts, key)
+	if acc == nil {
+		return
+	}
+
+	balances := make(map[string]*Holder)
+	balance := func(symbol string) *Holder {
+		if h, ok := balances[symbol]; ok {
+			return h
+		}
+		h := &Holder{Address: addr}
+		balances[symbol] = h
+		return h
+	}
+	for _, coin := range acc.GetCoins() {
+		balance(coin.Denom).Free = coin.Amount
+	}
+	for _, coin := range acc.GetFrozenCoins() {
+		balance(coin.Denom).Frozen = coin.Amount
+	}
+	for _, coin := range acc.GetLockedCoins() {
+		balance(coin.Denom).Locked = coin.Amount
+	}
+
+	symbols := make([]string, 0, len(balances))
+	for symbol, holder := range balances {
+		if holder.Total() <= 0 {
+			continue
+		}
+		token, ok := index.tokens[symbol]
+		if !ok {
+			token = &tokenHolders{holders: make(map[string]Holder)}
+			index.tokens[symbol] = token
+		}
+		token.holders[key] = *holder
+		token.totalFree += holder.Free
+		token.totalFrozen += holder.Frozen
+		token.totalLocked += holder.Locked
+		token.top = nil
+		symbols = append(symbols, symbol)
+	}
+	if len(symbols) > 0 {
+		index.accounts[key] = symbols
+	}
+}
+
+// Holders returns the holder statistics of the token with at most limit top holders
+func (index *HolderIndex) Holders(symbol string, limit int) TokenHolders {
+	if limit <= 0 || limit > index.maxTop {
+		limit = index.maxTop
+	}
+	index.mtx.Lock()
+	defer index.mtx.Unlock()
+
+	res := TokenHolders{Symbol: symbol, Top: make([]Holder, 0)}
+	token, ok := index.tokens[symbol]
+	if !ok {
+		return res
+	}
+	if token.top == nil {
+		token.top = topHolders(token.holders, index.maxTop)
+	}
+	res.Holders = int64(len(token.holders))
+	res.TotalFree, res.TotalFrozen, res.TotalLocked = token.totalFree, token.totalFrozen, token.totalLocked
+	if limit > len(token.top) {
+		limit = len(token.top)
+	}
+	res.Top = append(res.Top, token.top[:limit]...)
+	return res
+}
+
+// holderHeap is a min heap of the holders by the total balance, ties are broken by the address
+type holderHeap []Holder
+
+func (h holderHeap) Len() int { return len(h) }
+func (h holderHeap) Less(i, j int) bool {
+	if h[i].Total() != h[j].Total() {
+		return h[i].Total() < h[j].Total()
+	}
+	return string(h[i].Address) > string(h[j].Address)
+}
+func (h holderHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *holderHeap) Push(x interface{}) { *h = append(*h, x.(Holder)) }
+func (h *holderHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+func topHolders(holders map[string]Holder, n int) []Holder {
+	top := make(holderHeap, 0, n+1)
+	for _, holder := range holders {
+		heap.Push(&top, holder)
+		if top.Len() > n {
+			heap.Pop(&top)
+		}
+	}
+	sort.Sort(sort.Reverse(top))
+	return top
+}
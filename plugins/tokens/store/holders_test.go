@@ -0,0 +1,51 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+)
+
+func newAccount(addr sdk.AccAddress, free, frozen, locked sdk.Coins) types.NamedAccount {
+	acc := &types.AppAccount{}
+	acc.SetAddress(addr)
+	acc.SetCoins(free)
+	acc.SetFrozenCoins(frozen)
+	acc.SetLockedCoins(locked)
+	return acc
+}
+
+func TestHolderIndex(t *testing.T) {
+	index := NewHolderIndex(2)
+	addr1 := sdk.AccAddress([]byte("addr1"))
+	addr2 := sdk.AccAddress([]byte("addr2"))
+	addr3 := sdk.AccAddress([]byte("addr3"))
+
+	index.Update(addr1, newAccount(addr1, sdk.Coins{sdk.NewCoin("BNB", 100), sdk.NewCoin("XYZ-000", 10)}, nil, nil))
+	index.Update(addr2, newAccount(addr2, sdk.Coins{sdk.NewCoin("BNB", 50)}, sdk.Coins{sdk.NewCoin("BNB", 100)}, nil))
+	index.Update(addr3, newAccount(addr3, sdk.Coins{sdk.NewCoin("BNB", 10)}, nil, sdk.Coins{sdk.NewCoin("BNB", 5)}))
+
+	holders := index.Holders("BNB", 0)
+	require.Equal(t, int64(3), holders.Holders)
+	require.Equal(t, int64(160), holders.TotalFree)
+	require.Equal(t, int64(100), holders.TotalFrozen)
+	require.Equal(t, int64(5), holders.TotalLocked)
+	require.Equal(t, []Holder{{addr2, 50, 100, 0}, {addr1, 100, 0, 0}}, holders.Top)
+	require.Len(t, index.Holders("BNB", 1).Top, 1)
+
+	// addr1 transfers all its BNB to addr3
+	index.Update(addr1, newAccount(addr1, sdk.Coins{sdk.NewCoin("XYZ-000", 10)}, nil, nil))
+	index.Update(addr3, newAccount(addr3, sdk.Coins{sdk.NewCoin("BNB", 110)}, nil, sdk.Coins{sdk.NewCoin("BNB", 5)}))
+	holders = index.Holders("BNB", 10)
+	require.Equal(t, int64(2), holders.Holders)
+	require.Equal(t, int64(160), holders.TotalFree)
+	require.Equal(t, []Holder{{addr2, 50, 100, 0}, {addr3, 110, 0, 5}}, holders.Top)
+
+	require.Equal(t, int64(1), index.Holders("XYZ-000", 0).Holders)
+	index.Update(addr1, nil)
+	require.Equal(t, TokenHolders{Symbol: "XYZ-000", Top: []Holder{}}, index.Holders("XYZ-000", 0))
+}
@@ -41,19 +41,23 @@ type Mapper interface {
 	UpdateBind(ctx sdk.Context, symbol string, contractAddress string, decimals int8) error
 	UpdateMiniTokenURI(ctx sdk.Context, symbol string, uri string) error
 	UpdateOwner(ctx sdk.Context, symbol string, newOwner sdk.AccAddress) error
+	GetTokenMetadata(ctx sdk.Context, symbol string) (*types.TokenMetadata, error)
+	SetTokenMetadata(ctx sdk.Context, symbol string, metadata types.TokenMetadata) error
 }
 
 var _ Mapper = mapper{}
 
 type mapper struct {
-	key sdk.StoreKey
-	cdc *wire.Codec
+	key         sdk.StoreKey
+	metadataKey sdk.StoreKey // the metadata of the tokens are kept in their own store, keyed by the symbols
+	cdc         *wire.Codec
 }
 
-func NewMapper(cdc *wire.Codec, key sdk.StoreKey) mapper {
+func NewMapper(cdc *wire.Codec, key sdk.StoreKey, metadataKey sdk.StoreKey) mapper {
 	return mapper{
-		key: key,
-		cdc: cdc,
+		key:         key,
+		metadataKey: metadataKey,
+		cdc:         cdc,
 	}
 }
 
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+)
+
+func (m mapper) GetTokenMetadata(ctx sdk.Context, symbol string) (*types.TokenMetadata, error) {
+	store := ctx.KVStore(m.metadataKey)
+	bz := store.Get([]byte(strings.ToUpper(symbol)))
+	if bz == nil {
+		return nil, fmt.Errorf("metadata of token(%v) not found", symbol)
+	}
+
+	var metadata types.TokenMetadata
+	if err := m.cdc.UnmarshalBinaryBare(bz, &metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+func (m mapper) SetTokenMetadata(ctx sdk.Context, symbol string, metadata types.TokenMetadata) error {
+	if len(symbol) == 0 {
+		return errors.New("symbol cannot be empty")
+	}
+	if err := metadata.Validate(); err != nil {
+		return err
+	}
+
+	symbol = strings.ToUpper(symbol)
+	if !ctx.KVStore(m.key).Has([]byte(symbol)) {
+		return errors.New("token does not exist")
+	}
+
+	bz, err := m.cdc.MarshalBinaryBare(metadata)
+	if err != nil {
+		return err
+	}
+	ctx.KVStore(m.metadataKey).Set([]byte(symbol), bz)
+	return nil
+}
@@ -18,6 +18,9 @@ const (
 	CodeCanNotUnlock               sdk.CodeType = 7
 	CodeUnknownTimeLock            sdk.CodeType = 8
 	CodeTimeLockRecordAlreadyExist sdk.CodeType = 9
+	CodeInvalidVestingSchedule     sdk.CodeType = 10
+	CodeVestingRecordDoesNotExist  sdk.CodeType = 11
+	CodeVestingNotRevocable        sdk.CodeType = 12
 )
 
 //----------------------------------------
@@ -60,3 +63,17 @@ func ErrTimeLockRecordAlreadyExist(codespace sdk.CodespaceType, addr sdk.AccAddr
 	return sdk.NewError(codespace, CodeTimeLockRecordAlreadyExist,
 		fmt.Sprintf("Time lock already exists, address=%s, id=%d", addr.String(), id))
 }
+
+func ErrInvalidVestingSchedule(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidVestingSchedule, fmt.Sprintf("Invalid vesting schedule: %s", msg))
+}
+
+func ErrVestingRecordDoesNotExist(codespace sdk.CodespaceType, addr sdk.AccAddress, id int64) sdk.Error {
+	return sdk.NewError(codespace, CodeVestingRecordDoesNotExist,
+		fmt.Sprintf("Vesting does not exist, address=%s, id=%d", addr.String(), id))
+}
+
+func ErrVestingNotRevocable(codespace sdk.CodespaceType, addr sdk.AccAddress, id int64) sdk.Error {
+	return sdk.NewError(codespace, CodeVestingNotRevocable,
+		fmt.Sprintf("Vesting is not revocable, address=%s, id=%d", addr.String(), id))
+}
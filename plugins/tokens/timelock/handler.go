@@ -5,6 +5,8 @@ import (
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 func NewHandler(keeper Keeper) sdk.Handler {
@@ -14,14 +16,29 @@ func NewHandler(keeper Keeper) sdk.Handler {
 			if sdk.IsUpgrade(sdk.FirstSunsetFork) {
 				return sdk.ErrMsgNotSupported("").Result()
 			}
+			if len(msg.Beneficiary) != 0 && !sdk.IsUpgrade(upgrade.TimeLockBeneficiary) {
+				return sdk.ErrMsgNotSupported("time lock for a beneficiary is not supported yet").Result()
+			}
 			return handleTimeLock(ctx, keeper, msg)
 		case TimeUnlockMsg:
+			if len(msg.Grantor) != 0 && !sdk.IsUpgrade(upgrade.TimeLockBeneficiary) {
+				return sdk.ErrMsgNotSupported("time unlock by a beneficiary is not supported yet").Result()
+			}
 			return handleTimeUnlock(ctx, keeper, msg)
 		case TimeRelockMsg:
 			if sdk.IsUpgrade(sdk.FirstSunsetFork) {
 				return sdk.ErrMsgNotSupported("").Result()
 			}
 			return handleTimeRelock(ctx, keeper, msg)
+		case VestingLockMsg:
+			if sdk.IsUpgrade(sdk.FirstSunsetFork) {
+				return sdk.ErrMsgNotSupported("").Result()
+			}
+			return handleVestingLock(ctx, keeper, msg)
+		case VestingClaimMsg:
+			return handleVestingClaim(ctx, keeper, msg)
+		case VestingRevokeMsg:
+			return handleVestingRevoke(ctx, keeper, msg)
 		default:
 			errMsg := fmt.Sprintf("unrecognized time lock message type: %T", msg)
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -30,7 +47,7 @@ func NewHandler(keeper Keeper) sdk.Handler {
 }
 
 func handleTimeLock(ctx sdk.Context, keeper Keeper, msg TimeLockMsg) sdk.Result {
-	record, err := keeper.TimeLock(ctx, msg.From, msg.Description, msg.Amount, time.Unix(msg.LockTime, 0))
+	record, err := keeper.TimeLockFor(ctx, msg.From, msg.Beneficiary, msg.Description, msg.Amount, time.Unix(msg.LockTime, 0))
 	if err != nil {
 		return err.Result()
 	}
@@ -60,7 +77,12 @@ func handleTimeRelock(ctx sdk.Context, keeper Keeper, msg TimeRelockMsg) sdk.Res
 }
 
 func handleTimeUnlock(ctx sdk.Context, keeper Keeper, msg TimeUnlockMsg) sdk.Result {
-	err := keeper.TimeUnlock(ctx, msg.From, msg.Id, false)
+	var err sdk.Error
+	if len(msg.Grantor) != 0 {
+		err = keeper.BeneficiaryTimeUnlock(ctx, msg.From, msg.Grantor, msg.Id)
+	} else {
+		err = keeper.TimeUnlock(ctx, msg.From, msg.Id, false)
+	}
 	if err != nil {
 		return err.Result()
 	}
@@ -70,3 +92,39 @@ func handleTimeUnlock(ctx sdk.Context, keeper Keeper, msg TimeUnlockMsg) sdk.Res
 		Data: timeLockId,
 	}
 }
+
+func handleVestingLock(ctx sdk.Context, keeper Keeper, msg VestingLockMsg) sdk.Result {
+	record, err := keeper.VestingLock(ctx, msg.From, msg.Beneficiary, msg.Description, msg.Amount,
+		time.Unix(msg.StartTime, 0), time.Unix(msg.CliffTime, 0), time.Unix(msg.EndTime, 0), msg.Period, msg.Revocable)
+	if err != nil {
+		return err.Result()
+	}
+
+	vestingId := []byte(fmt.Sprintf("%d", record.Id))
+	return sdk.Result{
+		Data: vestingId,
+	}
+}
+
+func handleVestingClaim(ctx sdk.Context, keeper Keeper, msg VestingClaimMsg) sdk.Result {
+	released, err := keeper.VestingClaim(ctx, msg.From, msg.Id)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{
+		Data: []byte(released.String()),
+	}
+}
+
+func handleVestingRevoke(ctx sdk.Context, keeper Keeper, msg VestingRevokeMsg) sdk.Result {
+	err := keeper.VestingRevoke(ctx, msg.From, msg.Beneficiary, msg.Id)
+	if err != nil {
+		return err.Result()
+	}
+
+	vestingId := []byte(fmt.Sprintf("%d", msg.Id))
+	return sdk.Result{
+		Data: vestingId,
+	}
+}
@@ -50,11 +50,17 @@ func (keeper Keeper) setTimeLockRecord(ctx sdk.Context, addr sdk.AccAddress, rec
 	store := ctx.KVStore(keeper.storeKey)
 	bz := keeper.cdc.MustMarshalBinaryLengthPrefixed(record)
 	store.Set(KeyRecord(addr, record.Id), bz)
+	if record.HasBeneficiary() {
+		store.Set(KeyBeneficiaryRecord(record.Beneficiary, addr, record.Id), KeyRecord(addr, record.Id))
+	}
 }
 
-func (keeper Keeper) deleteTimeLockRecord(ctx sdk.Context, addr sdk.AccAddress, recordId int64) {
+func (keeper Keeper) deleteTimeLockRecord(ctx sdk.Context, addr sdk.AccAddress, record TimeLockRecord) {
 	store := ctx.KVStore(keeper.storeKey)
-	store.Delete(KeyRecord(addr, recordId))
+	store.Delete(KeyRecord(addr, record.Id))
+	if record.HasBeneficiary() {
+		store.Delete(KeyBeneficiaryRecord(record.Beneficiary, addr, record.Id))
+	}
 }
 
 func (keeper Keeper) getTimeLockRecordsIterator(ctx sdk.Context, addr sdk.AccAddress) sdk.Iterator {
@@ -74,6 +80,8 @@ func (keeper Keeper) GetTimeLockRecord(ctx sdk.Context, addr sdk.AccAddress, rec
 	return record, true
 }
 
+// GetTimeLockRecords returns the time lock records locked by the address, including the ones for a third-party
+// beneficiary, and the ones locked for the address by others
 func (keeper Keeper) GetTimeLockRecords(ctx sdk.Context, addr sdk.AccAddress) []TimeLockRecord {
 	var records []TimeLockRecord
 	iterator := keeper.getTimeLockRecordsIterator(ctx, addr)
@@ -84,6 +92,19 @@ func (keeper Keeper) GetTimeLockRecords(ctx sdk.Context, addr sdk.AccAddress) []
 		records = append(records, record)
 	}
 
+	store := ctx.KVStore(keeper.storeKey)
+	beneficiaryIterator := sdk.KVStorePrefixIterator(store, KeyBeneficiaryRecordSubSpace(addr))
+	defer beneficiaryIterator.Close()
+	for ; beneficiaryIterator.Valid(); beneficiaryIterator.Next() {
+		bz := store.Get(beneficiaryIterator.Value())
+		if bz == nil {
+			continue
+		}
+		record := TimeLockRecord{}
+		keeper.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &record)
+		records = append(records, record)
+	}
+
 	sort.Sort(TimeLockRecords(records))
 
 	return records
@@ -91,7 +112,7 @@ func (keeper Keeper) GetTimeLockRecords(ctx sdk.Context, addr sdk.AccAddress) []
 
 func (kp *Keeper) GetTimeLockRecordIterator(ctx sdk.Context) (iterator store.Iterator) {
 	kvStore := ctx.KVStore(kp.storeKey)
-	return sdk.KVStorePrefixIterator(kvStore, []byte{})
+	return sdk.KVStorePrefixIterator(kvStore, recordKeyPrefix)
 }
 
 func (keeper Keeper) getTimeLockId(ctx sdk.Context, from sdk.AccAddress) int64 {
@@ -100,6 +121,13 @@ func (keeper Keeper) getTimeLockId(ctx sdk.Context, from sdk.AccAddress) int64 {
 }
 
 func (keeper Keeper) TimeLock(ctx sdk.Context, from sdk.AccAddress, description string, amount sdk.Coins, lockTime time.Time) (TimeLockRecord, sdk.Error) {
+	return keeper.TimeLockFor(ctx, from, nil, description, amount, lockTime)
+}
+
+// TimeLockFor locks the coins of the grantor for a third-party beneficiary, only the beneficiary can unlock them after
+// the lock time. The coins are locked for the grantor itself if the beneficiary is empty.
+func (keeper Keeper) TimeLockFor(ctx sdk.Context, from, beneficiary sdk.AccAddress, description string, amount sdk.Coins,
+	lockTime time.Time) (TimeLockRecord, sdk.Error) {
 	if !lockTime.After(ctx.BlockHeader().Time.Add(MinLockTime)) {
 		return TimeLockRecord{}, ErrInvalidLockTime(DefaultCodespace,
 			fmt.Sprintf("lock time(%s) should be %d minute(s) after now(%s)", lockTime.UTC().String(),
@@ -123,27 +151,57 @@ func (keeper Keeper) TimeLock(ctx sdk.Context, from sdk.AccAddress, description
 		Amount:      amount,
 		LockTime:    lockTime,
 	}
+	if len(beneficiary) != 0 && !beneficiary.Equals(from) {
+		record.Grantor = from
+		record.Beneficiary = beneficiary
+	}
 	keeper.setTimeLockRecord(ctx, from, record)
 	return record, nil
 }
 
+// TimeUnlock unlocks a time lock record of the address, the coins locked for a third-party beneficiary can only be
+// unlocked by the beneficiary with BeneficiaryTimeUnlock, except that they are refunded to the beneficiary after
+// BC fusion
 func (keeper Keeper) TimeUnlock(ctx sdk.Context, from sdk.AccAddress, recordId int64, isBCFusionRefund bool) sdk.Error {
 	record, found := keeper.GetTimeLockRecord(ctx, from, recordId)
 	if !found {
 		return ErrTimeLockRecordDoesNotExist(DefaultCodespace, from, recordId)
 	}
 
+	if !isBCFusionRefund && record.HasBeneficiary() {
+		return ErrCanNotUnlock(DefaultCodespace, fmt.Sprintf("only the beneficiary(%s) can unlock the time lock",
+			record.Beneficiary.String()))
+	}
+
+	return keeper.unlock(ctx, from, record, isBCFusionRefund)
+}
+
+// BeneficiaryTimeUnlock unlocks a time lock record locked by the grantor for the beneficiary
+func (keeper Keeper) BeneficiaryTimeUnlock(ctx sdk.Context, beneficiary, grantor sdk.AccAddress, recordId int64) sdk.Error {
+	record, found := keeper.GetTimeLockRecord(ctx, grantor, recordId)
+	if !found || !record.Beneficiary.Equals(beneficiary) {
+		return ErrTimeLockRecordDoesNotExist(DefaultCodespace, grantor, recordId)
+	}
+
+	return keeper.unlock(ctx, grantor, record, false)
+}
+
+func (keeper Keeper) unlock(ctx sdk.Context, addr sdk.AccAddress, record TimeLockRecord, isBCFusionRefund bool) sdk.Error {
 	if !isBCFusionRefund && ctx.BlockHeader().Time.Before(record.LockTime) {
 		return ErrCanNotUnlock(DefaultCodespace, fmt.Sprintf("lock time(%s) is after now(%s)",
 			record.LockTime.UTC().String(), ctx.BlockHeader().Time.UTC().String()))
 	}
 
-	_, err := keeper.ck.SendCoins(ctx, TimeLockCoinsAccAddr, from, record.Amount)
+	to := addr
+	if record.HasBeneficiary() {
+		to = record.Beneficiary
+	}
+	_, err := keeper.ck.SendCoins(ctx, TimeLockCoinsAccAddr, to, record.Amount)
 	if err != nil {
 		return err
 	}
 
-	keeper.deleteTimeLockRecord(ctx, from, recordId)
+	keeper.deleteTimeLockRecord(ctx, addr, record)
 	return nil
 }
 
@@ -153,6 +211,11 @@ func (keeper Keeper) TimeRelock(ctx sdk.Context, from sdk.AccAddress, recordId i
 		return ErrTimeLockRecordDoesNotExist(DefaultCodespace, from, recordId)
 	}
 
+	// the grantor should not be able to postpone the unlock of the beneficiary
+	if record.HasBeneficiary() {
+		return ErrInvalidRelock(DefaultCodespace, "time lock for a beneficiary can not be relocked")
+	}
+
 	if newRecord.Description != "" {
 		record.Description = newRecord.Description
 	}
@@ -296,3 +296,99 @@ func TestKeeper_TimeRelock(t *testing.T) {
 	require.Equal(t, newRecord.LockTime.UTC(), queryRecord.LockTime.UTC())
 	require.Equal(t, newRecord.Amount, queryRecord.Amount)
 }
+
+func TestKeeper_TimeLock_Beneficiary(t *testing.T) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accountCache := getAccountCache(cdc, cms)
+	ctx := sdk.NewContext(cms, abci.Header{Time: time.Now()}, sdk.RunTxModeDeliver, logger).WithAccountCache(accountCache)
+
+	_, grantor := testutils.NewAccount(ctx, accKeeper, 0)
+	_ = grantor.SetCoins(sdk.Coins{
+		sdk.NewCoin("BNB", 1000e8),
+	}.Sort())
+	accKeeper.SetAccount(ctx, grantor)
+	_, beneficiary := testutils.NewAccount(ctx, accKeeper, 0)
+	_ = beneficiary.SetCoins(sdk.Coins{
+		sdk.NewCoin("BNB", 100e8),
+	}.Sort())
+	accKeeper.SetAccount(ctx, beneficiary)
+
+	lockCoins := sdk.Coins{
+		sdk.NewCoin("BNB", 900e8),
+	}.Sort()
+
+	record, err := keeper.TimeLockFor(ctx, grantor.GetAddress(), beneficiary.GetAddress(), "Test", lockCoins, time.Now().Add(1000*time.Second))
+	require.Nil(t, err)
+	require.Equal(t, grantor.GetAddress(), record.Grantor)
+	require.Equal(t, beneficiary.GetAddress(), record.Beneficiary)
+
+	ownRecord, err := keeper.TimeLock(ctx, beneficiary.GetAddress(), "Test", sdk.Coins{sdk.NewCoin("BNB", 100e8)}, time.Now().Add(1000*time.Second))
+	require.Nil(t, err)
+	require.False(t, ownRecord.HasBeneficiary())
+
+	records := keeper.GetTimeLockRecords(ctx, grantor.GetAddress())
+	require.Len(t, records, 1)
+	require.Equal(t, beneficiary.GetAddress(), records[0].Beneficiary)
+	records = keeper.GetTimeLockRecords(ctx, beneficiary.GetAddress())
+	require.Len(t, records, 2)
+	require.NotEqual(t, records[0].HasBeneficiary(), records[1].HasBeneficiary())
+
+	// the grantor can neither unlock nor relock the time lock
+	ctx = ctx.WithBlockTime(time.Now().Add(2000 * time.Second))
+	err = keeper.TimeUnlock(ctx, grantor.GetAddress(), record.Id, false)
+	require.NotNil(t, err)
+	require.Equal(t, CodeCanNotUnlock, err.Code())
+	err = keeper.TimeRelock(ctx, grantor.GetAddress(), record.Id, TimeLockRecord{Description: "Test2"})
+	require.NotNil(t, err)
+	require.Equal(t, CodeInvalidRelock, err.Code())
+
+	err = keeper.BeneficiaryTimeUnlock(ctx, grantor.GetAddress(), grantor.GetAddress(), record.Id)
+	require.NotNil(t, err)
+	require.Equal(t, CodeTimeLockRecordDoesNotExist, err.Code())
+
+	err = keeper.BeneficiaryTimeUnlock(ctx.WithBlockTime(time.Now()), beneficiary.GetAddress(), grantor.GetAddress(), record.Id)
+	require.NotNil(t, err)
+	require.Equal(t, CodeCanNotUnlock, err.Code())
+
+	err = keeper.BeneficiaryTimeUnlock(ctx, beneficiary.GetAddress(), grantor.GetAddress(), record.Id)
+	require.Nil(t, err)
+	require.Equal(t, lockCoins, accKeeper.GetAccount(ctx, beneficiary.GetAddress()).GetCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 100e8)}, accKeeper.GetAccount(ctx, grantor.GetAddress()).GetCoins())
+
+	require.Len(t, keeper.GetTimeLockRecords(ctx, grantor.GetAddress()), 0)
+	records = keeper.GetTimeLockRecords(ctx, beneficiary.GetAddress())
+	require.Len(t, records, 1)
+	require.False(t, records[0].HasBeneficiary())
+}
+
+func TestKeeper_TimeUnlock_BeneficiaryRefund(t *testing.T) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accountCache := getAccountCache(cdc, cms)
+	ctx := sdk.NewContext(cms, abci.Header{Time: time.Now()}, sdk.RunTxModeDeliver, logger).WithAccountCache(accountCache)
+
+	_, grantor := testutils.NewAccount(ctx, accKeeper, 0)
+	_ = grantor.SetCoins(sdk.Coins{
+		sdk.NewCoin("BNB", 1000e8),
+	}.Sort())
+	accKeeper.SetAccount(ctx, grantor)
+	_, beneficiary := testutils.NewAccount(ctx, accKeeper, 0)
+
+	lockCoins := sdk.Coins{
+		sdk.NewCoin("BNB", 1000e8),
+	}.Sort()
+
+	record, err := keeper.TimeLockFor(ctx, grantor.GetAddress(), beneficiary.GetAddress(), "Test", lockCoins, time.Now().Add(1000*time.Second))
+	require.Nil(t, err)
+
+	// the coins are refunded to the beneficiary after BC fusion
+	err = keeper.TimeUnlock(ctx, grantor.GetAddress(), record.Id, true)
+	require.Nil(t, err)
+	require.Equal(t, lockCoins, accKeeper.GetAccount(ctx, beneficiary.GetAddress()).GetCoins())
+	require.Len(t, keeper.GetTimeLockRecords(ctx, beneficiary.GetAddress()), 0)
+}
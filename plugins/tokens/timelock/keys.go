@@ -9,6 +9,14 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+var (
+	recordKeyPrefix  = []byte("record:")
+	vestingKeyPrefix = []byte("vesting:")
+	// the next id of the vesting records, the vesting records of an address may come from multiple grantors so
+	// the ids are not derived from the sequence of an account like the time lock records
+	vestingIdKey = []byte("vestingId")
+)
+
 func KeyRecord(addr sdk.AccAddress, id int64) []byte {
 	return []byte(fmt.Sprintf("record:%d:%d", addr, id))
 }
@@ -18,7 +26,36 @@ func KeyRecordSubSpace(addr sdk.AccAddress) []byte {
 }
 
 func ParseKeyRecord(key []byte) (sdk.AccAddress, int64, error) {
-	key = bytes.TrimPrefix(key, []byte("record:"))
+	return parseKey(bytes.TrimPrefix(key, recordKeyPrefix))
+}
+
+// KeyBeneficiaryRecord is the index key of a time lock record for a third-party beneficiary, the record itself is
+// kept under the grantor
+func KeyBeneficiaryRecord(beneficiary, grantor sdk.AccAddress, id int64) []byte {
+	return []byte(fmt.Sprintf("beneficiary:%d:%d:%d", beneficiary, grantor, id))
+}
+
+func KeyBeneficiaryRecordSubSpace(beneficiary sdk.AccAddress) []byte {
+	return []byte(fmt.Sprintf("beneficiary:%d", beneficiary))
+}
+
+func KeyVestingRecord(addr sdk.AccAddress, id int64) []byte {
+	return []byte(fmt.Sprintf("vesting:%d:%d", addr, id))
+}
+
+func KeyVestingRecordSubSpace(addr sdk.AccAddress) []byte {
+	return []byte(fmt.Sprintf("vesting:%d", addr))
+}
+
+func ParseKeyVestingRecord(key []byte) (sdk.AccAddress, int64, error) {
+	return parseKey(bytes.TrimPrefix(key, vestingKeyPrefix))
+}
+
+// parseKey parses the hex address and the id of a key without its prefix
+func parseKey(key []byte) (sdk.AccAddress, int64, error) {
+	if len(key) < sdk.AddrLen*2+2 {
+		return []byte{}, 0, fmt.Errorf("invalid key %s", string(key))
+	}
 	accKeyStr := key[:sdk.AddrLen*2]
 	accKeyBytes, err := hex.DecodeString(string(accKeyStr))
 	if err != nil {
@@ -30,3 +30,26 @@ func TestParseKeyRecord(t *testing.T) {
 		return
 	}
 }
+
+func TestParseKeyVestingRecord(t *testing.T) {
+	account, err := sdk.AccAddressFromHex("5B38Da6a701c568545dCfcB03FcB875f56beddC4")
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	key := KeyVestingRecord(account, 7)
+
+	acc, id, err := ParseKeyVestingRecord(key)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if !acc.Equals(account) || id != 7 {
+		t.Fatal("parse vesting key error")
+		return
+	}
+
+	if _, _, err = ParseKeyVestingRecord([]byte("vesting:")); err == nil {
+		t.Fatal("invalid key should not be parsed")
+	}
+}
@@ -16,6 +16,11 @@ const (
 	MaxDescriptionLength       = 128
 	MinLockTime                = 60 * time.Second
 	MaxLockTime          int64 = 253402300800 //seconds of 10000-01-01, which is required by amino
+
+	// initial fees of the vesting msgs, they can be changed by the fee change proposals
+	VestingLockFee   = 1e6
+	VestingClaimFee  = 1e6
+	VestingRevokeFee = 1e6
 )
 
 var _ sdk.Msg = TimeLockMsg{}
@@ -25,6 +30,8 @@ type TimeLockMsg struct {
 	Description string         `json:"description"`
 	Amount      sdk.Coins      `json:"amount"`
 	LockTime    int64          `json:"lock_time"`
+	// the coins are locked for the sender itself if the beneficiary is empty
+	Beneficiary sdk.AccAddress `json:"beneficiary,omitempty"`
 }
 
 func NewTimeLockMsg(from sdk.AccAddress, description string, amount sdk.Coins, lockTime int64) TimeLockMsg {
@@ -36,17 +43,34 @@ func NewTimeLockMsg(from sdk.AccAddress, description string, amount sdk.Coins, l
 	}
 }
 
+func NewBeneficiaryTimeLockMsg(from, beneficiary sdk.AccAddress, description string, amount sdk.Coins, lockTime int64) TimeLockMsg {
+	return TimeLockMsg{
+		From:        from,
+		Description: description,
+		Amount:      amount,
+		LockTime:    lockTime,
+		Beneficiary: beneficiary,
+	}
+}
+
 func (msg TimeLockMsg) Route() string { return MsgRoute }
 func (msg TimeLockMsg) Type() string  { return "timeLock" }
 func (msg TimeLockMsg) String() string {
-	return fmt.Sprintf("TimeLock{%s#%v#%v#%v}", msg.From, msg.Description, msg.Amount, msg.LockTime)
+	return fmt.Sprintf("TimeLock{%s#%v#%v#%v#%s}", msg.From, msg.Description, msg.Amount, msg.LockTime, msg.Beneficiary)
 }
 func (msg TimeLockMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	if len(msg.Beneficiary) != 0 {
+		return []sdk.AccAddress{msg.From, msg.Beneficiary, TimeLockCoinsAccAddr}
+	}
 	return []sdk.AccAddress{msg.From, TimeLockCoinsAccAddr}
 }
 func (msg TimeLockMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
 
 func (msg TimeLockMsg) ValidateBasic() sdk.Error {
+	if len(msg.Beneficiary) != 0 && len(msg.Beneficiary) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("length of beneficiary address should be %d", sdk.AddrLen))
+	}
+
 	if len(msg.Description) == 0 || len(msg.Description) > MaxDescriptionLength {
 		return ErrInvalidDescription(DefaultCodespace,
 			fmt.Sprintf("length of description(%d) should be larger than 0 and be less than or equal to %d",
@@ -170,6 +194,8 @@ func (msg TimeRelockMsg) GetSignBytes() []byte {
 type TimeUnlockMsg struct {
 	From sdk.AccAddress `json:"from"`
 	Id   int64          `json:"time_lock_id"`
+	// the grantor is set when the beneficiary unlocks a time lock locked by the grantor for it
+	Grantor sdk.AccAddress `json:"grantor,omitempty"`
 }
 
 func NewTimeUnlockMsg(from sdk.AccAddress, id int64) TimeUnlockMsg {
@@ -179,10 +205,18 @@ func NewTimeUnlockMsg(from sdk.AccAddress, id int64) TimeUnlockMsg {
 	}
 }
 
+func NewBeneficiaryTimeUnlockMsg(from, grantor sdk.AccAddress, id int64) TimeUnlockMsg {
+	return TimeUnlockMsg{
+		From:    from,
+		Id:      id,
+		Grantor: grantor,
+	}
+}
+
 func (msg TimeUnlockMsg) Route() string { return MsgRoute }
 func (msg TimeUnlockMsg) Type() string  { return "timeUnlock" }
 func (msg TimeUnlockMsg) String() string {
-	return fmt.Sprintf("TimeUnlock{%s#%v}", msg.From, msg.Id)
+	return fmt.Sprintf("TimeUnlock{%s#%v#%s}", msg.From, msg.Id, msg.Grantor)
 }
 func (msg TimeUnlockMsg) GetInvolvedAddresses() []sdk.AccAddress {
 	return []sdk.AccAddress{msg.From, TimeLockCoinsAccAddr}
@@ -193,6 +227,10 @@ func (msg TimeUnlockMsg) ValidateBasic() sdk.Error {
 	if msg.Id < InitialRecordId {
 		return ErrInvalidTimeLockId(DefaultCodespace, fmt.Sprintf("time lock id should not be less than %d", InitialRecordId))
 	}
+
+	if len(msg.Grantor) != 0 && len(msg.Grantor) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("length of grantor address should be %d", sdk.AddrLen))
+	}
 	return nil
 }
 
@@ -203,3 +241,183 @@ func (msg TimeUnlockMsg) GetSignBytes() []byte {
 	}
 	return b
 }
+
+var _ sdk.Msg = VestingLockMsg{}
+
+// VestingLockMsg locks the coins of the sender and releases them to the beneficiary by the vesting schedule
+type VestingLockMsg struct {
+	From        sdk.AccAddress `json:"from"`
+	Beneficiary sdk.AccAddress `json:"beneficiary"`
+	Description string         `json:"description"`
+	Amount      sdk.Coins      `json:"amount"`
+	StartTime   int64          `json:"start_time"`
+	CliffTime   int64          `json:"cliff_time"`
+	EndTime     int64          `json:"end_time"`
+	Period      int64          `json:"period"`
+	Revocable   bool           `json:"revocable"`
+}
+
+func NewVestingLockMsg(from, beneficiary sdk.AccAddress, description string, amount sdk.Coins,
+	startTime, cliffTime, endTime, period int64, revocable bool) VestingLockMsg {
+	return VestingLockMsg{
+		From:        from,
+		Beneficiary: beneficiary,
+		Description: description,
+		Amount:      amount,
+		StartTime:   startTime,
+		CliffTime:   cliffTime,
+		EndTime:     endTime,
+		Period:      period,
+		Revocable:   revocable,
+	}
+}
+
+func (msg VestingLockMsg) Route() string { return MsgRoute }
+func (msg VestingLockMsg) Type() string  { return "vestingLock" }
+func (msg VestingLockMsg) String() string {
+	return fmt.Sprintf("VestingLock{%s#%s#%v#%v#%v#%v#%v#%v#%v}", msg.From, msg.Beneficiary, msg.Description,
+		msg.Amount, msg.StartTime, msg.CliffTime, msg.EndTime, msg.Period, msg.Revocable)
+}
+func (msg VestingLockMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From, msg.Beneficiary, TimeLockCoinsAccAddr}
+}
+func (msg VestingLockMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
+
+func (msg VestingLockMsg) ValidateBasic() sdk.Error {
+	if len(msg.Beneficiary) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("length of beneficiary address should be %d", sdk.AddrLen))
+	}
+
+	if len(msg.Description) == 0 || len(msg.Description) > MaxDescriptionLength {
+		return ErrInvalidDescription(DefaultCodespace,
+			fmt.Sprintf("length of description(%d) should be larger than 0 and be less than or equal to %d",
+				len(msg.Description), MaxDescriptionLength))
+	}
+
+	if msg.StartTime <= 0 {
+		return ErrInvalidVestingSchedule(DefaultCodespace, fmt.Sprintf("start time(%d) should be larger than 0", msg.StartTime))
+	}
+
+	if msg.EndTime >= MaxLockTime {
+		return ErrInvalidVestingSchedule(DefaultCodespace, fmt.Sprintf("end time(%d) should be less than %d", msg.EndTime, MaxLockTime))
+	}
+
+	if msg.CliffTime < msg.StartTime || msg.CliffTime > msg.EndTime {
+		return ErrInvalidVestingSchedule(DefaultCodespace,
+			fmt.Sprintf("cliff time(%d) should be between start time(%d) and end time(%d)", msg.CliffTime, msg.StartTime, msg.EndTime))
+	}
+
+	if msg.Period <= 0 || msg.Period > msg.EndTime-msg.StartTime {
+		return ErrInvalidVestingSchedule(DefaultCodespace,
+			fmt.Sprintf("period(%d) should be larger than 0 and not be larger than the vesting duration(%d)",
+				msg.Period, msg.EndTime-msg.StartTime))
+	}
+
+	if !msg.Amount.IsValid() {
+		return sdk.ErrInvalidCoins(msg.Amount.String())
+	}
+
+	if !msg.Amount.IsPositive() {
+		return sdk.ErrInvalidCoins(msg.Amount.String())
+	}
+
+	symbolError := types.ValidateTokenSymbols(msg.Amount)
+	if symbolError != nil {
+		return sdk.ErrInvalidCoins(symbolError.Error())
+	}
+
+	return nil
+}
+
+func (msg VestingLockMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var _ sdk.Msg = VestingClaimMsg{}
+
+// VestingClaimMsg releases the coins vested to the beneficiary
+type VestingClaimMsg struct {
+	From sdk.AccAddress `json:"from"`
+	Id   int64          `json:"vesting_id"`
+}
+
+func NewVestingClaimMsg(from sdk.AccAddress, id int64) VestingClaimMsg {
+	return VestingClaimMsg{
+		From: from,
+		Id:   id,
+	}
+}
+
+func (msg VestingClaimMsg) Route() string { return MsgRoute }
+func (msg VestingClaimMsg) Type() string  { return "vestingClaim" }
+func (msg VestingClaimMsg) String() string {
+	return fmt.Sprintf("VestingClaim{%s#%v}", msg.From, msg.Id)
+}
+func (msg VestingClaimMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From, TimeLockCoinsAccAddr}
+}
+func (msg VestingClaimMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
+
+func (msg VestingClaimMsg) ValidateBasic() sdk.Error {
+	if msg.Id < InitialRecordId {
+		return ErrInvalidTimeLockId(DefaultCodespace, fmt.Sprintf("vesting id should not be less than %d", InitialRecordId))
+	}
+	return nil
+}
+
+func (msg VestingClaimMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var _ sdk.Msg = VestingRevokeMsg{}
+
+// VestingRevokeMsg revokes a revocable vesting by its grantor
+type VestingRevokeMsg struct {
+	From        sdk.AccAddress `json:"from"`
+	Beneficiary sdk.AccAddress `json:"beneficiary"`
+	Id          int64          `json:"vesting_id"`
+}
+
+func NewVestingRevokeMsg(from, beneficiary sdk.AccAddress, id int64) VestingRevokeMsg {
+	return VestingRevokeMsg{
+		From:        from,
+		Beneficiary: beneficiary,
+		Id:          id,
+	}
+}
+
+func (msg VestingRevokeMsg) Route() string { return MsgRoute }
+func (msg VestingRevokeMsg) Type() string  { return "vestingRevoke" }
+func (msg VestingRevokeMsg) String() string {
+	return fmt.Sprintf("VestingRevoke{%s#%s#%v}", msg.From, msg.Beneficiary, msg.Id)
+}
+func (msg VestingRevokeMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From, msg.Beneficiary, TimeLockCoinsAccAddr}
+}
+func (msg VestingRevokeMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
+
+func (msg VestingRevokeMsg) ValidateBasic() sdk.Error {
+	if len(msg.Beneficiary) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("length of beneficiary address should be %d", sdk.AddrLen))
+	}
+	if msg.Id < InitialRecordId {
+		return ErrInvalidTimeLockId(DefaultCodespace, fmt.Sprintf("vesting id should not be less than %d", InitialRecordId))
+	}
+	return nil
+}
+
+func (msg VestingRevokeMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -262,3 +262,23 @@ func TestTimeUnlockMsg(t *testing.T) {
 		}
 	}
 }
+
+func TestBeneficiaryTimeLockMsg(t *testing.T) {
+	_, addrs, _, _ := mock.CreateGenAccounts(2, sdk.Coins{})
+	amount := sdk.Coins{sdk.NewCoin("BNB", 2000e8)}
+
+	require.Nil(t, NewBeneficiaryTimeLockMsg(addrs[0], addrs[1], "Test", amount, 1000).ValidateBasic())
+	require.Nil(t, NewBeneficiaryTimeLockMsg(addrs[0], nil, "Test", amount, 1000).ValidateBasic())
+	err := NewBeneficiaryTimeLockMsg(addrs[0], addrs[1][:10], "Test", amount, 1000).ValidateBasic()
+	require.NotNil(t, err)
+	require.Equal(t, sdk.CodeInvalidAddress, err.Code())
+
+	require.Nil(t, NewBeneficiaryTimeUnlockMsg(addrs[1], addrs[0], 1).ValidateBasic())
+	err = NewBeneficiaryTimeUnlockMsg(addrs[1], addrs[0][:10], 1).ValidateBasic()
+	require.NotNil(t, err)
+	require.Equal(t, sdk.CodeInvalidAddress, err.Code())
+
+	// the sign bytes of the time locks without beneficiary are not changed
+	require.NotContains(t, string(NewTimeLockMsg(addrs[0], "Test", amount, 1000).GetSignBytes()), "beneficiary")
+	require.NotContains(t, string(NewTimeUnlockMsg(addrs[0], 1).GetSignBytes()), "grantor")
+}
@@ -12,6 +12,8 @@ import (
 const (
 	QueryTimeLocks = "timelocks"
 	QueryTimeLock  = "timelock"
+	QueryVestings  = "vestings"
+	QueryVesting   = "vesting"
 )
 
 func NewQuerier(keeper Keeper) sdk.Querier {
@@ -21,6 +23,10 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			return queryTimeLocks(ctx, req, keeper)
 		case QueryTimeLock:
 			return queryTimeLock(ctx, req, keeper)
+		case QueryVestings:
+			return queryVestings(ctx, req, keeper)
+		case QueryVesting:
+			return queryVesting(ctx, req, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown time lock query endpoint %s", path[0]))
 		}
@@ -88,3 +94,65 @@ func queryTimeLock(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byt
 
 	return bz, nil
 }
+
+// Params for query 'custom/timelock/vestings'
+type QueryVestingsParams struct {
+	Account sdk.AccAddress
+}
+
+// nolint: unparam
+func queryVestings(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params QueryVestingsParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+
+	if len(params.Account) != sdk.AddrLen {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("length of address should be %d", sdk.AddrLen))
+	}
+
+	vestings := keeper.GetVestingRecords(ctx, params.Account)
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, vestings)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return bz, nil
+}
+
+// Params for query 'custom/timelock/vesting'
+type QueryVestingParams struct {
+	Account sdk.AccAddress
+	Id      int64
+}
+
+// nolint: unparam
+func queryVesting(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params QueryVestingParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+
+	if len(params.Account) != sdk.AddrLen {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("length of address should be %d", sdk.AddrLen))
+	}
+
+	if params.Id < InitialRecordId {
+		return nil, ErrInvalidTimeLockId(DefaultCodespace,
+			fmt.Sprintf("vesting id(%d) should not be less than %d", params.Id, InitialRecordId))
+	}
+
+	vesting, found := keeper.GetVestingRecord(ctx, params.Account, params.Id)
+	if !found {
+		return nil, ErrVestingRecordDoesNotExist(DefaultCodespace, params.Account, params.Id)
+	}
+
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, vesting)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return bz, nil
+}
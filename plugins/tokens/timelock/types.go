@@ -1,6 +1,7 @@
 package timelock
 
 import (
+	"math/big"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -11,6 +12,14 @@ type TimeLockRecord struct {
 	Description string    `json:"description"`
 	Amount      sdk.Coins `json:"amount"`
 	LockTime    time.Time `json:"lock_time"`
+	// Grantor and Beneficiary are only set for the time locks for a third-party beneficiary
+	Grantor     sdk.AccAddress `json:"grantor,omitempty"`
+	Beneficiary sdk.AccAddress `json:"beneficiary,omitempty"`
+}
+
+// HasBeneficiary returns whether the coins are locked for a third-party beneficiary
+func (record TimeLockRecord) HasBeneficiary() bool {
+	return len(record.Beneficiary) != 0
 }
 
 type TimeLockRecords []TimeLockRecord
@@ -24,3 +33,61 @@ func (a TimeLockRecords) Swap(i, j int) {
 func (a TimeLockRecords) Less(i, j int) bool {
 	return a[i].Id < a[j].Id
 }
+
+// VestingRecord locks the coins granted to the beneficiary and releases them linearly every period from the start
+// time to the end time, nothing is released before the cliff time. The cliff time equals the start time for a
+// linear vesting.
+type VestingRecord struct {
+	Id          int64          `json:"id"`
+	Grantor     sdk.AccAddress `json:"grantor"`
+	Beneficiary sdk.AccAddress `json:"beneficiary"`
+	Description string         `json:"description"`
+	Amount      sdk.Coins      `json:"amount"`   // the total amount to vest
+	Released    sdk.Coins      `json:"released"` // the amount released to the beneficiary
+	StartTime   time.Time      `json:"start_time"`
+	CliffTime   time.Time      `json:"cliff_time"`
+	EndTime     time.Time      `json:"end_time"`
+	Period      int64          `json:"period"` // seconds between two releases
+	Revocable   bool           `json:"revocable"`
+}
+
+// VestedAmount returns the amount vested by the time, including the amount released
+func (record VestingRecord) VestedAmount(now time.Time) sdk.Coins {
+	if now.Before(record.CliffTime) {
+		return sdk.Coins{}
+	}
+	if !now.Before(record.EndTime) {
+		return record.Amount
+	}
+	duration := record.EndTime.Unix() - record.StartTime.Unix()
+	periods := (duration + record.Period - 1) / record.Period
+	elapsed := (now.Unix() - record.StartTime.Unix()) / record.Period
+
+	vested := sdk.Coins{}
+	for _, coin := range record.Amount {
+		// amount * elapsed may overflow int64
+		amount := new(big.Int).Mul(big.NewInt(coin.Amount), big.NewInt(elapsed))
+		amount.Quo(amount, big.NewInt(periods))
+		if amount.Sign() > 0 {
+			vested = append(vested, sdk.NewCoin(coin.Denom, amount.Int64()))
+		}
+	}
+	return vested
+}
+
+// Releasable returns the amount vested by the time but not released yet
+func (record VestingRecord) Releasable(now time.Time) sdk.Coins {
+	return record.VestedAmount(now).Minus(record.Released)
+}
+
+type VestingRecords []VestingRecord
+
+func (a VestingRecords) Len() int {
+	return len(a)
+}
+func (a VestingRecords) Swap(i, j int) {
+	a[i], a[j] = a[j], a[i]
+}
+func (a VestingRecords) Less(i, j int) bool {
+	return a[i].Id < a[j].Id
+}
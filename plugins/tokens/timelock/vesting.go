@@ -0,0 +1,174 @@
+package timelock
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func (keeper Keeper) setVestingRecord(ctx sdk.Context, record VestingRecord) {
+	store := ctx.KVStore(keeper.storeKey)
+	bz := keeper.cdc.MustMarshalBinaryLengthPrefixed(record)
+	store.Set(KeyVestingRecord(record.Beneficiary, record.Id), bz)
+}
+
+func (keeper Keeper) deleteVestingRecord(ctx sdk.Context, addr sdk.AccAddress, recordId int64) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Delete(KeyVestingRecord(addr, recordId))
+}
+
+func (keeper Keeper) GetVestingRecord(ctx sdk.Context, addr sdk.AccAddress, recordId int64) (VestingRecord, bool) {
+	store := ctx.KVStore(keeper.storeKey)
+	bz := store.Get(KeyVestingRecord(addr, recordId))
+	if bz == nil {
+		return VestingRecord{}, false
+	}
+
+	var record VestingRecord
+	keeper.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &record)
+	return record, true
+}
+
+// GetVestingRecords returns the vesting records of which the address is the beneficiary
+func (keeper Keeper) GetVestingRecords(ctx sdk.Context, addr sdk.AccAddress) []VestingRecord {
+	var records []VestingRecord
+	store := ctx.KVStore(keeper.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, KeyVestingRecordSubSpace(addr))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		record := VestingRecord{}
+		keeper.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &record)
+		records = append(records, record)
+	}
+
+	sort.Sort(VestingRecords(records))
+
+	return records
+}
+
+func (kp *Keeper) GetVestingRecordIterator(ctx sdk.Context) (iterator store.Iterator) {
+	kvStore := ctx.KVStore(kp.storeKey)
+	return sdk.KVStorePrefixIterator(kvStore, vestingKeyPrefix)
+}
+
+func (keeper Keeper) nextVestingId(ctx sdk.Context) int64 {
+	store := ctx.KVStore(keeper.storeKey)
+	id := int64(InitialRecordId)
+	if bz := store.Get(vestingIdKey); bz != nil {
+		id = int64(binary.BigEndian.Uint64(bz))
+	}
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(id+1))
+	store.Set(vestingIdKey, bz)
+	return id
+}
+
+// VestingLock locks the coins of the grantor and releases them to the beneficiary by the vesting schedule
+func (keeper Keeper) VestingLock(ctx sdk.Context, grantor, beneficiary sdk.AccAddress, description string, amount sdk.Coins,
+	startTime, cliffTime, endTime time.Time, period int64, revocable bool) (VestingRecord, sdk.Error) {
+	if !endTime.After(ctx.BlockHeader().Time.Add(MinLockTime)) {
+		return VestingRecord{}, ErrInvalidVestingSchedule(DefaultCodespace,
+			fmt.Sprintf("end time(%s) should be %d minute(s) after now(%s)", endTime.UTC().String(),
+				MinLockTime/time.Minute, ctx.BlockHeader().Time.Add(MinLockTime).UTC().String()))
+	}
+
+	_, err := keeper.ck.SendCoins(ctx, grantor, TimeLockCoinsAccAddr, amount)
+	if err != nil {
+		return VestingRecord{}, err
+	}
+
+	record := VestingRecord{
+		Id:          keeper.nextVestingId(ctx),
+		Grantor:     grantor,
+		Beneficiary: beneficiary,
+		Description: description,
+		Amount:      amount,
+		StartTime:   startTime,
+		CliffTime:   cliffTime,
+		EndTime:     endTime,
+		Period:      period,
+		Revocable:   revocable,
+	}
+	keeper.setVestingRecord(ctx, record)
+	return record, nil
+}
+
+// VestingClaim releases the coins vested but not released yet to the beneficiary, the record is deleted once all
+// the coins are released
+func (keeper Keeper) VestingClaim(ctx sdk.Context, beneficiary sdk.AccAddress, recordId int64) (sdk.Coins, sdk.Error) {
+	record, found := keeper.GetVestingRecord(ctx, beneficiary, recordId)
+	if !found {
+		return nil, ErrVestingRecordDoesNotExist(DefaultCodespace, beneficiary, recordId)
+	}
+
+	releasable := record.Releasable(ctx.BlockHeader().Time)
+	if releasable.IsZero() {
+		return nil, ErrCanNotUnlock(DefaultCodespace, fmt.Sprintf("nothing is vested after released(%s) by now(%s)",
+			record.Released.String(), ctx.BlockHeader().Time.UTC().String()))
+	}
+
+	_, err := keeper.ck.SendCoins(ctx, TimeLockCoinsAccAddr, beneficiary, releasable)
+	if err != nil {
+		return nil, err
+	}
+
+	record.Released = record.Released.Plus(releasable)
+	if record.Released.IsEqual(record.Amount) {
+		keeper.deleteVestingRecord(ctx, beneficiary, recordId)
+	} else {
+		keeper.setVestingRecord(ctx, record)
+	}
+	return releasable, nil
+}
+
+// VestingRevoke revokes a revocable vesting by its grantor, the coins vested but not released yet are released to
+// the beneficiary and the coins not vested yet are returned to the grantor
+func (keeper Keeper) VestingRevoke(ctx sdk.Context, grantor, beneficiary sdk.AccAddress, recordId int64) sdk.Error {
+	record, found := keeper.GetVestingRecord(ctx, beneficiary, recordId)
+	if !found || !record.Grantor.Equals(grantor) {
+		return ErrVestingRecordDoesNotExist(DefaultCodespace, beneficiary, recordId)
+	}
+	if !record.Revocable {
+		return ErrVestingNotRevocable(DefaultCodespace, beneficiary, recordId)
+	}
+
+	vested := record.VestedAmount(ctx.BlockHeader().Time)
+	if releasable := vested.Minus(record.Released); !releasable.IsZero() {
+		_, err := keeper.ck.SendCoins(ctx, TimeLockCoinsAccAddr, beneficiary, releasable)
+		if err != nil {
+			return err
+		}
+	}
+	if unvested := record.Amount.Minus(vested); !unvested.IsZero() {
+		_, err := keeper.ck.SendCoins(ctx, TimeLockCoinsAccAddr, grantor, unvested)
+		if err != nil {
+			return err
+		}
+	}
+
+	keeper.deleteVestingRecord(ctx, beneficiary, recordId)
+	return nil
+}
+
+// VestingRefund releases all the coins not released yet to the beneficiary regardless of the vesting schedule,
+// it's used to refund the vesting records after BC fusion
+func (keeper Keeper) VestingRefund(ctx sdk.Context, beneficiary sdk.AccAddress, recordId int64) sdk.Error {
+	record, found := keeper.GetVestingRecord(ctx, beneficiary, recordId)
+	if !found {
+		return ErrVestingRecordDoesNotExist(DefaultCodespace, beneficiary, recordId)
+	}
+
+	if remaining := record.Amount.Minus(record.Released); !remaining.IsZero() {
+		_, err := keeper.ck.SendCoins(ctx, TimeLockCoinsAccAddr, beneficiary, remaining)
+		if err != nil {
+			return err
+		}
+	}
+
+	keeper.deleteVestingRecord(ctx, beneficiary, recordId)
+	return nil
+}
@@ -0,0 +1,196 @@
+package timelock
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/common/testutils"
+)
+
+func setupVesting(t *testing.T) (sdk.Context, auth.AccountKeeper, Keeper, sdk.Account, sdk.Account) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accountCache := getAccountCache(cdc, cms)
+	ctx := sdk.NewContext(cms, abci.Header{Time: time.Unix(1000, 0)}, sdk.RunTxModeDeliver, logger).WithAccountCache(accountCache)
+
+	_, grantor := testutils.NewAccount(ctx, accKeeper, 0)
+	_ = grantor.SetCoins(sdk.Coins{
+		sdk.NewCoin("BNB", 1000e8),
+	}.Sort())
+	accKeeper.SetAccount(ctx, grantor)
+	_, beneficiary := testutils.NewAccount(ctx, accKeeper, 0)
+	return ctx, accKeeper, keeper, grantor, beneficiary
+}
+
+func TestVestingRecord_VestedAmount(t *testing.T) {
+	record := VestingRecord{
+		Amount:    sdk.Coins{sdk.NewCoin("BNB", 1200e8), sdk.NewCoin("XYZ-000", 7)}.Sort(),
+		StartTime: time.Unix(1000, 0),
+		CliffTime: time.Unix(1300, 0),
+		EndTime:   time.Unix(2200, 0),
+		Period:    100,
+	}
+
+	require.True(t, record.VestedAmount(time.Unix(1299, 0)).IsZero())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 300e8), sdk.NewCoin("XYZ-000", 1)}, record.VestedAmount(time.Unix(1300, 0)))
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 300e8), sdk.NewCoin("XYZ-000", 1)}, record.VestedAmount(time.Unix(1399, 0)))
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1100e8), sdk.NewCoin("XYZ-000", 6)}, record.VestedAmount(time.Unix(2199, 0)))
+	require.Equal(t, record.Amount, record.VestedAmount(time.Unix(2200, 0)))
+
+	record.Released = sdk.Coins{sdk.NewCoin("BNB", 300e8), sdk.NewCoin("XYZ-000", 2)}
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 100e8)}, record.Releasable(time.Unix(1400, 0)))
+
+	// the last period is shorter than the others
+	record = VestingRecord{
+		Amount:    sdk.Coins{sdk.NewCoin("BNB", 1000)},
+		StartTime: time.Unix(1000, 0),
+		CliffTime: time.Unix(1000, 0),
+		EndTime:   time.Unix(1250, 0),
+		Period:    100,
+	}
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 666)}, record.VestedAmount(time.Unix(1200, 0)))
+	require.Equal(t, record.Amount, record.VestedAmount(time.Unix(1250, 0)))
+}
+
+func TestKeeper_VestingLock_ErrorEndTime(t *testing.T) {
+	ctx, _, keeper, grantor, beneficiary := setupVesting(t)
+
+	_, err := keeper.VestingLock(ctx, grantor.GetAddress(), beneficiary.GetAddress(), "Test",
+		sdk.Coins{sdk.NewCoin("BNB", 100e8)}, time.Unix(1000, 0), time.Unix(1000, 0), time.Unix(1030, 0), 10, false)
+	require.NotNil(t, err)
+	require.Equal(t, CodeInvalidVestingSchedule, err.Code())
+}
+
+func TestKeeper_VestingClaim(t *testing.T) {
+	ctx, accKeeper, keeper, grantor, beneficiary := setupVesting(t)
+
+	record, err := keeper.VestingLock(ctx, grantor.GetAddress(), beneficiary.GetAddress(), "Test",
+		sdk.Coins{sdk.NewCoin("BNB", 1000e8)}, time.Unix(1000, 0), time.Unix(2000, 0), time.Unix(11000, 0), 1000, false)
+	require.Nil(t, err)
+	require.Equal(t, int64(InitialRecordId), record.Id)
+	require.True(t, accKeeper.GetAccount(ctx, grantor.GetAddress()).GetCoins().IsZero())
+	require.Len(t, keeper.GetVestingRecords(ctx, beneficiary.GetAddress()), 1)
+
+	// nothing is released before the cliff
+	ctx = ctx.WithBlockTime(time.Unix(1999, 0))
+	_, err = keeper.VestingClaim(ctx, beneficiary.GetAddress(), record.Id)
+	require.NotNil(t, err)
+	require.Equal(t, CodeCanNotUnlock, err.Code())
+
+	// only the beneficiary can claim
+	_, err = keeper.VestingClaim(ctx, grantor.GetAddress(), record.Id)
+	require.NotNil(t, err)
+	require.Equal(t, CodeVestingRecordDoesNotExist, err.Code())
+
+	ctx = ctx.WithBlockTime(time.Unix(4500, 0))
+	released, err := keeper.VestingClaim(ctx, beneficiary.GetAddress(), record.Id)
+	require.Nil(t, err)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 300e8)}, released)
+	require.Equal(t, released, accKeeper.GetAccount(ctx, beneficiary.GetAddress()).GetCoins())
+
+	// nothing more is vested in the same period
+	ctx = ctx.WithBlockTime(time.Unix(4999, 0))
+	_, err = keeper.VestingClaim(ctx, beneficiary.GetAddress(), record.Id)
+	require.NotNil(t, err)
+
+	ctx = ctx.WithBlockTime(time.Unix(11000, 0))
+	released, err = keeper.VestingClaim(ctx, beneficiary.GetAddress(), record.Id)
+	require.Nil(t, err)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 700e8)}, released)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1000e8)}, accKeeper.GetAccount(ctx, beneficiary.GetAddress()).GetCoins())
+
+	_, found := keeper.GetVestingRecord(ctx, beneficiary.GetAddress(), record.Id)
+	require.False(t, found)
+}
+
+func TestKeeper_VestingRevoke(t *testing.T) {
+	ctx, accKeeper, keeper, grantor, beneficiary := setupVesting(t)
+
+	record, err := keeper.VestingLock(ctx, grantor.GetAddress(), beneficiary.GetAddress(), "Test",
+		sdk.Coins{sdk.NewCoin("BNB", 500e8)}, time.Unix(1000, 0), time.Unix(1000, 0), time.Unix(11000, 0), 1000, false)
+	require.Nil(t, err)
+	revocable, err := keeper.VestingLock(ctx, grantor.GetAddress(), beneficiary.GetAddress(), "Test",
+		sdk.Coins{sdk.NewCoin("BNB", 500e8)}, time.Unix(1000, 0), time.Unix(1000, 0), time.Unix(11000, 0), 1000, true)
+	require.Nil(t, err)
+	require.Equal(t, record.Id+1, revocable.Id)
+
+	ctx = ctx.WithBlockTime(time.Unix(3000, 0))
+	err = keeper.VestingRevoke(ctx, grantor.GetAddress(), beneficiary.GetAddress(), record.Id)
+	require.NotNil(t, err)
+	require.Equal(t, CodeVestingNotRevocable, err.Code())
+
+	// only the grantor can revoke
+	err = keeper.VestingRevoke(ctx, beneficiary.GetAddress(), beneficiary.GetAddress(), revocable.Id)
+	require.NotNil(t, err)
+	require.Equal(t, CodeVestingRecordDoesNotExist, err.Code())
+
+	released, err := keeper.VestingClaim(ctx, beneficiary.GetAddress(), revocable.Id)
+	require.Nil(t, err)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 100e8)}, released)
+
+	ctx = ctx.WithBlockTime(time.Unix(5000, 0))
+	err = keeper.VestingRevoke(ctx, grantor.GetAddress(), beneficiary.GetAddress(), revocable.Id)
+	require.Nil(t, err)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 200e8)}, accKeeper.GetAccount(ctx, beneficiary.GetAddress()).GetCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 300e8)}, accKeeper.GetAccount(ctx, grantor.GetAddress()).GetCoins())
+
+	records := keeper.GetVestingRecords(ctx, beneficiary.GetAddress())
+	require.Len(t, records, 1)
+	require.Equal(t, record.Id, records[0].Id)
+}
+
+func TestKeeper_VestingRefund(t *testing.T) {
+	ctx, accKeeper, keeper, grantor, beneficiary := setupVesting(t)
+
+	record, err := keeper.VestingLock(ctx, grantor.GetAddress(), beneficiary.GetAddress(), "Test",
+		sdk.Coins{sdk.NewCoin("BNB", 1000e8)}, time.Unix(1000, 0), time.Unix(1000, 0), time.Unix(11000, 0), 1000, true)
+	require.Nil(t, err)
+
+	ctx = ctx.WithBlockTime(time.Unix(2000, 0))
+	_, err = keeper.VestingClaim(ctx, beneficiary.GetAddress(), record.Id)
+	require.Nil(t, err)
+
+	err = keeper.VestingRefund(ctx, beneficiary.GetAddress(), record.Id)
+	require.Nil(t, err)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1000e8)}, accKeeper.GetAccount(ctx, beneficiary.GetAddress()).GetCoins())
+	require.Len(t, keeper.GetVestingRecords(ctx, beneficiary.GetAddress()), 0)
+}
+
+func TestVestingLockMsg(t *testing.T) {
+	_, addrs, _, _ := mock.CreateGenAccounts(2, sdk.Coins{})
+	amount := sdk.Coins{sdk.NewCoin("BNB", 1000e8)}
+
+	valid := NewVestingLockMsg(addrs[0], addrs[1], "Test", amount, 1000, 2000, 11000, 1000, true)
+	require.Nil(t, valid.ValidateBasic())
+
+	tests := []struct {
+		msg       VestingLockMsg
+		errorCode sdk.CodeType
+	}{
+		{NewVestingLockMsg(addrs[0], nil, "Test", amount, 1000, 2000, 11000, 1000, true), sdk.CodeInvalidAddress},
+		{NewVestingLockMsg(addrs[0], addrs[1], "", amount, 1000, 2000, 11000, 1000, true), CodeInvalidDescription},
+		{NewVestingLockMsg(addrs[0], addrs[1], "Test", amount, 0, 0, 11000, 1000, true), CodeInvalidVestingSchedule},
+		{NewVestingLockMsg(addrs[0], addrs[1], "Test", amount, 1000, 999, 11000, 1000, true), CodeInvalidVestingSchedule},
+		{NewVestingLockMsg(addrs[0], addrs[1], "Test", amount, 1000, 11001, 11000, 1000, true), CodeInvalidVestingSchedule},
+		{NewVestingLockMsg(addrs[0], addrs[1], "Test", amount, 1000, 2000, MaxLockTime, 1000, true), CodeInvalidVestingSchedule},
+		{NewVestingLockMsg(addrs[0], addrs[1], "Test", amount, 1000, 2000, 11000, 0, true), CodeInvalidVestingSchedule},
+		{NewVestingLockMsg(addrs[0], addrs[1], "Test", amount, 1000, 2000, 11000, 10001, true), CodeInvalidVestingSchedule},
+		{NewVestingLockMsg(addrs[0], addrs[1], "Test", sdk.Coins{sdk.NewCoin("BNB", 0)}, 1000, 2000, 11000, 1000, true), sdk.CodeInvalidCoins},
+	}
+
+	for i, test := range tests {
+		err := test.msg.ValidateBasic()
+		require.NotNil(t, err, "case %d", i)
+		require.Equal(t, test.errorCode, err.Code(), "case %d", i)
+	}
+}
@@ -3,5 +3,7 @@ package tokens
 import "github.com/bnb-chain/node/plugins/tokens/store"
 
 type Mapper = store.Mapper
+type HolderIndex = store.HolderIndex
 
 var NewMapper = store.NewMapper
+var NewHolderIndex = store.NewHolderIndex
@@ -4,6 +4,7 @@ import (
 	"github.com/bnb-chain/node/plugins/tokens/burn"
 	"github.com/bnb-chain/node/plugins/tokens/freeze"
 	"github.com/bnb-chain/node/plugins/tokens/issue"
+	"github.com/bnb-chain/node/plugins/tokens/metadata"
 	"github.com/bnb-chain/node/plugins/tokens/ownership"
 	"github.com/bnb-chain/node/plugins/tokens/seturi"
 	"github.com/bnb-chain/node/plugins/tokens/swap"
@@ -21,6 +22,9 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(timelock.TimeLockMsg{}, "tokens/TimeLockMsg", nil)
 	cdc.RegisterConcrete(timelock.TimeUnlockMsg{}, "tokens/TimeUnlockMsg", nil)
 	cdc.RegisterConcrete(timelock.TimeRelockMsg{}, "tokens/TimeRelockMsg", nil)
+	cdc.RegisterConcrete(timelock.VestingLockMsg{}, "tokens/VestingLockMsg", nil)
+	cdc.RegisterConcrete(timelock.VestingClaimMsg{}, "tokens/VestingClaimMsg", nil)
+	cdc.RegisterConcrete(timelock.VestingRevokeMsg{}, "tokens/VestingRevokeMsg", nil)
 	cdc.RegisterConcrete(swap.HTLTMsg{}, "tokens/HTLTMsg", nil)
 	cdc.RegisterConcrete(swap.DepositHTLTMsg{}, "tokens/DepositHTLTMsg", nil)
 	cdc.RegisterConcrete(swap.ClaimHTLTMsg{}, "tokens/ClaimHTLTMsg", nil)
@@ -29,4 +33,5 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(issue.IssueTinyMsg{}, "tokens/IssueTinyMsg", nil)
 	cdc.RegisterConcrete(seturi.SetURIMsg{}, "tokens/SetURIMsg", nil)
 	cdc.RegisterConcrete(ownership.TransferOwnershipMsg{}, "tokens/TransferOwnershipMsg", nil)
+	cdc.RegisterConcrete(metadata.SetTokenMetadataMsg{}, "tokens/SetTokenMetadataMsg", nil)
 }